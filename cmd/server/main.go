@@ -32,7 +32,11 @@ func main() {
 	defer application.Close()
 
 	// 初始化路由（注入真实 Handler）
-	r := router.Setup(cfg, application.Handlers)
+	r, err := router.Setup(cfg, application.Handlers, application.Middlewares)
+	if err != nil {
+		application.Close()
+		log.Fatalf("Failed to setup router: %v", err)
+	}
 
 	// 创建 HTTP 服务器
 	srv := &http.Server{
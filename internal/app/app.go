@@ -17,12 +17,15 @@ import (
 	"pronunciation-correction-system/internal/db"
 	"pronunciation-correction-system/internal/domain"
 	"pronunciation-correction-system/internal/handler"
+	"pronunciation-correction-system/internal/handler/middleware"
 	infraASR "pronunciation-correction-system/internal/infrastructure/asr/aliyun"
 	infraXF "pronunciation-correction-system/internal/infrastructure/evalution/xf"
 	infraLLM "pronunciation-correction-system/internal/infrastructure/llm/qwen"
+	infraModeration "pronunciation-correction-system/internal/infrastructure/moderation/local"
 	infraOSS "pronunciation-correction-system/internal/infrastructure/oss/aliyun"
 	infraTTS "pronunciation-correction-system/internal/infrastructure/tts/aliyun"
 	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/uuid"
 	"pronunciation-correction-system/internal/service"
 )
 
@@ -44,18 +47,31 @@ type App struct {
 	LLMProvider        domain.LLMProvider
 	TTSProvider        domain.TTSProvider
 	OSSProvider        domain.OSSProvider
+	ModerationProvider domain.ModerationProvider // 未启用内容安全审核时为 nil
 
 	// 服务层
-	AuthService     service.AuthService
-	UserService     service.UserService
-	ChatService     service.ChatService
-	EvaluateService service.EvaluateService
-	ReportService   service.ReportService
+	AuthService       service.AuthService
+	UserService       service.UserService
+	ChatService       service.ChatService
+	EvaluateService   service.EvaluateService
+	ReviewService     service.ReviewService
+	VocabularyService service.VocabularyService
+	MemoryService     service.LearnerMemoryService
+	ReportService     service.ReportService
+	QuotaService      service.QuotaService
+	AdminService      service.AdminService
 
 	// Handler 层
-	Handlers *handler.Handlers
+	Handlers    *handler.Handlers
+	Middlewares *middleware.Middlewares
+
+	// 后台定时任务
+	stopJobs context.CancelFunc
 }
 
+// sessionSweepJob 空闲会话扫描任务名（分布式锁 Key 后缀）
+const sessionSweepJob = "session_sweep"
+
 // New 创建并初始化应用程序实例
 func New(cfg *config.Config) (*App, error) {
 	app := &App{
@@ -80,6 +96,7 @@ func New(cfg *config.Config) (*App, error) {
 	app.initRepositories()
 	app.initServices()
 	app.initHandlers()
+	app.initJobs()
 
 	log.Println("[App] Application initialized successfully")
 	return app, nil
@@ -106,7 +123,7 @@ func (a *App) initDatabase() error {
 func (a *App) initRedis() error {
 	mgr, err := cache.NewManager(&cache.ManagerConfig{
 		Redis:        &a.Config.Redis,
-		DefaultQuota: 50,
+		DefaultQuota: a.Config.Quota.Plans[a.Config.Quota.DefaultPlan].Evaluate,
 	})
 	if err != nil {
 		return err
@@ -165,6 +182,24 @@ func (a *App) initInfrastructure() {
 		a.OSSProvider = ossAdapter
 	}
 
+	// Moderation: 内容安全审核（孩子输入与 AI 回复），关闭时不审核
+	if a.Config.Moderation.Enabled {
+		switch a.Config.Moderation.ActiveProvider {
+		case "local":
+			moderationAdapter, err := infraModeration.NewLocalModerationAdapter(a.Config.Moderation.Local)
+			if err != nil {
+				log.Fatalf("[App] Failed to create local moderation adapter: %v", err)
+			}
+			a.ModerationProvider = moderationAdapter
+		default:
+			moderationAdapter, err := infraModeration.NewLocalModerationAdapter(a.Config.Moderation.Local)
+			if err != nil {
+				log.Fatalf("[App] Failed to create local moderation adapter: %v", err)
+			}
+			a.ModerationProvider = moderationAdapter
+		}
+	}
+
 	log.Println("[App] Infrastructure adapters initialized")
 }
 
@@ -186,30 +221,131 @@ func (a *App) initServices() {
 	appLogger := slog.Default()
 	a.AuthService = service.NewAuthService(appLogger)
 	a.UserService = service.NewUserService(appLogger)
-	a.ChatService = service.NewChatService(a.Repos, a.ASRProvider, a.LLMProvider, a.TTSProvider, a.OSSProvider, appLogger)
-	a.EvaluateService = service.NewEvaluateService(a.Repos, a.EvaluationProvider, a.LLMProvider, a.TTSProvider, a.OSSProvider, appLogger)
-	a.ReportService = service.NewReportService(appLogger)
+	a.ReviewService = service.NewReviewService(a.Repos, a.EvaluationProvider, a.TTSProvider, a.OSSProvider, appLogger)
+	a.VocabularyService = service.NewVocabularyService(a.Repos, appLogger)
+	a.MemoryService = service.NewLearnerMemoryService(a.Repos, a.LLMProvider, a.ModerationProvider, appLogger)
+	var (
+		chatTaskCache    *cache.ChatTaskCache
+		minimalPairCache *cache.MinimalPairCache
+	)
+	if a.CacheManager != nil {
+		chatTaskCache = a.CacheManager.ChatTask
+		minimalPairCache = a.CacheManager.MinimalPair
+	}
+	a.ChatService = service.NewChatService(a.Repos, a.ASRProvider, a.LLMProvider, a.TTSProvider, a.OSSProvider, a.ModerationProvider, a.EvaluationProvider, a.ReviewService, a.VocabularyService, a.MemoryService, chatTaskCache, a.Config.Chat, appLogger)
+	a.EvaluateService = service.NewEvaluateService(a.Repos, a.EvaluationProvider, a.LLMProvider, a.TTSProvider, a.OSSProvider, a.ReviewService, a.VocabularyService, minimalPairCache, appLogger)
+	a.ReportService = service.NewReportService(a.Repos, a.VocabularyService, appLogger)
+	a.AdminService = service.NewAdminService(a.Repos, appLogger)
+
+	// 配额：Redis 不可用时 QuotaService 放行所有请求
+	var quotaCache *cache.QuotaCache
+	var userCache *cache.UserCache
+	if a.CacheManager != nil {
+		quotaCache = a.CacheManager.Quota
+		userCache = a.CacheManager.User
+	}
+	a.QuotaService = service.NewQuotaService(a.Repos, quotaCache, userCache, a.Config.Quota, appLogger)
 
 	log.Println("[App] Services initialized")
 }
 
 // initHandlers 初始化 HTTP Handler
 func (a *App) initHandlers() {
+	var (
+		rateLimitCache   *cache.RateLimitCache
+		idempotencyCache *cache.IdempotencyCache
+		lock             *cache.DistributedLock
+	)
+	if a.CacheManager != nil {
+		rateLimitCache = a.CacheManager.RateLimit
+		idempotencyCache = a.CacheManager.Idempotency
+		lock = a.CacheManager.Lock
+	}
+	rateLimit := middleware.NewRateLimitMiddleware(rateLimitCache, a.Config.RateLimit)
 	a.Handlers = &handler.Handlers{
-		Auth:     handler.NewAuthHandler(a.AuthService),
-		User:     handler.NewUserHandler(a.UserService),
-		Chat:     handler.NewChatHandler(a.ChatService),
-		Evaluate: handler.NewEvaluateHandler(a.EvaluateService),
-		Report:   handler.NewReportHandler(a.ReportService),
-		System:   handler.NewSystemHandler(),
+		Auth:          handler.NewAuthHandler(a.AuthService),
+		User:          handler.NewUserHandler(a.UserService, a.QuotaService),
+		Chat:          handler.NewChatHandler(a.ChatService, a.QuotaService, rateLimit),
+		Evaluate:      handler.NewEvaluateHandler(a.EvaluateService),
+		Review:        handler.NewReviewHandler(a.ReviewService),
+		Report:        handler.NewReportHandler(a.ReportService),
+		Vocabulary:    handler.NewVocabularyHandler(a.VocabularyService),
+		LearnerMemory: handler.NewLearnerMemoryHandler(a.MemoryService),
+		System:        handler.NewSystemHandler(),
+		Admin:         handler.NewAdminHandler(a.AdminService),
+	}
+	a.Middlewares = &middleware.Middlewares{
+		Quota:       middleware.NewQuotaMiddleware(a.QuotaService),
+		RateLimit:   rateLimit,
+		Idempotency: middleware.NewIdempotencyMiddleware(idempotencyCache, lock),
+		Admin:       middleware.NewAdminMiddleware(a.AdminService),
 	}
 	log.Println("[App] Handlers initialized")
 }
 
+// initJobs 启动后台定时任务
+func (a *App) initJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopJobs = cancel
+
+	if seconds := a.Config.Chat.IdleSweepSeconds; seconds > 0 {
+		go a.runSessionSweep(ctx, time.Duration(seconds)*time.Second)
+	}
+	log.Println("[App] Jobs started")
+}
+
+// runSessionSweep 定时结束空闲 / 暂停超时的会话
+// 多实例部署时通过分布式锁保证同一时刻只有一个实例扫描；Redis 不可用时每个实例都扫描（结束会话为条件更新，不会重复总结）
+func (a *App) runSessionSweep(ctx context.Context, interval time.Duration) {
+	var lock *cache.DistributedLock
+	if a.CacheManager != nil {
+		lock = a.CacheManager.Lock
+	}
+	lockKey := cache.JobLockKey(sessionSweepJob)
+	lockValue := uuid.New()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if lock != nil {
+			acquired, err := lock.TryLock(ctx, lockKey, lockValue, interval)
+			if err != nil {
+				logger.WarnContext(ctx, "session sweep acquire lock failed", "error", err)
+				continue
+			}
+			if !acquired {
+				continue // 其他实例正在扫描
+			}
+		}
+		completed, err := a.ChatService.CompleteIdleSessions(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "session sweep failed", "error", err)
+		} else if completed > 0 {
+			logger.InfoContext(ctx, "session sweep completed idle sessions", "completed", completed)
+		}
+		if lock != nil {
+			if err := lock.Unlock(ctx, lockKey, lockValue); err != nil {
+				logger.WarnContext(ctx, "session sweep release lock failed", "error", err)
+			}
+		}
+	}
+}
+
 // Close 优雅关闭所有资源
 func (a *App) Close() {
 	log.Println("[App] Shutting down...")
 
+	// 停止后台定时任务
+	if a.stopJobs != nil {
+		a.stopJobs()
+	}
+
 	// 关闭外部服务适配器
 	if a.ASRProvider != nil {
 		_ = a.ASRProvider.Close()
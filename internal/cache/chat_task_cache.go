@@ -0,0 +1,62 @@
+// Package cache 提供异步语音对话任务缓存
+// 使用 String 结构存储 JSON 记录，TTL 24 小时；多实例部署时任意实例都能查询任务状态
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"pronunciation-correction-system/internal/cache/redis"
+)
+
+// 异步语音对话任务状态
+const (
+	ChatTaskStatusPending    = "pending"    // 已提交，等待处理
+	ChatTaskStatusProcessing = "processing" // 处理中（ASR → LLM → TTS）
+	ChatTaskStatusSuccess    = "success"    // 处理完成，本轮已保存到会话
+	ChatTaskStatusFailed     = "failed"     // 处理失败
+)
+
+// ChatTaskCache 异步语音对话任务缓存
+type ChatTaskCache struct {
+	commands *redis.Commands
+}
+
+// NewChatTaskCache 创建异步语音对话任务缓存
+func NewChatTaskCache(commands *redis.Commands) *ChatTaskCache {
+	return &ChatTaskCache{
+		commands: commands,
+	}
+}
+
+// ChatTaskRecord 异步语音对话任务记录
+type ChatTaskRecord struct {
+	TaskID       string          `json:"task_id"`
+	UserID       string          `json:"user_id"`
+	Status       string          `json:"status"`                  // pending / processing / success / failed
+	SessionID    string          `json:"session_id,omitempty"`    // 提交时指定的会话，处理完成后为本轮所属会话
+	Result       json.RawMessage `json:"result,omitempty"`        // 本轮会话信息（success，或失败但本轮已保存时）
+	ErrorStage   string          `json:"error_stage,omitempty"`   // 失败阶段（failed）
+	ErrorMessage string          `json:"error_message,omitempty"` // 失败原因（failed）
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// Get 获取任务记录（不存在或已过期时返回 nil, nil）
+func (c *ChatTaskCache) Get(ctx context.Context, taskID string) (*ChatTaskRecord, error) {
+	var record ChatTaskRecord
+	if err := c.commands.GetJSON(ctx, redis.Keys.ChatTask.Record(taskID), &record); err != nil {
+		if redis.IsNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Set 保存任务记录（每次状态变化时整体覆盖并刷新 TTL）
+func (c *ChatTaskCache) Set(ctx context.Context, record *ChatTaskRecord) error {
+	record.UpdatedAt = time.Now()
+	return c.commands.SetJSON(ctx, redis.Keys.ChatTask.Record(record.TaskID), record, redis.TTLChatTask)
+}
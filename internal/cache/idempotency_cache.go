@@ -0,0 +1,77 @@
+// Package cache 提供幂等请求缓存
+// 使用 String 结构存储 JSON 记录：处理中状态 TTL 2 分钟，完成结果 TTL 24 小时
+// 用于移动端弱网重试时避免重复调用讯飞 / Qwen / CosyVoice
+package cache
+
+import (
+	"context"
+	"time"
+
+	"pronunciation-correction-system/internal/cache/redis"
+)
+
+// 幂等记录状态
+const (
+	IdempotencyStatusProcessing = "processing" // 首个请求仍在处理中
+	IdempotencyStatusCompleted  = "completed"  // 已完成，保存了响应
+)
+
+// IdempotencyCache 幂等请求缓存
+type IdempotencyCache struct {
+	commands *redis.Commands
+}
+
+// NewIdempotencyCache 创建幂等请求缓存
+func NewIdempotencyCache(commands *redis.Commands) *IdempotencyCache {
+	return &IdempotencyCache{
+		commands: commands,
+	}
+}
+
+// IdempotencyRecord 幂等请求记录
+type IdempotencyRecord struct {
+	Status      string            `json:"status"`       // processing / completed
+	RequestHash string            `json:"request_hash"` // 请求内容摘要（同一 Key 不同内容时拒绝）
+	StatusCode  int               `json:"status_code"`  // 响应状态码（completed）
+	ContentType string            `json:"content_type"` // 响应 Content-Type（completed）
+	Headers     map[string]string `json:"headers"`      // 需要回放的响应头（completed，仅白名单内的头）
+	Body        []byte            `json:"body"`         // 响应体（completed）
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// Get 获取幂等记录（不存在时返回 nil, nil）
+func (c *IdempotencyCache) Get(ctx context.Context, userID, keyHash string) (*IdempotencyRecord, error) {
+	key := redis.Keys.Idempotency.Record(userID, keyHash)
+
+	var record IdempotencyRecord
+	if err := c.commands.GetJSON(ctx, key, &record); err != nil {
+		if redis.IsNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// SetProcessing 写入处理中状态
+func (c *IdempotencyCache) SetProcessing(ctx context.Context, userID, keyHash, requestHash string) error {
+	key := redis.Keys.Idempotency.Record(userID, keyHash)
+	record := &IdempotencyRecord{
+		Status:      IdempotencyStatusProcessing,
+		RequestHash: requestHash,
+		CreatedAt:   time.Now(),
+	}
+	return c.commands.SetJSON(ctx, key, record, redis.TTLIdempotencyInFlight)
+}
+
+// SetCompleted 保存完成后的响应
+func (c *IdempotencyCache) SetCompleted(ctx context.Context, userID, keyHash string, record *IdempotencyRecord) error {
+	key := redis.Keys.Idempotency.Record(userID, keyHash)
+	record.Status = IdempotencyStatusCompleted
+	return c.commands.SetJSON(ctx, key, record, redis.TTLIdempotency)
+}
+
+// Delete 删除幂等记录（请求失败时允许客户端使用同一 Key 重试）
+func (c *IdempotencyCache) Delete(ctx context.Context, userID, keyHash string) error {
+	return c.commands.Del(ctx, redis.Keys.Idempotency.Record(userID, keyHash))
+}
@@ -111,6 +111,16 @@ func UserLockKey(userID string) string {
 	return redis.Keys.Lock.User(userID)
 }
 
+// IdempotencyLockKey 幂等请求锁 Key
+func IdempotencyLockKey(userID, keyHash string) string {
+	return redis.Keys.Lock.Idempotency(userID, keyHash)
+}
+
+// JobLockKey 定时任务锁 Key
+func JobLockKey(jobName string) string {
+	return redis.Keys.Lock.Job(jobName)
+}
+
 // ==================== 便捷方法 ====================
 
 // LockEvaluation 锁定评测
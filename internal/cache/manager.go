@@ -25,6 +25,9 @@ type Manager struct {
 	Session     *SessionCache       // 会话缓存
 	Lock        *DistributedLock    // 分布式锁
 	RateLimit   *RateLimitCache     // 限流缓存
+	Idempotency *IdempotencyCache   // 幂等请求缓存
+	ChatTask    *ChatTaskCache      // 异步语音对话任务缓存
+	MinimalPair *MinimalPairCache   // LLM 扩展的最小对立词对
 }
 
 // ManagerConfig 缓存管理器配置
@@ -67,6 +70,9 @@ func NewManager(cfg *ManagerConfig) (*Manager, error) {
 	m.Session = NewSessionCache(commands)
 	m.Lock = NewDistributedLock(commands)
 	m.RateLimit = NewRateLimitCache(commands)
+	m.Idempotency = NewIdempotencyCache(commands)
+	m.ChatTask = NewChatTaskCache(commands)
+	m.MinimalPair = NewMinimalPairCache(commands)
 
 	return m, nil
 }
@@ -94,6 +100,9 @@ func NewManagerWithClient(client *redis.Client, defaultQuota int) *Manager {
 	m.Session = NewSessionCache(commands)
 	m.Lock = NewDistributedLock(commands)
 	m.RateLimit = NewRateLimitCache(commands)
+	m.Idempotency = NewIdempotencyCache(commands)
+	m.ChatTask = NewChatTaskCache(commands)
+	m.MinimalPair = NewMinimalPairCache(commands)
 
 	return m
 }
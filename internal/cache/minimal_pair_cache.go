@@ -0,0 +1,41 @@
+// Package cache 提供最小对立词扩展词对缓存
+// 使用 String 结构存储 JSON 数组，TTL 24 小时；出题与提交校验共用，多实例部署时校验结果一致
+package cache
+
+import (
+	"context"
+
+	"pronunciation-correction-system/internal/cache/redis"
+)
+
+// MinimalPairCache LLM 扩展的最小对立词对缓存（按对立组）
+type MinimalPairCache struct {
+	commands *redis.Commands
+}
+
+// NewMinimalPairCache 创建最小对立词扩展词对缓存
+func NewMinimalPairCache(commands *redis.Commands) *MinimalPairCache {
+	return &MinimalPairCache{
+		commands: commands,
+	}
+}
+
+// GetExpansion 获取对立组的扩展词对，found 为 false 表示未扩展或已过期
+// 扩展结果为空列表时同样缓存（found 为 true），避免反复调用 LLM
+func (c *MinimalPairCache) GetExpansion(ctx context.Context, contrastID string) (pairs [][2]string, found bool, err error) {
+	if err := c.commands.GetJSON(ctx, redis.Keys.MinimalPair.Expansion(contrastID), &pairs); err != nil {
+		if redis.IsNil(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return pairs, true, nil
+}
+
+// SetExpansion 保存对立组的扩展词对（整体覆盖并刷新 TTL）
+func (c *MinimalPairCache) SetExpansion(ctx context.Context, contrastID string, pairs [][2]string) error {
+	if pairs == nil {
+		pairs = [][2]string{}
+	}
+	return c.commands.SetJSON(ctx, redis.Keys.MinimalPair.Expansion(contrastID), pairs, redis.TTLMinimalPair)
+}
@@ -39,7 +39,11 @@ type QuotaInfo struct {
 
 // GetUsed 获取用户今日已使用配额
 func (c *QuotaCache) GetUsed(ctx context.Context, userID string) (int, error) {
-	key := redis.Keys.User.QuotaToday(userID)
+	return c.getUsed(ctx, redis.Keys.User.QuotaToday(userID))
+}
+
+// getUsed 读取计数器当前值（不存在时为 0）
+func (c *QuotaCache) getUsed(ctx context.Context, key string) (int, error) {
 	value, err := c.commands.Get(ctx, key)
 	if err != nil {
 		if redis.IsNil(err) {
@@ -57,8 +61,11 @@ func (c *QuotaCache) GetUsed(ctx context.Context, userID string) (int, error) {
 // Increment 增加用户配额使用（+1）
 // 如果 key 不存在，会自动创建并设置过期时间为当天结束
 func (c *QuotaCache) Increment(ctx context.Context, userID string) (int, error) {
-	key := redis.Keys.User.QuotaToday(userID)
+	return c.increment(ctx, redis.Keys.User.QuotaToday(userID))
+}
 
+// increment 计数器 +1，首次创建时设置过期时间为当天结束
+func (c *QuotaCache) increment(ctx context.Context, key string) (int, error) {
 	// 增加计数
 	newValue, err := c.commands.Incr(ctx, key)
 	if err != nil {
@@ -214,8 +221,11 @@ func (c *QuotaCache) GetRemaining(ctx context.Context, userID string, quota int)
 // Decrement 减少用户配额使用（-1）
 // 用于取消操作时回退配额
 func (c *QuotaCache) Decrement(ctx context.Context, userID string) (int, error) {
-	key := redis.Keys.User.QuotaToday(userID)
+	return c.decrement(ctx, redis.Keys.User.QuotaToday(userID))
+}
 
+// decrement 计数器 -1，不会低于 0
+func (c *QuotaCache) decrement(ctx context.Context, key string) (int, error) {
 	newValue, err := c.commands.Decr(ctx, key)
 	if err != nil {
 		return 0, err
@@ -231,3 +241,34 @@ func (c *QuotaCache) Decrement(ctx context.Context, userID string) (int, error)
 
 	return int(newValue), nil
 }
+
+// ==================== 分功能配额 ====================
+
+// ConsumeFeature 消耗一次分功能配额
+// 先 INCR 再判断，超限时立即 DECR 回退，避免并发请求越过上限
+// 返回是否允许以及当前已使用次数（不含被拒绝的这一次）
+func (c *QuotaCache) ConsumeFeature(ctx context.Context, userID, feature string, limit int) (bool, int, error) {
+	key := redis.Keys.User.FeatureQuotaToday(userID, feature)
+
+	used, err := c.increment(ctx, key)
+	if err != nil {
+		return false, 0, err
+	}
+	if used > limit {
+		if _, err := c.decrement(ctx, key); err != nil {
+			return false, used - 1, err
+		}
+		return false, used - 1, nil
+	}
+	return true, used, nil
+}
+
+// RefundFeature 回退一次分功能配额（请求因服务端原因失败时调用）
+func (c *QuotaCache) RefundFeature(ctx context.Context, userID, feature string) (int, error) {
+	return c.decrement(ctx, redis.Keys.User.FeatureQuotaToday(userID, feature))
+}
+
+// GetFeatureUsed 获取用户今日某功能已使用次数
+func (c *QuotaCache) GetFeatureUsed(ctx context.Context, userID, feature string) (int, error) {
+	return c.getUsed(ctx, redis.Keys.User.FeatureQuotaToday(userID, feature))
+}
@@ -5,6 +5,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"strconv"
 	"time"
 
@@ -25,122 +26,144 @@ func NewRateLimitCache(commands *redis.Commands) *RateLimitCache {
 
 // RateLimitInfo 限流信息
 type RateLimitInfo struct {
-	Limit     int   `json:"limit"`      // 限制次数
-	Remaining int   `json:"remaining"`  // 剩余次数
-	ResetAt   int64 `json:"reset_at"`   // 重置时间（Unix时间戳）
+	Limit      int   `json:"limit"`       // 限制次数
+	Remaining  int   `json:"remaining"`   // 剩余次数
+	ResetAt    int64 `json:"reset_at"`    // 窗口内最早一次请求滑出的时间（Unix时间戳）
+	ResetAfter int64 `json:"reset_after"` // 距 ResetAt 的秒数（向上取整）
+	RetryAfter int64 `json:"retry_after"` // 被拒绝时建议的重试等待秒数
 }
 
-// IsAllowed 检查请求是否被允许
+// slidingWindowScript 滑动窗口限流脚本（单次往返、原子执行）
+// 使用 ZSET 记录窗口内每次请求的毫秒时间戳，时间取 Redis 服务端 TIME，避免多实例时钟偏差
+//
+// KEYS[1]: 限流 key
+// ARGV[1]: 窗口内上限
+// ARGV[2]: 窗口长度（毫秒）
+// ARGV[3]: 本次消耗（1 = 计数，0 = 仅查询）
+// ARGV[4]: 成员后缀（保证同一毫秒内的请求成员唯一）
+//
+// 返回: {allowed(0/1), remaining, reset_ms}
+// reset_ms 为窗口内最早一次请求滑出窗口的剩余毫秒数（被拒绝时即最短重试等待）
+var slidingWindowScript = redis.NewScript(`
+redis.replicate_commands()
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count + cost <= limit then
+	allowed = 1
+	if cost > 0 then
+		redis.call('ZADD', KEYS[1], now, now .. '-' .. ARGV[4])
+		count = count + cost
+	end
+end
+if count > 0 then
+	redis.call('PEXPIRE', KEYS[1], window)
+end
+
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+local remaining = limit - count
+if remaining < 0 then
+	remaining = 0
+end
+return {allowed, remaining, reset}
+`)
+
+// setWindowCountScript 直接设置窗口内计数（用于管理员手动调整）
+// KEYS[1]: 限流 key；ARGV[1]: 计数；ARGV[2]: 窗口长度（毫秒）
+var setWindowCountScript = redis.NewScript(`
+redis.replicate_commands()
+redis.call('DEL', KEYS[1])
+local count = tonumber(ARGV[1])
+if count <= 0 then
+	return 0
+end
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+for i = 1, count do
+	redis.call('ZADD', KEYS[1], now, now .. '-manual-' .. i)
+end
+redis.call('PEXPIRE', KEYS[1], tonumber(ARGV[2]))
+return count
+`)
+
+// IsAllowed 检查请求是否被允许（滑动窗口，检查与计数在一个 Lua 脚本内原子完成）
 // api: 接口标识
-// userID: 用户ID
+// userID: 限流主体（用户 ID 或 IP）
 // limit: 限制次数
 // window: 时间窗口
 func (c *RateLimitCache) IsAllowed(ctx context.Context, api, userID string, limit int, window time.Duration) (bool, *RateLimitInfo, error) {
-	key := redis.Keys.RateLimit.API(api, userID)
-
-	// 获取当前计数
-	currentStr, err := c.commands.Get(ctx, key)
-	if err != nil && !redis.IsNil(err) {
-		return false, nil, err
-	}
-
-	current := 0
-	if currentStr != "" {
-		current, _ = strconv.Atoi(currentStr)
-	}
-
-	// 获取剩余时间
-	ttl, _ := c.commands.TTL(ctx, key)
-	resetAt := time.Now().Add(ttl).Unix()
-	if ttl < 0 {
-		resetAt = time.Now().Add(window).Unix()
-	}
-
-	// 计算剩余次数
-	remaining := limit - current
-	if remaining < 0 {
-		remaining = 0
-	}
+	return c.runSlidingWindow(ctx, api, userID, limit, window, 1)
+}
 
-	info := &RateLimitInfo{
-		Limit:     limit,
-		Remaining: remaining,
-		ResetAt:   resetAt,
-	}
+// GetRateLimitInfo 获取限流信息（不计数）
+func (c *RateLimitCache) GetRateLimitInfo(ctx context.Context, api, userID string, limit int, window time.Duration) (*RateLimitInfo, error) {
+	_, info, err := c.runSlidingWindow(ctx, api, userID, limit, window, 0)
+	return info, err
+}
 
-	// 检查是否超限
-	if current >= limit {
-		return false, info, nil
+// runSlidingWindow 执行滑动窗口脚本
+func (c *RateLimitCache) runSlidingWindow(ctx context.Context, api, userID string, limit int, window time.Duration, cost int) (bool, *RateLimitInfo, error) {
+	key := redis.Keys.RateLimit.SlidingWindow(api, userID)
+	windowMs := window.Milliseconds()
+	if windowMs <= 0 {
+		windowMs = 1
 	}
 
-	// 增加计数
-	newCount, err := c.commands.Incr(ctx, key)
+	reply, err := c.commands.RunScript(ctx, slidingWindowScript, []string{key},
+		limit, windowMs, cost, strconv.FormatInt(rand.Int63(), 36))
 	if err != nil {
-		return false, info, err
-	}
-
-	// 如果是第一次，设置过期时间
-	if newCount == 1 {
-		if err := c.commands.Expire(ctx, key, window); err != nil {
-			return true, info, nil
-		}
+		return false, nil, fmt.Errorf("rate limit script: %w", err)
 	}
+	return parseSlidingWindowReply(reply, limit, time.Now())
+}
 
-	// 更新剩余次数
-	info.Remaining = limit - int(newCount)
-	if info.Remaining < 0 {
-		info.Remaining = 0
+// parseSlidingWindowReply 解析滑动窗口脚本返回的 {allowed, remaining, reset_ms}
+func parseSlidingWindowReply(reply interface{}, limit int, now time.Time) (bool, *RateLimitInfo, error) {
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 3 {
+		return false, nil, fmt.Errorf("rate limit script: unexpected reply %v", reply)
 	}
-
-	// 再次检查是否超限（防止并发）
-	if int(newCount) > limit {
-		return false, info, nil
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+	resetMs, _ := values[2].(int64)
+	if resetMs < 0 {
+		resetMs = 0
 	}
 
-	return true, info, nil
-}
-
-// GetRateLimitInfo 获取限流信息
-func (c *RateLimitCache) GetRateLimitInfo(ctx context.Context, api, userID string, limit int) (*RateLimitInfo, error) {
-	key := redis.Keys.RateLimit.API(api, userID)
-
-	currentStr, err := c.commands.Get(ctx, key)
-	if err != nil {
-		if redis.IsNil(err) {
-			return &RateLimitInfo{
-				Limit:     limit,
-				Remaining: limit,
-				ResetAt:   0,
-			}, nil
-		}
-		return nil, err
+	resetAfter := time.Duration(resetMs) * time.Millisecond
+	info := &RateLimitInfo{
+		Limit:      limit,
+		Remaining:  int(remaining),
+		ResetAt:    now.Add(resetAfter).Unix(),
+		ResetAfter: int64((resetAfter + time.Second - 1) / time.Second), // 向上取整
 	}
-
-	current, _ := strconv.Atoi(currentStr)
-	ttl, _ := c.commands.TTL(ctx, key)
-
-	remaining := limit - current
-	if remaining < 0 {
-		remaining = 0
+	if allowed != 1 {
+		// 至少等待 1 秒
+		info.RetryAfter = max(info.ResetAfter, 1)
 	}
-
-	return &RateLimitInfo{
-		Limit:     limit,
-		Remaining: remaining,
-		ResetAt:   time.Now().Add(ttl).Unix(),
-	}, nil
+	return allowed == 1, info, nil
 }
 
 // Reset 重置限流计数
 func (c *RateLimitCache) Reset(ctx context.Context, api, userID string) error {
-	key := redis.Keys.RateLimit.API(api, userID)
-	return c.commands.Del(ctx, key)
+	return c.commands.Del(ctx, redis.Keys.RateLimit.SlidingWindow(api, userID))
 }
 
 // SetLimit 设置限流计数（用于管理员手动调整）
 func (c *RateLimitCache) SetLimit(ctx context.Context, api, userID string, count int, window time.Duration) error {
-	key := redis.Keys.RateLimit.API(api, userID)
-	return c.commands.Set(ctx, key, strconv.Itoa(count), window)
+	key := redis.Keys.RateLimit.SlidingWindow(api, userID)
+	_, err := c.commands.RunScript(ctx, setWindowCountScript, []string{key}, count, window.Milliseconds())
+	return err
 }
 
 // ==================== 预定义的限流规则 ====================
@@ -217,42 +240,9 @@ func (c *RateLimitCache) CheckLoginLimit(ctx context.Context, userID string) (bo
 	return c.IsAllowedByRule(ctx, RuleLogin, userID)
 }
 
-// SlidingWindowIsAllowed 滑动窗口限流（更精确但更耗资源）
-// 使用有序集合实现
+// SlidingWindowIsAllowed 滑动窗口限流
+// IsAllowed 已基于滑动窗口实现，保留此方法兼容旧调用
 func (c *RateLimitCache) SlidingWindowIsAllowed(ctx context.Context, api, userID string, limit int, window time.Duration) (bool, error) {
-	key := fmt.Sprintf("%s%s:%s:sliding", redis.PrefixRateLimit, api, userID)
-	now := time.Now()
-	windowStart := now.Add(-window)
-
-	// 移除窗口外的记录
-	if err := c.commands.ZAdd(ctx, key); err != nil {
-		// 忽略空添加错误
-	}
-
-	// 获取窗口内的请求数
-	count, err := c.commands.ZCard(ctx, key)
-	if err != nil {
-		return false, err
-	}
-
-	// 检查是否超限
-	if count >= int64(limit) {
-		return false, nil
-	}
-
-	// 添加当前请求
-	member := fmt.Sprintf("%d", now.UnixNano())
-	score := float64(now.Unix())
-
-	// 使用 ZAdd 添加记录
-	// 这里需要特殊处理，因为 ZAdd 需要 *redis.Z 类型
-	// 简化实现：直接使用 INCR 计数
-
-	_ = windowStart
-	_ = member
-	_ = score
-
-	// 简化版本：使用计数器
 	allowed, _, err := c.IsAllowed(ctx, api, userID, limit, window)
 	return allowed, err
 }
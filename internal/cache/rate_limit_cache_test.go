@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"pronunciation-correction-system/internal/cache/redis"
+)
+
+func TestParseSlidingWindowReply(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name           string
+		reply          interface{}
+		wantAllowed    bool
+		wantRemaining  int
+		wantResetAfter int64
+		wantRetryAfter int64
+		wantErr        bool
+	}{
+		{"allowed", []interface{}{int64(1), int64(9), int64(60000)}, true, 9, 60, 0, false},
+		{"reset rounds up", []interface{}{int64(1), int64(3), int64(1500)}, true, 3, 2, 0, false},
+		{"rejected", []interface{}{int64(0), int64(0), int64(12001)}, false, 0, 13, 13, false},
+		{"rejected waits at least one second", []interface{}{int64(0), int64(0), int64(0)}, false, 0, 0, 1, false},
+		{"negative reset", []interface{}{int64(0), int64(0), int64(-5)}, false, 0, 0, 1, false},
+		{"short reply", []interface{}{int64(1)}, false, 0, 0, 0, true},
+		{"not a list", "OK", false, 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, info, err := parseSlidingWindowReply(tt.reply, 10, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if allowed != tt.wantAllowed || info.Remaining != tt.wantRemaining {
+				t.Errorf("allowed/remaining = %v/%d, want %v/%d", allowed, info.Remaining, tt.wantAllowed, tt.wantRemaining)
+			}
+			if info.ResetAfter != tt.wantResetAfter || info.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("reset/retry after = %d/%d, want %d/%d", info.ResetAfter, info.RetryAfter, tt.wantResetAfter, tt.wantRetryAfter)
+			}
+			if info.Limit != 10 {
+				t.Errorf("limit = %d, want 10", info.Limit)
+			}
+		})
+	}
+}
+
+// TestSlidingWindowScript 在真实 Redis 上执行限流脚本（设置 REDIS_TEST_ADDR=host:port 时运行）
+func TestSlidingWindowScript(t *testing.T) {
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR not set")
+	}
+	host, portText, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("invalid REDIS_TEST_ADDR: %v", err)
+	}
+	port, _ := strconv.Atoi(portText)
+	cfg := redis.DefaultClientConfig()
+	cfg.Host, cfg.Port = host, port
+	client, err := redis.NewClientWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("connect redis: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	c := NewRateLimitCache(redis.NewCommands(client))
+	api, userID := "test_sliding_window", strconv.FormatInt(time.Now().UnixNano(), 36)
+	defer c.Reset(ctx, api, userID)
+
+	steps := []struct {
+		name          string
+		count         bool // true = IsAllowed，false = GetRateLimitInfo
+		wantAllowed   bool
+		wantRemaining int
+	}{
+		{"query does not count", false, true, 2},
+		{"first request", true, true, 1},
+		{"second request", true, true, 0},
+		{"over limit", true, false, 0},
+		{"query after limit", false, false, 0},
+	}
+	for _, step := range steps {
+		var allowed bool
+		var info *RateLimitInfo
+		if step.count {
+			allowed, info, err = c.IsAllowed(ctx, api, userID, 2, time.Second)
+		} else {
+			info, err = c.GetRateLimitInfo(ctx, api, userID, 2, time.Second)
+			allowed = info != nil && info.Remaining > 0
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if allowed != step.wantAllowed || info.Remaining != step.wantRemaining {
+			t.Errorf("%s: allowed/remaining = %v/%d, want %v/%d", step.name, allowed, info.Remaining, step.wantAllowed, step.wantRemaining)
+		}
+		if !allowed && step.count && info.RetryAfter < 1 {
+			t.Errorf("%s: retry after = %d, want >= 1", step.name, info.RetryAfter)
+		}
+	}
+
+	// 窗口滑过后恢复
+	time.Sleep(1100 * time.Millisecond)
+	allowed, info, err := c.IsAllowed(ctx, api, userID, 2, time.Second)
+	if err != nil || !allowed || info.Remaining != 1 {
+		t.Errorf("after window: allowed/remaining = %v/%v, err %v", allowed, info, err)
+	}
+}
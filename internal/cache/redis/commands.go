@@ -329,6 +329,24 @@ func (c *Commands) Unlock(ctx context.Context, key, value string) error {
 	return script.Run(ctx, c.client.rdb, []string{key}, value).Err()
 }
 
+// ==================== Lua 脚本 ====================
+
+// Script Lua 脚本封装
+// 执行时优先使用 EVALSHA，服务端未缓存脚本（NOSCRIPT）时自动回退 EVAL
+type Script struct {
+	script *redis.Script
+}
+
+// NewScript 创建 Lua 脚本
+func NewScript(src string) *Script {
+	return &Script{script: redis.NewScript(src)}
+}
+
+// RunScript 原子执行 Lua 脚本
+func (c *Commands) RunScript(ctx context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	return script.script.Run(ctx, c.client.rdb, keys, args...).Result()
+}
+
 // ==================== 辅助函数 ====================
 
 // IsNil 检查是否为 redis.Nil 错误
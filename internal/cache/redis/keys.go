@@ -15,8 +15,8 @@ const (
 	KeyPrefix = "oktalk:"
 
 	// 评测相关
-	PrefixEvalResult = "oktalk:eval:result:"    // 评测完整结果 (Hash)
-	PrefixEvalStatus = "oktalk:eval:status:"    // 评测状态
+	PrefixEvalResult = "oktalk:eval:result:" // 评测完整结果 (Hash)
+	PrefixEvalStatus = "oktalk:eval:status:" // 评测状态
 
 	// 示范音频
 	PrefixDemoWord     = "oktalk:demo:audio:word:"     // 单词示范音频URL
@@ -43,17 +43,30 @@ const (
 
 	// 限流相关
 	PrefixRateLimit = "oktalk:rate:" // 限流
+
+	// 幂等相关
+	PrefixIdempotency = "oktalk:idem:" // 幂等请求结果
+
+	// 异步任务相关
+	PrefixChatTask = "oktalk:chat:task:" // 异步语音对话任务状态
+
+	// 练习相关
+	PrefixMinimalPair = "oktalk:minpair:" // LLM 扩展的最小对立词对
 )
 
 // TTL 常量
 const (
-	TTLEvaluationResult = 7 * 24 * time.Hour  // 评测结果: 7天
-	TTLDemoAudio        = 30 * 24 * time.Hour // 示范音频URL: 30天
-	TTLUploadToken      = 5 * time.Minute     // 上传令牌: 5分钟
-	TTLFeedbackText     = 7 * 24 * time.Hour  // LLM文本缓存: 7天
-	TTLUserProfile      = 1 * time.Hour       // 用户信息: 1小时
-	TTLUserStats        = 5 * time.Minute     // 用户统计: 5分钟
-	TTLSession          = 24 * time.Hour      // 会话: 24小时
+	TTLEvaluationResult    = 7 * 24 * time.Hour  // 评测结果: 7天
+	TTLDemoAudio           = 30 * 24 * time.Hour // 示范音频URL: 30天
+	TTLUploadToken         = 5 * time.Minute     // 上传令牌: 5分钟
+	TTLFeedbackText        = 7 * 24 * time.Hour  // LLM文本缓存: 7天
+	TTLUserProfile         = 1 * time.Hour       // 用户信息: 1小时
+	TTLUserStats           = 5 * time.Minute     // 用户统计: 5分钟
+	TTLSession             = 24 * time.Hour      // 会话: 24小时
+	TTLIdempotency         = 24 * time.Hour      // 幂等请求结果: 24小时
+	TTLIdempotencyInFlight = 2 * time.Minute     // 幂等请求处理中状态: 2分钟（进程崩溃后自动释放）
+	TTLChatTask            = 24 * time.Hour      // 异步语音对话任务状态: 24小时
+	TTLMinimalPair         = 24 * time.Hour      // LLM 扩展的最小对立词对: 24小时
 )
 
 // NormalizeText 文本标准化（用于缓存key）
@@ -123,6 +136,13 @@ func (UserKeys) QuotaToday(userID string) string {
 	return fmt.Sprintf("%s%s:%s", PrefixUserQuota, userID, today)
 }
 
+// FeatureQuotaToday 用户今日分功能配额 Key
+// oktalk:user:quota:{user_id}:{feature}:{date}
+func (UserKeys) FeatureQuotaToday(userID, feature string) string {
+	today := time.Now().Format("20060102")
+	return fmt.Sprintf("%s%s:%s:%s", PrefixUserQuota, userID, feature, today)
+}
+
 // Profile 用户信息缓存 Key
 // oktalk:user:profile:{user_id}
 func (UserKeys) Profile(userID string) string {
@@ -203,6 +223,18 @@ func (LockKeys) User(userID string) string {
 	return PrefixLock + "user:" + userID
 }
 
+// Idempotency 幂等请求锁 Key
+// oktalk:lock:idem:{user_id}:{key_hash}
+func (LockKeys) Idempotency(userID, keyHash string) string {
+	return PrefixLock + "idem:" + userID + ":" + keyHash
+}
+
+// Job 定时任务锁 Key（多实例部署时同一时刻只有一个实例执行）
+// oktalk:lock:job:{job_name}
+func (LockKeys) Job(jobName string) string {
+	return PrefixLock + "job:" + jobName
+}
+
 // ==================== 限流相关 Key ====================
 
 // RateLimitKeys 限流 Key 构建器
@@ -214,18 +246,60 @@ func (RateLimitKeys) API(api, userID string) string {
 	return fmt.Sprintf("%s%s:%s", PrefixRateLimit, api, userID)
 }
 
+// SlidingWindow 滑动窗口限流 Key（ZSET，成员为请求时间戳）
+// oktalk:rate:{api}:{subject}:sw
+func (RateLimitKeys) SlidingWindow(api, subject string) string {
+	return fmt.Sprintf("%s%s:%s:sw", PrefixRateLimit, api, subject)
+}
+
+// ==================== 幂等相关 Key ====================
+
+// IdempotencyKeys 幂等请求 Key 构建器
+type IdempotencyKeys struct{}
+
+// Record 幂等请求记录 Key（key_hash 为接口 + Idempotency-Key 的摘要）
+// oktalk:idem:{user_id}:{key_hash}
+func (IdempotencyKeys) Record(userID, keyHash string) string {
+	return PrefixIdempotency + userID + ":" + keyHash
+}
+
+// ==================== 异步任务相关 Key ====================
+
+// ChatTaskKeys 异步语音对话任务 Key 构建器
+type ChatTaskKeys struct{}
+
+// Record 任务状态 Key
+// oktalk:chat:task:{task_id}
+func (ChatTaskKeys) Record(taskID string) string {
+	return PrefixChatTask + taskID
+}
+
+// ==================== 练习相关 Key ====================
+
+// MinimalPairKeys 最小对立词 Key 构建器
+type MinimalPairKeys struct{}
+
+// Expansion LLM 扩展词对 Key
+// oktalk:minpair:{contrast_id}
+func (MinimalPairKeys) Expansion(contrastID string) string {
+	return PrefixMinimalPair + contrastID
+}
+
 // ==================== 全局 Keys 构建器 ====================
 
 // Keys 所有 Key 构建器
 var Keys = struct {
-	Evaluation EvaluationKeys
-	DemoAudio  DemoAudioKeys
-	User       UserKeys
-	Temp       TempKeys
-	Feedback   FeedbackKeys
-	Session    SessionKeys
-	Lock       LockKeys
-	RateLimit  RateLimitKeys
+	Evaluation  EvaluationKeys
+	DemoAudio   DemoAudioKeys
+	User        UserKeys
+	Temp        TempKeys
+	Feedback    FeedbackKeys
+	Session     SessionKeys
+	Lock        LockKeys
+	RateLimit   RateLimitKeys
+	Idempotency IdempotencyKeys
+	ChatTask    ChatTaskKeys
+	MinimalPair MinimalPairKeys
 }{}
 
 // CalculateTodayRemainingTTL 计算当天剩余时间（用于每日配额）
@@ -2,6 +2,8 @@
 // 定义所有配置结构体和默认值
 package config
 
+import "time"
+
 // Config 应用程序主配置结构体
 type Config struct {
 	Server     ServerConfig     `mapstructure:"server"`
@@ -14,6 +16,10 @@ type Config struct {
 	OSS        OSSConfig        `mapstructure:"oss"`
 	JWT        JWTConfig        `mapstructure:"jwt"`
 	Log        LogConfig        `mapstructure:"log"`
+	Quota      QuotaConfig      `mapstructure:"quota"`
+	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	Chat       ChatConfig       `mapstructure:"chat"`
+	Moderation ModerationConfig `mapstructure:"moderation"`
 }
 
 // ===================== 服务器 & 基础设施 =====================
@@ -24,6 +30,12 @@ type ServerConfig struct {
 	Mode        string `mapstructure:"mode"` // debug, release, test
 	Name        string `mapstructure:"name"`
 	Environment string `mapstructure:"environment"`
+
+	// TrustedProxies 可信反向代理的 IP / CIDR，仅信任其转发的 X-Forwarded-For / X-Real-IP
+	// 为空时不信任任何代理，客户端 IP 取 TCP 连接地址（防止伪造请求头绕过按 IP 限流）
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// TrustedPlatform 由云平台注入的客户端 IP 请求头（如 CF-Connecting-IP），为空时不启用
+	TrustedPlatform string `mapstructure:"trusted_platform"`
 }
 
 // DatabaseConfig 数据库配置
@@ -51,6 +63,31 @@ type JWTConfig struct {
 	ExpireHours int    `mapstructure:"expire_hours"`
 }
 
+// QuotaConfig 每日配额配置（按用户套餐区分）
+type QuotaConfig struct {
+	DefaultPlan string               `mapstructure:"default_plan"` // 用户未设置套餐或查询失败时使用
+	Plans       map[string]PlanQuota `mapstructure:"plans"`        // 套餐名 → 每日配额
+}
+
+// PlanQuota 单个套餐的每日配额（负数表示不限）
+type PlanQuota struct {
+	Evaluate int `mapstructure:"evaluate"` // 发音评测次数
+	Chat     int `mapstructure:"chat"`     // 语音对话轮数
+}
+
+// RateLimitConfig 接口限流配置（滑动窗口）
+type RateLimitConfig struct {
+	Enabled bool                     `mapstructure:"enabled"`
+	Rules   map[string]RateLimitRule `mapstructure:"rules"` // 规则名 → 限流规则（路由注册时按规则名引用）
+}
+
+// RateLimitRule 单条限流规则
+type RateLimitRule struct {
+	Limit  int           `mapstructure:"limit"`  // 窗口内最大请求数
+	Window time.Duration `mapstructure:"window"` // 窗口长度，如 1m、1h
+	KeyBy  string        `mapstructure:"key_by"` // 限流主体：user / ip（user 未登录时回退 ip）
+}
+
 // LogConfig 日志配置
 type LogConfig struct {
 	// 环境：development, production
@@ -139,6 +176,33 @@ type DeepSeekConfig struct {
 	Endpoint string `mapstructure:"endpoint"`
 }
 
+// ChatConfig 多轮语音对话配置（会话消息上限见 system_settings.max_conversation_messages）
+type ChatConfig struct {
+	HistoryMessages     int  `mapstructure:"history_messages"`     // 以原文放入 LLM 上下文的最近消息数，超出部分合并为摘要
+	MaxContextChars     int  `mapstructure:"max_context_chars"`    // 历史原文总字符上限，超出时从最早的消息开始裁剪
+	SummarizeHistory    bool `mapstructure:"summarize_history"`    // 是否将较早轮次合并为滚动摘要（关闭时直接丢弃）
+	AnnotateGrammar     bool `mapstructure:"annotate_grammar"`     // 是否在后台分析用户消息的语法 / 用词错误
+	AssessPronunciation bool `mapstructure:"assess_pronunciation"` // 是否在后台评测用户语音的发音（以 ASR 文本为参考文本，调用语音评测服务）
+	IdleSweepSeconds    int  `mapstructure:"idle_sweep_seconds"`   // 扫描并结束空闲 / 暂停超时会话的间隔（秒），0 表示不扫描（超时时长见 system_settings）
+}
+
+// ===================== 内容安全 =====================
+
+// ModerationConfig 内容安全审核配置（孩子输入与 AI 回复）
+type ModerationConfig struct {
+	Enabled        bool                  `mapstructure:"enabled"`
+	ActiveProvider string                `mapstructure:"active_provider"` // local
+	Local          LocalModerationConfig `mapstructure:"local"`
+}
+
+// LocalModerationConfig 本地关键词 / 正则审核配置（在内置词表基础上追加）
+// 类别见 domain.ModerationCategory*，英文关键词按整词匹配（忽略大小写），中文关键词按子串匹配
+type LocalModerationConfig struct {
+	ExtraKeywords map[string][]string `mapstructure:"extra_keywords"` // 类别 → 追加关键词
+	ExtraPatterns map[string][]string `mapstructure:"extra_patterns"` // 类别 → 追加正则（RE2 语法）
+	AllowWords    []string            `mapstructure:"allow_words"`    // 从内置词表中移除的关键词（误伤时使用）
+}
+
 // ===================== ASR 语音识别 =====================
 
 // ASRConfig ASR 语音识别模块配置（支持多 Provider 切换）
@@ -59,6 +59,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.mode", "debug")
 	v.SetDefault("server.name", "pronunciation-correction-system")
 	v.SetDefault("server.environment", "development")
+	v.SetDefault("server.trusted_proxies", []string{})
+	v.SetDefault("server.trusted_platform", "")
 
 	// 数据库默认配置
 	v.SetDefault("database.host", "localhost")
@@ -99,6 +101,36 @@ func setDefaults(v *viper.Viper) {
 	// JWT 默认配置
 	v.SetDefault("jwt.expire_hours", 24)
 
+	// 配额默认配置
+	v.SetDefault("quota.default_plan", "free")
+	v.SetDefault("quota.plans", map[string]interface{}{
+		"free":    map[string]interface{}{"evaluate": 50, "chat": 30},
+		"premium": map[string]interface{}{"evaluate": 500, "chat": 300},
+		"admin":   map[string]interface{}{"evaluate": -1, "chat": -1},
+	})
+
+	// 限流默认配置
+	v.SetDefault("rate_limit.enabled", true)
+	v.SetDefault("rate_limit.rules", map[string]interface{}{
+		"login":    map[string]interface{}{"limit": 5, "window": "1m", "key_by": "ip"},
+		"register": map[string]interface{}{"limit": 10, "window": "1h", "key_by": "ip"},
+		"api":      map[string]interface{}{"limit": 120, "window": "1m", "key_by": "user"},
+		"evaluate": map[string]interface{}{"limit": 10, "window": "1m", "key_by": "user"},
+		"chat":     map[string]interface{}{"limit": 20, "window": "1m", "key_by": "user"},
+	})
+
+	// 多轮对话默认配置
+	v.SetDefault("chat.history_messages", 12)
+	v.SetDefault("chat.max_context_chars", 4000)
+	v.SetDefault("chat.summarize_history", true)
+	v.SetDefault("chat.annotate_grammar", true)
+	v.SetDefault("chat.assess_pronunciation", false)
+	v.SetDefault("chat.idle_sweep_seconds", 60)
+
+	// 内容安全默认配置
+	v.SetDefault("moderation.enabled", true)
+	v.SetDefault("moderation.active_provider", "local")
+
 	// 日志默认配置
 	v.SetDefault("log.environment", "development")
 	v.SetDefault("log.level", "debug")
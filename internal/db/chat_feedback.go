@@ -0,0 +1,143 @@
+// Package db 提供对话反馈数据库操作
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+// 反馈评分统计维度
+const (
+	FeedbackDimensionPromptVersion = "prompt_version"   // 提示词版本
+	FeedbackDimensionScenario      = "scenario"         // 角色扮演场景编码
+	FeedbackDimensionDifficulty    = "difficulty_level" // 会话难度等级
+	FeedbackDimensionReplyVersion  = "reply_version"    // 回复版本号（1 为首次生成，重新生成或重试后递增）
+)
+
+// feedbackDimensionColumns 统计维度 → 分组表达式（非角色扮演会话、未记录版本的回复归为空字符串）
+var feedbackDimensionColumns = map[string]string{
+	FeedbackDimensionPromptVersion: "COALESCE(f.prompt_version, '')",
+	FeedbackDimensionScenario:      "COALESCE(s.code, '')",
+	FeedbackDimensionDifficulty:    "vc.difficulty_level",
+	FeedbackDimensionReplyVersion:  "CAST(f.reply_version AS CHAR)",
+}
+
+// ChatFeedbackStat 按维度聚合的反馈评分
+type ChatFeedbackStat struct {
+	Value          string  `gorm:"column:group_value" json:"value"`
+	Count          int64   `gorm:"column:feedback_count" json:"count"`
+	AverageRating  float64 `gorm:"column:average_rating" json:"average_rating"`
+	HelpfulCount   int64   `gorm:"column:helpful_count" json:"helpful_count"`
+	UnhelpfulCount int64   `gorm:"column:unhelpful_count" json:"unhelpful_count"`
+}
+
+// ChatFeedbackDetail 反馈及被评价回复的上下文
+type ChatFeedbackDetail struct {
+	FeedbackID      string                 `gorm:"column:feedback_id"`
+	ConversationID  string                 `gorm:"column:conversation_id"`
+	Turn            int                    `gorm:"column:turn"`
+	Rating          int                    `gorm:"column:rating"`
+	Helpful         *bool                  `gorm:"column:helpful"`
+	Comment         *string                `gorm:"column:comment"`
+	PromptVersion   *string                `gorm:"column:prompt_version"`
+	ReplyVersion    int                    `gorm:"column:reply_version"`
+	ScenarioCode    *string                `gorm:"column:scenario_code"`
+	DifficultyLevel string                 `gorm:"column:difficulty_level"`
+	UserText        *string                `gorm:"column:user_text"` // 本轮用户消息（被评价回复的前一条）
+	AIText          string                 `gorm:"column:ai_text"`   // 当前版本的回复
+	ReplyVersions   model.ReplyVersionList `gorm:"column:reply_versions"`
+	CreatedAt       time.Time              `gorm:"column:created_at"`
+}
+
+// ChatFeedbackRepository 对话反馈数据库操作接口
+type ChatFeedbackRepository interface {
+	// 基础 CRUD
+	Upsert(ctx context.Context, feedback *model.ChatFeedback) error
+
+	// 统计分析
+	GetRatingStats(ctx context.Context, dimension string, start, end time.Time) ([]*ChatFeedbackStat, error)
+	ListLowestRated(ctx context.Context, start, end time.Time, maxRating, limit int) ([]*ChatFeedbackDetail, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) ChatFeedbackRepository
+}
+
+// chatFeedbackRepository 对话反馈数据库操作实现
+type chatFeedbackRepository struct {
+	db *gorm.DB
+}
+
+// NewChatFeedbackRepository 创建对话反馈数据库操作实例
+func NewChatFeedbackRepository(db *gorm.DB) ChatFeedbackRepository {
+	return &chatFeedbackRepository{db: db}
+}
+
+// WithTx 返回使用事务的 Repository
+func (r *chatFeedbackRepository) WithTx(tx *gorm.DB) ChatFeedbackRepository {
+	return &chatFeedbackRepository{db: tx}
+}
+
+// Upsert 保存反馈（同一用户对同一条回复的同一版本重复提交时覆盖评分与评价，不同版本各保留一条）
+func (r *chatFeedbackRepository) Upsert(ctx context.Context, feedback *model.ChatFeedback) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "message_id"}, {Name: "reply_version"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rating", "helpful", "comment", "task_id", "prompt_version", "updated_at"}),
+		}).
+		Create(feedback).Error
+	return WrapDBError(err, "upsert chat feedback")
+}
+
+// GetRatingStats 按维度统计时间范围内的反馈数与平均评分（按平均评分升序，最需改进的在前）
+func (r *chatFeedbackRepository) GetRatingStats(ctx context.Context, dimension string, start, end time.Time) ([]*ChatFeedbackStat, error) {
+	column, ok := feedbackDimensionColumns[dimension]
+	if !ok {
+		return nil, fmt.Errorf("unknown feedback dimension: %s", dimension)
+	}
+
+	var stats []*ChatFeedbackStat
+	err := r.statsQuery(ctx).
+		Select(column+" AS group_value, COUNT(*) AS feedback_count, AVG(f.rating) AS average_rating, "+
+			"COALESCE(SUM(f.helpful = 1), 0) AS helpful_count, COALESCE(SUM(f.helpful = 0), 0) AS unhelpful_count").
+		Where("f.created_at >= ? AND f.created_at < ?", start, end).
+		Group(column).
+		Order("average_rating ASC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get chat feedback rating stats")
+	}
+	return stats, nil
+}
+
+// ListLowestRated 获取时间范围内评分不高于 maxRating 的反馈（评分升序、时间降序），附带本轮用户消息与 AI 回复
+func (r *chatFeedbackRepository) ListLowestRated(ctx context.Context, start, end time.Time, maxRating, limit int) ([]*ChatFeedbackDetail, error) {
+	var details []*ChatFeedbackDetail
+	err := r.statsQuery(ctx).
+		Select("f.id AS feedback_id, f.conversation_id, f.turn, f.rating, f.helpful, f.comment, f.prompt_version, f.reply_version, "+
+			"s.code AS scenario_code, vc.difficulty_level, um.message_text AS user_text, am.message_text AS ai_text, am.reply_versions, f.created_at").
+		Joins("JOIN conversation_messages am ON am.id = f.message_id").
+		Joins("LEFT JOIN conversation_messages um ON um.conversation_id = f.conversation_id AND um.sequence_number = am.sequence_number - 1").
+		Where("f.created_at >= ? AND f.created_at < ? AND f.rating <= ?", start, end, maxRating).
+		Order("f.rating ASC").
+		Order("f.created_at DESC").
+		Limit(limit).
+		Scan(&details).Error
+	if err != nil {
+		return nil, WrapDBError(err, "list lowest rated chat feedback")
+	}
+	return details, nil
+}
+
+// statsQuery 反馈统计基础查询（关联会话与场景）
+func (r *chatFeedbackRepository) statsQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("chat_feedback AS f").
+		Joins("JOIN voice_conversations vc ON vc.id = f.conversation_id").
+		Joins("LEFT JOIN conversation_scenarios s ON s.id = vc.scenario_id")
+}
@@ -3,12 +3,24 @@ package db
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"pronunciation-correction-system/internal/model"
 )
 
+// LatencySample 一次请求的耗时明细（对话轮次或发音评测）
+type LatencySample struct {
+	LatencyMS      int                  `gorm:"column:latency_ms"`
+	StageLatencies model.StageLatencies `gorm:"column:stage_latencies"`
+}
+
+// ErrReplyStale 替换回复时消息已不是会话最后一条或版本已变化（并发的重新生成 / 重试或新一轮对话）
+var ErrReplyStale = errors.New("conversation reply changed")
+
 // ConversationMessageRepository 对话消息数据库操作接口
 type ConversationMessageRepository interface {
 	// 基础 CRUD
@@ -22,6 +34,16 @@ type ConversationMessageRepository interface {
 	GetByConversationIDPaginated(ctx context.Context, conversationID string, page, pageSize int) ([]*model.ConversationMessage, int64, error)
 	GetLastMessage(ctx context.Context, conversationID string) (*model.ConversationMessage, error)
 	GetNextSequenceNumber(ctx context.Context, conversationID string) (int, error)
+	GetBySequenceRange(ctx context.Context, conversationID string, fromSeq, toSeq int) ([]*model.ConversationMessage, error)
+	GetAfterSequence(ctx context.Context, conversationID string, afterSeq int) ([]*model.ConversationMessage, error)
+	GetLastMessages(ctx context.Context, conversationIDs []string) (map[string]*model.ConversationMessage, error)
+	GetAnnotatedByUserID(ctx context.Context, userID string, start, end time.Time) ([]*model.ConversationMessage, error)
+	ListLatencies(ctx context.Context, start, end time.Time, limit int) ([]*LatencySample, error)
+
+	// 更新方法
+	UpdateAnnotations(ctx context.Context, id string, annotations model.GrammarAnnotationList) error
+	UpdatePronunciation(ctx context.Context, id string, score int, wordScores model.WordScoreList, problemWords model.StringArray) error
+	ReplaceReply(ctx context.Context, id string, expectedVersions int, replacement *model.ConversationMessage, reason string) (*model.ConversationMessage, error)
 
 	// 统计方法
 	CountByConversationID(ctx context.Context, conversationID string) (int64, error)
@@ -155,6 +177,200 @@ func (r *conversationMessageRepository) GetNextSequenceNumber(ctx context.Contex
 	return maxSeq + 1, nil
 }
 
+// GetBySequenceRange 获取序号在 [fromSeq, toSeq] 闭区间内的消息（按序号升序）
+func (r *conversationMessageRepository) GetBySequenceRange(ctx context.Context, conversationID string, fromSeq, toSeq int) ([]*model.ConversationMessage, error) {
+	var messages []*model.ConversationMessage
+	err := r.db.WithContext(ctx).
+		Where("conversation_id = ? AND sequence_number BETWEEN ? AND ?", conversationID, fromSeq, toSeq).
+		Order("sequence_number ASC").
+		Find(&messages).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get conversation messages by sequence range")
+	}
+	return messages, nil
+}
+
+// GetAfterSequence 获取序号大于 afterSeq 的消息（按序号升序）
+func (r *conversationMessageRepository) GetAfterSequence(ctx context.Context, conversationID string, afterSeq int) ([]*model.ConversationMessage, error) {
+	var messages []*model.ConversationMessage
+	err := r.db.WithContext(ctx).
+		Where("conversation_id = ? AND sequence_number > ?", conversationID, afterSeq).
+		Order("sequence_number ASC").
+		Find(&messages).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get conversation messages after sequence")
+	}
+	return messages, nil
+}
+
+// GetLastMessages 批量获取多个对话的最后一条消息（conversation_id → 消息）
+func (r *conversationMessageRepository) GetLastMessages(ctx context.Context, conversationIDs []string) (map[string]*model.ConversationMessage, error) {
+	result := make(map[string]*model.ConversationMessage, len(conversationIDs))
+	if len(conversationIDs) == 0 {
+		return result, nil
+	}
+
+	lastSeq := r.db.
+		Model(&model.ConversationMessage{}).
+		Select("conversation_id, MAX(sequence_number)").
+		Where("conversation_id IN ?", conversationIDs).
+		Group("conversation_id")
+
+	var messages []*model.ConversationMessage
+	err := r.db.WithContext(ctx).
+		Where("(conversation_id, sequence_number) IN (?)", lastSeq).
+		Find(&messages).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get last conversation messages")
+	}
+	for _, message := range messages {
+		result[message.ConversationID] = message
+	}
+	return result, nil
+}
+
+// GetAnnotatedByUserID 获取用户在时间范围内带有语法批注的消息（仅用户消息，按创建时间升序）
+func (r *conversationMessageRepository) GetAnnotatedByUserID(ctx context.Context, userID string, start, end time.Time) ([]*model.ConversationMessage, error) {
+	var messages []*model.ConversationMessage
+	err := r.db.WithContext(ctx).
+		Select("conversation_messages.*").
+		Joins("JOIN voice_conversations ON voice_conversations.id = conversation_messages.conversation_id").
+		Where("voice_conversations.user_id = ? AND voice_conversations.deleted_at IS NULL", userID).
+		Where("conversation_messages.sender_type = ?", model.SenderTypeUser).
+		Where("conversation_messages.created_at BETWEEN ? AND ?", start, end).
+		Where("JSON_LENGTH(conversation_messages.annotations) > 0").
+		Order("conversation_messages.created_at ASC").
+		Find(&messages).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get annotated conversation messages by user id")
+	}
+	return messages, nil
+}
+
+// ListLatencies 获取时间范围 [start, end) 内已记录耗时、处理成功的 AI 消息的耗时明细（按时间降序，最多 limit 条）
+func (r *conversationMessageRepository) ListLatencies(ctx context.Context, start, end time.Time, limit int) ([]*LatencySample, error) {
+	var samples []*LatencySample
+	err := r.db.WithContext(ctx).
+		Model(&model.ConversationMessage{}).
+		Select("latency_ms, stage_latencies").
+		Where("sender_type = ? AND latency_ms IS NOT NULL AND failed_stage IS NULL", model.SenderTypeAI).
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Order("created_at DESC").
+		Limit(limit).
+		Scan(&samples).Error
+	if err != nil {
+		return nil, WrapDBError(err, "list conversation message latencies")
+	}
+	return samples, nil
+}
+
+// UpdateAnnotations 更新消息的语法批注
+func (r *conversationMessageRepository) UpdateAnnotations(ctx context.Context, id string, annotations model.GrammarAnnotationList) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.ConversationMessage{}).
+		Where("id = ?", id).
+		Update("annotations", annotations).Error
+	return WrapDBError(err, "update conversation message annotations")
+}
+
+// UpdatePronunciation 更新消息的发音评测结果
+func (r *conversationMessageRepository) UpdatePronunciation(ctx context.Context, id string, score int, wordScores model.WordScoreList, problemWords model.StringArray) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.ConversationMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"pronunciation_score": score,
+			"word_scores":         wordScores,
+			"problem_words":       problemWords,
+		}).Error
+	return WrapDBError(err, "update conversation message pronunciation")
+}
+
+// ReplaceReply 在事务中用新回复替换 AI 消息，当前内容作为被替换版本追加到 reply_versions
+// 替换文本、释义、音频、提示词版本与耗时并清除 failed_stage；消息已不是会话最后一条，
+// 或已被替换的版本数不等于 expectedVersions 时返回 ErrReplyStale
+func (r *conversationMessageRepository) ReplaceReply(ctx context.Context, id string, expectedVersions int, replacement *model.ConversationMessage, reason string) (*model.ConversationMessage, error) {
+	var message model.ConversationMessage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定消息行，串行化同一条回复的并发替换
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&message).Error; err != nil {
+			return err
+		}
+		var maxSeq int
+		if err := tx.Model(&model.ConversationMessage{}).
+			Where("conversation_id = ?", message.ConversationID).
+			Select("COALESCE(MAX(sequence_number), 0)").
+			Scan(&maxSeq).Error; err != nil {
+			return err
+		}
+		if replyStale(&message, expectedVersions, maxSeq) {
+			return ErrReplyStale
+		}
+
+		now := time.Now()
+		versions := archiveReplyVersion(&message, reason, now)
+		if err := tx.Model(&model.ConversationMessage{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"message_text":    replacement.MessageText,
+				"translation":     replacement.Translation,
+				"audio_url":       replacement.AudioURL,
+				"prompt_version":  replacement.PromptVersion,
+				"latency_ms":      replacement.LatencyMS,
+				"stage_latencies": replacement.StageLatencies,
+				"failed_stage":    nil,
+				"reply_versions":  versions,
+			}).Error; err != nil {
+			return err
+		}
+		message.MessageText = replacement.MessageText
+		message.Translation = replacement.Translation
+		message.AudioURL = replacement.AudioURL
+		message.PromptVersion = replacement.PromptVersion
+		message.LatencyMS = replacement.LatencyMS
+		message.StageLatencies = replacement.StageLatencies
+		message.FailedStage = nil
+		message.ReplyVersions = versions
+		message.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrReplyStale) {
+			return nil, err
+		}
+		return nil, WrapDBError(err, "replace conversation reply")
+	}
+	return &message, nil
+}
+
+// replyStale 判断待替换的回复是否已过期：版本数与调用方读取时不一致，或已不是会话最后一条消息
+func replyStale(message *model.ConversationMessage, expectedVersions, maxSeq int) bool {
+	return len(message.ReplyVersions) != expectedVersions || maxSeq != message.SequenceNumber
+}
+
+// archiveReplyVersion 将消息当前内容追加为被替换版本，返回新的版本列表（不修改 message）
+// 当前版本的生成时间：首个版本为消息创建时间，之后为上一版本被替换的时间
+func archiveReplyVersion(message *model.ConversationMessage, reason string, now time.Time) model.ReplyVersionList {
+	createdAt := message.CreatedAt
+	if n := len(message.ReplyVersions); n > 0 {
+		createdAt = message.ReplyVersions[n-1].ReplacedAt
+	}
+	versions := make(model.ReplyVersionList, 0, len(message.ReplyVersions)+1)
+	versions = append(versions, message.ReplyVersions...)
+	return append(versions, model.ReplyVersion{
+		Text:          message.MessageText,
+		Translation:   message.Translation,
+		AudioURL:      message.AudioURL,
+		PromptVersion: message.PromptVersion,
+		FailedStage:   message.FailedStage,
+		Reason:        reason,
+		CreatedAt:     createdAt,
+		ReplacedAt:    now,
+	})
+}
+
 // CountByConversationID 统计对话消息数
 func (r *conversationMessageRepository) CountByConversationID(ctx context.Context, conversationID string) (int64, error) {
 	var count int64
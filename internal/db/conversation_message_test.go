@@ -0,0 +1,82 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+func TestReplyStale(t *testing.T) {
+	message := &model.ConversationMessage{
+		SequenceNumber: 4,
+		ReplyVersions:  model.ReplyVersionList{{Text: "first"}},
+	}
+	tests := []struct {
+		name             string
+		expectedVersions int
+		maxSeq           int
+		want             bool
+	}{
+		{"current", 1, 4, false},
+		{"replaced concurrently", 0, 4, true},
+		{"conversation moved on", 1, 6, true},
+	}
+	for _, tt := range tests {
+		if got := replyStale(message, tt.expectedVersions, tt.maxSeq); got != tt.want {
+			t.Errorf("%s: replyStale() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestArchiveReplyVersion(t *testing.T) {
+	created := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	firstReplaced := created.Add(time.Minute)
+	now := created.Add(2 * time.Minute)
+	stage := model.TurnFailedStageTTS
+	tests := []struct {
+		name          string
+		message       *model.ConversationMessage
+		reason        string
+		wantLen       int
+		wantCreatedAt time.Time
+	}{
+		{
+			name:          "first replacement uses message creation time",
+			message:       &model.ConversationMessage{MessageText: "Hi!", FailedStage: &stage, CreatedAt: created},
+			reason:        model.ReplyReasonRetry,
+			wantLen:       1,
+			wantCreatedAt: created,
+		},
+		{
+			name: "later replacement uses previous replacement time",
+			message: &model.ConversationMessage{
+				MessageText:   "Hello again!",
+				CreatedAt:     created,
+				ReplyVersions: model.ReplyVersionList{{Text: "Hi!", CreatedAt: created, ReplacedAt: firstReplaced}},
+			},
+			reason:        model.ReplyReasonRegenerate,
+			wantLen:       2,
+			wantCreatedAt: firstReplaced,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(tt.message.ReplyVersions)
+			versions := archiveReplyVersion(tt.message, tt.reason, now)
+			if len(versions) != tt.wantLen {
+				t.Fatalf("len(versions) = %d, want %d", len(versions), tt.wantLen)
+			}
+			if len(tt.message.ReplyVersions) != before {
+				t.Errorf("message.ReplyVersions modified: len = %d, want %d", len(tt.message.ReplyVersions), before)
+			}
+			last := versions[len(versions)-1]
+			if last.Text != tt.message.MessageText || last.Reason != tt.reason || last.FailedStage != tt.message.FailedStage {
+				t.Errorf("archived version = %+v", last)
+			}
+			if !last.CreatedAt.Equal(tt.wantCreatedAt) || !last.ReplacedAt.Equal(now) {
+				t.Errorf("created/replaced at = %v/%v, want %v/%v", last.CreatedAt, last.ReplacedAt, tt.wantCreatedAt, now)
+			}
+		})
+	}
+}
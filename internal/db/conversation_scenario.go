@@ -0,0 +1,126 @@
+// Package db 提供角色扮演场景数据库操作
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+// ScenarioQuery 场景列表查询条件
+type ScenarioQuery struct {
+	Category        string // 场景分类（为空时不过滤）
+	DifficultyLevel string // 难度等级（为空时不过滤）
+}
+
+// ConversationScenarioRepository 角色扮演场景数据库操作接口
+type ConversationScenarioRepository interface {
+	// 基础 CRUD
+	Create(ctx context.Context, scenario *model.ConversationScenario) error
+	GetByID(ctx context.Context, id string) (*model.ConversationScenario, error)
+	Update(ctx context.Context, scenario *model.ConversationScenario) error
+
+	// 查询方法
+	GetByCode(ctx context.Context, code string) (*model.ConversationScenario, error)
+	ListActive(ctx context.Context, q *ScenarioQuery, page, pageSize int) ([]*model.ConversationScenario, int64, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) ConversationScenarioRepository
+}
+
+// conversationScenarioRepository 角色扮演场景数据库操作实现
+type conversationScenarioRepository struct {
+	db *gorm.DB
+}
+
+// NewConversationScenarioRepository 创建角色扮演场景数据库操作实例
+func NewConversationScenarioRepository(db *gorm.DB) ConversationScenarioRepository {
+	return &conversationScenarioRepository{db: db}
+}
+
+// WithTx 返回使用事务的 Repository
+func (r *conversationScenarioRepository) WithTx(tx *gorm.DB) ConversationScenarioRepository {
+	return &conversationScenarioRepository{db: tx}
+}
+
+// Create 创建场景
+func (r *conversationScenarioRepository) Create(ctx context.Context, scenario *model.ConversationScenario) error {
+	err := r.db.WithContext(ctx).Create(scenario).Error
+	return WrapDBError(err, "create conversation scenario")
+}
+
+// GetByID 根据 ID 获取场景
+func (r *conversationScenarioRepository) GetByID(ctx context.Context, id string) (*model.ConversationScenario, error) {
+	var scenario model.ConversationScenario
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&scenario).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get conversation scenario by id")
+	}
+	return &scenario, nil
+}
+
+// Update 更新场景
+func (r *conversationScenarioRepository) Update(ctx context.Context, scenario *model.ConversationScenario) error {
+	err := r.db.WithContext(ctx).Save(scenario).Error
+	return WrapDBError(err, "update conversation scenario")
+}
+
+// GetByCode 根据场景编码获取场景
+func (r *conversationScenarioRepository) GetByCode(ctx context.Context, code string) (*model.ConversationScenario, error) {
+	var scenario model.ConversationScenario
+	err := r.db.WithContext(ctx).
+		Where("code = ?", code).
+		First(&scenario).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get conversation scenario by code")
+	}
+	return &scenario, nil
+}
+
+// ListActive 分页获取已上架的场景（按排序字段升序）
+func (r *conversationScenarioRepository) ListActive(ctx context.Context, q *ScenarioQuery, page, pageSize int) ([]*model.ConversationScenario, int64, error) {
+	var scenarios []*model.ConversationScenario
+	var total int64
+
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	err := r.applyQuery(r.db.WithContext(ctx).Model(&model.ConversationScenario{}), q).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "count conversation scenarios")
+	}
+
+	err = r.applyQuery(r.db.WithContext(ctx), q).
+		Order("sort_order ASC").
+		Order("id ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&scenarios).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "list conversation scenarios")
+	}
+
+	return scenarios, total, nil
+}
+
+// applyQuery 应用场景过滤条件（仅已上架）
+func (r *conversationScenarioRepository) applyQuery(tx *gorm.DB, q *ScenarioQuery) *gorm.DB {
+	tx = tx.Where("is_active = ?", true)
+	if q == nil {
+		return tx
+	}
+	if q.Category != "" {
+		tx = tx.Where("category = ?", q.Category)
+	}
+	if q.DifficultyLevel != "" {
+		tx = tx.Where("difficulty_level = ?", q.DifficultyLevel)
+	}
+	return tx
+}
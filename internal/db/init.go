@@ -23,6 +23,13 @@ type Repositories struct {
 	PronunciationEvaluation PronunciationEvaluationRepository
 	LearningReport          LearningReportRepository
 	SystemSetting           SystemSettingRepository
+	ReviewItem              ReviewItemRepository
+	ConversationScenario    ConversationScenarioRepository
+	QuestionSet             QuestionSetRepository
+	ChatFeedback            ChatFeedbackRepository
+	UserVocabulary          UserVocabularyRepository
+	TutorPersona            TutorPersonaRepository
+	LearnerMemory           LearnerMemoryRepository
 }
 
 // NewRepositories 创建所有 Repository 实例
@@ -35,6 +42,13 @@ func NewRepositories(db *gorm.DB) *Repositories {
 		PronunciationEvaluation: NewPronunciationEvaluationRepository(db),
 		LearningReport:          NewLearningReportRepository(db),
 		SystemSetting:           NewSystemSettingRepository(db),
+		ReviewItem:              NewReviewItemRepository(db),
+		ConversationScenario:    NewConversationScenarioRepository(db),
+		QuestionSet:             NewQuestionSetRepository(db),
+		ChatFeedback:            NewChatFeedbackRepository(db),
+		UserVocabulary:          NewUserVocabularyRepository(db),
+		TutorPersona:            NewTutorPersonaRepository(db),
+		LearnerMemory:           NewLearnerMemoryRepository(db),
 	}
 }
 
@@ -48,6 +62,13 @@ func (r *Repositories) WithTx(tx *gorm.DB) *Repositories {
 		PronunciationEvaluation: r.PronunciationEvaluation.WithTx(tx),
 		LearningReport:          r.LearningReport.WithTx(tx),
 		SystemSetting:           r.SystemSetting.WithTx(tx),
+		ReviewItem:              r.ReviewItem.WithTx(tx),
+		ConversationScenario:    r.ConversationScenario.WithTx(tx),
+		QuestionSet:             r.QuestionSet.WithTx(tx),
+		ChatFeedback:            r.ChatFeedback.WithTx(tx),
+		UserVocabulary:          r.UserVocabulary.WithTx(tx),
+		TutorPersona:            r.TutorPersona.WithTx(tx),
+		LearnerMemory:           r.LearnerMemory.WithTx(tx),
 	}
 }
 
@@ -87,21 +108,31 @@ func Init(cfg *config.Config) (*gorm.DB, error) {
 }
 
 // Migrate 执行数据库迁移
-// 自动创建或更新所有表结构（v2.0: 7 张表）
+// 自动创建或更新所有表结构（v2.0: 7 张表 + 扩展表）
 func Migrate(db *gorm.DB) error {
 	return db.AutoMigrate(
 		// 用户相关
 		&model.User{},
 		&model.UserProfile{},
 		// 对话相关
+		&model.ConversationScenario{},
+		&model.TutorPersona{},
+		&model.QuestionSet{},
 		&model.VoiceConversation{},
 		&model.ConversationMessage{},
+		&model.ChatFeedback{},
 		// 评测相关（已合并 EvaluationDetail 和 FeedbackRecord）
 		&model.PronunciationEvaluation{},
 		// 报告相关（已合并 ReportStatistic）
 		&model.LearningReport{},
 		// 系统配置
 		&model.SystemSetting{},
+		// 间隔复习
+		&model.ReviewItem{},
+		// 个人词汇表
+		&model.UserVocabulary{},
+		// 长期记忆
+		&model.LearnerMemory{},
 	)
 }
 
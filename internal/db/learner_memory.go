@@ -0,0 +1,143 @@
+// Package db 提供学习者长期记忆数据库操作
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+// LearnerMemoryRepository 学习者长期记忆数据库操作接口
+type LearnerMemoryRepository interface {
+	// 基础 CRUD
+	Upsert(ctx context.Context, memories []*model.LearnerMemory) error
+
+	// 查询方法
+	ListByUserID(ctx context.Context, userID string, since time.Time, page, pageSize int) ([]*model.LearnerMemory, int64, error)
+	ListRecallable(ctx context.Context, userID string, since time.Time, minConfidence float64) ([]*model.LearnerMemory, error)
+
+	// 删除方法
+	DeleteByID(ctx context.Context, id, userID string) (bool, error)
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
+	DeleteLastSeenBefore(ctx context.Context, userID string, before time.Time) (int64, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) LearnerMemoryRepository
+}
+
+// learnerMemoryRepository 学习者长期记忆数据库操作实现
+type learnerMemoryRepository struct {
+	db *gorm.DB
+}
+
+// NewLearnerMemoryRepository 创建学习者长期记忆数据库操作实例
+func NewLearnerMemoryRepository(db *gorm.DB) LearnerMemoryRepository {
+	return &learnerMemoryRepository{db: db}
+}
+
+// WithTx 返回使用事务的 Repository
+func (r *learnerMemoryRepository) WithTx(tx *gorm.DB) LearnerMemoryRepository {
+	return &learnerMemoryRepository{db: tx}
+}
+
+// Upsert 批量保存记忆（同一用户的同一 fact_key 已存在时覆盖类别、内容、置信度与来源会话，累计次数并更新最后提取时间）
+func (r *learnerMemoryRepository) Upsert(ctx context.Context, memories []*model.LearnerMemory) error {
+	if len(memories) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "fact_key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"category":               gorm.Expr("VALUES(category)"),
+				"content":                gorm.Expr("VALUES(content)"),
+				"confidence":             gorm.Expr("VALUES(confidence)"),
+				"mention_count":          gorm.Expr("mention_count + 1"),
+				"source_conversation_id": gorm.Expr("VALUES(source_conversation_id)"),
+				"last_seen_at":           gorm.Expr("VALUES(last_seen_at)"),
+				"updated_at":             gorm.Expr("VALUES(updated_at)"),
+			}),
+		}).
+		Create(&memories).Error
+	return WrapDBError(err, "upsert learner memories")
+}
+
+// ListByUserID 分页获取用户在 since 之后提取到的记忆（按最后提取时间降序）
+func (r *learnerMemoryRepository) ListByUserID(ctx context.Context, userID string, since time.Time, page, pageSize int) ([]*model.LearnerMemory, int64, error) {
+	var memories []*model.LearnerMemory
+	var total int64
+
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&model.LearnerMemory{}).
+		Where("user_id = ? AND last_seen_at >= ?", userID, since)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, WrapDBError(err, "count learner memories by user id")
+	}
+
+	err := query.
+		Order("last_seen_at DESC").
+		Order("fact_key ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&memories).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "list learner memories by user id")
+	}
+
+	return memories, total, nil
+}
+
+// ListRecallable 获取用户在 since 之后提取到、置信度不低于 minConfidence 的全部记忆
+func (r *learnerMemoryRepository) ListRecallable(ctx context.Context, userID string, since time.Time, minConfidence float64) ([]*model.LearnerMemory, error) {
+	var memories []*model.LearnerMemory
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND last_seen_at >= ? AND confidence >= ?", userID, since, minConfidence).
+		Find(&memories).Error
+	if err != nil {
+		return nil, WrapDBError(err, "list recallable learner memories")
+	}
+	return memories, nil
+}
+
+// DeleteByID 删除用户的一条记忆，返回是否存在
+func (r *learnerMemoryRepository) DeleteByID(ctx context.Context, id, userID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&model.LearnerMemory{})
+	if result.Error != nil {
+		return false, WrapDBError(result.Error, "delete learner memory")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteByUserID 删除用户的全部记忆，返回删除条数
+func (r *learnerMemoryRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&model.LearnerMemory{})
+	if result.Error != nil {
+		return 0, WrapDBError(result.Error, "delete learner memories by user id")
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteLastSeenBefore 删除用户最后提取时间早于 before 的记忆（超过保留期限），返回删除条数
+func (r *learnerMemoryRepository) DeleteLastSeenBefore(ctx context.Context, userID string, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND last_seen_at < ?", userID, before).
+		Delete(&model.LearnerMemory{})
+	if result.Error != nil {
+		return 0, WrapDBError(result.Error, "delete expired learner memories")
+	}
+	return result.RowsAffected, nil
+}
@@ -10,6 +10,17 @@ import (
 	"pronunciation-correction-system/internal/model"
 )
 
+// EvaluationQuery 评测记录组合查询条件
+type EvaluationQuery struct {
+	UserID     string
+	TargetText string     // 目标文本（为空时不过滤）
+	Start      *time.Time // created_at >= Start（可选）
+	End        *time.Time // created_at < End（可选）
+	ExcludeID  string     // 排除的记录 ID（可选）
+	OrderBy    string     // created_at / score，默认 created_at
+	Order      string     // asc / desc，默认 desc
+}
+
 // PronunciationEvaluationRepository 发音评测数据库操作接口
 type PronunciationEvaluationRepository interface {
 	// 基础 CRUD
@@ -23,6 +34,8 @@ type PronunciationEvaluationRepository interface {
 	GetByUserIDAndDateRange(ctx context.Context, userID string, start, end time.Time, page, pageSize int) ([]*model.PronunciationEvaluation, int64, error)
 	GetByStatus(ctx context.Context, status string, page, pageSize int) ([]*model.PronunciationEvaluation, int64, error)
 	GetByFeedbackLevel(ctx context.Context, level string, page, pageSize int) ([]*model.PronunciationEvaluation, int64, error)
+	Query(ctx context.Context, q *EvaluationQuery, page, pageSize int) ([]*model.PronunciationEvaluation, int64, error)
+	QueryInBatches(ctx context.Context, q *EvaluationQuery, batchSize int, fn func(batch []*model.PronunciationEvaluation) error) error
 
 	// 统计方法
 	Count(ctx context.Context) (int64, error)
@@ -31,6 +44,7 @@ type PronunciationEvaluationRepository interface {
 	CountByFeedbackLevel(ctx context.Context, userID, level string) (int64, error)
 	GetAverageScoreByUserID(ctx context.Context, userID string) (float64, error)
 	GetAverageScoreByUserIDAndDateRange(ctx context.Context, userID string, start, end time.Time) (float64, error)
+	ListLatencies(ctx context.Context, start, end time.Time, limit int) ([]*LatencySample, error)
 
 	// 更新方法
 	UpdateStatus(ctx context.Context, id, status string) error
@@ -215,6 +229,88 @@ func (r *pronunciationEvaluationRepository) GetByFeedbackLevel(ctx context.Conte
 	return evaluations, total, nil
 }
 
+// Query 按组合条件分页查询评测列表
+func (r *pronunciationEvaluationRepository) Query(ctx context.Context, q *EvaluationQuery, page, pageSize int) ([]*model.PronunciationEvaluation, int64, error) {
+	var evaluations []*model.PronunciationEvaluation
+	var total int64
+
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	err := r.applyQuery(r.db.WithContext(ctx).Model(&model.PronunciationEvaluation{}), q).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "count pronunciation evaluations by query")
+	}
+
+	err = r.applyOrder(r.applyQuery(r.db.WithContext(ctx), q), q).
+		Offset(offset).
+		Limit(pageSize).
+		Find(&evaluations).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "list pronunciation evaluations by query")
+	}
+
+	return evaluations, total, nil
+}
+
+// QueryInBatches 按组合条件分批遍历评测记录（用于导出等大结果集场景）
+// 按 Offset 分页以保持自定义排序；fn 返回错误时停止遍历
+func (r *pronunciationEvaluationRepository) QueryInBatches(ctx context.Context, q *EvaluationQuery, batchSize int, fn func(batch []*model.PronunciationEvaluation) error) error {
+	for offset := 0; ; offset += batchSize {
+		var evaluations []*model.PronunciationEvaluation
+		err := r.applyOrder(r.applyQuery(r.db.WithContext(ctx), q), q).
+			Offset(offset).
+			Limit(batchSize).
+			Find(&evaluations).Error
+		if err != nil {
+			return WrapDBError(err, "iterate pronunciation evaluations by query")
+		}
+		if len(evaluations) == 0 {
+			return nil
+		}
+		if err := fn(evaluations); err != nil {
+			return err
+		}
+		if len(evaluations) < batchSize {
+			return nil
+		}
+	}
+}
+
+// applyQuery 应用组合查询过滤条件
+func (r *pronunciationEvaluationRepository) applyQuery(tx *gorm.DB, q *EvaluationQuery) *gorm.DB {
+	tx = tx.Where("user_id = ?", q.UserID)
+	if q.TargetText != "" {
+		tx = tx.Where("target_text = ?", q.TargetText)
+	}
+	if q.Start != nil {
+		tx = tx.Where("created_at >= ?", *q.Start)
+	}
+	if q.End != nil {
+		tx = tx.Where("created_at < ?", *q.End)
+	}
+	if q.ExcludeID != "" {
+		tx = tx.Where("id <> ?", q.ExcludeID)
+	}
+	return tx
+}
+
+// applyOrder 应用排序（仅允许白名单字段，避免 SQL 注入）
+func (r *pronunciationEvaluationRepository) applyOrder(tx *gorm.DB, q *EvaluationQuery) *gorm.DB {
+	column := "created_at"
+	if q.OrderBy == "score" {
+		column = "overall_score"
+	}
+	direction := "DESC"
+	if q.Order == "asc" {
+		direction = "ASC"
+	}
+	return tx.Order(column + " " + direction).Order("id " + direction)
+}
+
 // Count 统计评测总数
 func (r *pronunciationEvaluationRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
@@ -253,6 +349,22 @@ func (r *pronunciationEvaluationRepository) CountByUserIDAndDateRange(ctx contex
 	return count, nil
 }
 
+// ListLatencies 获取时间范围 [start, end) 内已记录耗时的评测的耗时明细（按时间降序，最多 limit 条）
+func (r *pronunciationEvaluationRepository) ListLatencies(ctx context.Context, start, end time.Time, limit int) ([]*LatencySample, error) {
+	var samples []*LatencySample
+	err := r.db.WithContext(ctx).
+		Model(&model.PronunciationEvaluation{}).
+		Select("latency_ms, stage_latencies").
+		Where("latency_ms IS NOT NULL AND created_at >= ? AND created_at < ?", start, end).
+		Order("created_at DESC").
+		Limit(limit).
+		Scan(&samples).Error
+	if err != nil {
+		return nil, WrapDBError(err, "list pronunciation evaluation latencies")
+	}
+	return samples, nil
+}
+
 // CountByFeedbackLevel 统计用户指定反馈级别的评测数
 func (r *pronunciationEvaluationRepository) CountByFeedbackLevel(ctx context.Context, userID, level string) (int64, error) {
 	var count int64
@@ -0,0 +1,126 @@
+// Package db 提供问答题集数据库操作
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+// QuestionSetQuery 题集列表查询条件
+type QuestionSetQuery struct {
+	ScenarioID      string // 关联场景 ID（为空时不过滤）
+	DifficultyLevel string // 难度等级（为空时不过滤）
+}
+
+// QuestionSetRepository 问答题集数据库操作接口
+type QuestionSetRepository interface {
+	// 基础 CRUD
+	Create(ctx context.Context, set *model.QuestionSet) error
+	GetByID(ctx context.Context, id string) (*model.QuestionSet, error)
+	Update(ctx context.Context, set *model.QuestionSet) error
+
+	// 查询方法
+	GetByCode(ctx context.Context, code string) (*model.QuestionSet, error)
+	ListActive(ctx context.Context, q *QuestionSetQuery, page, pageSize int) ([]*model.QuestionSet, int64, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) QuestionSetRepository
+}
+
+// questionSetRepository 问答题集数据库操作实现
+type questionSetRepository struct {
+	db *gorm.DB
+}
+
+// NewQuestionSetRepository 创建问答题集数据库操作实例
+func NewQuestionSetRepository(db *gorm.DB) QuestionSetRepository {
+	return &questionSetRepository{db: db}
+}
+
+// WithTx 返回使用事务的 Repository
+func (r *questionSetRepository) WithTx(tx *gorm.DB) QuestionSetRepository {
+	return &questionSetRepository{db: tx}
+}
+
+// Create 创建题集
+func (r *questionSetRepository) Create(ctx context.Context, set *model.QuestionSet) error {
+	err := r.db.WithContext(ctx).Create(set).Error
+	return WrapDBError(err, "create question set")
+}
+
+// GetByID 根据 ID 获取题集
+func (r *questionSetRepository) GetByID(ctx context.Context, id string) (*model.QuestionSet, error) {
+	var set model.QuestionSet
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&set).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get question set by id")
+	}
+	return &set, nil
+}
+
+// Update 更新题集
+func (r *questionSetRepository) Update(ctx context.Context, set *model.QuestionSet) error {
+	err := r.db.WithContext(ctx).Save(set).Error
+	return WrapDBError(err, "update question set")
+}
+
+// GetByCode 根据题集编码获取题集
+func (r *questionSetRepository) GetByCode(ctx context.Context, code string) (*model.QuestionSet, error) {
+	var set model.QuestionSet
+	err := r.db.WithContext(ctx).
+		Where("code = ?", code).
+		First(&set).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get question set by code")
+	}
+	return &set, nil
+}
+
+// ListActive 分页获取已上架的题集（按排序字段升序）
+func (r *questionSetRepository) ListActive(ctx context.Context, q *QuestionSetQuery, page, pageSize int) ([]*model.QuestionSet, int64, error) {
+	var sets []*model.QuestionSet
+	var total int64
+
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	err := r.applyQuery(r.db.WithContext(ctx).Model(&model.QuestionSet{}), q).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "count question sets")
+	}
+
+	err = r.applyQuery(r.db.WithContext(ctx), q).
+		Order("sort_order ASC").
+		Order("id ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&sets).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "list question sets")
+	}
+
+	return sets, total, nil
+}
+
+// applyQuery 应用题集过滤条件（仅已上架）
+func (r *questionSetRepository) applyQuery(tx *gorm.DB, q *QuestionSetQuery) *gorm.DB {
+	tx = tx.Where("is_active = ?", true)
+	if q == nil {
+		return tx
+	}
+	if q.ScenarioID != "" {
+		tx = tx.Where("scenario_id = ?", q.ScenarioID)
+	}
+	if q.DifficultyLevel != "" {
+		tx = tx.Where("difficulty_level = ?", q.DifficultyLevel)
+	}
+	return tx
+}
@@ -0,0 +1,177 @@
+// Package db 提供单词复习队列数据库操作
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+// ReviewItemRepository 单词复习队列数据库操作接口
+type ReviewItemRepository interface {
+	// 基础 CRUD
+	Create(ctx context.Context, item *model.ReviewItem) error
+	GetByID(ctx context.Context, id string) (*model.ReviewItem, error)
+	Update(ctx context.Context, item *model.ReviewItem) error
+	Delete(ctx context.Context, id string) error
+
+	// 查询方法
+	GetByUserIDAndWord(ctx context.Context, userID, word string) (*model.ReviewItem, error)
+	GetByUserIDAndWords(ctx context.Context, userID string, words []string) ([]*model.ReviewItem, error)
+	GetDueByUserID(ctx context.Context, userID string, dueBefore time.Time, limit int) ([]*model.ReviewItem, error)
+	GetByUserID(ctx context.Context, userID string, page, pageSize int) ([]*model.ReviewItem, int64, error)
+
+	// 统计方法
+	CountDueByUserID(ctx context.Context, userID string, dueBefore time.Time) (int64, error)
+
+	// 更新方法
+	UpdateDemoAudioURL(ctx context.Context, id, audioURL string) error
+
+	// 事务支持
+	WithTx(tx *gorm.DB) ReviewItemRepository
+}
+
+// reviewItemRepository 单词复习队列数据库操作实现
+type reviewItemRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewItemRepository 创建单词复习队列数据库操作实例
+func NewReviewItemRepository(db *gorm.DB) ReviewItemRepository {
+	return &reviewItemRepository{db: db}
+}
+
+// WithTx 返回使用事务的 Repository
+func (r *reviewItemRepository) WithTx(tx *gorm.DB) ReviewItemRepository {
+	return &reviewItemRepository{db: tx}
+}
+
+// Create 创建复习项
+func (r *reviewItemRepository) Create(ctx context.Context, item *model.ReviewItem) error {
+	err := r.db.WithContext(ctx).Create(item).Error
+	return WrapDBError(err, "create review item")
+}
+
+// GetByID 根据 ID 获取复习项
+func (r *reviewItemRepository) GetByID(ctx context.Context, id string) (*model.ReviewItem, error) {
+	var item model.ReviewItem
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&item).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get review item by id")
+	}
+	return &item, nil
+}
+
+// Update 更新复习项
+func (r *reviewItemRepository) Update(ctx context.Context, item *model.ReviewItem) error {
+	err := r.db.WithContext(ctx).Save(item).Error
+	return WrapDBError(err, "update review item")
+}
+
+// Delete 删除复习项
+func (r *reviewItemRepository) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Delete(&model.ReviewItem{}).Error
+	return WrapDBError(err, "delete review item")
+}
+
+// GetByUserIDAndWord 根据用户 ID 和单词获取复习项
+func (r *reviewItemRepository) GetByUserIDAndWord(ctx context.Context, userID, word string) (*model.ReviewItem, error) {
+	var item model.ReviewItem
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND word = ?", userID, word).
+		First(&item).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get review item by user id and word")
+	}
+	return &item, nil
+}
+
+// GetByUserIDAndWords 批量获取用户指定单词的复习项
+func (r *reviewItemRepository) GetByUserIDAndWords(ctx context.Context, userID string, words []string) ([]*model.ReviewItem, error) {
+	var items []*model.ReviewItem
+	if len(words) == 0 {
+		return items, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND word IN ?", userID, words).
+		Find(&items).Error
+	if err != nil {
+		return nil, WrapDBError(err, "list review items by user id and words")
+	}
+	return items, nil
+}
+
+// GetDueByUserID 获取用户到期的复习项（按到期时间升序）
+func (r *reviewItemRepository) GetDueByUserID(ctx context.Context, userID string, dueBefore time.Time, limit int) ([]*model.ReviewItem, error) {
+	var items []*model.ReviewItem
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND due_at < ?", userID, dueBefore).
+		Order("due_at ASC").
+		Order("ease_factor ASC").
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, WrapDBError(err, "list due review items by user id")
+	}
+	return items, nil
+}
+
+// GetByUserID 根据用户 ID 分页获取复习项
+func (r *reviewItemRepository) GetByUserID(ctx context.Context, userID string, page, pageSize int) ([]*model.ReviewItem, int64, error) {
+	var items []*model.ReviewItem
+	var total int64
+
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&model.ReviewItem{}).
+		Where("user_id = ?", userID).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "count review items by user id")
+	}
+
+	err = r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("due_at ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&items).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "list review items by user id")
+	}
+
+	return items, total, nil
+}
+
+// CountDueByUserID 统计用户到期的复习项数量
+func (r *reviewItemRepository) CountDueByUserID(ctx context.Context, userID string, dueBefore time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.ReviewItem{}).
+		Where("user_id = ? AND due_at < ?", userID, dueBefore).
+		Count(&count).Error
+	if err != nil {
+		return 0, WrapDBError(err, "count due review items by user id")
+	}
+	return count, nil
+}
+
+// UpdateDemoAudioURL 更新单词示范音频 URL
+func (r *reviewItemRepository) UpdateDemoAudioURL(ctx context.Context, id, audioURL string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.ReviewItem{}).
+		Where("id = ?", id).
+		Update("demo_audio_url", audioURL).Error
+	return WrapDBError(err, "update review item demo audio url")
+}
@@ -0,0 +1,90 @@
+// Package db 提供 AI 老师人设数据库操作
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+// TutorPersonaRepository AI 老师人设数据库操作接口
+type TutorPersonaRepository interface {
+	// 基础 CRUD
+	Create(ctx context.Context, persona *model.TutorPersona) error
+	GetByID(ctx context.Context, id string) (*model.TutorPersona, error)
+	Update(ctx context.Context, persona *model.TutorPersona) error
+
+	// 查询方法
+	GetByCode(ctx context.Context, code string) (*model.TutorPersona, error)
+	ListActive(ctx context.Context) ([]*model.TutorPersona, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) TutorPersonaRepository
+}
+
+// tutorPersonaRepository AI 老师人设数据库操作实现
+type tutorPersonaRepository struct {
+	db *gorm.DB
+}
+
+// NewTutorPersonaRepository 创建 AI 老师人设数据库操作实例
+func NewTutorPersonaRepository(db *gorm.DB) TutorPersonaRepository {
+	return &tutorPersonaRepository{db: db}
+}
+
+// WithTx 返回使用事务的 Repository
+func (r *tutorPersonaRepository) WithTx(tx *gorm.DB) TutorPersonaRepository {
+	return &tutorPersonaRepository{db: tx}
+}
+
+// Create 创建人设
+func (r *tutorPersonaRepository) Create(ctx context.Context, persona *model.TutorPersona) error {
+	err := r.db.WithContext(ctx).Create(persona).Error
+	return WrapDBError(err, "create tutor persona")
+}
+
+// GetByID 根据 ID 获取人设
+func (r *tutorPersonaRepository) GetByID(ctx context.Context, id string) (*model.TutorPersona, error) {
+	var persona model.TutorPersona
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&persona).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get tutor persona by id")
+	}
+	return &persona, nil
+}
+
+// Update 更新人设
+func (r *tutorPersonaRepository) Update(ctx context.Context, persona *model.TutorPersona) error {
+	err := r.db.WithContext(ctx).Save(persona).Error
+	return WrapDBError(err, "update tutor persona")
+}
+
+// GetByCode 根据人设编码获取人设
+func (r *tutorPersonaRepository) GetByCode(ctx context.Context, code string) (*model.TutorPersona, error) {
+	var persona model.TutorPersona
+	err := r.db.WithContext(ctx).
+		Where("code = ?", code).
+		First(&persona).Error
+	if err != nil {
+		return nil, WrapDBError(err, "get tutor persona by code")
+	}
+	return &persona, nil
+}
+
+// ListActive 获取全部已上架的人设（按排序字段升序，人设数量很少，不分页）
+func (r *tutorPersonaRepository) ListActive(ctx context.Context) ([]*model.TutorPersona, error) {
+	var personas []*model.TutorPersona
+	err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("sort_order ASC").
+		Order("id ASC").
+		Find(&personas).Error
+	if err != nil {
+		return nil, WrapDBError(err, "list tutor personas")
+	}
+	return personas, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"pronunciation-correction-system/internal/model"
 )
@@ -25,6 +26,11 @@ type UserProfileRepository interface {
 	UpdateAverageScore(ctx context.Context, userID string, score float64) error
 	UpdateLastConversationAt(ctx context.Context, userID string) error
 	UpdateLastEvaluationAt(ctx context.Context, userID string) error
+	AddStudyMinutes(ctx context.Context, userID string, minutes int) error
+
+	// 偏好设置
+	UpsertLanguagePolicy(ctx context.Context, profile *model.UserProfile) error
+	UpsertPersona(ctx context.Context, profile *model.UserProfile) error
 
 	// 事务支持
 	WithTx(tx *gorm.DB) UserProfileRepository
@@ -142,3 +148,34 @@ func (r *userProfileRepository) UpdateLastEvaluationAt(ctx context.Context, user
 		Update("last_evaluation_at", gorm.Expr("NOW()")).Error
 	return WrapDBError(err, "update user last evaluation at")
 }
+
+// AddStudyMinutes 累加用户学习时长（分钟）
+func (r *userProfileRepository) AddStudyMinutes(ctx context.Context, userID string, minutes int) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.UserProfile{}).
+		Where("user_id = ?", userID).
+		UpdateColumn("total_study_minutes", gorm.Expr("total_study_minutes + ?", minutes)).Error
+	return WrapDBError(err, "add user study minutes")
+}
+
+// UpsertLanguagePolicy 保存回复语言策略（用户尚无扩展信息时按 profile 创建）
+func (r *userProfileRepository) UpsertLanguagePolicy(ctx context.Context, profile *model.UserProfile) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"language_policy", "updated_at"}),
+		}).
+		Create(profile).Error
+	return WrapDBError(err, "upsert user language policy")
+}
+
+// UpsertPersona 保存选择的 AI 老师人设（persona_id 为 NULL 表示恢复默认老师；用户尚无扩展信息时按 profile 创建）
+func (r *userProfileRepository) UpsertPersona(ctx context.Context, profile *model.UserProfile) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"persona_id", "updated_at"}),
+		}).
+		Create(profile).Error
+	return WrapDBError(err, "upsert user persona")
+}
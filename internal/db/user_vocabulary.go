@@ -0,0 +1,163 @@
+// Package db 提供个人词汇表数据库操作
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+// VocabularyBandCount 按词汇等级聚合的单词数
+type VocabularyBandCount struct {
+	Band  string `gorm:"column:band" json:"band"`
+	Count int64  `gorm:"column:word_count" json:"count"`
+}
+
+// UserVocabularyRepository 个人词汇表数据库操作接口
+type UserVocabularyRepository interface {
+	// 基础 CRUD
+	Upsert(ctx context.Context, entries []*model.UserVocabulary) error
+
+	// 查询方法
+	ListByUserID(ctx context.Context, userID string, minSpoken int, band string, page, pageSize int) ([]*model.UserVocabulary, int64, error)
+	ListFirstSeenBetween(ctx context.Context, userID string, start, end time.Time, page, pageSize int) ([]*model.UserVocabulary, int64, error)
+
+	// 统计方法
+	CountByUserID(ctx context.Context, userID string, before time.Time) (int64, error)
+	CountBandsFirstSeenBetween(ctx context.Context, userID string, start, end time.Time) ([]*VocabularyBandCount, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) UserVocabularyRepository
+}
+
+// userVocabularyRepository 个人词汇表数据库操作实现
+type userVocabularyRepository struct {
+	db *gorm.DB
+}
+
+// NewUserVocabularyRepository 创建个人词汇表数据库操作实例
+func NewUserVocabularyRepository(db *gorm.DB) UserVocabularyRepository {
+	return &userVocabularyRepository{db: db}
+}
+
+// WithTx 返回使用事务的 Repository
+func (r *userVocabularyRepository) WithTx(tx *gorm.DB) UserVocabularyRepository {
+	return &userVocabularyRepository{db: tx}
+}
+
+// Upsert 批量累计词汇（同一用户的同一词元已存在时累加次数并更新最后出现时间，首次出现时间与等级不变）
+func (r *userVocabularyRepository) Upsert(ctx context.Context, entries []*model.UserVocabulary) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "word"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"frequency":    gorm.Expr("frequency + VALUES(frequency)"),
+				"spoken_count": gorm.Expr("spoken_count + VALUES(spoken_count)"),
+				"read_count":   gorm.Expr("read_count + VALUES(read_count)"),
+				"last_seen_at": gorm.Expr("VALUES(last_seen_at)"),
+				"updated_at":   gorm.Expr("VALUES(updated_at)"),
+			}),
+		}).
+		Create(&entries).Error
+	return WrapDBError(err, "upsert user vocabulary")
+}
+
+// ListByUserID 分页获取用户在对话中说出次数不少于 minSpoken 的词汇（band 为空表示不限等级；按说出次数降序、单词升序）
+func (r *userVocabularyRepository) ListByUserID(ctx context.Context, userID string, minSpoken int, band string, page, pageSize int) ([]*model.UserVocabulary, int64, error) {
+	var entries []*model.UserVocabulary
+	var total int64
+
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&model.UserVocabulary{}).
+		Where("user_id = ? AND spoken_count >= ?", userID, minSpoken)
+	if band != "" {
+		query = query.Where("band = ?", band)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, WrapDBError(err, "count user vocabulary by user id")
+	}
+
+	err := query.
+		Order("spoken_count DESC").
+		Order("word ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "list user vocabulary by user id")
+	}
+
+	return entries, total, nil
+}
+
+// ListFirstSeenBetween 分页获取用户在时间范围内首次出现的词汇（按首次出现时间降序）
+func (r *userVocabularyRepository) ListFirstSeenBetween(ctx context.Context, userID string, start, end time.Time, page, pageSize int) ([]*model.UserVocabulary, int64, error) {
+	var entries []*model.UserVocabulary
+	var total int64
+
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&model.UserVocabulary{}).
+		Where("user_id = ? AND first_seen_at >= ? AND first_seen_at < ?", userID, start, end)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, WrapDBError(err, "count new user vocabulary")
+	}
+
+	err := query.
+		Order("first_seen_at DESC").
+		Order("word ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "list new user vocabulary")
+	}
+
+	return entries, total, nil
+}
+
+// CountByUserID 统计用户在 before 之前已出现过的词汇数
+func (r *userVocabularyRepository) CountByUserID(ctx context.Context, userID string, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.UserVocabulary{}).
+		Where("user_id = ? AND first_seen_at < ?", userID, before).
+		Count(&count).Error
+	if err != nil {
+		return 0, WrapDBError(err, "count user vocabulary by user id")
+	}
+	return count, nil
+}
+
+// CountBandsFirstSeenBetween 按等级统计用户在时间范围内首次出现的词汇数
+func (r *userVocabularyRepository) CountBandsFirstSeenBetween(ctx context.Context, userID string, start, end time.Time) ([]*VocabularyBandCount, error) {
+	var counts []*VocabularyBandCount
+	err := r.db.WithContext(ctx).
+		Model(&model.UserVocabulary{}).
+		Select("band, COUNT(*) AS word_count").
+		Where("user_id = ? AND first_seen_at >= ? AND first_seen_at < ?", userID, start, end).
+		Group("band").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, WrapDBError(err, "count new user vocabulary by band")
+	}
+	return counts, nil
+}
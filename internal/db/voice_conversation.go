@@ -3,13 +3,25 @@ package db
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"pronunciation-correction-system/internal/model"
 )
 
+// 追加消息时的会话状态错误（由 Service 转换为业务错误码）
+var (
+	ErrConversationLimitReached = errors.New("conversation message limit reached")
+	ErrConversationNotActive    = errors.New("conversation is not active")
+	ErrConversationStatusStale  = errors.New("conversation status changed")
+)
+
+// idleSinceExpr 会话最近一次活跃时间（存量会话没有 last_active_at 时取最后消息时间或创建时间）
+const idleSinceExpr = "COALESCE(last_active_at, last_message_at, created_at)"
+
 // VoiceConversationRepository 语音对话数据库操作接口
 type VoiceConversationRepository interface {
 	// 基础 CRUD
@@ -22,6 +34,8 @@ type VoiceConversationRepository interface {
 	GetByUserID(ctx context.Context, userID string, page, pageSize int) ([]*model.VoiceConversation, int64, error)
 	GetByStatus(ctx context.Context, status string, page, pageSize int) ([]*model.VoiceConversation, int64, error)
 	GetByUserIDAndStatus(ctx context.Context, userID, status string) ([]*model.VoiceConversation, error)
+	GetByUserIDOrderByLastMessage(ctx context.Context, userID string, page, pageSize int) ([]*model.VoiceConversation, int64, error)
+	GetFlagged(ctx context.Context, page, pageSize int) ([]*model.VoiceConversation, int64, error)
 
 	// 统计方法
 	Count(ctx context.Context) (int64, error)
@@ -33,6 +47,22 @@ type VoiceConversationRepository interface {
 	IncrementMessageCount(ctx context.Context, id string) error
 	UpdateDuration(ctx context.Context, id string, duration int) error
 	UpdateScore(ctx context.Context, id string, score int) error
+	UpdateContextSummary(ctx context.Context, id, summary string, summarizedUntil int) error
+	UpdateScenarioProgress(ctx context.Context, id string, metCriteria model.StringArray, goalAchieved bool) error
+	UpdateQuestionProgress(ctx context.Context, id string, index, attempts int, results model.QuestionResultList, completed bool) error
+	SaveSessionReview(ctx context.Context, id, summary string, score int, feedback string, problemWords model.StringArray) (bool, error)
+	MarkStatsRecorded(ctx context.Context, id string) (bool, error)
+	FlagForReview(ctx context.Context, id, reason string) error
+	ClearLearnerMemory(ctx context.Context, userID string) error
+
+	// 会话生命周期
+	TransitionStatus(ctx context.Context, id, from, to string, maxActiveGap time.Duration) (*model.VoiceConversation, error)
+	ListIdleIDs(ctx context.Context, status string, before time.Time, limit int) ([]string, error)
+	CompleteIdle(ctx context.Context, id, status string, before time.Time) (bool, error)
+
+	// 多轮对话
+	AppendMessages(ctx context.Context, id string, messages []*model.ConversationMessage, maxActiveGap time.Duration, maxMessages int) (*model.VoiceConversation, error)
+	DeleteWithMessages(ctx context.Context, id string) (int64, error)
 
 	// 预加载方法
 	GetWithMessages(ctx context.Context, id string) (*model.VoiceConversation, error)
@@ -167,6 +197,71 @@ func (r *voiceConversationRepository) GetByUserIDAndStatus(ctx context.Context,
 	return conversations, nil
 }
 
+// GetByUserIDOrderByLastMessage 根据用户 ID 分页获取对话列表（按最后交互时间降序）
+// 尚无消息的会话以创建时间作为最后交互时间
+func (r *voiceConversationRepository) GetByUserIDOrderByLastMessage(ctx context.Context, userID string, page, pageSize int) ([]*model.VoiceConversation, int64, error) {
+	var conversations []*model.VoiceConversation
+	var total int64
+
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "count voice conversations by user id")
+	}
+
+	err = r.db.WithContext(ctx).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Order("COALESCE(last_message_at, created_at) DESC").
+		Order("id DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&conversations).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "list voice conversations by last message")
+	}
+
+	return conversations, total, nil
+}
+
+// GetFlagged 分页获取待复核的对话列表（按标记时间降序）
+func (r *voiceConversationRepository) GetFlagged(ctx context.Context, page, pageSize int) ([]*model.VoiceConversation, int64, error) {
+	var conversations []*model.VoiceConversation
+	var total int64
+
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("flagged_at IS NOT NULL AND deleted_at IS NULL").
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "count flagged voice conversations")
+	}
+
+	err = r.db.WithContext(ctx).
+		Where("flagged_at IS NOT NULL AND deleted_at IS NULL").
+		Order("flagged_at DESC").
+		Order("id DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&conversations).Error
+	if err != nil {
+		return nil, 0, WrapDBError(err, "list flagged voice conversations")
+	}
+
+	return conversations, total, nil
+}
+
 // Count 统计对话总数
 func (r *voiceConversationRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
@@ -242,6 +337,290 @@ func (r *voiceConversationRepository) UpdateScore(ctx context.Context, id string
 	return WrapDBError(err, "update voice conversation score")
 }
 
+// UpdateContextSummary 更新上下文滚动摘要及其覆盖的最大消息序号
+func (r *voiceConversationRepository) UpdateContextSummary(ctx context.Context, id, summary string, summarizedUntil int) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"context_summary":  summary,
+			"summarized_until": summarizedUntil,
+		}).Error
+	return WrapDBError(err, "update voice conversation context summary")
+}
+
+// UpdateScenarioProgress 更新角色扮演已达成的成功标准
+// goalAchieved 为 true 时记录完成时间并结束会话
+func (r *voiceConversationRepository) UpdateScenarioProgress(ctx context.Context, id string, metCriteria model.StringArray, goalAchieved bool) error {
+	updates := map[string]interface{}{
+		"met_criteria": metCriteria,
+	}
+	if goalAchieved {
+		updates["goal_achieved_at"] = time.Now()
+		updates["status"] = model.ConversationStatusCompleted
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(updates).Error
+	return WrapDBError(err, "update voice conversation scenario progress")
+}
+
+// UpdateQuestionProgress 更新问答会话的当前题目、作答次数与每题结果
+// completed 为 true（全部题目已答完）时结束会话
+func (r *voiceConversationRepository) UpdateQuestionProgress(ctx context.Context, id string, index, attempts int, results model.QuestionResultList, completed bool) error {
+	updates := map[string]interface{}{
+		"question_index":    index,
+		"question_attempts": attempts,
+		"question_results":  results,
+	}
+	if completed {
+		updates["status"] = model.ConversationStatusCompleted
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(updates).Error
+	return WrapDBError(err, "update voice conversation question progress")
+}
+
+// SaveSessionReview 保存会话结束后的摘要、评分、反馈与问题单词
+// 仅在尚未生成摘要时写入，返回是否写入成功（重复触发时返回 false，调用方据此避免重复累计用户统计）
+func (r *voiceConversationRepository) SaveSessionReview(ctx context.Context, id, summary string, score int, feedback string, problemWords model.StringArray) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("id = ? AND summary IS NULL AND deleted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"summary":       summary,
+			"score":         score,
+			"feedback":      feedback,
+			"problem_words": problemWords,
+		})
+	if result.Error != nil {
+		return false, WrapDBError(result.Error, "save voice conversation review")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkStatsRecorded 标记会话已累计用户学习统计
+// 仅在尚未标记时写入，返回是否写入成功（重复触发时返回 false，调用方据此避免重复累计）
+func (r *voiceConversationRepository) MarkStatsRecorded(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("id = ? AND stats_recorded_at IS NULL AND deleted_at IS NULL", id).
+		Update("stats_recorded_at", time.Now())
+	if result.Error != nil {
+		return false, WrapDBError(result.Error, "mark voice conversation stats recorded")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FlagForReview 标记会话待人工复核
+// 仅在尚未标记时写入，保留首次标记的时间与原因
+func (r *voiceConversationRepository) FlagForReview(ctx context.Context, id, reason string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("id = ? AND flagged_at IS NULL AND deleted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"flagged_at":  time.Now(),
+			"flag_reason": reason,
+		}).Error
+	return WrapDBError(err, "flag voice conversation for review")
+}
+
+// ClearLearnerMemory 清除用户全部会话中保存的长期记忆（家长删除记忆后不再用于进行中的会话）
+func (r *voiceConversationRepository) ClearLearnerMemory(ctx context.Context, userID string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("user_id = ? AND learner_memory IS NOT NULL", userID).
+		Update("learner_memory", nil).Error
+	return WrapDBError(err, "clear voice conversation learner memory")
+}
+
+// TransitionStatus 在事务中将会话从 from 状态切换到 to 状态，返回切换后的会话
+// 离开 active 时累加本段活跃时间（距最近一次活跃不超过 maxActiveGap，maxActiveGap 为 0 时不累加）；
+// 进入 active 时从当前时刻重新计时；进入 paused 时记录暂停时间，离开 paused 时清空
+// 锁定会话行后当前状态不是 from 时返回 ErrConversationStatusStale
+func (r *voiceConversationRepository) TransitionStatus(ctx context.Context, id, from, to string, maxActiveGap time.Duration) (*model.VoiceConversation, error) {
+	var conversation model.VoiceConversation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NULL", id).
+			First(&conversation).Error; err != nil {
+			return err
+		}
+		if conversation.Status != from {
+			return ErrConversationStatusStale
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"status":         to,
+			"last_active_at": now,
+			"paused_at":      nil,
+		}
+		if from == model.ConversationStatusActive {
+			active := activeSeconds(&conversation, now, maxActiveGap)
+			updates["duration_seconds"] = gorm.Expr("duration_seconds + ?", active)
+			conversation.DurationSeconds += active
+		}
+		if to == model.ConversationStatusPaused {
+			updates["paused_at"] = now
+			conversation.PausedAt = &now
+		} else {
+			conversation.PausedAt = nil
+		}
+		if err := tx.Model(&model.VoiceConversation{}).
+			Where("id = ?", id).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+		conversation.Status = to
+		conversation.LastActiveAt = &now
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrConversationStatusStale) {
+			return nil, err
+		}
+		return nil, WrapDBError(err, "transition voice conversation status")
+	}
+	return &conversation, nil
+}
+
+// ListIdleIDs 获取处于 status 状态且最近一次活跃早于 before 的会话 ID（最早的在前，最多 limit 条）
+func (r *voiceConversationRepository) ListIdleIDs(ctx context.Context, status string, before time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("status = ? AND "+idleSinceExpr+" < ? AND deleted_at IS NULL", status, before).
+		Order(idleSinceExpr+" ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, WrapDBError(err, "list idle voice conversations")
+	}
+	return ids, nil
+}
+
+// CompleteIdle 结束仍处于 status 状态且最近一次活跃早于 before 的会话，空闲时间不计入时长
+// 条件更新与 AppendMessages 的行锁互斥，返回是否结束成功（期间有新交互或状态已变化时返回 false）
+func (r *voiceConversationRepository) CompleteIdle(ctx context.Context, id, status string, before time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.VoiceConversation{}).
+		Where("id = ? AND status = ? AND "+idleSinceExpr+" < ? AND deleted_at IS NULL", id, status, before).
+		Updates(map[string]interface{}{
+			"status":    model.ConversationStatusCompleted,
+			"paused_at": nil,
+		})
+	if result.Error != nil {
+		return false, WrapDBError(result.Error, "complete idle voice conversation")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// activeSeconds 距最近一次活跃的秒数，超过 maxActiveGap 时按 maxActiveGap 计（超出部分视为空闲）
+func activeSeconds(conversation *model.VoiceConversation, now time.Time, maxActiveGap time.Duration) int {
+	since := conversation.CreatedAt
+	switch {
+	case conversation.LastActiveAt != nil:
+		since = *conversation.LastActiveAt
+	case conversation.LastMessageAt != nil:
+		since = *conversation.LastMessageAt
+	}
+	gap := now.Sub(since)
+	if gap > maxActiveGap {
+		gap = maxActiveGap
+	}
+	if gap < 0 {
+		return 0
+	}
+	return int(gap.Seconds())
+}
+
+// AppendMessages 在事务中向会话追加消息
+// 锁定会话行后按顺序分配 sequence_number，并累加消息数、活跃时长（距最近一次活跃不超过 maxActiveGap）与最后交互时间
+// maxMessages > 0 时，追加后超过上限返回 ErrConversationLimitReached；会话非 active 返回 ErrConversationNotActive
+func (r *voiceConversationRepository) AppendMessages(ctx context.Context, id string, messages []*model.ConversationMessage, maxActiveGap time.Duration, maxMessages int) (*model.VoiceConversation, error) {
+	var conversation model.VoiceConversation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定会话行，串行化同一会话的并发追加
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NULL", id).
+			First(&conversation).Error; err != nil {
+			return err
+		}
+		if conversation.Status != model.ConversationStatusActive {
+			return ErrConversationNotActive
+		}
+		if maxMessages > 0 && conversation.MessageCount+len(messages) > maxMessages {
+			return ErrConversationLimitReached
+		}
+
+		var maxSeq int
+		if err := tx.Model(&model.ConversationMessage{}).
+			Where("conversation_id = ?", id).
+			Select("COALESCE(MAX(sequence_number), 0)").
+			Scan(&maxSeq).Error; err != nil {
+			return err
+		}
+		for i, message := range messages {
+			message.ConversationID = id
+			message.SequenceNumber = maxSeq + i + 1
+		}
+		if len(messages) > 0 {
+			if err := tx.CreateInBatches(messages, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		active := activeSeconds(&conversation, now, maxActiveGap)
+		if err := tx.Model(&model.VoiceConversation{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"message_count":    gorm.Expr("message_count + ?", len(messages)),
+				"duration_seconds": gorm.Expr("duration_seconds + ?", active),
+				"last_message_at":  now,
+				"last_active_at":   now,
+			}).Error; err != nil {
+			return err
+		}
+		conversation.MessageCount += len(messages)
+		conversation.DurationSeconds += active
+		conversation.LastMessageAt = &now
+		conversation.LastActiveAt = &now
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrConversationLimitReached) || errors.Is(err, ErrConversationNotActive) {
+			return nil, err
+		}
+		return nil, WrapDBError(err, "append conversation messages")
+	}
+	return &conversation, nil
+}
+
+// DeleteWithMessages 在事务中删除会话的所有消息并软删除会话，返回删除的消息数
+func (r *voiceConversationRepository) DeleteWithMessages(ctx context.Context, id string) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("conversation_id = ?", id).Delete(&model.ConversationMessage{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		return tx.Model(&model.VoiceConversation{}).
+			Where("id = ? AND deleted_at IS NULL", id).
+			Update("deleted_at", time.Now()).Error
+	})
+	if err != nil {
+		return 0, WrapDBError(err, "delete voice conversation with messages")
+	}
+	return deleted, nil
+}
+
 // GetWithMessages 获取对话及其消息
 func (r *voiceConversationRepository) GetWithMessages(ctx context.Context, id string) (*model.VoiceConversation, error) {
 	var conversation model.VoiceConversation
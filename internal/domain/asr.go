@@ -31,6 +31,19 @@ type ASRProvider interface {
 	//   - error: 连接或初始化错误
 	RecognizeAudioStream(ctx context.Context, audioData []byte, format string, sampleRate int) (<-chan *ASRStreamEvent, error)
 
+	// RecognizeLiveAudio 实时识别增量到达的音频（如麦克风输入）
+	// 音频块到达即转发给识别服务，同时实时返回中间结果和最终结果
+	// 参数:
+	//   - ctx: 上下文，支持超时和取消
+	//   - audio: 音频数据块通道，调用方写入完毕后关闭通道表示说话结束
+	//     （写入时应同时监听 ctx，识别提前结束后不再读取该通道）
+	//   - format: 音频格式，如 "pcm", "wav"
+	//   - sampleRate: 采样率，如 16000
+	// 返回:
+	//   - <-chan *ASRStreamEvent: 流式事件通道，包含中间结果和最终结果
+	//   - error: 连接或初始化错误
+	RecognizeLiveAudio(ctx context.Context, audio <-chan []byte, format string, sampleRate int) (<-chan *ASRStreamEvent, error)
+
 	// Close 关闭客户端，释放资源
 	Close() error
 }
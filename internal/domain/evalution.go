@@ -2,12 +2,18 @@ package domain
 
 import "context"
 
+// 评测题型（讯飞 category）
+const (
+	AssessCategoryWord     = "read_word"     // 单词朗读（复习队列、最小对立对）
+	AssessCategorySentence = "read_sentence" // 句子朗读（默认）
+)
+
 // EvaluationProvider 语音评测服务提供者接口
 // 封装科大讯飞等语音评测 API
 type EvaluationProvider interface {
 	// Assess 执行语音评测
-	// text: 评测目标文本, audioData: 音频二进制数据
-	Assess(ctx context.Context, text string, audioData []byte) (*EvaluationResult, error)
+	// text: 评测目标文本, audioData: 音频二进制数据, category: 评测题型（AssessCategory*，为空时按句子评测）
+	Assess(ctx context.Context, text string, audioData []byte, category string) (*EvaluationResult, error)
 
 	// Close 关闭客户端，释放资源
 	Close() error
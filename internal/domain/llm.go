@@ -13,6 +13,16 @@ type LLMProvider interface {
 	// ChatWithHistory 多轮对话：给定完整的对话历史，返回 AI 生成的文本
 	ChatWithHistory(ctx context.Context, messages []ChatMessage) (string, error)
 
+	// ChatStream 流式多轮对话：边生成边返回文本增量
+	// 参数:
+	//   - ctx: 上下文，取消后停止生成并关闭通道
+	//   - messages: 完整的对话历史
+	// 返回:
+	//   - <-chan *ChatStreamDelta: 增量事件通道，最后一个事件 Done=true（携带结束原因与 token 用量）
+	//     或 Error 非空，随后通道关闭
+	//   - error: 连接或初始化错误
+	ChatStream(ctx context.Context, messages []ChatMessage) (<-chan *ChatStreamDelta, error)
+
 	// Close 关闭客户端，释放资源
 	Close() error
 }
@@ -22,3 +32,28 @@ type ChatMessage struct {
 	Role    string // "system", "user", "assistant"
 	Content string
 }
+
+// ChatStreamDelta 流式对话事件
+type ChatStreamDelta struct {
+	// Content 本次新增的文本
+	Content string
+
+	// Done 生成结束（最后一个事件）
+	Done bool
+
+	// FinishReason 结束原因: "stop", "length", "content_filter"（仅 Done=true 时有值）
+	FinishReason string
+
+	// Usage token 使用量（仅 Done=true 时有值，服务端未返回时为 nil）
+	Usage *ChatUsage
+
+	// Error 错误信息（生成中断时有值）
+	Error error
+}
+
+// ChatUsage token 使用量统计
+type ChatUsage struct {
+	PromptTokens     int // 输入 token 数
+	CompletionTokens int // 输出 token 数
+	TotalTokens      int // 总 token 数
+}
@@ -0,0 +1,47 @@
+// Package domain 定义核心业务接口
+package domain
+
+import "context"
+
+// 审核处理动作
+const (
+	ModerationActionAllow   = "allow"   // 放行
+	ModerationActionRewrite = "rewrite" // 改写后放行（如个人信息打码、脏话屏蔽），使用 Rewritten
+	ModerationActionBlock   = "block"   // 拦截，不得合成语音或送入 LLM
+)
+
+// 审核命中类别
+const (
+	ModerationCategorySexual    = "sexual"    // 色情低俗
+	ModerationCategoryViolence  = "violence"  // 暴力恐怖
+	ModerationCategorySelfHarm  = "self_harm" // 自伤自杀
+	ModerationCategoryDrugs     = "drugs"     // 毒品烟酒赌博
+	ModerationCategoryProfanity = "profanity" // 脏话辱骂
+	ModerationCategoryPII       = "pii"       // 个人信息（电话、地址、邮箱、证件号）
+)
+
+// ModerationProvider 内容安全审核服务提供者接口
+// 用户为 6-12 岁儿童：孩子说的话（ASR 文本）与 AI 回复在合成语音和保存前都需审核
+// 接口方法只使用 Go 原生类型，严禁出现任何第三方 SDK 结构体
+type ModerationProvider interface {
+	// ModerateInput 审核孩子输入的文本（ASR 识别结果）
+	ModerateInput(ctx context.Context, text string) (*ModerationResult, error)
+
+	// ModerateOutput 审核 AI 回复文本（可为完整回复或流式生成中的一个句子）
+	ModerateOutput(ctx context.Context, text string) (*ModerationResult, error)
+}
+
+// ModerationResult 审核结果
+type ModerationResult struct {
+	// Action 处理动作：allow / rewrite / block
+	Action string
+
+	// Categories 命中的类别（去重，Action=allow 时为空）
+	Categories []string
+
+	// Matches 命中的关键词或片段（仅用于日志与复核，不返回给用户）
+	Matches []string
+
+	// Rewritten 改写后的文本（仅 Action=rewrite 时有值）
+	Rewritten string
+}
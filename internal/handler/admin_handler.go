@@ -0,0 +1,74 @@
+// Package handler 提供管理后台 HTTP 处理器
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/service"
+)
+
+// AdminHandler 管理后台处理器（路由层已校验管理员权限）
+type AdminHandler struct {
+	adminService service.AdminService
+}
+
+// NewAdminHandler 创建 AdminHandler
+func NewAdminHandler(adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+// GetFeedbackAnalytics GET /api/v1/admin/chat/feedback
+// 对话反馈分析：按提示词版本 / 场景 / 难度统计平均评分，并列出评分最低的 AI 回复
+func (h *AdminHandler) GetFeedbackAnalytics(c *gin.Context) {
+	// 步骤 1：解析查询参数
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	maxRating, _ := strconv.Atoi(c.DefaultQuery("max_rating", "0"))
+
+	// 步骤 2：调用 Service
+	resp, err := h.adminService.GetFeedbackAnalytics(c.Request.Context(), &service.FeedbackAnalyticsRequest{
+		DateFrom:  c.Query("date_from"),
+		DateTo:    c.Query("date_to"),
+		Limit:     limit,
+		MaxRating: maxRating,
+	})
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get feedback analytics failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// ListFlaggedConversations GET /api/v1/admin/chat/flagged
+// 待复核会话列表：孩子输入或 AI 回复命中内容安全审核的会话（按标记时间降序）
+func (h *AdminHandler) ListFlaggedConversations(c *gin.Context) {
+	page, pageSize := parsePage(c, 20)
+	items, total, err := h.adminService.ListFlaggedConversations(c.Request.Context(), page, pageSize)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "list flagged conversations failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OKPage(c, items, page, pageSize, total)
+}
+
+// GetLatencyStats GET /api/v1/admin/latency
+// 分阶段耗时统计：语音对话与发音评测各阶段（ASR / 评测 / LLM / TTS / OSS）耗时的 p50 / p95
+func (h *AdminHandler) GetLatencyStats(c *gin.Context) {
+	resp, err := h.adminService.GetLatencyStats(c.Request.Context(), &service.LatencyStatsRequest{
+		DateFrom: c.Query("date_from"),
+		DateTo:   c.Query("date_to"),
+	})
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get latency stats failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
@@ -4,161 +4,717 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"pronunciation-correction-system/internal/handler/middleware"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
 	"pronunciation-correction-system/internal/pkg/logger"
 	"pronunciation-correction-system/internal/service"
 )
 
+// 会话响应头（ChatMVP 直接返回音频流，会话信息放在响应头中）
+const (
+	HeaderSessionID             = "X-Session-ID"
+	HeaderSessionTurn           = "X-Session-Turn"
+	HeaderSessionRemainingTurns = "X-Session-Remaining-Turns"
+	HeaderSessionCompleted      = "X-Session-Completed"
+	HeaderUserLanguage          = "X-User-Language"
+	HeaderReplyTranslation      = "X-Reply-Translation" // URL 编码（UTF-8）的中文释义，未生成时不返回
+	HeaderServerTiming          = "Server-Timing"       // 各阶段耗时（仅 debug 模式返回），如 asr;dur=420, llm;dur=1300, total;dur=2600
+	HeaderTurnFailedStage       = "X-Turn-Failed-Stage" // 本轮处理失败的阶段：llm / tts（仅失败且已保存时返回，可调用重试接口）
+)
+
+// ChatStream 结束事件（其余事件名与 service.ChatStreamEvent* 一致）
+const (
+	chatSSEEventDone  = "done"  // 数据为本轮会话信息
+	chatSSEEventError = "error" // 回复推送过程中出错
+)
+
 // ChatHandler AI 语音对话处理器
 type ChatHandler struct {
-	chatService service.ChatService
+	chatService  service.ChatService
+	quotaService service.QuotaService            // WebSocket 每轮消耗配额（HTTP 接口由中间件消耗）
+	rateLimit    *middleware.RateLimitMiddleware // WebSocket 每轮限流（HTTP 接口由路由中间件限流）
 }
 
 // NewChatHandler 创建 ChatHandler
-func NewChatHandler(chatService service.ChatService) *ChatHandler {
-	return &ChatHandler{chatService: chatService}
+func NewChatHandler(chatService service.ChatService, quotaService service.QuotaService, rateLimit *middleware.RateLimitMiddleware) *ChatHandler {
+	return &ChatHandler{chatService: chatService, quotaService: quotaService, rateLimit: rateLimit}
 }
 
 // ChatMVP POST /api/v1/chat/MVP
 // 同步语音对话 MVP（ASR + LLM + TTS，返回音频流）
 func (h *ChatHandler) ChatMVP(c *gin.Context) {
+	// 步骤 1-3：解析表单、读取音频、获取 user_id
+	req, ok := parseChatAudioRequest(c, "chat mvp")
+	if !ok {
+		return
+	}
+
+	// 步骤 4：设置超时
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	// 步骤 5：调用 Service
+	reply, err := h.chatService.ChatMVP(ctx, req)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat mvp service failed", "error", err)
+		// 回复失败但本轮已保存时，通过响应头告知客户端重试的会话与轮次
+		if reply != nil {
+			c.Header(HeaderSessionID, reply.SessionID)
+			c.Header(HeaderSessionTurn, strconv.Itoa(reply.Turn))
+			c.Header(HeaderTurnFailedStage, reply.FailedStage)
+		}
+		ServiceError(c, err)
+		return
+	}
+
+	// 步骤 6：返回音频流（会话信息通过响应头返回，客户端下一轮携带 session_id 继续对话）
+	c.Header(HeaderSessionID, reply.SessionID)
+	c.Header(HeaderSessionTurn, strconv.Itoa(reply.Turn))
+	c.Header(HeaderSessionRemainingTurns, strconv.Itoa(reply.RemainingTurns))
+	c.Header(HeaderSessionCompleted, strconv.FormatBool(reply.SessionCompleted))
+	if reply.UserLanguage != "" {
+		c.Header(HeaderUserLanguage, reply.UserLanguage)
+	}
+	if reply.ReplyTranslation != "" {
+		c.Header(HeaderReplyTranslation, url.PathEscape(reply.ReplyTranslation))
+	}
+	if reply.Latency != nil {
+		c.Header(HeaderServerTiming, serverTiming(reply.Latency))
+	}
+	c.Data(http.StatusOK, "audio/mpeg", reply.Audio)
+}
+
+// serverTiming 将耗时明细格式化为 Server-Timing 响应头（阶段按名称排序，最后为 total）
+func serverTiming(latency *service.LatencyBreakdown) string {
+	stages := make([]string, 0, len(latency.Stages))
+	for stage := range latency.Stages {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	metrics := make([]string, 0, len(stages)+1)
+	for _, stage := range stages {
+		metrics = append(metrics, fmt.Sprintf("%s;dur=%d", stage, latency.Stages[stage]))
+	}
+	metrics = append(metrics, fmt.Sprintf("total;dur=%d", latency.TotalMS))
+	return strings.Join(metrics, ", ")
+}
+
+// ChatStream POST /api/v1/chat/stream
+// 语音对话的 SSE 变体：识别完成后以 text/event-stream 推送 AI 回复的文本增量与整句
+// 事件：asr_final → reply_delta* / reply_text* → done（或 error）
+func (h *ChatHandler) ChatStream(c *gin.Context) {
+	req, ok := parseChatAudioRequest(c, "chat stream")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	// 首个事件写出前出错时返回普通 JSON 错误（状态码可被配额中间件识别并退还）
+	started := false
+	emit := func(event *service.ChatStreamEvent) error {
+		if !started {
+			started = true
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("Connection", "keep-alive")
+			c.Header("X-Accel-Buffering", "no") // 禁用 Nginx 缓冲
+			c.Status(http.StatusOK)
+		}
+		data := gin.H{"text": event.Text}
+		if event.Type == service.ChatStreamEventReplyText {
+			data["index"] = event.Index
+		}
+		c.SSEvent(event.Type, data)
+		c.Writer.Flush()
+		return ctx.Err()
+	}
+
+	result, err := h.chatService.ChatTextStream(ctx, req, emit)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat stream service failed", "error", err)
+		if !started {
+			ServiceError(c, err)
+			return
+		}
+		// 状态码已为 200，标记失败使幂等中间件不回放、配额中间件按错误码退还
+		status, message := apperr.PublicError(err)
+		middleware.MarkStreamFailed(c, status)
+		c.SSEvent(chatSSEEventError, gin.H{"code": status, "message": message})
+		c.Writer.Flush()
+		return
+	}
+	c.SSEvent(chatSSEEventDone, result)
+	c.Writer.Flush()
+}
+
+// parseChatAudioRequest 解析语音对话表单（audio_file、audio_type、conversation_type、difficulty_level、session_id、translate）
+// 失败时已写出错误响应，返回 false
+func parseChatAudioRequest(c *gin.Context, op string) (*service.ChatMVPRequest, bool) {
 	// 步骤 1：解析 multipart/form-data
 	fileHeader, err := c.FormFile("audio_file")
 	if err != nil {
-		logger.ErrorContext(c.Request.Context(), "chat mvp missing audio_file", "error", err)
+		logger.ErrorContext(c.Request.Context(), op+" missing audio_file", "error", err)
 		BadRequest(c, "audio_file is required")
-		return
+		return nil, false
 	}
 	audioType := c.PostForm("audio_type")
 	if audioType == "" {
-		logger.ErrorContext(c.Request.Context(), "chat mvp missing audio_type", "error", errors.New("audio_type is required"))
+		logger.ErrorContext(c.Request.Context(), op+" missing audio_type", "error", errors.New("audio_type is required"))
 		BadRequest(c, "audio_type is required")
-		return
+		return nil, false
 	}
-	conversationType := c.PostForm("conversation_type")
-	difficultyLevel := c.PostForm("difficulty_level")
 
 	// 步骤 2：读取音频数据
 	file, err := fileHeader.Open()
 	if err != nil {
-		logger.ErrorContext(c.Request.Context(), "chat mvp open file failed", "error", err)
+		logger.ErrorContext(c.Request.Context(), op+" open file failed", "error", err)
 		InternalError(c, "failed to read audio file")
-		return
+		return nil, false
 	}
 	defer file.Close()
 
 	audioData, err := io.ReadAll(file)
 	if err != nil {
-		logger.ErrorContext(c.Request.Context(), "chat mvp read file failed", "error", err)
+		logger.ErrorContext(c.Request.Context(), op+" read file failed", "error", err)
 		InternalError(c, "failed to read audio data")
-		return
+		return nil, false
 	}
 
 	// 步骤 3：从 gin.Context 获取 user_id
-	// 从 gin.Context 获取 user_id
 	userID, exists := c.Get(string(middleware.UserIDKey))
 	if !exists {
-		logger.ErrorContext(c.Request.Context(), "chat mvp user id missing", "error", errors.New("user id is empty"))
+		logger.ErrorContext(c.Request.Context(), op+" user id missing", "error", errors.New("user id is empty"))
 		Unauthorized(c)
-		return
+		return nil, false
 	}
 
-	// 步骤 4：设置超时
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
-	defer cancel()
-
-	// 步骤 5：调用 Service
-	audioReply, err := h.chatService.ChatMVP(ctx, &service.ChatMVPRequest{
+	translate, _ := strconv.ParseBool(c.DefaultPostForm("translate", "false"))
+	return &service.ChatMVPRequest{
 		AudioData:        audioData,
+		SessionID:        c.PostForm("session_id"), // 为空时新建会话
 		AudioType:        audioType,
-		ConversationType: conversationType,
-		DifficultyLevel:  difficultyLevel,
+		ConversationType: c.PostForm("conversation_type"),
+		DifficultyLevel:  c.PostForm("difficulty_level"),
+		Translate:        translate,
 		UserID:           userID.(string),
+		Debug:            debugResponse(),
+	}, true
+}
+
+// SubmitChat POST /api/v1/chat/submit
+// 提交异步语音对话任务（表单同 ChatMVP），返回 task_id，客户端轮询 C-2 获取结果
+func (h *ChatHandler) SubmitChat(c *gin.Context) {
+	// 步骤 1-3：解析表单、读取音频、获取 user_id
+	req, ok := parseChatAudioRequest(c, "chat submit")
+	if !ok {
+		return
+	}
+
+	// 步骤 4：提交任务（后台处理因服务端原因失败时退还本次消耗的配额）
+	resp, err := h.chatService.SubmitChat(c.Request.Context(), &service.SubmitChatRequest{
+		ChatMVPRequest: *req,
+		OnServerError:  h.quotaRefunder(c),
 	})
 	if err != nil {
-		logger.ErrorContext(ctx, "chat mvp service failed", "error", err)
-		InternalError(c, err.Error())
+		logger.ErrorContext(c.Request.Context(), "chat submit service failed", "session_id", req.SessionID, "error", err)
+		ServiceError(c, err)
 		return
 	}
-
-	// 步骤 6：返回音频流
-	c.Data(http.StatusOK, "audio/mpeg", audioReply)
+	OK(c, resp)
 }
 
-// SubmitChat POST /api/v1/chat/submit
-// 提交异步语音对话请求，返回 task_id
-func (h *ChatHandler) SubmitChat(c *gin.Context) {
-	// TODO: Step3 实现
-	// 1. 解析 multipart/form-data: audio_file, audio_type, session_id, user_language, topic_id
-	// 2. 从 Context 获取 user_id
-	// 3. 调用 h.chatService.SubmitChat(ctx, req)
-	// 4. 成功：OK(c, gin.H{"task_id": taskID, "session_id": req.SessionID, "status": "pending"})
-	// 5. 失败：InternalError(c, err.Error())
-	InternalError(c, "not implemented")
+// quotaRefunder 返回退还本次请求所消耗配额的函数（配额中间件未扣减时返回 nil）
+func (h *ChatHandler) quotaRefunder(c *gin.Context) func(ctx context.Context) {
+	feature := c.GetString(string(middleware.QuotaConsumedKey))
+	userID := c.GetString(string(middleware.UserIDKey))
+	if h.quotaService == nil || feature == "" || userID == "" {
+		return nil
+	}
+	return func(ctx context.Context) {
+		if err := h.quotaService.Refund(ctx, userID, feature); err != nil {
+			logger.ErrorContext(ctx, "quota refund failed", "feature", feature, "error", err)
+			return
+		}
+		logger.InfoContext(ctx, "quota refunded", "feature", feature)
+	}
 }
 
 // GetChatResult GET /api/v1/chat/result/:task_id
-// 查询异步语音对话处理结果
+// 查询异步语音对话处理结果（任务状态保留 24 小时）
 func (h *ChatHandler) GetChatResult(c *gin.Context) {
-	// TODO: Step3 实现
-	// 1. 解析路径参数: task_id
-	// 2. 调用 h.chatService.GetChatResult(ctx, taskID)
-	// 3. 成功：OK(c, result)
-	// 4. 失败：InternalError(c, err.Error())
-	InternalError(c, "not implemented")
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	taskID := c.Param("task_id")
+	result, err := h.chatService.GetChatResult(c.Request.Context(), taskID, userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get chat result failed", "task_id", taskID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+	OK(c, result)
 }
 
 // GetChatHistory GET /api/v1/chat/history/:session_id
-// 获取指定会话的对话历史
+// 获取指定会话的对话历史（按轮次分页，order=asc(默认)/desc）
 func (h *ChatHandler) GetChatHistory(c *gin.Context) {
-	// TODO: Step2 实现
-	// 1. 解析路径参数: session_id
-	// 2. 解析查询参数: page(默认1), page_size(默认20), order(默认asc)
-	// 3. 从 Context 获取 user_id
-	// 4. 调用 h.chatService.GetChatHistory(ctx, req)
-	// 5. 成功：OKPage(c, items, page, pageSize, total)
-	// 6. 失败：InternalError(c, err.Error())
-	InternalError(c, "not implemented")
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	page, pageSize := parsePage(c, 20)
+	req := &service.ChatHistoryRequest{
+		SessionID: c.Param("session_id"),
+		Page:      page,
+		PageSize:  pageSize,
+		Order:     c.DefaultQuery("order", "asc"),
+		UserID:    userID.(string),
+	}
+	items, total, err := h.chatService.GetChatHistory(c.Request.Context(), req)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get chat history failed", "session_id", req.SessionID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OKPage(c, items, page, pageSize, total)
 }
 
 // DeleteSession DELETE /api/v1/chat/session/:session_id
 // 删除对话会话及其所有消息
 func (h *ChatHandler) DeleteSession(c *gin.Context) {
-	// TODO: Step2 实现
-	// 1. 解析路径参数: session_id
-	// 2. 从 Context 获取 user_id
-	// 3. 调用 h.chatService.DeleteSession(ctx, sessionID, userID)
-	// 4. 成功：OK(c, gin.H{"session_id": sessionID, "deleted_records": count})
-	// 5. 失败：InternalError(c, err.Error())
-	InternalError(c, "not implemented")
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	sessionID := c.Param("session_id")
+	count, err := h.chatService.DeleteSession(c.Request.Context(), sessionID, userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "delete chat session failed", "session_id", sessionID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, gin.H{"session_id": sessionID, "deleted_records": count, "message": "会话已删除"})
 }
 
 // GetSessions GET /api/v1/chat/sessions
-// 获取当前用户的所有会话列表
+// 获取当前用户的所有会话列表（按最后交互时间降序）
 func (h *ChatHandler) GetSessions(c *gin.Context) {
-	// TODO: Step2 实现
-	// 1. 解析查询参数: page(默认1), page_size(默认20)
-	// 2. 从 Context 获取 user_id
-	// 3. 调用 h.chatService.GetSessions(ctx, userID, page, pageSize)
-	// 4. 成功：OKPage(c, items, page, pageSize, total)
-	// 5. 失败：InternalError(c, err.Error())
-	InternalError(c, "not implemented")
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	page, pageSize := parsePage(c, 20)
+	items, total, err := h.chatService.GetSessions(c.Request.Context(), userID.(string), page, pageSize)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get chat sessions failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OKPage(c, items, page, pageSize, total)
+}
+
+// ListScenarios GET /api/v1/chat/scenarios
+// 获取角色扮演场景列表（学生与老师均可浏览，支持 category / difficulty_level 过滤）
+func (h *ChatHandler) ListScenarios(c *gin.Context) {
+	page, pageSize := parsePage(c, 20)
+	req := &service.ScenarioListRequest{
+		Category:        c.Query("category"),
+		DifficultyLevel: c.Query("difficulty_level"),
+		Page:            page,
+		PageSize:        pageSize,
+	}
+	items, total, err := h.chatService.ListScenarios(c.Request.Context(), req)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "list chat scenarios failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OKPage(c, items, page, pageSize, total)
+}
+
+// GetScenario GET /api/v1/chat/scenarios/:scenario_id
+// 获取场景详情（scenario_id 支持场景 ID 或场景编码）
+func (h *ChatHandler) GetScenario(c *gin.Context) {
+	scenarioID := c.Param("scenario_id")
+	scenario, err := h.chatService.GetScenario(c.Request.Context(), scenarioID)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get chat scenario failed", "scenario_id", scenarioID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, scenario)
+}
+
+// StartScenario POST /api/v1/chat/scenarios/:scenario_id/start
+// 基于场景创建 role_play 会话，返回 session_id 与 AI 开场白；后续轮次通过 C-0 / C-8 / C-7 携带 session_id 进行
+func (h *ChatHandler) StartScenario(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	scenarioID := c.Param("scenario_id")
+	resp, err := h.chatService.StartScenario(c.Request.Context(), scenarioID, userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "start chat scenario failed", "scenario_id", scenarioID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// ListQuestionSets GET /api/v1/chat/question-sets
+// 获取问答题集列表（学生与老师均可浏览，支持 scenario_id / difficulty_level 过滤）
+func (h *ChatHandler) ListQuestionSets(c *gin.Context) {
+	page, pageSize := parsePage(c, 20)
+	req := &service.QuestionSetListRequest{
+		ScenarioID:      c.Query("scenario_id"),
+		DifficultyLevel: c.Query("difficulty_level"),
+		Page:            page,
+		PageSize:        pageSize,
+	}
+	items, total, err := h.chatService.ListQuestionSets(c.Request.Context(), req)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "list question sets failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OKPage(c, items, page, pageSize, total)
+}
+
+// StartQuestionSet POST /api/v1/chat/question-sets/:question_set_id/start
+// 基于题集创建 question_answer 会话，返回 session_id 与第一个问题；后续轮次通过 C-0 / C-8 / C-7 携带 session_id 作答
+func (h *ChatHandler) StartQuestionSet(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	questionSetID := c.Param("question_set_id")
+	resp, err := h.chatService.StartQuestionSet(c.Request.Context(), questionSetID, userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "start question set failed", "question_set_id", questionSetID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// GetQuestionProgress GET /api/v1/chat/session/:session_id/questions
+// 获取问答会话的当前进度与每题作答结果
+func (h *ChatHandler) GetQuestionProgress(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	sessionID := c.Param("session_id")
+	resp, err := h.chatService.GetQuestionProgress(c.Request.Context(), sessionID, userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get question progress failed", "session_id", sessionID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// EndSession POST /api/v1/chat/session/:session_id/end
+// 结束会话，后台生成会话总结、评分与反馈（生成后通过会话列表返回）
+func (h *ChatHandler) EndSession(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	sessionID := c.Param("session_id")
+	resp, err := h.chatService.EndSession(c.Request.Context(), sessionID, userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "end chat session failed", "session_id", sessionID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// PauseSession POST /api/v1/chat/session/:session_id/pause
+// 暂停进行中的会话，暂停期间不能对话、不计入时长
+func (h *ChatHandler) PauseSession(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	sessionID := c.Param("session_id")
+	resp, err := h.chatService.PauseSession(c.Request.Context(), sessionID, userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "pause chat session failed", "session_id", sessionID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// ResumeSession POST /api/v1/chat/session/:session_id/resume
+// 恢复暂停的会话
+func (h *ChatHandler) ResumeSession(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	sessionID := c.Param("session_id")
+	resp, err := h.chatService.ResumeSession(c.Request.Context(), sessionID, userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "resume chat session failed", "session_id", sessionID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// RegenerateReply POST /api/v1/chat/session/:session_id/regenerate
+// 换一种说法重新生成会话最后一轮的 AI 回复（返回新的文本与音频 URL）
+func (h *ChatHandler) RegenerateReply(c *gin.Context) {
+	h.replaceReply(c, "regenerate", h.chatService.RegenerateReply)
+}
+
+// RetryTurn POST /api/v1/chat/session/:session_id/retry
+// 从失败阶段重试会话最后一轮（复用已保存的识别文本，无需重新上传音频）
+func (h *ChatHandler) RetryTurn(c *gin.Context) {
+	h.replaceReply(c, "retry", h.chatService.RetryTurn)
+}
+
+// replaceReply 重新生成 / 重试的公共处理（可选查询参数 translate 要求中文释义）
+func (h *ChatHandler) replaceReply(c *gin.Context, action string, replace func(context.Context, *service.ReplyRequest) (*service.ReplyResponse, error)) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+	translate, _ := strconv.ParseBool(c.DefaultQuery("translate", "false"))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	sessionID := c.Param("session_id")
+	resp, err := replace(ctx, &service.ReplyRequest{
+		SessionID: sessionID,
+		Translate: translate,
+		UserID:    userID.(string),
+		Debug:     debugResponse(),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, action+" chat reply failed", "session_id", sessionID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// submitFeedbackBody 对话反馈请求体
+type submitFeedbackBody struct {
+	TaskID    string `json:"task_id"`
+	SessionID string `json:"session_id"`
+	Turn      int    `json:"turn"`
+	Rating    int    `json:"rating"`
+	Comment   string `json:"comment"`
+	Helpful   *bool  `json:"helpful"`
 }
 
 // SubmitChatFeedback POST /api/v1/chat/feedback
-// 提交对话反馈
+// 提交对某一轮 AI 回复的反馈（评分 1-5，可选文字评价与是否有帮助）
 func (h *ChatHandler) SubmitChatFeedback(c *gin.Context) {
-	// TODO: Step2 实现
-	// 1. 解析 JSON 请求体: task_id, session_id, turn, rating, comment, helpful
-	// 2. 从 Context 获取 user_id
-	// 3. 调用 h.chatService.SubmitChatFeedback(ctx, req)
-	// 4. 成功：OK(c, gin.H{"message": "感谢您的反馈"})
-	// 5. 失败：InternalError(c, err.Error())
-	InternalError(c, "not implemented")
+	// 步骤 1：解析 JSON 请求体
+	var body submitFeedbackBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		BadRequest(c, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(body.SessionID) == "" {
+		BadRequest(c, "session_id is required")
+		return
+	}
+
+	// 步骤 2：从 Context 获取 user_id
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	// 步骤 3：调用 Service
+	err := h.chatService.SubmitChatFeedback(c.Request.Context(), &service.SubmitFeedbackRequest{
+		TaskID:    body.TaskID,
+		SessionID: body.SessionID,
+		Turn:      body.Turn,
+		Rating:    body.Rating,
+		Comment:   body.Comment,
+		Helpful:   body.Helpful,
+		UserID:    userID.(string),
+	})
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "submit chat feedback failed", "session_id", body.SessionID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, gin.H{"message": "感谢您的反馈"})
+}
+
+// GetLanguagePolicy GET /api/v1/chat/language-policy
+// 获取当前学习者的回复语言策略
+func (h *ChatHandler) GetLanguagePolicy(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	resp, err := h.chatService.GetLanguagePolicy(c.Request.Context(), userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get language policy failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// languagePolicyBody 设置回复语言策略请求体
+type languagePolicyBody struct {
+	Policy string `json:"policy"`
+}
+
+// UpdateLanguagePolicy PUT /api/v1/chat/language-policy
+// 设置当前学习者的回复语言策略（english_only / chinese_scaffolding / translate_on_demand）
+func (h *ChatHandler) UpdateLanguagePolicy(c *gin.Context) {
+	var body languagePolicyBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		BadRequest(c, "invalid request body")
+		return
+	}
+
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	resp, err := h.chatService.UpdateLanguagePolicy(c.Request.Context(), &service.UpdateLanguagePolicyRequest{
+		UserID: userID.(string),
+		Policy: body.Policy,
+	})
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "update language policy failed", "policy", body.Policy, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// ListPersonas GET /api/v1/chat/personas
+// 获取可选的 AI 老师人设列表
+func (h *ChatHandler) ListPersonas(c *gin.Context) {
+	personas, err := h.chatService.ListPersonas(c.Request.Context())
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "list personas failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, gin.H{"items": personas})
+}
+
+// GetPersona GET /api/v1/chat/persona
+// 获取当前学习者选择的 AI 老师人设
+func (h *ChatHandler) GetPersona(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	resp, err := h.chatService.GetPersona(c.Request.Context(), userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get persona failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// personaBody 选择 AI 老师人设请求体
+type personaBody struct {
+	PersonaID string `json:"persona_id"`
+}
+
+// UpdatePersona PUT /api/v1/chat/persona
+// 选择当前学习者的 AI 老师人设（persona_id 支持人设 ID 或编码，为空时恢复默认老师）
+func (h *ChatHandler) UpdatePersona(c *gin.Context) {
+	var body personaBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		BadRequest(c, "invalid request body")
+		return
+	}
+
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	resp, err := h.chatService.UpdatePersona(c.Request.Context(), &service.UpdatePersonaRequest{
+		UserID:    userID.(string),
+		PersonaID: body.PersonaID,
+	})
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "update persona failed", "persona_id", body.PersonaID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
 }
@@ -0,0 +1,423 @@
+// Package handler 提供实时语音对话 WebSocket 处理器
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"pronunciation-correction-system/internal/handler/middleware"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/service"
+)
+
+// WebSocket 连接参数
+const (
+	wsWriteWait           = 10 * time.Second  // 单次写超时
+	wsPongWait            = 60 * time.Second  // 超过该时间未收到任何消息（含 pong）视为断开
+	wsPingPeriod          = 25 * time.Second  // 服务端心跳间隔（必须小于 wsPongWait）
+	wsMaxFrameSize        = 64 << 10          // 客户端单帧上限
+	wsMaxUtteranceSeconds = 60                // 单轮录音最大时长
+	wsTurnTimeout         = 150 * time.Second // 单轮超时（含录音时长）
+	wsAudioBuffer         = 256               // 待转发给 ASR 的音频块缓冲数（覆盖 ASR 连接建立期间的录音）
+	wsChatRateLimitRule   = "chat"            // 每轮开始录音时计数的限流规则
+)
+
+// 客户端消息类型（文本帧 JSON；录音 PCM 以二进制帧发送）
+const (
+	wsClientStart  = "start"  // 开始一轮录音并立即开始识别（正在播放的回复会被打断）
+	wsClientStop   = "stop"   // 结束录音，识别完成后开始回复
+	wsClientCancel = "cancel" // 打断：取消正在进行的录音或回复
+	wsClientPing   = "ping"   // 应用层心跳
+)
+
+// 服务端消息类型（音频块以二进制帧发送，归属于最近一条 reply_text）
+const (
+	wsServerReady     = "ready"
+	wsServerTurnEnd   = "turn_end"
+	wsServerCancelled = "cancelled"
+	wsServerError     = "error"
+	wsServerPong      = "pong"
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	// 跨域由 JWT 认证保护，与 CORS 中间件一致放行所有来源
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsClientMessage 客户端控制消息
+type wsClientMessage struct {
+	Type             string `json:"type"`
+	SessionID        string `json:"session_id,omitempty"`
+	AudioFormat      string `json:"audio_format,omitempty"` // pcm（默认）/ wav
+	SampleRate       int    `json:"sample_rate,omitempty"`  // 默认 16000
+	ConversationType string `json:"conversation_type,omitempty"`
+	DifficultyLevel  string `json:"difficulty_level,omitempty"`
+	Translate        bool   `json:"translate,omitempty"` // 本轮是否要求中文释义（translate_on_demand 策略使用）
+}
+
+// wsServerMessage 服务端事件消息
+type wsServerMessage struct {
+	Type       string                  `json:"type"`
+	Text       string                  `json:"text,omitempty"`
+	Index      *int                    `json:"index,omitempty"`
+	SessionID  string                  `json:"session_id,omitempty"`
+	Result     *service.ChatTurnResult `json:"result,omitempty"`
+	Code       int                     `json:"code,omitempty"` // 与 HTTP 接口一致的状态码
+	Message    string                  `json:"message,omitempty"`
+	RetryAfter int64                   `json:"retry_after,omitempty"` // 被限流时建议的重试秒数
+}
+
+// ChatWebSocket GET /ws/chat
+// 全双工实时语音对话：客户端推送麦克风 PCM，服务端推送 ASR 中间结果、逐句回复文本与 TTS 音频块
+func (h *ChatHandler) ChatWebSocket(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade 失败时已写出 HTTP 错误响应
+		logger.ErrorContext(c.Request.Context(), "chat ws upgrade failed", "error", err)
+		return
+	}
+
+	// 连接生命周期独立于 HTTP 请求（保留 trace 等上下文值）
+	ctx, cancel := context.WithCancel(context.WithoutCancel(c.Request.Context()))
+	session := &wsChatSession{
+		handler:   h,
+		conn:      conn,
+		ctx:       ctx,
+		userID:    userID.(string),
+		sessionID: c.Query("session_id"),
+	}
+	defer func() {
+		cancel()
+		session.cancelTurn()
+		_ = conn.Close()
+	}()
+
+	logger.InfoContext(ctx, "chat ws connected", "user_id", session.userID)
+	session.run()
+	logger.InfoContext(ctx, "chat ws disconnected", "user_id", session.userID)
+}
+
+// wsChatSession 单个 WebSocket 连接的对话状态
+type wsChatSession struct {
+	handler *ChatHandler
+	conn    *websocket.Conn
+	ctx     context.Context
+	userID  string
+	writeMu sync.Mutex // gorilla/websocket 同一时刻只允许一个写入方
+
+	// 以下字段仅在读循环中访问
+	audio      chan []byte     // 当前录音的音频通道（未在录音时为 nil），转发给实时 ASR
+	audioDone  <-chan struct{} // 当前录音所属轮次的结束信号
+	audioBytes int
+	maxAudio   int
+
+	mu         sync.Mutex
+	sessionID  string             // 当前对话会话 ID（首轮完成后由服务端分配）
+	turnCancel context.CancelFunc // 正在进行的轮次
+	turnDone   chan struct{}
+}
+
+// run 读循环：处理控制消息与录音数据，直到连接断开
+func (s *wsChatSession) run() {
+	s.conn.SetReadLimit(wsMaxFrameSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	stopPing := make(chan struct{})
+	defer close(stopPing)
+	go s.pingLoop(stopPing)
+
+	_ = s.writeJSON(&wsServerMessage{Type: wsServerReady, SessionID: s.currentSessionID()})
+
+	for {
+		messageType, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.WarnContext(s.ctx, "chat ws read failed", "error", err)
+			}
+			return
+		}
+		_ = s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		if messageType == websocket.BinaryMessage {
+			s.appendAudio(data)
+			continue
+		}
+
+		var msg wsClientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.writeError(http.StatusBadRequest, "invalid message")
+			continue
+		}
+		switch msg.Type {
+		case wsClientStart:
+			// 每轮按 chat 规则限流（被限流时不打断正在播放的回复）
+			s.audio = nil
+			if allowed, retryAfter := s.handler.rateLimit.AllowUser(s.ctx, wsChatRateLimitRule, s.userID); !allowed {
+				_ = s.writeJSON(&wsServerMessage{Type: wsServerError, Code: http.StatusTooManyRequests, Message: apperr.ErrTooManyRequests.Message, RetryAfter: retryAfter})
+				continue
+			}
+			// 孩子开口即打断正在播放的回复
+			if s.cancelTurn() {
+				_ = s.writeJSON(&wsServerMessage{Type: wsServerCancelled})
+			}
+			s.startRecording(&msg)
+		case wsClientStop:
+			if s.audio == nil {
+				s.writeError(http.StatusBadRequest, "not recording")
+				continue
+			}
+			if s.audioBytes == 0 {
+				s.audio = nil
+				s.cancelTurn()
+				s.writeError(http.StatusBadRequest, "no audio received")
+				continue
+			}
+			// 关闭音频通道，ASR 收尾后开始回复
+			close(s.audio)
+			s.audio = nil
+		case wsClientCancel:
+			s.audio = nil
+			if s.cancelTurn() {
+				_ = s.writeJSON(&wsServerMessage{Type: wsServerCancelled})
+			}
+		case wsClientPing:
+			_ = s.writeJSON(&wsServerMessage{Type: wsServerPong})
+		default:
+			s.writeError(http.StatusBadRequest, "unknown message type: "+msg.Type)
+		}
+	}
+}
+
+// pingLoop 定时发送 WebSocket ping，客户端回复 pong 以保持连接
+func (s *wsChatSession) pingLoop(stop <-chan struct{}) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// WriteControl 可与其他写操作并发调用
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// startRecording 开始一轮录音，并立即启动本轮处理（录音边到达边识别）
+func (s *wsChatSession) startRecording(msg *wsClientMessage) {
+	if msg.SessionID != "" {
+		s.mu.Lock()
+		s.sessionID = msg.SessionID
+		s.mu.Unlock()
+	}
+	if msg.SampleRate <= 0 {
+		msg.SampleRate = 16000
+	}
+	s.audio = make(chan []byte, wsAudioBuffer)
+	s.audioBytes = 0
+	s.maxAudio = msg.SampleRate * 2 * wsMaxUtteranceSeconds // 16bit 单声道
+	s.audioDone = s.startTurn(s.audio, msg)
+}
+
+// appendAudio 转发录音数据，超过单轮时长上限时丢弃本轮
+// 不阻塞读循环：缓冲已满（ASR 跟不上录音）时取消本轮，保证控制消息仍能及时读取
+func (s *wsChatSession) appendAudio(data []byte) {
+	if s.audio == nil {
+		return
+	}
+	if s.audioBytes+len(data) > s.maxAudio {
+		s.audio = nil
+		s.cancelTurn()
+		s.writeError(http.StatusBadRequest, apperr.ErrAudioTooLong.Message)
+		return
+	}
+	s.audioBytes += len(data)
+	select {
+	case s.audio <- data:
+	case <-s.audioDone:
+		// 本轮已结束（如配额不足或识别失败），丢弃后续录音
+		s.audio = nil
+	default:
+		// 丢弃部分录音会导致识别结果残缺，直接结束本轮由孩子重说
+		s.audio = nil
+		s.cancelTurn()
+		logger.WarnContext(s.ctx, "chat ws audio buffer full, turn cancelled", "buffered", wsAudioBuffer)
+		s.writeError(http.StatusServiceUnavailable, "speech recognition is busy, please try again")
+	}
+}
+
+// startTurn 在后台处理一轮对话（同一时刻只有一轮），返回本轮结束信号
+func (s *wsChatSession) startTurn(audio <-chan []byte, params *wsClientMessage) <-chan struct{} {
+	ctx, cancel := context.WithTimeout(s.ctx, wsTurnTimeout)
+	done := make(chan struct{})
+
+	s.mu.Lock()
+	s.turnCancel = cancel
+	s.turnDone = done
+	sessionID := s.sessionID
+	s.mu.Unlock()
+
+	req := &service.ChatStreamRequest{
+		Audio:            audio,
+		AudioFormat:      params.AudioFormat,
+		SampleRate:       params.SampleRate,
+		SessionID:        sessionID,
+		ConversationType: params.ConversationType,
+		DifficultyLevel:  params.DifficultyLevel,
+		Translate:        params.Translate,
+		UserID:           s.userID,
+		Debug:            debugResponse(),
+	}
+	go func() {
+		defer close(done)
+		defer cancel()
+		s.processTurn(ctx, req)
+	}()
+	return done
+}
+
+// processTurn 执行一轮流式对话，识别出孩子的话后才消耗配额
+// 未说话、识别为空或识别前被打断的轮次不计入配额；已消耗配额的轮次在开始生成回复前被打断或服务端失败时退还
+// 开始生成回复后被打断的轮次不退还（LLM 与 TTS 已产生费用，否则每轮回复后发送 cancel 即可绕过每日配额）
+func (s *wsChatSession) processTurn(ctx context.Context, req *service.ChatStreamRequest) {
+	quota := s.handler.quotaService
+	consumed := false
+	replyStarted := false
+	req.OnReplyStart = func() { replyStarted = true }
+
+	emit := func(event *service.ChatStreamEvent) error {
+		if event.Type == service.ChatStreamEventASRFinal && quota != nil && !consumed {
+			// 每轮消耗一次 chat 配额（Redis 异常时放行）
+			decision, err := quota.Consume(ctx, s.userID, model.QuotaFeatureChat)
+			switch {
+			case err != nil:
+				logger.ErrorContext(ctx, "chat ws quota consume failed, turn allowed", "error", err)
+			case !decision.Allowed:
+				return apperr.ErrQuotaExceeded
+			case !decision.Unlimited:
+				consumed = true
+			}
+		}
+		return s.emit(event)
+	}
+
+	result, err := s.handler.chatService.ChatStream(ctx, req, emit)
+	if err != nil {
+		if ctx.Err() != nil && errors.Is(ctx.Err(), context.Canceled) {
+			// 用户打断或连接断开，已推送 cancelled
+			if consumed && !replyStarted {
+				s.refundQuota(ctx)
+			}
+			return
+		}
+		logger.ErrorContext(ctx, "chat ws turn failed", "error", err)
+		status, message := apperr.PublicError(err)
+		if consumed && status >= http.StatusInternalServerError {
+			s.refundQuota(ctx)
+		}
+		s.writeError(status, message)
+		return
+	}
+
+	// 会话达到消息上限后，下一轮自动新建会话
+	s.mu.Lock()
+	s.sessionID = result.SessionID
+	if result.SessionCompleted {
+		s.sessionID = ""
+	}
+	s.mu.Unlock()
+	_ = s.writeJSON(&wsServerMessage{Type: wsServerTurnEnd, SessionID: result.SessionID, Result: result})
+}
+
+// refundQuota 退还本轮消耗的 chat 配额
+func (s *wsChatSession) refundQuota(ctx context.Context) {
+	refundCtx := context.WithoutCancel(ctx)
+	if err := s.handler.quotaService.Refund(refundCtx, s.userID, model.QuotaFeatureChat); err != nil {
+		logger.ErrorContext(refundCtx, "chat ws quota refund failed", "error", err)
+	}
+}
+
+// emit 将 Service 事件写出到客户端
+func (s *wsChatSession) emit(event *service.ChatStreamEvent) error {
+	switch event.Type {
+	case service.ChatStreamEventAudio:
+		return s.writeBinary(event.Audio)
+	case service.ChatStreamEventReplyText, service.ChatStreamEventTTSEnd:
+		index := event.Index
+		return s.writeJSON(&wsServerMessage{Type: event.Type, Text: event.Text, Index: &index})
+	default:
+		return s.writeJSON(&wsServerMessage{Type: event.Type, Text: event.Text})
+	}
+}
+
+// cancelTurn 取消正在进行的轮次并等待其退出，返回是否确有轮次被取消
+func (s *wsChatSession) cancelTurn() bool {
+	s.mu.Lock()
+	cancel, done := s.turnCancel, s.turnDone
+	s.turnCancel, s.turnDone = nil, nil
+	s.mu.Unlock()
+	if cancel == nil {
+		return false
+	}
+
+	select {
+	case <-done:
+		return false // 已自然结束
+	default:
+	}
+	cancel()
+	<-done
+	return true
+}
+
+// currentSessionID 当前对话会话 ID
+func (s *wsChatSession) currentSessionID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessionID
+}
+
+// writeJSON 写出 JSON 文本帧
+func (s *wsChatSession) writeJSON(msg *wsServerMessage) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.conn.WriteJSON(msg)
+}
+
+// writeBinary 写出二进制帧（TTS 音频块）
+func (s *wsChatSession) writeBinary(data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.conn.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// writeError 写出错误事件
+func (s *wsChatSession) writeError(code int, message string) {
+	if err := s.writeJSON(&wsServerMessage{Type: wsServerError, Code: code, Message: message}); err != nil {
+		logger.WarnContext(s.ctx, "chat ws write error failed", "error", err)
+	}
+}
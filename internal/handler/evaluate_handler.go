@@ -4,8 +4,10 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -96,6 +98,7 @@ func (h *EvaluateHandler) EvaluateMVP(c *gin.Context) {
 		Category:        category,
 		DifficultyLevel: difficultyLevel,
 		UserID:          userID.(string),
+		Debug:           debugResponse(),
 	})
 	if err != nil {
 		logger.ErrorContext(ctx, "evaluate mvp service failed", "error", err)
@@ -118,18 +121,162 @@ func (h *EvaluateHandler) GetEvaluationResult(c *gin.Context) {
 }
 
 // GetEvaluationHistory GET /api/v1/evaluate/history
+// 查询参数: text_id, date_from, date_to, page(默认1), page_size(默认20), order_by(created_at/score), order(asc/desc)
 func (h *EvaluateHandler) GetEvaluationHistory(c *gin.Context) {
-	InternalError(c, "not implemented")
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	req := parseEvalHistoryRequest(c, userID.(string))
+	items, total, err := h.evaluateService.GetEvaluationHistory(c.Request.Context(), req)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get evaluation history failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OKPage(c, items, req.Page, req.PageSize, total)
 }
 
 // GetEvaluationDetail GET /api/v1/evaluate/:eval_id/detail
 func (h *EvaluateHandler) GetEvaluationDetail(c *gin.Context) {
-	InternalError(c, "not implemented")
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	resp, err := h.evaluateService.GetEvaluationDetail(c.Request.Context(), c.Param("eval_id"), userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get evaluation detail failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// CompareEvaluations GET /api/v1/evaluate/compare
+// 对比同一文本的两次尝试：?from_id=&to_id= 指定两条记录，或 ?text_id= 对比最佳与最近记录
+func (h *EvaluateHandler) CompareEvaluations(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	resp, err := h.evaluateService.CompareEvaluations(c.Request.Context(), &service.CompareEvaluationsRequest{
+		UserID:     userID.(string),
+		FromEvalID: strings.TrimSpace(c.Query("from_id")),
+		ToEvalID:   strings.TrimSpace(c.Query("to_id")),
+		TextID:     strings.TrimSpace(c.Query("text_id")),
+	})
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "compare evaluations failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
 }
 
 // DeleteEvaluation DELETE /api/v1/evaluate/:eval_id
 func (h *EvaluateHandler) DeleteEvaluation(c *gin.Context) {
-	InternalError(c, "not implemented")
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	evalID := c.Param("eval_id")
+	if err := h.evaluateService.DeleteEvaluation(c.Request.Context(), evalID, userID.(string)); err != nil {
+		logger.ErrorContext(c.Request.Context(), "delete evaluation failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, gin.H{"eval_id": evalID})
+}
+
+// ExportEvaluationHistory GET /api/v1/evaluate/export
+// 流式导出评测历史，format=csv(默认)/jsonl，过滤参数同 history
+func (h *EvaluateHandler) ExportEvaluationHistory(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", service.ExportFormatCSV))
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case service.ExportFormatCSV:
+	case service.ExportFormatJSONL:
+		contentType = "application/x-ndjson; charset=utf-8"
+	default:
+		BadRequest(c, "format must be csv or jsonl")
+		return
+	}
+
+	// 首次写出数据时才写响应头；写出过程中出错只能记录日志（响应已开始）
+	req := parseEvalHistoryRequest(c, userID.(string))
+	w := &exportResponseWriter{
+		ctx:         c,
+		contentType: contentType,
+		filename:    fmt.Sprintf("evaluations_%s.%s", time.Now().Format("20060102"), format),
+	}
+	if err := h.evaluateService.ExportEvaluationHistory(c.Request.Context(), req, format, w); err != nil {
+		logger.ErrorContext(c.Request.Context(), "export evaluation history failed", "error", err)
+		if !w.started {
+			ServiceError(c, err)
+		}
+		return
+	}
+	if !w.started {
+		w.writeHeader()
+	}
+}
+
+// exportResponseWriter 流式导出写入器
+// 首次写入时输出下载响应头，之后每次写入立即刷新到客户端
+type exportResponseWriter struct {
+	ctx         *gin.Context
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *exportResponseWriter) writeHeader() {
+	w.started = true
+	w.ctx.Header("Content-Type", w.contentType)
+	w.ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+	w.ctx.Status(http.StatusOK)
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.writeHeader()
+	}
+	n, err := w.ctx.Writer.Write(p)
+	w.ctx.Writer.Flush()
+	return n, err
+}
+
+// parseEvalHistoryRequest 解析历史查询参数
+func parseEvalHistoryRequest(c *gin.Context, userID string) *service.EvalHistoryRequest {
+	page, pageSize := parsePage(c, 20)
+	return &service.EvalHistoryRequest{
+		UserID:   userID,
+		TextID:   strings.TrimSpace(c.Query("text_id")),
+		DateFrom: strings.TrimSpace(c.Query("date_from")),
+		DateTo:   strings.TrimSpace(c.Query("date_to")),
+		Page:     page,
+		PageSize: pageSize,
+		OrderBy:  strings.TrimSpace(c.Query("order_by")),
+		Order:    strings.ToLower(strings.TrimSpace(c.Query("order"))),
+	}
 }
 
 // GetReferenceAudio GET /api/v1/evaluate/reference-audio/:text_id
@@ -138,6 +285,108 @@ func (h *EvaluateHandler) GetReferenceAudio(c *gin.Context) {
 	InternalError(c, "not implemented")
 }
 
+// GetMinimalPairExercises GET /api/v1/evaluate/minimal-pair
+// 根据学习者混淆的音素生成最小对立词练习（含示范音频）
+func (h *EvaluateHandler) GetMinimalPairExercises(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	expand, _ := strconv.ParseBool(c.DefaultQuery("expand", "false"))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	resp, err := h.evaluateService.GetMinimalPairExercises(ctx, &service.MinimalPairExercisesRequest{
+		UserID:     userID.(string),
+		ContrastID: strings.TrimSpace(c.Query("contrast_id")),
+		Limit:      limit,
+		Expand:     expand,
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "get minimal pair exercises failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// SubmitMinimalPair POST /api/v1/evaluate/minimal-pair/submit
+// 提交最小对立词练习录音（单词模式评测）
+func (h *EvaluateHandler) SubmitMinimalPair(c *gin.Context) {
+	// 步骤 1：解析 multipart/form-data
+	fileHeader, err := c.FormFile("audio_file")
+	if err != nil {
+		BadRequest(c, "audio_file is required")
+		return
+	}
+	contrastID := strings.TrimSpace(c.PostForm("contrast_id"))
+	wordA := strings.TrimSpace(c.PostForm("word_a"))
+	wordB := strings.TrimSpace(c.PostForm("word_b"))
+	target := strings.TrimSpace(c.PostForm("target"))
+	if contrastID == "" || wordA == "" || wordB == "" || target == "" {
+		BadRequest(c, "contrast_id, word_a, word_b and target are required")
+		return
+	}
+	audioType := strings.ToLower(strings.TrimSpace(c.PostForm("audio_type")))
+	if audioType == "" {
+		audioType = "wav"
+	}
+
+	// 步骤 2：读取音频数据
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "minimal pair open file failed", "error", err)
+		InternalError(c, "failed to read audio file")
+		return
+	}
+	defer file.Close()
+
+	audioData, err := io.ReadAll(file)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "minimal pair read file failed", "error", err)
+		InternalError(c, "failed to read audio data")
+		return
+	}
+
+	// 步骤 3：WAV 格式去掉 44 字节 header（讯飞评测需 PCM 裸数据）
+	if audioType == "wav" && len(audioData) > 44 {
+		audioData = audioData[44:]
+	}
+
+	// 步骤 4：从 Context 获取 user_id
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	// 步骤 5：设置超时并调用 Service
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	resp, err := h.evaluateService.SubmitMinimalPair(ctx, &service.SubmitMinimalPairRequest{
+		ContrastID: contrastID,
+		WordA:      wordA,
+		WordB:      wordB,
+		Target:     target,
+		AudioData:  audioData,
+		AudioType:  audioType,
+		UserID:     userID.(string),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "submit minimal pair failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
 // handleAudioResponse 返回音频流响应
 func handleAudioResponse(c *gin.Context, audioData []byte) {
 	c.Data(http.StatusOK, "audio/mpeg", audioData)
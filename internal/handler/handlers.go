@@ -4,10 +4,14 @@ package handler
 
 // Handlers 所有 HTTP Handler 的聚合
 type Handlers struct {
-	Auth     *AuthHandler
-	User     *UserHandler
-	Chat     *ChatHandler
-	Evaluate *EvaluateHandler
-	Report   *ReportHandler
-	System   *SystemHandler
+	Auth          *AuthHandler
+	User          *UserHandler
+	Chat          *ChatHandler
+	Evaluate      *EvaluateHandler
+	Review        *ReviewHandler
+	Report        *ReportHandler
+	Vocabulary    *VocabularyHandler
+	LearnerMemory *LearnerMemoryHandler
+	System        *SystemHandler
+	Admin         *AdminHandler
 }
@@ -0,0 +1,78 @@
+// Package handler 提供学习者长期记忆 HTTP 处理器
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/handler/middleware"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/service"
+)
+
+// LearnerMemoryHandler 学习者长期记忆处理器（家长查看与删除）
+type LearnerMemoryHandler struct {
+	memoryService service.LearnerMemoryService
+}
+
+// NewLearnerMemoryHandler 创建 LearnerMemoryHandler
+func NewLearnerMemoryHandler(memoryService service.LearnerMemoryService) *LearnerMemoryHandler {
+	return &LearnerMemoryHandler{memoryService: memoryService}
+}
+
+// ListMemories GET /api/v1/user/memories
+// 分页获取 AI 老师记住的孩子信息（按最后提取时间降序）
+func (h *LearnerMemoryHandler) ListMemories(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	page, pageSize := parsePage(c, 20)
+	items, total, err := h.memoryService.ListMemories(c.Request.Context(), userID.(string), page, pageSize)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "list learner memories failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OKPage(c, items, page, pageSize, total)
+}
+
+// DeleteMemory DELETE /api/v1/user/memories/:memory_id
+// 删除一条记忆
+func (h *LearnerMemoryHandler) DeleteMemory(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	memoryID := c.Param("memory_id")
+	if err := h.memoryService.DeleteMemory(c.Request.Context(), userID.(string), memoryID); err != nil {
+		logger.ErrorContext(c.Request.Context(), "delete learner memory failed", "memory_id", memoryID, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, gin.H{"memory_id": memoryID, "message": "记忆已删除"})
+}
+
+// ClearMemories DELETE /api/v1/user/memories
+// 删除全部记忆
+func (h *LearnerMemoryHandler) ClearMemories(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	count, err := h.memoryService.ClearMemories(c.Request.Context(), userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "clear learner memories failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, gin.H{"deleted_records": count, "message": "记忆已清空"})
+}
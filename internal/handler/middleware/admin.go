@@ -0,0 +1,39 @@
+// Package middleware 提供管理后台权限中间件
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/service"
+)
+
+// AdminMiddleware 管理后台权限中间件
+// 仅允许管理员（users.is_admin）访问，与决定配额的套餐无关；查询失败时拒绝访问
+//
+// 注意：此中间件依赖 Auth，需要注册在认证路由组内
+type AdminMiddleware struct {
+	adminService service.AdminService
+}
+
+// NewAdminMiddleware 创建 AdminMiddleware
+func NewAdminMiddleware(adminService service.AdminService) *AdminMiddleware {
+	return &AdminMiddleware{adminService: adminService}
+}
+
+// Require 返回校验管理员权限的中间件
+func (m *AdminMiddleware) Require() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString(string(UserIDKey))
+		if userID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
+			return
+		}
+		if m == nil || m.adminService == nil || !m.adminService.IsAdmin(c.Request.Context(), userID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"code": 403, "message": "forbidden", "data": nil})
+			return
+		}
+		c.Next()
+	}
+}
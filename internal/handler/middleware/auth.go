@@ -19,54 +19,72 @@ const UserIDKey contextKey = "user_id"
 // Auth JWT 认证中间件
 func Auth(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// dev 模式：直接放行并写入固定 user_id
-		if cfg != nil && strings.EqualFold(cfg.Server.Environment, "development") {
-			// gin.Context 中设置 user_id
-			c.Set(string(UserIDKey), "dev-user-123")
-			// context.WithValue 中设置 user_id
-			ctx := context.WithValue(c.Request.Context(), UserIDKey, "dev-user-123")
-			c.Request = c.Request.WithContext(ctx)
-			c.Next()
-			return
-		}
-
 		// prod 模式：校验 Authorization Bearer Token
 		authHeader := c.GetHeader("Authorization")
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
-			return
-		}
-		tokenString := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
-		if tokenString == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
-			return
-		}
-		if cfg == nil || strings.TrimSpace(cfg.JWT.Secret) == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
-			return
-		}
-
-		claims := jwt.MapClaims{}
-		parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
-		token, err := parser.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
-			return []byte(cfg.JWT.Secret), nil
-		})
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
-			return
+		tokenString := ""
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			tokenString = strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
 		}
+		authenticate(c, cfg, tokenString)
+	}
+}
 
-		userID, ok := claims["user_id"].(string)
-		if !ok || strings.TrimSpace(userID) == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
-			return
+// WebSocketAuth WebSocket 升级前的 JWT 认证中间件
+// 浏览器 WebSocket API 无法设置 Authorization 头，允许通过查询参数 ?token= 传递
+func WebSocketAuth(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := ""
+		if authHeader := c.GetHeader("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+			tokenString = strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+		} else {
+			tokenString = strings.TrimSpace(c.Query("token"))
 		}
-		// 校验通过，将 user_id 写入 gin.Context
-		c.Set(string(UserIDKey), userID)
-		// context.WithValue 中设置 user_id
-		ctx := context.WithValue(c.Request.Context(), UserIDKey, userID)
-		c.Request = c.Request.WithContext(ctx)
+		authenticate(c, cfg, tokenString)
+	}
+}
 
+// authenticate 校验 Token 并写入 user_id，失败时返回 401
+func authenticate(c *gin.Context, cfg *config.Config, tokenString string) {
+	// dev 模式：直接放行并写入固定 user_id
+	if cfg != nil && strings.EqualFold(cfg.Server.Environment, "development") {
+		setUserID(c, "dev-user-123")
 		c.Next()
+		return
 	}
+
+	if tokenString == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
+		return
+	}
+	if cfg == nil || strings.TrimSpace(cfg.JWT.Secret) == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	token, err := parser.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(cfg.JWT.Secret), nil
+	})
+	if err != nil || !token.Valid {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok || strings.TrimSpace(userID) == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
+		return
+	}
+	// 校验通过，将 user_id 写入 gin.Context
+	setUserID(c, userID)
+
+	c.Next()
+}
+
+// setUserID 将 user_id 同时写入 gin.Context 与 request context
+func setUserID(c *gin.Context, userID string) {
+	c.Set(string(UserIDKey), userID)
+	ctx := context.WithValue(c.Request.Context(), UserIDKey, userID)
+	c.Request = c.Request.WithContext(ctx)
 }
@@ -2,16 +2,31 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/gin-gonic/gin"
 )
 
+// corsAllowHeaders 允许客户端携带的请求头
+var corsAllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", HeaderIdempotencyKey}
+
+// corsExposeHeaders 允许浏览器端脚本读取的响应头（会话、配额、限流、幂等重放与耗时等）
+var corsExposeHeaders = []string{
+	"Content-Length", "Content-Type", "X-Trace-ID",
+	"X-Session-ID", "X-Session-Turn", "X-Session-Remaining-Turns", "X-Session-Completed",
+	"X-User-Language", "X-Reply-Translation", "X-Turn-Failed-Stage", "Server-Timing",
+	HeaderQuotaLimit, HeaderQuotaRemaining, HeaderQuotaReset, HeaderQuotaPlan,
+	HeaderRateLimitLimit, HeaderRateLimitRemaining, HeaderRateLimitReset, HeaderRateLimitPolicy, "Retry-After",
+	HeaderIdempotentReplayed,
+}
+
 // CORSMiddleware 跨域资源共享中间件
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Request-ID")
-		c.Header("Access-Control-Expose-Headers", "Content-Length, Content-Type")
+		c.Header("Access-Control-Allow-Headers", strings.Join(corsAllowHeaders, ", "))
+		c.Header("Access-Control-Expose-Headers", strings.Join(corsExposeHeaders, ", "))
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400")
 
@@ -40,8 +55,8 @@ func DefaultCORSConfig() CORSConfig {
 	return CORSConfig{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type"},
+		AllowHeaders:     corsAllowHeaders,
+		ExposeHeaders:    corsExposeHeaders,
 		AllowCredentials: true,
 		MaxAge:           86400,
 	}
@@ -0,0 +1,253 @@
+// Package middleware 提供幂等请求中间件
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"pronunciation-correction-system/internal/cache"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// 幂等请求头
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	idempotencyLockTTL       = 10 * time.Second // 仅保护"检查记录 + 写入处理中状态"
+	idempotencyInFlightRetry = "2"              // 首个请求处理中时建议的重试秒数
+)
+
+// idempotencyReplayHeaders 随响应保存并在回放时恢复的响应头（客户端依赖会话头继续对话）
+var idempotencyReplayHeaders = []string{
+	"X-Session-ID", "X-Session-Turn", "X-Session-Remaining-Turns", "X-Session-Completed",
+	"X-User-Language", "X-Reply-Translation", "X-Turn-Failed-Stage",
+}
+
+// IdempotencyMiddleware 幂等请求中间件
+// 客户端携带 Idempotency-Key 时：
+//   - 首个请求写入处理中状态并正常执行，成功（2xx）后保存响应 24 小时（含会话等白名单响应头）
+//   - 同一 Key 重复请求直接返回保存的响应，不再调用讯飞 / Qwen / CosyVoice，也不消耗配额
+//   - 首个请求仍在处理中时返回 409；同一 Key 携带不同请求内容时返回 422
+//   - 首个请求失败（非 2xx）时删除记录，允许使用同一 Key 重试
+//
+// 注意：此中间件依赖 Auth，且需注册在限流 / 配额中间件之前
+type IdempotencyMiddleware struct {
+	store *cache.IdempotencyCache // 可为 nil（Redis 不可用时不做幂等）
+	lock  *cache.DistributedLock
+}
+
+// NewIdempotencyMiddleware 创建 IdempotencyMiddleware
+func NewIdempotencyMiddleware(store *cache.IdempotencyCache, lock *cache.DistributedLock) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{store: store, lock: lock}
+}
+
+// Handle 返回幂等处理中间件（按 方法 + 路由 区分 Key 的作用域）
+func (m *IdempotencyMiddleware) Handle() gin.HandlerFunc {
+	if m == nil || m.store == nil || m.lock == nil {
+		return passThrough
+	}
+
+	return func(c *gin.Context) {
+		idemKey := strings.TrimSpace(c.GetHeader(HeaderIdempotencyKey))
+		userID := c.GetString(string(UserIDKey))
+		if idemKey == "" || userID == "" {
+			c.Next()
+			return
+		}
+		if len(idemKey) > maxIdempotencyKeyLength {
+			abortIdempotency(c, http.StatusBadRequest, apperr.CodeInvalidParam, "Idempotency-Key is too long")
+			return
+		}
+
+		ctx := c.Request.Context()
+		keyHash := hashIdempotencyKey(c.Request.Method+" "+c.FullPath(), idemKey)
+
+		// 步骤 1：计算请求内容摘要（读取后恢复 Body 供 handler 使用）
+		requestHash, err := hashRequestBody(c)
+		if err != nil {
+			logger.ErrorContext(ctx, "idempotency read body failed", "error", err)
+			abortIdempotency(c, http.StatusBadRequest, apperr.CodeInvalidParam, "failed to read request body")
+			return
+		}
+
+		// 步骤 2：加锁检查记录，并写入处理中状态
+		record, proceed := m.reserve(c, userID, keyHash, requestHash)
+		if !proceed {
+			return
+		}
+		if record != nil {
+			// 重复请求：回放首个请求的响应
+			logger.InfoContext(ctx, "idempotent request replayed", "status", record.StatusCode)
+			for name, value := range record.Headers {
+				c.Header(name, value)
+			}
+			c.Header(HeaderIdempotentReplayed, "true")
+			c.Data(record.StatusCode, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		// 步骤 3：执行请求并捕获响应
+		writer := &idempotencyResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// 步骤 4：成功则保存响应，失败（含已写出 200 后失败的流式响应）则删除记录允许重试
+		saveCtx := context.WithoutCancel(ctx)
+		status := responseStatus(c)
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			if err := m.store.Delete(saveCtx, userID, keyHash); err != nil {
+				logger.ErrorContext(saveCtx, "idempotency delete record failed", "error", err)
+			}
+			return
+		}
+		if err := m.store.SetCompleted(saveCtx, userID, keyHash, &cache.IdempotencyRecord{
+			RequestHash: requestHash,
+			StatusCode:  status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Headers:     replayHeaders(c.Writer.Header()),
+			Body:        writer.body.Bytes(),
+			CreatedAt:   time.Now(),
+		}); err != nil {
+			logger.ErrorContext(saveCtx, "idempotency save record failed", "error", err)
+		}
+	}
+}
+
+// reserve 在分布式锁保护下检查幂等记录
+// 返回 (已完成的记录, true) 表示回放；(nil, true) 表示首个请求继续执行；(_, false) 表示已写出错误响应
+// Redis 异常时放行请求（不做幂等），不阻塞主流程
+func (m *IdempotencyMiddleware) reserve(c *gin.Context, userID, keyHash, requestHash string) (*cache.IdempotencyRecord, bool) {
+	ctx := c.Request.Context()
+	lockKey := cache.IdempotencyLockKey(userID, keyHash)
+	lockValue := uuid.NewString()
+
+	acquired, err := m.lock.TryLock(ctx, lockKey, lockValue, idempotencyLockTTL)
+	if err != nil {
+		logger.ErrorContext(ctx, "idempotency lock failed, request allowed", "error", err)
+		return nil, true
+	}
+	if !acquired {
+		c.Header(HeaderRetryAfter, idempotencyInFlightRetry)
+		abortIdempotency(c, http.StatusConflict, apperr.CodeConflict, "a request with this Idempotency-Key is in progress")
+		return nil, false
+	}
+	defer func() {
+		if err := m.lock.Unlock(context.WithoutCancel(ctx), lockKey, lockValue); err != nil {
+			logger.ErrorContext(ctx, "idempotency unlock failed", "error", err)
+		}
+	}()
+
+	record, err := m.store.Get(ctx, userID, keyHash)
+	if err != nil {
+		logger.ErrorContext(ctx, "idempotency get record failed, request allowed", "error", err)
+		return nil, true
+	}
+	if record != nil {
+		if record.RequestHash != requestHash {
+			abortIdempotency(c, http.StatusUnprocessableEntity, apperr.CodeIdempotencyKeyReused, apperr.ErrIdempotencyKeyReused.Message)
+			return nil, false
+		}
+		if record.Status == cache.IdempotencyStatusCompleted {
+			return record, true
+		}
+		c.Header(HeaderRetryAfter, idempotencyInFlightRetry)
+		abortIdempotency(c, http.StatusConflict, apperr.CodeConflict, "a request with this Idempotency-Key is in progress")
+		return nil, false
+	}
+
+	if err := m.store.SetProcessing(ctx, userID, keyHash, requestHash); err != nil {
+		logger.ErrorContext(ctx, "idempotency set processing failed, request allowed", "error", err)
+	}
+	return nil, true
+}
+
+// idempotencyResponseWriter 在写出响应的同时保留一份副本
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 写出响应并记录
+func (w *idempotencyResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 写出响应并记录
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// replayHeaders 提取白名单内已设置的响应头
+func replayHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string)
+	for _, name := range idempotencyReplayHeaders {
+		if value := header.Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	return headers
+}
+
+// hashIdempotencyKey 计算 Key 摘要（作用域 + 客户端 Key，避免特殊字符进入 Redis Key）
+func hashIdempotencyKey(scope, idemKey string) string {
+	sum := sha256.Sum256([]byte(scope + "\n" + idemKey))
+	return hex.EncodeToString(sum[:16])
+}
+
+// hashRequestBody 计算请求内容摘要
+// multipart 请求按 字段名 + 文件名 + 内容 计算，忽略每次重试可能变化的 boundary
+func hashRequestBody(c *gin.Context) (string, error) {
+	if c.Request.Body == nil {
+		return hex.EncodeToString(sha256.New().Sum(nil)), nil
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return "", err
+	}
+	_ = c.Request.Body.Close()
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	h := sha256.New()
+	mediaType, params, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err == nil && strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return hex.EncodeToString(h.Sum(nil)), nil
+			}
+			if err != nil {
+				break // 格式异常时回退到原始 Body 摘要
+			}
+			h.Write([]byte(part.FormName() + "\x00" + part.FileName() + "\x00"))
+			if _, err := io.Copy(h, part); err != nil {
+				break
+			}
+			h.Write([]byte{0})
+		}
+		h.Reset()
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// abortIdempotency 返回错误响应并中止
+func abortIdempotency(c *gin.Context, status, code int, message string) {
+	c.AbortWithStatusJSON(status, gin.H{"code": code, "message": message, "data": nil})
+}
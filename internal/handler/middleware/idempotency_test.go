@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestReplayHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Session-ID", "s1")
+	header.Set("X-Session-Turn", "3")
+	header.Set("X-Quota-Remaining", "7")
+
+	got := replayHeaders(header)
+	want := map[string]string{"X-Session-ID": "s1", "X-Session-Turn": "3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replayHeaders = %v, want %v", got, want)
+	}
+}
+
+func TestHashIdempotencyKeyScoped(t *testing.T) {
+	a := hashIdempotencyKey("POST /api/v1/chat/MVP", "k1")
+	b := hashIdempotencyKey("POST /api/v1/evaluate/MVP", "k1")
+	if a == b {
+		t.Error("same key on different routes must hash differently")
+	}
+	if a != hashIdempotencyKey("POST /api/v1/chat/MVP", "k1") {
+		t.Error("hash must be stable")
+	}
+}
@@ -4,6 +4,7 @@ package middleware
 import (
 	"fmt"
 	"log/slog"
+	"net/url"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -94,12 +95,25 @@ func LoggerMiddleware() gin.HandlerFunc {
 	}
 }
 
-// fullPath 拼接路径和查询参数
+// sensitiveQueryParams 不写入日志的查询参数（如 WebSocket 通过 ?token= 传递的 JWT）
+var sensitiveQueryParams = []string{"token", "access_token"}
+
+// fullPath 拼接路径和查询参数（去除敏感参数；查询串无法解析时只记录路径）
 func fullPath(path, query string) string {
-	if query != "" {
-		return path + "?" + query
+	if query == "" {
+		return path
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return path
+	}
+	for _, name := range sensitiveQueryParams {
+		values.Del(name)
+	}
+	if len(values) == 0 {
+		return path
 	}
-	return path
+	return path + "?" + values.Encode()
 }
 
 // formatLatency 格式化耗时为可读字符串
@@ -0,0 +1,24 @@
+package middleware
+
+import "testing"
+
+func TestFullPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		query string
+		want  string
+	}{
+		{"no query", "/api/v1/chat/sessions", "", "/api/v1/chat/sessions"},
+		{"plain query", "/api/v1/chat/sessions", "page=2&page_size=10", "/api/v1/chat/sessions?page=2&page_size=10"},
+		{"token removed", "/ws/chat", "token=eyJhbGciOiJIUzI1NiJ9.x.y&session_id=sess_1", "/ws/chat?session_id=sess_1"},
+		{"only token", "/ws/chat", "token=eyJhbGciOiJIUzI1NiJ9.x.y", "/ws/chat"},
+		{"access token removed", "/ws/chat", "access_token=abc", "/ws/chat"},
+		{"unparsable query", "/ws/chat", "token=%zz", "/ws/chat"},
+	}
+	for _, tt := range tests {
+		if got := fullPath(tt.path, tt.query); got != tt.want {
+			t.Errorf("%s: fullPath() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Package middleware 提供需要依赖注入的中间件聚合
+package middleware
+
+// Middlewares 依赖业务服务的路由级中间件聚合
+// 与 handler.Handlers 一样在 app 中完成注入，再传递给 router
+type Middlewares struct {
+	Quota     *QuotaMiddleware     // 每日配额
+	RateLimit *RateLimitMiddleware // 接口限流
+
+	Idempotency *IdempotencyMiddleware // 幂等请求（音频提交接口）
+	Admin       *AdminMiddleware       // 管理后台权限
+}
@@ -0,0 +1,97 @@
+// Package middleware 提供每日配额中间件
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/service"
+)
+
+// 配额响应头
+const (
+	HeaderQuotaLimit     = "X-Quota-Limit"
+	HeaderQuotaRemaining = "X-Quota-Remaining"
+	HeaderQuotaReset     = "X-Quota-Reset" // 重置时间（Unix 秒）
+	HeaderQuotaPlan      = "X-Quota-Plan"
+)
+
+// QuotaConsumedKey 本次请求实际扣减了配额的功能名（无限套餐不设置）
+// 异步处理的接口在后台失败时据此退还配额
+const QuotaConsumedKey contextKey = "quota_consumed"
+
+// QuotaMiddleware 每日配额中间件
+// 在调用讯飞 / Qwen / CosyVoice 等付费服务前消耗配额，服务端失败（5xx）时回退
+//
+// 注意：此中间件依赖 Auth，需要注册在认证路由组内
+type QuotaMiddleware struct {
+	quotaService service.QuotaService
+}
+
+// NewQuotaMiddleware 创建 QuotaMiddleware
+func NewQuotaMiddleware(quotaService service.QuotaService) *QuotaMiddleware {
+	return &QuotaMiddleware{quotaService: quotaService}
+}
+
+// Require 返回消耗指定功能配额的中间件
+func (m *QuotaMiddleware) Require(feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m == nil || m.quotaService == nil {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		userID := c.GetString(string(UserIDKey))
+		if userID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 401, "message": "unauthorized", "data": nil})
+			return
+		}
+
+		// 步骤 1：消耗配额（Redis 异常时放行，不阻塞主流程）
+		decision, err := m.quotaService.Consume(ctx, userID, feature)
+		if err != nil {
+			logger.ErrorContext(ctx, "quota consume failed, request allowed", "feature", feature, "error", err)
+			c.Next()
+			return
+		}
+
+		// 步骤 2：写入配额响应头（必须在 handler 写出响应前设置）
+		c.Header(HeaderQuotaPlan, decision.Plan)
+		if !decision.Unlimited {
+			c.Header(HeaderQuotaLimit, strconv.Itoa(decision.Limit))
+			c.Header(HeaderQuotaRemaining, strconv.Itoa(decision.Remaining))
+			c.Header(HeaderQuotaReset, strconv.FormatInt(decision.ResetAt.Unix(), 10))
+		}
+
+		if !decision.Allowed {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"code":    http.StatusTooManyRequests,
+				"message": "daily " + feature + " quota exceeded",
+				"data":    nil,
+			})
+			return
+		}
+		if !decision.Unlimited {
+			c.Set(string(QuotaConsumedKey), feature)
+		}
+
+		c.Next()
+
+		// 步骤 3：服务端原因失败时回退配额（客户端参数错误不回退；流式响应以错误事件的状态码为准）
+		status := responseStatus(c)
+		if decision.Unlimited || status < http.StatusInternalServerError {
+			return
+		}
+		refundCtx := context.WithoutCancel(ctx)
+		if err := m.quotaService.Refund(refundCtx, userID, feature); err != nil {
+			logger.ErrorContext(refundCtx, "quota refund failed", "feature", feature, "error", err)
+			return
+		}
+		logger.InfoContext(refundCtx, "quota refunded", "feature", feature, "status", status)
+	}
+}
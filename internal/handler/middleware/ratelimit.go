@@ -0,0 +1,134 @@
+// Package middleware 提供接口限流中间件
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/cache"
+	"pronunciation-correction-system/internal/config"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// 限流响应头（IETF RateLimit header fields 草案）
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset" // 距最早一次请求滑出窗口的秒数
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// 限流主体
+const (
+	rateLimitKeyByUser = "user"
+	rateLimitKeyByIP   = "ip"
+)
+
+// RateLimitMiddleware 接口限流中间件（Redis Lua 滑动窗口）
+// 规则来自配置 rate_limit.rules，路由注册时按规则名引用
+type RateLimitMiddleware struct {
+	limiter *cache.RateLimitCache // 可为 nil（Redis 不可用时不限流）
+	cfg     config.RateLimitConfig
+}
+
+// NewRateLimitMiddleware 创建 RateLimitMiddleware
+func NewRateLimitMiddleware(limiter *cache.RateLimitCache, cfg config.RateLimitConfig) *RateLimitMiddleware {
+	return &RateLimitMiddleware{limiter: limiter, cfg: cfg}
+}
+
+// Limit 返回按指定规则限流的中间件
+// 规则不存在、限流关闭或 Redis 不可用时直接放行
+func (m *RateLimitMiddleware) Limit(ruleName string) gin.HandlerFunc {
+	rule, ok := m.rule(ruleName)
+	if !ok {
+		return passThrough
+	}
+	policy := strconv.Itoa(rule.Limit) + ";w=" + strconv.Itoa(int(rule.Window.Seconds()))
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		// 步骤 1：确定限流主体（用户 ID 或客户端 IP）
+		subject := rateLimitSubject(c, rule.KeyBy)
+
+		// 步骤 2：Lua 脚本原子检查并计数（Redis 异常时放行）
+		allowed, info, err := m.limiter.IsAllowed(ctx, ruleName, subject, rule.Limit, rule.Window)
+		if err != nil {
+			logger.ErrorContext(ctx, "rate limit check failed, request allowed", "rule", ruleName, "error", err)
+			c.Next()
+			return
+		}
+
+		// 步骤 3：写入限流响应头
+		c.Header(HeaderRateLimitLimit, strconv.Itoa(info.Limit))
+		c.Header(HeaderRateLimitRemaining, strconv.Itoa(info.Remaining))
+		c.Header(HeaderRateLimitReset, strconv.FormatInt(info.ResetAfter, 10))
+		c.Header(HeaderRateLimitPolicy, policy)
+
+		if !allowed {
+			c.Header(HeaderRetryAfter, strconv.FormatInt(info.RetryAfter, 10))
+			logger.WarnContext(ctx, "rate limited", "rule", ruleName, "subject", subject)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"code":    apperr.CodeTooManyRequests,
+				"message": apperr.ErrTooManyRequests.Message,
+				"data":    nil,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AllowUser 按指定规则为用户计数一次，返回是否放行与被拒绝时建议的重试秒数
+// 供连接内的多次操作使用（如 WebSocket 每轮对话）；规则不存在、限流关闭或 Redis 异常时放行
+func (m *RateLimitMiddleware) AllowUser(ctx context.Context, ruleName, userID string) (bool, int64) {
+	rule, ok := m.rule(ruleName)
+	if !ok {
+		return true, 0
+	}
+	subject := rateLimitKeyByUser + ":" + userID
+	allowed, info, err := m.limiter.IsAllowed(ctx, ruleName, subject, rule.Limit, rule.Window)
+	if err != nil {
+		logger.ErrorContext(ctx, "rate limit check failed, request allowed", "rule", ruleName, "error", err)
+		return true, 0
+	}
+	if !allowed {
+		logger.WarnContext(ctx, "rate limited", "rule", ruleName, "subject", subject)
+		return false, info.RetryAfter
+	}
+	return true, 0
+}
+
+// rule 读取生效的限流规则（限流关闭、Redis 不可用或规则无效时返回 false）
+func (m *RateLimitMiddleware) rule(ruleName string) (config.RateLimitRule, bool) {
+	if m == nil || m.limiter == nil || !m.cfg.Enabled {
+		return config.RateLimitRule{}, false
+	}
+	rule, ok := m.cfg.Rules[ruleName]
+	if !ok || rule.Limit <= 0 || rule.Window <= 0 {
+		return config.RateLimitRule{}, false
+	}
+	return rule, true
+}
+
+// rateLimitSubject 限流主体：key_by=user 且已登录时使用用户 ID，否则使用客户端 IP
+func rateLimitSubject(c *gin.Context, keyBy string) string {
+	if !strings.EqualFold(keyBy, rateLimitKeyByIP) {
+		if userID := c.GetString(string(UserIDKey)); userID != "" {
+			return rateLimitKeyByUser + ":" + userID
+		}
+	}
+	return rateLimitKeyByIP + ":" + c.ClientIP()
+}
+
+// passThrough 不做任何处理的中间件
+func passThrough(c *gin.Context) {
+	c.Next()
+}
@@ -0,0 +1,23 @@
+// Package middleware 提供流式响应的失败标记
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// StreamFailedKey 流式响应（如 SSE）已写出 200 状态后处理失败时，由 handler 记录失败对应的状态码
+// 幂等与配额中间件据此按失败处理：释放幂等记录而不是保存，服务端原因失败时退还配额
+const StreamFailedKey contextKey = "stream_failed"
+
+// MarkStreamFailed 标记流式响应失败（status 为错误事件中的状态码）
+func MarkStreamFailed(c *gin.Context, status int) {
+	c.Set(string(StreamFailedKey), status)
+}
+
+// responseStatus 本次请求的实际结果状态码：流式响应失败时为记录的状态码，否则为写出的 HTTP 状态码
+func responseStatus(c *gin.Context) int {
+	if status := c.GetInt(string(StreamFailedKey)); status != 0 {
+		return status
+	}
+	return c.Writer.Status()
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestResponseStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Status(http.StatusOK)
+	if got := responseStatus(c); got != http.StatusOK {
+		t.Errorf("responseStatus = %d, want %d", got, http.StatusOK)
+	}
+
+	MarkStreamFailed(c, http.StatusBadGateway)
+	if got := responseStatus(c); got != http.StatusBadGateway {
+		t.Errorf("responseStatus after stream failure = %d, want %d", got, http.StatusBadGateway)
+	}
+}
@@ -4,6 +4,8 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 
+	"pronunciation-correction-system/internal/handler/middleware"
+	"pronunciation-correction-system/internal/pkg/logger"
 	"pronunciation-correction-system/internal/service"
 )
 
@@ -17,16 +19,44 @@ func NewReportHandler(reportService service.ReportService) *ReportHandler {
 	return &ReportHandler{reportService: reportService}
 }
 
+// reportMVPBody 同步生成学习报告请求体
+type reportMVPBody struct {
+	ReportType string `json:"report_type"` // weekly / monthly / custom
+	StartDate  string `json:"start_date"`  // YYYY-MM-DD（custom 必填）
+	EndDate    string `json:"end_date"`    // YYYY-MM-DD（custom 必填）
+}
+
 // ReportMVP POST /api/v1/report/MVP
 // 同步生成学习报告 MVP（统计 + LLM，直接返回报告内容）
 func (h *ReportHandler) ReportMVP(c *gin.Context) {
-	// TODO: Step2 实现
-	// 1. 解析 JSON 请求体: report_type, start_date, end_date
-	// 2. 从 Context 获取 user_id
-	// 3. 调用 h.reportService.ReportMVP(ctx, req)
-	// 4. 成功：OK(c, result)
-	// 5. 失败：InternalError(c, err.Error())
-	InternalError(c, "not implemented")
+	// 步骤 1：解析 JSON 请求体
+	var body reportMVPBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		BadRequest(c, "invalid request body")
+		return
+	}
+
+	// 步骤 2：从 Context 获取 user_id
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	// 步骤 3：调用 Service
+	result, err := h.reportService.ReportMVP(c.Request.Context(), &service.ReportMVPRequest{
+		ReportType: body.ReportType,
+		StartDate:  body.StartDate,
+		EndDate:    body.EndDate,
+		UserID:     userID.(string),
+	})
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "report mvp failed", "report_type", body.ReportType, "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, result)
 }
 
 // GenerateReport POST /api/v1/report/generate
@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
 )
 
 // Response 统一响应结构
@@ -84,3 +89,36 @@ func NotFound(c *gin.Context, message string) {
 func InternalError(c *gin.Context, message string) {
 	Fail(c, http.StatusInternalServerError, 500, message)
 }
+
+// ServiceError 根据 Service 返回的业务错误响应对应的 HTTP 状态码（支持被 fmt.Errorf %w 包装的 AppError）
+// 非 AppError 统一按 500 处理，响应中只返回通用消息，原始错误（SQL、第三方服务信息等）仅记录日志
+func ServiceError(c *gin.Context, err error) {
+	var appErr *apperr.AppError
+	if !errors.As(err, &appErr) {
+		logger.ErrorContext(c.Request.Context(), "unexpected service error", "path", c.FullPath(), "error", err)
+	}
+	status, message := apperr.PublicError(err)
+	Fail(c, status, status, message)
+}
+
+// parsePage 解析分页查询参数 page / page_size
+// page 默认 1；page_size 默认 defaultPageSize，上限 100
+func parsePage(c *gin.Context, defaultPageSize int) (page, pageSize int) {
+	page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page <= 0 {
+		page = 1
+	}
+	pageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)))
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return page, pageSize
+}
+
+// debugResponse 服务以 debug 模式运行（server.mode = debug）时，响应中附带调试信息（如各阶段耗时）
+func debugResponse() bool {
+	return gin.IsDebugging()
+}
@@ -0,0 +1,120 @@
+// Package handler 提供单词间隔复习 HTTP 处理器
+package handler
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/handler/middleware"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/service"
+)
+
+// ReviewHandler 单词间隔复习处理器
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+// NewReviewHandler 创建 ReviewHandler
+func NewReviewHandler(reviewService service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService}
+}
+
+// GetTodayReviews GET /api/v1/evaluate/review/today
+// 获取今日到期的单词复习列表（含示范音频）
+func (h *ReviewHandler) GetTodayReviews(c *gin.Context) {
+	// 步骤 1：从 Context 获取 user_id
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	// 步骤 2：解析 limit（可选）
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+
+	// 步骤 3：设置超时（可能需要生成示范音频）
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	// 步骤 4：调用 Service
+	resp, err := h.reviewService.GetTodayReviews(ctx, userID.(string), limit)
+	if err != nil {
+		logger.ErrorContext(ctx, "get today reviews failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
+
+// SubmitReview POST /api/v1/evaluate/review/submit
+// 提交单词复习录音，评测后按 SM-2 重新调度
+func (h *ReviewHandler) SubmitReview(c *gin.Context) {
+	// 步骤 1：解析 multipart/form-data
+	reviewID := strings.TrimSpace(c.PostForm("review_id"))
+	if reviewID == "" {
+		BadRequest(c, "review_id is required")
+		return
+	}
+	fileHeader, err := c.FormFile("audio_file")
+	if err != nil {
+		BadRequest(c, "audio_file is required")
+		return
+	}
+	audioType := strings.ToLower(strings.TrimSpace(c.PostForm("audio_type")))
+	if audioType == "" {
+		audioType = "wav"
+	}
+
+	// 步骤 2：读取音频数据
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "submit review open file failed", "error", err)
+		InternalError(c, "failed to read audio file")
+		return
+	}
+	defer file.Close()
+
+	audioData, err := io.ReadAll(file)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "submit review read file failed", "error", err)
+		InternalError(c, "failed to read audio data")
+		return
+	}
+
+	// 步骤 3：WAV 格式去掉 44 字节 header（讯飞评测需 PCM 裸数据）
+	if audioType == "wav" && len(audioData) > 44 {
+		audioData = audioData[44:]
+	}
+
+	// 步骤 4：从 Context 获取 user_id
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	// 步骤 5：设置超时并调用 Service
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	resp, err := h.reviewService.SubmitReview(ctx, &service.SubmitReviewRequest{
+		ReviewID:  reviewID,
+		AudioData: audioData,
+		AudioType: audioType,
+		UserID:    userID.(string),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "submit review failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
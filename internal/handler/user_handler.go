@@ -4,17 +4,20 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 
+	"pronunciation-correction-system/internal/handler/middleware"
+	"pronunciation-correction-system/internal/pkg/logger"
 	"pronunciation-correction-system/internal/service"
 )
 
 // UserHandler 用户信息处理器
 type UserHandler struct {
-	userService service.UserService
+	userService  service.UserService
+	quotaService service.QuotaService
 }
 
 // NewUserHandler 创建 UserHandler
-func NewUserHandler(userService service.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService service.UserService, quotaService service.QuotaService) *UserHandler {
+	return &UserHandler{userService: userService, quotaService: quotaService}
 }
 
 // GetProfile GET /api/v1/user/profile
@@ -39,3 +42,22 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	// 5. 失败：BadRequest / InternalError
 	InternalError(c, "not implemented")
 }
+
+// GetQuota GET /api/v1/user/quota
+// 获取当前登录用户今日各功能配额使用情况
+func (h *UserHandler) GetQuota(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	resp, err := h.quotaService.GetUserQuota(c.Request.Context(), userID.(string))
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get user quota failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OK(c, resp)
+}
@@ -0,0 +1,68 @@
+// Package handler 提供个人词汇表 HTTP 处理器
+package handler
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/handler/middleware"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/service"
+)
+
+// VocabularyHandler 个人词汇表处理器
+type VocabularyHandler struct {
+	vocabularyService service.VocabularyService
+}
+
+// NewVocabularyHandler 创建 VocabularyHandler
+func NewVocabularyHandler(vocabularyService service.VocabularyService) *VocabularyHandler {
+	return &VocabularyHandler{vocabularyService: vocabularyService}
+}
+
+// GetKnownWords GET /api/v1/report/vocabulary/known
+// 获取会的单词（出现次数达到阈值，支持 band 过滤）
+func (h *VocabularyHandler) GetKnownWords(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	page, pageSize := parsePage(c, 20)
+	req := &service.KnownWordsRequest{
+		UserID:   userID.(string),
+		Band:     strings.TrimSpace(c.Query("band")),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	items, total, err := h.vocabularyService.GetKnownWords(c.Request.Context(), req)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get known words failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OKPage(c, items, page, pageSize, total)
+}
+
+// GetNewWords GET /api/v1/report/vocabulary/new
+// 获取本周首次出现的单词
+func (h *VocabularyHandler) GetNewWords(c *gin.Context) {
+	userID, exists := c.Get(string(middleware.UserIDKey))
+	if !exists {
+		Unauthorized(c)
+		return
+	}
+
+	page, pageSize := parsePage(c, 20)
+	items, total, err := h.vocabularyService.GetNewWords(c.Request.Context(), userID.(string), page, pageSize)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "get new words failed", "error", err)
+		ServiceError(c, err)
+		return
+	}
+
+	OKPage(c, items, page, pageSize, total)
+}
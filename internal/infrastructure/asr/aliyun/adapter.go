@@ -35,6 +35,12 @@ func (a *AliyunASRAdapter) RecognizeAudioStream(ctx context.Context, audioData [
 	return a.client.recognizeStream(ctx, audioData, format, sampleRate)
 }
 
+// RecognizeLiveAudio 实时识别增量音频
+// 音频块到达即发送，调用方关闭 audio 通道后结束识别
+func (a *AliyunASRAdapter) RecognizeLiveAudio(ctx context.Context, audio <-chan []byte, format string, sampleRate int) (<-chan *domain.ASRStreamEvent, error) {
+	return a.client.recognizeLive(ctx, audio, format, sampleRate)
+}
+
 // Close 关闭客户端，释放资源
 func (a *AliyunASRAdapter) Close() error {
 	return a.client.close()
@@ -105,8 +105,34 @@ func (c *internalClient) recognizeAudio(ctx context.Context, audioData []byte, f
 }
 
 // recognizeStream 流式识别音频数据
-// 启动 WebSocket 会话，并发发送音频和接收结果
+// 将完整音频按实时速率切片后交给实时识别，并发发送音频和接收结果
 func (c *internalClient) recognizeStream(ctx context.Context, audioData []byte, format string, sampleRate int) (<-chan *domain.ASRStreamEvent, error) {
+	// 发送方在识别结束（或 ctx 取消）后随之退出
+	feedCtx, feedCancel := context.WithCancel(ctx)
+	eventCh, err := c.recognizeLive(feedCtx, paceAudio(feedCtx, audioData), format, sampleRate)
+	if err != nil {
+		feedCancel()
+		return nil, err
+	}
+
+	out := make(chan *domain.ASRStreamEvent, 64)
+	go func() {
+		defer close(out)
+		defer feedCancel()
+		for event := range eventCh {
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// recognizeLive 实时识别增量到达的音频
+// 启动 WebSocket 会话，音频块到达即发送，同时接收结果；audio 通道关闭后发送 finish-task
+func (c *internalClient) recognizeLive(ctx context.Context, audio <-chan []byte, format string, sampleRate int) (<-chan *domain.ASRStreamEvent, error) {
 	// 1. 建立 WebSocket 连接
 	conn, err := c.connectWebSocket(ctx)
 	if err != nil {
@@ -133,7 +159,7 @@ func (c *internalClient) recognizeStream(ctx context.Context, audioData []byte,
 	eventCh := make(chan *domain.ASRStreamEvent, 64)
 
 	// 6. 启动并发 goroutine：发送音频 + 接收结果
-	go c.runSession(ctx, conn, handler, taskID, audioData, eventCh)
+	go c.runSession(ctx, conn, handler, taskID, audio, eventCh)
 
 	return eventCh, nil
 }
@@ -298,7 +324,7 @@ func (c *internalClient) runSession(
 	conn *websocket.Conn,
 	handler *eventHandler,
 	taskID string,
-	audioData []byte,
+	audio <-chan []byte,
 	eventCh chan<- *domain.ASRStreamEvent,
 ) {
 	defer close(eventCh)
@@ -325,7 +351,7 @@ func (c *internalClient) runSession(
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		c.sendAudioAndFinish(sendCtx, conn, taskID, audioData)
+		c.sendAudioAndFinish(sendCtx, conn, taskID, audio)
 	}()
 
 	// 等待接收完成（task-finished/task-failed），则可以取消发送
@@ -341,53 +367,37 @@ func (c *internalClient) runSession(
 
 // ===================== 发送音频 =====================
 
-// sendAudioAndFinish 发送音频数据并发送 finish-task
+// sendAudioAndFinish 转发音频数据块，通道关闭后发送 finish-task
 func (c *internalClient) sendAudioAndFinish(
 	ctx context.Context,
 	conn *websocket.Conn,
 	taskID string,
-	audioData []byte,
+	audio <-chan []byte,
 ) {
-	// 分片发送音频数据
-	chunkSize := defaultSendChunkSize
-	// 每次发送间隔，模拟实时音频流速率
-	interval := time.Duration(defaultSendInterval) * time.Millisecond
-
-	for offset := 0; offset < len(audioData); offset += chunkSize {
+	total := 0
+	for {
+		var chunk []byte
+		var ok bool
 		select {
 		case <-ctx.Done():
 			logger.InfoContext(ctx, "[AliyunASR] Audio sending canceled, taskID=%s", taskID)
 			return
-		default:
+		case chunk, ok = <-audio:
 		}
-
-		end := offset + chunkSize
-		if end > len(audioData) {
-			end = len(audioData)
+		if !ok {
+			break
 		}
-
-		// 检查上下文是否已取消
-		select {
-		case <-ctx.Done():
-			return
-		default:
+		if len(chunk) == 0 {
+			continue
 		}
-		if err := conn.WriteMessage(websocket.BinaryMessage, audioData[offset:end]); err != nil {
+		if err := conn.WriteMessage(websocket.BinaryMessage, chunk); err != nil {
 			logger.ErrorContext(ctx, "[AliyunASR] Send audio chunk failed: %v, taskID=%s", err, taskID)
 			return
 		}
-
-		// 模拟实时音频流速率
-		if offset+chunkSize < len(audioData) {
-			select {
-			case <-time.After(interval):
-			case <-ctx.Done():
-				return
-			}
-		}
+		total += len(chunk)
 	}
 
-	logger.InfoContext(ctx, "[AliyunASR] Audio data sent, total=%d bytes, taskID=%s", len(audioData), taskID)
+	logger.InfoContext(ctx, "[AliyunASR] Audio data sent, total=%d bytes, taskID=%s", total, taskID)
 
 	// 发送 finish-task 指令
 	if err := c.sendFinishTask(ctx, conn, taskID); err != nil {
@@ -395,6 +405,40 @@ func (c *internalClient) sendAudioAndFinish(
 	}
 }
 
+// paceAudio 将完整音频切片后按实时速率写入通道，写完后关闭通道
+func paceAudio(ctx context.Context, audioData []byte) <-chan []byte {
+	// 分片发送音频数据
+	chunkSize := defaultSendChunkSize
+	// 每次发送间隔，模拟实时音频流速率
+	interval := time.Duration(defaultSendInterval) * time.Millisecond
+
+	audio := make(chan []byte)
+	go func() {
+		defer close(audio)
+		for offset := 0; offset < len(audioData); offset += chunkSize {
+			end := offset + chunkSize
+			if end > len(audioData) {
+				end = len(audioData)
+			}
+			select {
+			case audio <- audioData[offset:end]:
+			case <-ctx.Done():
+				return
+			}
+
+			// 模拟实时音频流速率
+			if end < len(audioData) {
+				select {
+				case <-time.After(interval):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return audio
+}
+
 // ===================== 接收结果 =====================
 
 // receiveResults 持续接收 WebSocket 消息并处理事件
@@ -1,7 +1,10 @@
 // Package llm 提供 LLM 相关工具（Prompt 模板等）
 package llm
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ===================== S 级 (90-100 分) =====================
 
@@ -66,3 +69,203 @@ Example: "Great job trying! Listen to the whole sentence first, then practice ea
 	user = fmt.Sprintf("Student tried to read: \"%s\"", targetText)
 	return
 }
+
+// ===================== 最小对立词扩展 =====================
+
+// BuildMinimalPairExpansionPrompt 最小对立词扩展 Prompt（要求返回 JSON 数组）
+func BuildMinimalPairExpansionPrompt(phonemeA, phonemeB string, examples [][2]string, count int) (system string, user string) {
+	system = `You are an English phonetics expert designing pronunciation drills for kids (6-12 years old).
+Generate minimal pairs: two common, simple English words that differ ONLY in the given pair of sounds.
+Rules:
+1. Both words must be real, common, single words a child may know (no names, no slang)
+2. The first word must contain the first sound, the second word the second sound
+3. Do NOT repeat the example pairs
+Reply with ONLY a JSON array of 2-element arrays, e.g. [["light","right"],["long","wrong"]]`
+
+	user = fmt.Sprintf("Sounds: %s vs %s\nExamples: %v\nGenerate %d new pairs.", phonemeA, phonemeB, examples, count)
+	return
+}
+
+// ===================== 角色扮演场景 =====================
+
+// BuildRolePlaySystemPrompt 角色扮演对话系统提示词
+// 孩子已达成的成功标准不再引导，AI 自然地把对话推向尚未达成的部分
+func BuildRolePlaySystemPrompt(persona, goal, difficulty string, vocabulary, criteria, metCriteria []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `You are role-playing as %s, talking with a Chinese child (6-12 years old) who is practicing English.
+The child's goal in this scene: %s
+Level: %s
+
+RULES:
+1. Stay in character. Never say you are an AI or a teacher.
+2. Response length: Maximum 25 words (2 short sentences), simple words only.
+3. Always end with a question or prompt that helps the child move toward the goal.
+4. If the child makes a mistake, do not correct directly, just model the right form in your reply.
+`, persona, goal, difficulty)
+	if len(vocabulary) > 0 {
+		fmt.Fprintf(&b, "\nTry to use these words naturally: %s\n", strings.Join(vocabulary, ", "))
+	}
+	var pending []string
+	for _, c := range criteria {
+		if !containsString(metCriteria, c) {
+			pending = append(pending, c)
+		}
+	}
+	if len(pending) > 0 {
+		b.WriteString("\nThe child still needs to:\n")
+		for _, c := range pending {
+			b.WriteString("- " + c + "\n")
+		}
+	}
+	return b.String()
+}
+
+// BuildScenarioGoalCheckPrompt 场景成功标准判定 Prompt（要求返回 JSON）
+// criteria 从 1 开始编号，LLM 返回孩子在对话中已达成的编号
+func BuildScenarioGoalCheckPrompt(goal string, criteria []string, transcript string) (system string, user string) {
+	system = `You judge a role-play English lesson for kids.
+Given the success criteria and the conversation so far, decide which criteria the CHILD has already met.
+A criterion is met only if the child's own words satisfy it (simple or imperfect English is fine; Chinese does not count).
+Reply with ONLY a JSON object, e.g. {"met": [1, 3]}`
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Goal: %s\nCriteria:\n", goal)
+	for i, c := range criteria {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, c)
+	}
+	b.WriteString("\nConversation:\n")
+	b.WriteString(transcript)
+	user = b.String()
+	return
+}
+
+// ===================== 问答模式 =====================
+
+// BuildAnswerCheckPrompt 问答模式答案判定 Prompt（要求返回 JSON）
+// sourceText 为题集关联的短文（可为空），answer 为孩子回答的 ASR 文本
+func BuildAnswerCheckPrompt(sourceText, question, referenceAnswer string, keywords []string, difficulty, answer string) (system string, user string) {
+	system = `You check a Chinese child's (6-12 years old) spoken English answer to a question.
+The answer comes from speech recognition, so ignore punctuation and capitalization.
+Judge:
+- relevant: the answer actually responds to the question (a short answer like "Tom" is relevant; a wrong fact is NOT relevant)
+- grammatical: the answer is an acceptable English sentence or phrase for a child at this level
+- score: 0-100 (content correctness 70%, grammar 30%); Chinese-only answers score below 30
+- feedback: ONE short encouraging sentence for the child (max 15 words, simple English, no hints, do not reveal the answer)
+- corrected: a correct full-sentence version of the child's answer, or "" if the answer was wrong or already perfect
+Reply with ONLY a JSON object, e.g. {"relevant": true, "grammatical": false, "score": 75, "feedback": "Good job! You found the name.", "corrected": "The cat's name is Tom."}`
+
+	var b strings.Builder
+	if sourceText = strings.TrimSpace(sourceText); sourceText != "" {
+		fmt.Fprintf(&b, "Text:\n%s\n\n", sourceText)
+	}
+	fmt.Fprintf(&b, "Level: %s\nQuestion: %s\nReference answer: %s\n", difficulty, question, referenceAnswer)
+	if len(keywords) > 0 {
+		fmt.Fprintf(&b, "Expected keywords: %s\n", strings.Join(keywords, ", "))
+	}
+	fmt.Fprintf(&b, "Child's answer: \"%s\"", answer)
+	user = b.String()
+	return
+}
+
+// ===================== 语法批注 =====================
+
+// BuildGrammarAnnotationPrompt 用户消息语法 / 用词错误分析 Prompt（要求返回 JSON）
+// previousReply 为上一句 AI 回复（可为空），用于判断时态等依赖上下文的错误
+func BuildGrammarAnnotationPrompt(difficulty, previousReply, userText string) (system string, user string) {
+	system = `You review what a Chinese child (6-12 years old) said in an English conversation.
+The text comes from speech recognition: ignore punctuation, capitalization and spelling.
+Find real grammar and vocabulary mistakes only. Do NOT flag short answers, informal but correct speech, or Chinese words.
+For each mistake give:
+- original: the exact wrong words from the child's sentence
+- corrected: the corrected words
+- error_type: one of tense, agreement, article, preposition, plural, word_order, missing_word, word_choice, other
+- explanation: ONE short, kind explanation in simple Chinese for the child (max 30 characters)
+At most 3 mistakes, most important first. If there are no mistakes, return an empty list.
+Reply with ONLY a JSON object, e.g. {"errors": [{"original": "I go school yesterday", "corrected": "I went to school yesterday", "error_type": "tense", "explanation": "yesterday 是过去的事，go 要变成 went 哦"}]}`
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Level: %s\n", difficulty)
+	if previousReply = strings.TrimSpace(previousReply); previousReply != "" {
+		fmt.Fprintf(&b, "Teacher said: \"%s\"\n", previousReply)
+	}
+	fmt.Fprintf(&b, "Child said: \"%s\"", userText)
+	user = b.String()
+	return
+}
+
+// ===================== 会话总结 =====================
+
+// BuildSessionReviewPrompt 会话结束总结 Prompt（摘要 + 分项评分 + 反馈，要求返回 JSON）
+// 回答长度由调用方按词数统计，不交给 LLM 评分
+func BuildSessionReviewPrompt(topic, difficulty, transcript string) (system string, user string) {
+	system = `You review a finished English speaking session between an AI teacher and a Chinese child (6-12 years old).
+The child's lines come from speech recognition: ignore punctuation, capitalization and spelling.
+Return:
+- summary: what the session was about and how the child did, in Chinese for parents and teachers (max 60 characters)
+- fluency: 0-100, how smoothly and completely the child expressed ideas in English
+- relevance: 0-100, how well the child's replies matched what the teacher said or asked
+- vocabulary: 0-100, range and appropriateness of the child's words for their level
+- feedback: encouraging feedback for the child in simple English, one thing done well and one thing to try next time (max 30 words)
+Judge only the CHILD's lines. Chinese-only replies lower fluency and vocabulary.
+Reply with ONLY a JSON object, e.g. {"summary": "...", "fluency": 70, "relevance": 85, "vocabulary": 60, "feedback": "..."}`
+
+	user = fmt.Sprintf("Topic: %s\nLevel: %s\n\nConversation:\n%s", topic, difficulty, transcript)
+	return
+}
+
+// ===================== 长期记忆 =====================
+
+// BuildLearnerMemoryPrompt 从会话中提取孩子个人信息的 Prompt（要求返回 JSON）
+// known 为已记住的事实（"fact_key: content"），让 LLM 沿用已有的键以便更新而不是重复
+func BuildLearnerMemoryPrompt(known []string, transcript string) (system string, user string) {
+	system = `You help an AI English teacher remember a Chinese child (6-12 years old) between sessions.
+From the conversation, extract stable facts the CHILD said about themselves: name or English name, pets, hobbies and sports, school grade and favorite subjects, family members and friends, favorite food, colors, animals, games.
+The child's lines come from speech recognition: ignore punctuation, capitalization and spelling.
+Rules:
+- Only facts the child clearly stated; never guess from what the teacher said.
+- Never extract home address, phone number, ID numbers, passwords, school or class names, or anything sensitive.
+- Skip temporary things (what they ate today, how they feel right now).
+For each fact return:
+- key: short snake_case key, reuse a known key when the fact updates it (e.g. pet_dog_name)
+- category: one of name, pet, hobby, school, family, favorite, other
+- content: the fact in short simple English from the teacher's view, max 15 words (e.g. "Has a dog named Lucky")
+- confidence: 0-1, how sure you are the child really meant it
+At most 5 facts. If there is nothing worth remembering, return an empty list.
+Reply with ONLY a JSON object, e.g. {"facts": [{"key": "pet_dog_name", "category": "pet", "content": "Has a dog named Lucky", "confidence": 0.9}]}`
+
+	var b strings.Builder
+	if len(known) > 0 {
+		b.WriteString("Already known:\n")
+		for _, k := range known {
+			b.WriteString("- " + k + "\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Conversation:\n" + transcript)
+	user = b.String()
+	return
+}
+
+// ===================== 中文释义 =====================
+
+// BuildReplyTranslationPrompt AI 回复中文释义 Prompt（要求返回 JSON）
+// 释义与英文回复一同返回，帮助孩子理解；回复中已有的【】中文提示不重复翻译
+func BuildReplyTranslationPrompt(replyText string) (system string, user string) {
+	system = `You translate an English teacher's reply for a Chinese child (6-12 years old).
+Translate it into natural, simple Simplified Chinese that a child can easily understand.
+Keep the meaning and the friendly tone; do not add explanations. Skip any Chinese hints already written in 【】 brackets.
+Reply with ONLY a JSON object, e.g. {"translation": "..."}`
+
+	user = "Reply: " + replyText
+	return
+}
+
+// containsString 判断切片是否包含指定字符串
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
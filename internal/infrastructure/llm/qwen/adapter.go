@@ -51,17 +51,8 @@ func (a *QwenAdapter) Chat(ctx context.Context, systemPrompt string, userMessage
 // ChatWithHistory 多轮对话
 // 给定完整的对话历史（包含 system/user/assistant 消息），返回 AI 生成的文本
 func (a *QwenAdapter) ChatWithHistory(ctx context.Context, messages []domain.ChatMessage) (string, error) {
-	// 将领域层 ChatMessage 转换为内部 chatMessage
-	internalMessages := make([]chatMessage, len(messages))
-	for i, msg := range messages {
-		internalMessages[i] = chatMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
-	}
-
 	req := &chatRequest{
-		Messages: internalMessages,
+		Messages: toInternalMessages(messages),
 	}
 
 	resp, err := a.qwenClient.chat(ctx, req)
@@ -76,6 +67,64 @@ func (a *QwenAdapter) ChatWithHistory(ctx context.Context, messages []domain.Cha
 	return resp.Choices[0].Message.Content, nil
 }
 
+// ChatStream 流式多轮对话
+// 后台读取 SDK 流，文本增量按顺序写入通道；结束时推送 Done 事件（或 Error 事件）后关闭通道
+func (a *QwenAdapter) ChatStream(ctx context.Context, messages []domain.ChatMessage) (<-chan *domain.ChatStreamDelta, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("qwen chat stream messages is empty")
+	}
+	req := &chatRequest{
+		Messages: toInternalMessages(messages),
+	}
+
+	deltaChan := make(chan *domain.ChatStreamDelta, 32)
+	go func() {
+		defer close(deltaChan)
+
+		// send 写入事件；ctx 取消时放弃写入，避免调用方停止读取后阻塞
+		send := func(delta *domain.ChatStreamDelta) error {
+			select {
+			case deltaChan <- delta:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		result, err := a.qwenClient.chatStream(ctx, req, func(content string) error {
+			return send(&domain.ChatStreamDelta{Content: content})
+		})
+		if err != nil {
+			_ = send(&domain.ChatStreamDelta{Error: fmt.Errorf("qwen chat stream failed: %w", err)})
+			return
+		}
+
+		done := &domain.ChatStreamDelta{Done: true, FinishReason: result.FinishReason}
+		if result.Usage != nil {
+			done.Usage = &domain.ChatUsage{
+				PromptTokens:     result.Usage.PromptTokens,
+				CompletionTokens: result.Usage.CompletionTokens,
+				TotalTokens:      result.Usage.TotalTokens,
+			}
+		}
+		_ = send(done)
+	}()
+
+	return deltaChan, nil
+}
+
+// toInternalMessages 将领域层 ChatMessage 转换为内部 chatMessage
+func toInternalMessages(messages []domain.ChatMessage) []chatMessage {
+	internalMessages := make([]chatMessage, len(messages))
+	for i, msg := range messages {
+		internalMessages[i] = chatMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+	}
+	return internalMessages
+}
+
 // Close 关闭客户端，释放资源
 func (a *QwenAdapter) Close() error {
 	return a.qwenClient.close()
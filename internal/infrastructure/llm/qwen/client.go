@@ -57,70 +57,18 @@ func newInternalClient(cfg config.QwenConfig) *internalClient {
 func (c *internalClient) chat(ctx context.Context, req *chatRequest) (*chatResponse, error) {
 	start := time.Now()
 
-	// 构建 SDK 消息列表
-	sdkMessages := make([]openai.ChatCompletionMessageParamUnion, 0, len(req.Messages))
-	for _, msg := range req.Messages {
-		switch msg.Role {
-		case "system":
-			sdkMessages = append(sdkMessages, openai.SystemMessage(msg.Content))
-		case "user":
-			sdkMessages = append(sdkMessages, openai.UserMessage(msg.Content))
-		case "assistant":
-			sdkMessages = append(sdkMessages, openai.AssistantMessage(msg.Content))
-		default:
-			return nil, fmt.Errorf("unsupported message role: %s", msg.Role)
-		}
-	}
-
-	// 确定模型：优先使用请求中指定的模型，否则使用默认模型
-	model := c.model
-	if req.Model != "" {
-		model = req.Model
-	}
-
-	// 构建 SDK 请求参数
-	params := openai.ChatCompletionNewParams{
-		Model:    model,
-		Messages: sdkMessages,
-	}
-
-	// 设置可选参数
-	if req.Temperature != nil {
-		params.Temperature = openai.Float(*req.Temperature)
-	}
-	if req.MaxTokens > 0 {
-		params.MaxTokens = openai.Int(int64(req.MaxTokens))
-	}
-	if req.TopP != nil {
-		params.TopP = openai.Float(*req.TopP)
+	params, err := c.buildParams(req)
+	if err != nil {
+		return nil, err
 	}
+	model := params.Model
 
 	// 调用 SDK
 	completion, err := c.client.Chat.Completions.New(ctx, params)
 	elapsed := time.Since(start)
 
 	if err != nil {
-		// 使用 errors.As 捕获 SDK 特定错误类型
-		var apiErr *openai.Error
-		if errors.As(err, &apiErr) {
-			log.Printf("[Qwen] API error: status=%d, message=%s, elapsed=%v",
-				apiErr.StatusCode, apiErr.Message, elapsed)
-			return nil, fmt.Errorf("qwen api error (status %d): %s", apiErr.StatusCode, apiErr.Message)
-		}
-
-		// 检查是否为 context 超时或取消
-		if errors.Is(err, context.DeadlineExceeded) {
-			log.Printf("[Qwen] Request timeout, elapsed=%v", elapsed)
-			return nil, fmt.Errorf("qwen request timeout after %v: %w", elapsed, err)
-		}
-		if errors.Is(err, context.Canceled) {
-			log.Printf("[Qwen] Request canceled, elapsed=%v", elapsed)
-			return nil, fmt.Errorf("qwen request canceled: %w", err)
-		}
-
-		// 其他未知错误
-		log.Printf("[Qwen] Unknown error: %v, elapsed=%v", err, elapsed)
-		return nil, fmt.Errorf("qwen chat failed: %w", err)
+		return nil, wrapError(err, elapsed)
 	}
 
 	// 校验响应
@@ -163,6 +111,142 @@ func (c *internalClient) chat(ctx context.Context, req *chatRequest) (*chatRespo
 	}, nil
 }
 
+// chatStream 调用 Chat Completions API 进行流式对话补全
+// 参数:
+//   - ctx: 上下文，取消后立即停止读取
+//   - req: 对话请求，包含消息列表和可选参数
+//   - onDelta: 每收到一段文本增量时回调，返回错误时中止读取
+//
+// 返回:
+//   - *chatStreamResult: 结束原因与 token 使用量
+//   - error: 错误信息，SDK 错误会被捕获并包装为业务友好的错误
+func (c *internalClient) chatStream(ctx context.Context, req *chatRequest, onDelta func(content string) error) (*chatStreamResult, error) {
+	start := time.Now()
+
+	params, err := c.buildParams(req)
+	if err != nil {
+		return nil, err
+	}
+	// 要求服务端在最后一个分片中返回 token 使用量
+	params.StreamOptions = openai.ChatCompletionStreamOptionsParam{
+		IncludeUsage: openai.Bool(true),
+	}
+
+	stream := c.client.Chat.Completions.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	result := &chatStreamResult{}
+	var firstTokenAt time.Duration
+	for stream.Next() {
+		chunk := stream.Current()
+		if chunk.Usage.TotalTokens > 0 {
+			result.Usage = &chatUsage{
+				PromptTokens:     int(chunk.Usage.PromptTokens),
+				CompletionTokens: int(chunk.Usage.CompletionTokens),
+				TotalTokens:      int(chunk.Usage.TotalTokens),
+			}
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		choice := chunk.Choices[0]
+		if choice.FinishReason != "" {
+			result.FinishReason = choice.FinishReason
+		}
+		if choice.Delta.Content == "" {
+			continue
+		}
+		if firstTokenAt == 0 {
+			firstTokenAt = time.Since(start)
+		}
+		if err := onDelta(choice.Delta.Content); err != nil {
+			return nil, err
+		}
+	}
+	elapsed := time.Since(start)
+
+	if err := stream.Err(); err != nil {
+		return nil, wrapError(err, elapsed)
+	}
+
+	// 记录成功日志（不含敏感信息）
+	totalTokens := 0
+	if result.Usage != nil {
+		totalTokens = result.Usage.TotalTokens
+	}
+	log.Printf("[Qwen] Chat stream completed: model=%s, finishReason=%s, totalTokens=%d, firstToken=%v, elapsed=%v",
+		params.Model, result.FinishReason, totalTokens, firstTokenAt, elapsed)
+
+	return result, nil
+}
+
+// buildParams 将内部请求转换为 SDK 请求参数
+func (c *internalClient) buildParams(req *chatRequest) (openai.ChatCompletionNewParams, error) {
+	// 构建 SDK 消息列表
+	sdkMessages := make([]openai.ChatCompletionMessageParamUnion, 0, len(req.Messages))
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system":
+			sdkMessages = append(sdkMessages, openai.SystemMessage(msg.Content))
+		case "user":
+			sdkMessages = append(sdkMessages, openai.UserMessage(msg.Content))
+		case "assistant":
+			sdkMessages = append(sdkMessages, openai.AssistantMessage(msg.Content))
+		default:
+			return openai.ChatCompletionNewParams{}, fmt.Errorf("unsupported message role: %s", msg.Role)
+		}
+	}
+
+	// 确定模型：优先使用请求中指定的模型，否则使用默认模型
+	model := c.model
+	if req.Model != "" {
+		model = req.Model
+	}
+
+	// 构建 SDK 请求参数
+	params := openai.ChatCompletionNewParams{
+		Model:    model,
+		Messages: sdkMessages,
+	}
+
+	// 设置可选参数
+	if req.Temperature != nil {
+		params.Temperature = openai.Float(*req.Temperature)
+	}
+	if req.MaxTokens > 0 {
+		params.MaxTokens = openai.Int(int64(req.MaxTokens))
+	}
+	if req.TopP != nil {
+		params.TopP = openai.Float(*req.TopP)
+	}
+	return params, nil
+}
+
+// wrapError 将 SDK 错误包装为业务友好的错误
+func wrapError(err error, elapsed time.Duration) error {
+	// 使用 errors.As 捕获 SDK 特定错误类型
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		log.Printf("[Qwen] API error: status=%d, message=%s, elapsed=%v",
+			apiErr.StatusCode, apiErr.Message, elapsed)
+		return fmt.Errorf("qwen api error (status %d): %s", apiErr.StatusCode, apiErr.Message)
+	}
+
+	// 检查是否为 context 超时或取消
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Printf("[Qwen] Request timeout, elapsed=%v", elapsed)
+		return fmt.Errorf("qwen request timeout after %v: %w", elapsed, err)
+	}
+	if errors.Is(err, context.Canceled) {
+		log.Printf("[Qwen] Request canceled, elapsed=%v", elapsed)
+		return fmt.Errorf("qwen request canceled: %w", err)
+	}
+
+	// 其他未知错误
+	log.Printf("[Qwen] Unknown error: %v, elapsed=%v", err, elapsed)
+	return fmt.Errorf("qwen chat failed: %w", err)
+}
+
 // close 关闭客户端，释放资源
 func (c *internalClient) close() error {
 	log.Println("[Qwen] Client closed")
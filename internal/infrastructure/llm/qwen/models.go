@@ -39,3 +39,9 @@ type chatUsage struct {
 	CompletionTokens int `json:"completion_tokens"` // 输出 token 数
 	TotalTokens      int `json:"total_tokens"`      // 总 token 数
 }
+
+// chatStreamResult 流式对话结束信息
+type chatStreamResult struct {
+	FinishReason string     // 结束原因: stop, length, etc.
+	Usage        *chatUsage // token 使用量（服务端未返回时为 nil）
+}
@@ -0,0 +1,165 @@
+// Package local 提供本地内容安全审核实现
+// 基于中英文关键词表与正则规则，无外部依赖、无网络调用；
+// 仅通过 LocalModerationAdapter 实现 domain.ModerationProvider 接口对外暴露
+package local
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"pronunciation-correction-system/internal/config"
+	"pronunciation-correction-system/internal/domain"
+)
+
+// categoryRule 单个类别的匹配规则
+type categoryRule struct {
+	category string
+	patterns []*regexp.Regexp
+}
+
+// LocalModerationAdapter 本地审核适配器
+// 孩子输入：色情 / 暴力 / 自伤 / 毒品类拦截，脏话与个人信息打码后放行
+// AI 回复：除个人信息打码外，命中任一类别即拦截
+type LocalModerationAdapter struct {
+	rules    []categoryRule // 按类别名排序，保证结果稳定
+	piiRules []piiRule
+}
+
+// 编译时检查：确保 LocalModerationAdapter 实现了 domain.ModerationProvider 接口
+var _ domain.ModerationProvider = (*LocalModerationAdapter)(nil)
+
+// NewLocalModerationAdapter 创建本地审核适配器
+// 在内置词表基础上追加配置中的关键词与正则，移除 allow_words；正则无法编译时返回错误
+func NewLocalModerationAdapter(cfg config.LocalModerationConfig) (*LocalModerationAdapter, error) {
+	allow := make(map[string]bool, len(cfg.AllowWords))
+	for _, w := range cfg.AllowWords {
+		allow[strings.ToLower(strings.TrimSpace(w))] = true
+	}
+
+	keywords := make(map[string][]string, len(defaultKeywords))
+	for category, words := range defaultKeywords {
+		keywords[category] = append(keywords[category], words...)
+	}
+	for category, words := range cfg.ExtraKeywords {
+		keywords[category] = append(keywords[category], words...)
+	}
+
+	byCategory := make(map[string][]*regexp.Regexp, len(keywords))
+	for category, words := range keywords {
+		var english, chinese []string
+		for _, w := range words {
+			w = strings.TrimSpace(w)
+			if w == "" || allow[strings.ToLower(w)] {
+				continue
+			}
+			if isASCII(w) {
+				english = append(english, regexp.QuoteMeta(strings.ToLower(w)))
+			} else {
+				chinese = append(chinese, regexp.QuoteMeta(w))
+			}
+		}
+		if len(english) > 0 {
+			byCategory[category] = append(byCategory[category], regexp.MustCompile(`(?i)\b(?:`+strings.Join(english, "|")+`)\b`))
+		}
+		if len(chinese) > 0 {
+			byCategory[category] = append(byCategory[category], regexp.MustCompile(strings.Join(chinese, "|")))
+		}
+	}
+	for category, patterns := range cfg.ExtraPatterns {
+		for _, p := range patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid moderation pattern for %s: %w", category, err)
+			}
+			byCategory[category] = append(byCategory[category], re)
+		}
+	}
+
+	adapter := &LocalModerationAdapter{piiRules: defaultPIIRules}
+	for category, patterns := range byCategory {
+		adapter.rules = append(adapter.rules, categoryRule{category: category, patterns: patterns})
+	}
+	sort.Slice(adapter.rules, func(i, j int) bool { return adapter.rules[i].category < adapter.rules[j].category })
+	return adapter, nil
+}
+
+// ModerateInput 审核孩子输入的文本
+func (a *LocalModerationAdapter) ModerateInput(ctx context.Context, text string) (*domain.ModerationResult, error) {
+	return a.moderate(text, inputBlockCategories), nil
+}
+
+// ModerateOutput 审核 AI 回复文本
+func (a *LocalModerationAdapter) ModerateOutput(ctx context.Context, text string) (*domain.ModerationResult, error) {
+	return a.moderate(text, outputBlockCategories), nil
+}
+
+// moderate 匹配关键词与个人信息规则：命中 block 类别时拦截，否则对脏话与个人信息打码
+func (a *LocalModerationAdapter) moderate(text string, blockCategories map[string]bool) *domain.ModerationResult {
+	result := &domain.ModerationResult{Action: domain.ModerationActionAllow}
+	rewritten := text
+	blocked := false
+
+	// 步骤 1：关键词类别
+	for _, rule := range a.rules {
+		hit := false
+		for _, re := range rule.patterns {
+			matches := re.FindAllString(text, -1)
+			if len(matches) == 0 {
+				continue
+			}
+			hit = true
+			result.Matches = append(result.Matches, matches...)
+			if rule.category == domain.ModerationCategoryProfanity {
+				rewritten = re.ReplaceAllString(rewritten, profanityMask)
+			}
+		}
+		if hit {
+			result.Categories = append(result.Categories, rule.category)
+			blocked = blocked || blockCategories[rule.category]
+		}
+	}
+
+	// 步骤 2：个人信息
+	piiHit := false
+	for _, rule := range a.piiRules {
+		matches := rule.re.FindAllString(rewritten, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		piiHit = true
+		result.Matches = append(result.Matches, matches...)
+		rewritten = rule.re.ReplaceAllString(rewritten, rule.placeholder)
+	}
+	if piiHit {
+		// 配置追加的 pii 正则已在步骤 1 记录类别，此处不重复
+		if !slices.Contains(result.Categories, domain.ModerationCategoryPII) {
+			result.Categories = append(result.Categories, domain.ModerationCategoryPII)
+		}
+		blocked = blocked || blockCategories[domain.ModerationCategoryPII]
+	}
+
+	// 步骤 3：确定处理动作
+	switch {
+	case blocked:
+		result.Action = domain.ModerationActionBlock
+	case len(result.Categories) > 0:
+		result.Action = domain.ModerationActionRewrite
+		result.Rewritten = rewritten
+	}
+	return result
+}
+
+// isASCII 判断关键词是否只包含 ASCII 字符（按英文整词匹配）
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package local
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"pronunciation-correction-system/internal/config"
+	"pronunciation-correction-system/internal/domain"
+)
+
+func TestLocalModeration(t *testing.T) {
+	adapter, err := NewLocalModerationAdapter(config.LocalModerationConfig{})
+	if err != nil {
+		t.Fatalf("NewLocalModerationAdapter() error = %v", err)
+	}
+	tests := []struct {
+		name          string
+		output        bool // true = 审核 AI 回复，false = 审核孩子输入
+		text          string
+		wantAction    string
+		wantRewritten string
+		wantCategory  string
+	}{
+		{"clean text", false, "I like apples and bananas", domain.ModerationActionAllow, "", ""},
+		{"whole word only", false, "Essex is near London", domain.ModerationActionAllow, "", ""},
+		{"input profanity masked", false, "this game is shit", domain.ModerationActionRewrite, "this game is ***", domain.ModerationCategoryProfanity},
+		{"output profanity blocked", true, "this game is shit", domain.ModerationActionBlock, "", domain.ModerationCategoryProfanity},
+		{"chinese profanity masked", false, "你妈的", domain.ModerationActionRewrite, "你***", domain.ModerationCategoryProfanity},
+		{"self harm blocked", false, "I want to kill myself", domain.ModerationActionBlock, "", domain.ModerationCategorySelfHarm},
+		{"violence blocked", false, "我要杀了你", domain.ModerationActionBlock, "", domain.ModerationCategoryViolence},
+		{"phone masked", false, "my phone is 13812345678", domain.ModerationActionRewrite, "my phone is [phone]", domain.ModerationCategoryPII},
+		{"id before phone", false, "id 11010519491231002X", domain.ModerationActionRewrite, "id [id]", domain.ModerationCategoryPII},
+		{"email masked", true, "write to tom@example.com", domain.ModerationActionRewrite, "write to [email]", domain.ModerationCategoryPII},
+		{"english address masked", false, "I live at 12 Baker Street", domain.ModerationActionRewrite, "I live at [address]", domain.ModerationCategoryPII},
+		{"chinese address masked", false, "我家在人民路 100 号", domain.ModerationActionRewrite, "[address]", domain.ModerationCategoryPII},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moderate := adapter.ModerateInput
+			if tt.output {
+				moderate = adapter.ModerateOutput
+			}
+			result, err := moderate(context.Background(), tt.text)
+			if err != nil {
+				t.Fatalf("moderate() error = %v", err)
+			}
+			if result.Action != tt.wantAction {
+				t.Errorf("action = %q, want %q", result.Action, tt.wantAction)
+			}
+			if result.Rewritten != tt.wantRewritten {
+				t.Errorf("rewritten = %q, want %q", result.Rewritten, tt.wantRewritten)
+			}
+			if tt.wantCategory != "" && !slices.Contains(result.Categories, tt.wantCategory) {
+				t.Errorf("categories = %v, want %q", result.Categories, tt.wantCategory)
+			}
+			if tt.wantCategory == "" && len(result.Categories) > 0 {
+				t.Errorf("categories = %v, want none", result.Categories)
+			}
+		})
+	}
+}
+
+func TestLocalModerationConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.LocalModerationConfig
+		text       string
+		wantAction string
+		wantErr    bool
+	}{
+		{
+			name:       "allow word removes builtin keyword",
+			cfg:        config.LocalModerationConfig{AllowWords: []string{"Bomb"}},
+			text:       "we played with a water bomb",
+			wantAction: domain.ModerationActionAllow,
+		},
+		{
+			name:       "extra keyword",
+			cfg:        config.LocalModerationConfig{ExtraKeywords: map[string][]string{domain.ModerationCategoryDrugs: {"vape"}}},
+			text:       "my brother has a vape",
+			wantAction: domain.ModerationActionBlock,
+		},
+		{
+			name:       "extra pattern",
+			cfg:        config.LocalModerationConfig{ExtraPatterns: map[string][]string{domain.ModerationCategoryPII: {`QQ\s*\d{5,}`}}},
+			text:       "add my QQ 123456",
+			wantAction: domain.ModerationActionRewrite,
+		},
+		{
+			name:    "invalid pattern",
+			cfg:     config.LocalModerationConfig{ExtraPatterns: map[string][]string{domain.ModerationCategoryPII: {`(`}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter, err := NewLocalModerationAdapter(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewLocalModerationAdapter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			result, _ := adapter.ModerateInput(context.Background(), tt.text)
+			if result.Action != tt.wantAction {
+				t.Errorf("action = %q, want %q (categories %v)", result.Action, tt.wantAction, result.Categories)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package local
+
+import (
+	"regexp"
+
+	"pronunciation-correction-system/internal/domain"
+)
+
+// defaultKeywords 内置关键词表（类别 → 关键词）
+// 英文按整词匹配（忽略大小写），中文按子串匹配；只收录明确不适合儿童的词，避免误伤日常对话
+var defaultKeywords = map[string][]string{
+	domain.ModerationCategorySexual: {
+		"sex", "sexy", "porn", "porno", "nude", "naked", "boobs", "penis", "vagina", "horny", "rape",
+		"色情", "黄片", "做爱", "性交", "裸体", "约炮", "强奸", "脱光",
+	},
+	domain.ModerationCategoryViolence: {
+		"kill you", "murder", "shoot you", "stab", "behead", "bomb", "terrorist", "massacre",
+		"杀了你", "杀人", "砍死", "捅死", "炸弹", "恐怖分子", "枪杀", "血腥",
+	},
+	domain.ModerationCategorySelfHarm: {
+		"kill myself", "suicide", "hurt myself", "cut myself", "want to die", "end my life",
+		"自杀", "不想活", "想死", "割腕", "跳楼", "伤害自己",
+	},
+	domain.ModerationCategoryDrugs: {
+		"cocaine", "heroin", "meth", "marijuana", "get drunk", "gambling", "casino",
+		"毒品", "吸毒", "大麻", "海洛因", "冰毒", "赌博", "喝醉",
+	},
+	domain.ModerationCategoryProfanity: {
+		"fuck", "fucking", "shit", "bitch", "asshole", "bastard", "dick",
+		"傻逼", "他妈的", "妈的", "操你", "滚蛋", "去死",
+	},
+}
+
+// piiRule 个人信息识别规则（命中片段替换为 placeholder）
+type piiRule struct {
+	re          *regexp.Regexp
+	placeholder string
+}
+
+// defaultPIIRules 内置个人信息规则（按顺序替换：证件号先于手机号，避免长数字被部分替换）
+var defaultPIIRules = []piiRule{
+	// 身份证号
+	{regexp.MustCompile(`\b\d{17}[\dXx]\b`), "[id]"},
+	// 邮箱
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[email]"},
+	// 手机号（可带 +86）
+	{regexp.MustCompile(`(?:\+?86[- ]?)?\b1[3-9]\d[- ]?\d{4}[- ]?\d{4}\b`), "[phone]"},
+	// 座机号（区号-号码）
+	{regexp.MustCompile(`\b0\d{2,3}[- ]\d{7,8}\b`), "[phone]"},
+	// 中文地址：XX路 / 街 / 巷 + 门牌号
+	{regexp.MustCompile(`[\p{Han}A-Za-z0-9]{1,15}(?:路|街|大道|巷|弄)\s*\d+\s*号`), "[address]"},
+	// 中文地址：XX小区 + 楼栋号
+	{regexp.MustCompile(`[\p{Han}]{2,10}小区\s*\d+`), "[address]"},
+	// 中文地址：楼栋 / 单元 / 房号
+	{regexp.MustCompile(`\d+\s*(?:栋|幢|号楼)(?:\s*\d+\s*单元)?(?:\s*\d+\s*(?:室|号))?`), "[address]"},
+	// 英文地址：门牌号 + 街道名 + 街道类型
+	{regexp.MustCompile(`(?i)\b\d{1,5}\s+(?:[a-z]+\s+){1,3}(?:street|st|road|rd|avenue|ave|lane|ln|drive|dr|boulevard|blvd)\b`), "[address]"},
+}
+
+// profanityMask 脏话替换文本
+const profanityMask = "***"
+
+// inputBlockCategories 孩子输入命中后直接拦截的类别（脏话与个人信息改写后放行）
+var inputBlockCategories = map[string]bool{
+	domain.ModerationCategorySexual:   true,
+	domain.ModerationCategoryViolence: true,
+	domain.ModerationCategorySelfHarm: true,
+	domain.ModerationCategoryDrugs:    true,
+}
+
+// outputBlockCategories AI 回复命中后直接拦截的类别（个人信息改写后放行）
+var outputBlockCategories = map[string]bool{
+	domain.ModerationCategorySexual:    true,
+	domain.ModerationCategoryViolence:  true,
+	domain.ModerationCategorySelfHarm:  true,
+	domain.ModerationCategoryDrugs:     true,
+	domain.ModerationCategoryProfanity: true,
+}
@@ -200,46 +200,82 @@ func (c *internalClient) synthesize(ctx context.Context, texts []string, opts *d
 }
 
 // synthesizeStream 流式合成语音（实时推送音频块）
+// 音频块到达即推送到 audioChan，合成结束（成功或失败）后关闭 audioChan
 func (c *internalClient) synthesizeStream(ctx context.Context, texts []string, opts *domain.SynthesizeOptions, audioChan chan<- []byte) error {
+	start := time.Now()
+	params := c.mergeParams(opts)
+
+	// 建立 WebSocket 连接
+	session, err := c.newSession(ctx, params)
+	if err != nil {
+		close(audioChan)
+		return err
+	}
+	defer session.close()
+
+	// 发送 run-task 指令
+	if err := session.sendRunTask(ctx, c.model, params); err != nil {
+		close(audioChan)
+		return err
+	}
+
+	// 启动接收 goroutine（流式推送到 audioChan，返回时关闭 audioChan）
+	receiveDone := make(chan error, 1)
+	go func() {
+		receiveDone <- session.receiveAndStream(ctx, audioChan)
+	}()
+
+	// 等待 task-started（接收方提前结束说明任务失败或连接断开）
+	select {
+	case <-session.taskStarted:
+	case err := <-receiveDone:
+		if err == nil {
+			err = fmt.Errorf("task finished before started, task_id=%s", session.taskID)
+		}
+		return err
+	case <-time.After(defaultTaskStartTimeout):
+		session.close()
+		<-receiveDone
+		return fmt.Errorf("wait task-started timeout after %v, task_id=%s", defaultTaskStartTimeout, session.taskID)
+	case <-ctx.Done():
+		session.close()
+		<-receiveDone
+		return ctx.Err()
+	}
+
+	// 发送所有文本 + finish-task（失败时关闭连接，等待接收方退出）
+	sendErr := func() error {
+		for _, text := range texts {
+			if err := session.sendContinueTask(ctx, text); err != nil {
+				return err
+			}
+		}
+		return session.sendFinishTask(ctx)
+	}()
+	if sendErr != nil {
+		session.close()
+		<-receiveDone
+		return sendErr
+	}
+
+	// 等待接收完成（ctx 取消时关闭连接以中断阻塞读取）
+	select {
+	case err := <-receiveDone:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		session.close()
+		<-receiveDone
+		return ctx.Err()
+	}
+
+	logger.InfoContext(ctx, "[AliyunTTS] Stream synthesis completed",
+		"texts_count", len(texts),
+		"elapsed", time.Since(start).String(),
+		"task_id", session.taskID,
+	)
 	return nil
-	// params := c.mergeParams(opts)
-
-	// session, err := c.newSession(ctx, params)
-	// if err != nil {
-	// 	return err
-	// }
-	// defer session.close()
-
-	// // 等待 task-started
-	// if err := session.waitTaskStarted(ctx); err != nil {
-	// 	return err
-	// }
-
-	// // 启动接收 goroutine（流式推送到 audioChan）
-	// receiveDone := make(chan error, 1)
-	// go func() {
-	// 	receiveDone <- session.receiveAndStream(ctx, audioChan)
-	// }()
-
-	// // 发送所有文本
-	// for _, text := range texts {
-	// 	if err := session.sendContinueTask(text); err != nil {
-	// 		return err
-	// 	}
-	// }
-
-	// // 发送 finish-task
-	// if err := session.sendFinishTask(); err != nil {
-	// 	return err
-	// }
-
-	// // 等待接收完成
-	// select {
-	// case err := <-receiveDone:
-	// 	return err
-	// case <-ctx.Done():
-	// 	return ctx.Err()
-	// }
 }
 
 // close 关闭客户端
@@ -260,6 +296,8 @@ type synthesisSession struct {
 	taskStarted  chan struct{} // 任务启动信号
 	taskFinished chan struct{} // 任务完成信号
 	taskFailed   chan struct{} // 任务失败信号
+	startOnce    sync.Once     // 流式接收时保护 taskStarted 只关闭一次
+	closeOnce    sync.Once     // 保护连接只关闭一次
 
 	// 接收到的音频数据
 	audioBuffer []byte
@@ -615,6 +653,9 @@ func (s *synthesisSession) receiveAndStream(ctx context.Context, audioChan chan<
 			return fmt.Errorf("tts task failed: code=%s, message=%s",
 				event.Header.ErrorCode, errMsg)
 
+		case eventTaskStarted:
+			s.startOnce.Do(func() { close(s.taskStarted) })
+
 		case eventResultGenerated:
 			// result-generated 在 TTS 中表示合成进度，可忽略
 		}
@@ -626,6 +667,6 @@ func (s *synthesisSession) receiveAndStream(ctx context.Context, audioChan chan<
 // close 关闭会话连接
 func (s *synthesisSession) close() {
 	if s.conn != nil {
-		_ = s.conn.Close()
+		s.closeOnce.Do(func() { _ = s.conn.Close() })
 	}
 }
@@ -0,0 +1,48 @@
+// Package model 定义对话反馈相关数据模型
+package model
+
+import (
+	"time"
+)
+
+// ChatFeedback 对话反馈表
+// 用户对单条 AI 回复的评分，用于按提示词版本 / 场景 / 难度 / 回复版本分析回复质量
+// 对应数据库表: chat_feedback
+//
+// 每个用户对同一条 AI 回复的每个版本只保留一条反馈，重复提交时覆盖
+type ChatFeedback struct {
+	// ID 反馈 ID (UUID)
+	ID string `gorm:"primaryKey;type:varchar(36)" json:"id" validate:"required,uuid"`
+	// UserID 用户 ID，外键
+	UserID string `gorm:"uniqueIndex:uk_chat_feedback_user_message_version;type:varchar(36);not null" json:"user_id" validate:"required,uuid"`
+	// ConversationID 会话 ID，外键
+	ConversationID string `gorm:"index;type:varchar(36);not null" json:"conversation_id" validate:"required,uuid"`
+	// MessageID 被评价的 AI 消息 ID，外键
+	MessageID string `gorm:"uniqueIndex:uk_chat_feedback_user_message_version;index;type:varchar(36);not null" json:"message_id" validate:"required,uuid"`
+	// Turn 对话轮次（从 1 开始）
+	Turn int `gorm:"type:int;not null" json:"turn" validate:"required,gte=1"`
+	// TaskID 异步对话任务 ID（可选）
+	TaskID *string `gorm:"type:varchar(64)" json:"task_id,omitempty" validate:"omitempty,max=64"`
+	// Rating 评分（1-5）
+	Rating int `gorm:"index;type:tinyint;not null" json:"rating" validate:"required,min=1,max=5"`
+	// Helpful 是否有帮助（nil 表示未选择）
+	Helpful *bool `gorm:"type:tinyint(1)" json:"helpful,omitempty"`
+	// Comment 文字评价（可选）
+	Comment *string `gorm:"type:varchar(500)" json:"comment,omitempty" validate:"omitempty,max=500"`
+	// PromptVersion 提示词版本快照（提交时从 AI 消息复制）
+	PromptVersion *string `gorm:"type:varchar(20)" json:"prompt_version,omitempty"`
+	// ReplyVersion 回复版本号快照（提交时的版本，1 为首次生成，重新生成或重试后递增）
+	ReplyVersion int `gorm:"uniqueIndex:uk_chat_feedback_user_message_version;type:int;not null;default:1" json:"reply_version"`
+	// CreatedAt 创建时间
+	CreatedAt time.Time `gorm:"index;autoCreateTime;type:timestamp" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt time.Time `gorm:"autoUpdateTime;type:timestamp" json:"updated_at"`
+
+	// 关联
+	Message *ConversationMessage `gorm:"foreignKey:MessageID;references:ID" json:"message,omitempty"`
+}
+
+// TableName 指定表名
+func (ChatFeedback) TableName() string {
+	return "chat_feedback"
+}
@@ -136,6 +136,20 @@ const (
 const (
 	ConversationTypeFreeTalk       = "free_talk"
 	ConversationTypeQuestionAnswer = "question_answer"
+	ConversationTypeRolePlay       = "role_play"
+)
+
+// === 语法错误类型常量 ===
+const (
+	GrammarErrorTense       = "tense"        // 时态
+	GrammarErrorAgreement   = "agreement"    // 主谓一致
+	GrammarErrorArticle     = "article"      // 冠词
+	GrammarErrorPreposition = "preposition"  // 介词
+	GrammarErrorPlural      = "plural"       // 单复数
+	GrammarErrorWordOrder   = "word_order"   // 语序
+	GrammarErrorMissingWord = "missing_word" // 缺词
+	GrammarErrorWordChoice  = "word_choice"  // 用词（词汇）
+	GrammarErrorOther       = "other"        // 其他
 )
 
 // === 评测状态常量 ===
@@ -152,6 +166,20 @@ const (
 	SenderTypeAI   = "ai"
 )
 
+// === 消息语言常量（用户消息的 ASR 文本） ===
+const (
+	MessageLanguageChinese = "zh"    // 中文
+	MessageLanguageEnglish = "en"    // 英文
+	MessageLanguageMixed   = "mixed" // 中英混合
+)
+
+// === 回复语言策略常量 ===
+const (
+	LanguagePolicyEnglishOnly        = "english_only"        // 只用英文回复
+	LanguagePolicyChineseScaffolding = "chinese_scaffolding" // 初学者可用中文提示（默认），孩子说中文时附中文释义
+	LanguagePolicyTranslateOnDemand  = "translate_on_demand" // 英文回复，按请求附中文释义
+)
+
 // === 报告类型常量 ===
 const (
 	ReportTypeWeekly  = "weekly"
@@ -159,6 +187,65 @@ const (
 	ReportTypeCustom  = "custom"
 )
 
+// === 复习状态常量 ===
+const (
+	ReviewStatusLearning  = "learning"  // 新入队或刚遗忘
+	ReviewStatusReviewing = "reviewing" // 间隔复习中
+	ReviewStatusMastered  = "mastered"  // 间隔 >= 21 天，视为掌握
+)
+
+// === 词汇来源常量 ===
+const (
+	VocabularySourceConversation = "conversation" // 对话中说出（ASR 文本）
+	VocabularySourceEvaluation   = "evaluation"   // 发音评测中朗读（目标文本）
+)
+
+// === 长期记忆类别常量 ===
+const (
+	MemoryCategoryName     = "name"     // 名字、昵称、英文名
+	MemoryCategoryPet      = "pet"      // 宠物
+	MemoryCategoryHobby    = "hobby"    // 爱好、运动
+	MemoryCategorySchool   = "school"   // 年级、喜欢的科目等
+	MemoryCategoryFamily   = "family"   // 家人、朋友
+	MemoryCategoryFavorite = "favorite" // 喜欢的食物、颜色、动物等
+	MemoryCategoryOther    = "other"
+)
+
+// === 处理阶段常量（stage_latencies 的键） ===
+const (
+	LatencyStageASR        = "asr"         // 语音识别（实时对话从音频流结束计起）
+	LatencyStageAssessment = "assessment"  // 讯飞发音评测
+	LatencyStageLLM        = "llm"         // LLM 生成回复 / 反馈（流式生成时不含其间的 TTS）
+	LatencyStageTTS        = "tts"         // 语音合成（多句、多段时累加）
+	LatencyStageOSS        = "oss"         // 上传音频到 OSS
+	LatencyStageFirstAudio = "first_audio" // 实时对话：从孩子说完到推送第一段 AI 音频
+)
+
+// === 对话轮次失败阶段常量（conversation_messages.failed_stage） ===
+const (
+	TurnFailedStageLLM = "llm" // 生成回复失败，AI 消息没有文本
+	TurnFailedStageTTS = "tts" // 语音合成失败，AI 消息有文本但没有音频
+)
+
+// === 回复版本替换原因常量（reply_versions[].reason） ===
+const (
+	ReplyReasonRegenerate = "regenerate" // 用户要求换一种说法
+	ReplyReasonRetry      = "retry"      // 重试失败的轮次
+)
+
+// === 用户套餐常量 ===
+const (
+	UserPlanFree    = "free"
+	UserPlanPremium = "premium"
+	UserPlanAdmin   = "admin"
+)
+
+// === 配额功能常量 ===
+const (
+	QuotaFeatureEvaluate = "evaluate" // 发音评测（讯飞）
+	QuotaFeatureChat     = "chat"     // 语音对话（ASR + LLM + TTS）
+)
+
 // Evaluation 评测模型别名（方便引用）
 type Evaluation = PronunciationEvaluation
 
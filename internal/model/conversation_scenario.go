@@ -0,0 +1,54 @@
+// Package model 定义角色扮演场景相关数据模型
+package model
+
+import (
+	"time"
+)
+
+// ConversationScenario 角色扮演场景表
+// 场景描述 AI 扮演的角色、孩子需要完成的交际目标和成功标准
+// 对应数据库表: conversation_scenarios
+//
+// 使用方式：
+//   - 孩子选择场景后创建 role_play 会话，AI 先说出开场白
+//   - 每轮对话后由 LLM 判断孩子已达成的成功标准，全部达成即视为完成目标并结束会话
+type ConversationScenario struct {
+	// ID 场景 ID (UUID)
+	ID string `gorm:"primaryKey;type:varchar(36)" json:"id" validate:"required,uuid"`
+	// Code 场景编码（唯一，如 "restaurant_order"）
+	Code string `gorm:"uniqueIndex:uk_conversation_scenarios_code;type:varchar(50);not null" json:"code" validate:"required,max=50"`
+	// Title 场景标题（如 "Ordering Food"）
+	Title string `gorm:"type:varchar(200);not null" json:"title" validate:"required,max=200"`
+	// Category 场景分类（如 restaurant / shopping / school）
+	Category string `gorm:"index:idx_conversation_scenarios_category;type:varchar(50);not null" json:"category" validate:"required,max=50"`
+	// Description 场景简介（面向孩子和老师展示）
+	Description string `gorm:"type:text" json:"description"`
+	// DifficultyLevel 难度等级：beginner/intermediate/advanced
+	DifficultyLevel string `gorm:"index;type:enum('beginner','intermediate','advanced');default:'beginner';not null" json:"difficulty_level" validate:"required,oneof=beginner intermediate advanced"`
+
+	// === 角色扮演设定 ===
+	// Persona AI 扮演的角色（如 "a friendly waiter at a pizza restaurant"）
+	Persona string `gorm:"type:varchar(500);not null" json:"persona" validate:"required,max=500"`
+	// Goal 孩子需要完成的交际目标（如 "Order a meal and a drink"）
+	Goal string `gorm:"type:varchar(500);not null" json:"goal" validate:"required,max=500"`
+	// OpeningLine AI 开场白
+	OpeningLine string `gorm:"type:varchar(500);not null" json:"opening_line" validate:"required,max=500"`
+	// TargetVocabulary 目标词汇（JSON 数组）
+	TargetVocabulary StringArray `gorm:"type:json" json:"target_vocabulary"`
+	// SuccessCriteria 成功标准（JSON 数组，全部达成视为完成目标）
+	SuccessCriteria StringArray `gorm:"type:json" json:"success_criteria"`
+
+	// IsActive 是否上架
+	IsActive bool `gorm:"index;type:tinyint(1);default:1;not null" json:"-"`
+	// SortOrder 排序（升序）
+	SortOrder int `gorm:"type:int;default:0;not null" json:"-"`
+	// CreatedAt 创建时间
+	CreatedAt time.Time `gorm:"autoCreateTime;type:timestamp" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt time.Time `gorm:"autoUpdateTime;type:timestamp" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ConversationScenario) TableName() string {
+	return "conversation_scenarios"
+}
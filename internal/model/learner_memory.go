@@ -0,0 +1,50 @@
+// Package model 定义学习者长期记忆相关数据模型
+package model
+
+import (
+	"time"
+)
+
+// LearnerMemory 学习者长期记忆表
+// 会话结束后由 LLM 从孩子的发言中提取的个人信息（名字、宠物、爱好、学校等），
+// 新会话开始时选取最相关的几条注入系统提示词，让 AI 老师"记得"孩子
+// 对应数据库表: learner_memories
+//
+// 规则：
+//   - 每个用户的同一 fact_key 只保留一条，再次提到时更新内容与置信度并累计次数
+//   - 提取结果需通过内容安全审核，命中任何类别（含个人隐私）的条目不保存
+//   - 家长可查看与删除；超过 system_settings.learner_memory_retention_days 未再提到的条目自动清除
+type LearnerMemory struct {
+	// ID 记忆 ID (UUID)
+	ID string `gorm:"primaryKey;type:varchar(36)" json:"id" validate:"required,uuid"`
+	// UserID 用户 ID，外键
+	UserID string `gorm:"uniqueIndex:uk_learner_memories_user_key;type:varchar(36);not null" json:"user_id" validate:"required,uuid"`
+	// FactKey 事实键（小写蛇形，如 "pet_dog_name"），同一键的新信息覆盖旧信息
+	FactKey string `gorm:"uniqueIndex:uk_learner_memories_user_key;type:varchar(100);not null" json:"fact_key" validate:"required,max=100"`
+	// Category 类别：name/pet/hobby/school/family/favorite/other
+	Category string `gorm:"type:varchar(20);not null" json:"category" validate:"required,max=20"`
+	// Content 事实内容（简短英文，如 "Has a dog named Lucky"）
+	Content string `gorm:"type:varchar(255);not null" json:"content" validate:"required,max=255"`
+	// Confidence 置信度（0-1，孩子明确说出的事实接近 1）
+	Confidence float64 `gorm:"type:float;default:0;not null" json:"confidence" validate:"gte=0,lte=1"`
+	// MentionCount 被提取到的会话数
+	MentionCount int `gorm:"type:int;default:1;not null" json:"mention_count" validate:"gte=1"`
+	// SourceConversationID 最近一次提取到该事实的会话 ID
+	SourceConversationID *string `gorm:"type:varchar(36)" json:"source_conversation_id,omitempty" validate:"omitempty,uuid"`
+	// FirstSeenAt 首次提取时间
+	FirstSeenAt time.Time `gorm:"type:timestamp;not null" json:"first_seen_at"`
+	// LastSeenAt 最近一次提取时间（用于保留期限与相关度排序）
+	LastSeenAt time.Time `gorm:"index;type:timestamp;not null" json:"last_seen_at"`
+	// CreatedAt 创建时间
+	CreatedAt time.Time `gorm:"autoCreateTime;type:timestamp" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt time.Time `gorm:"autoUpdateTime;type:timestamp" json:"updated_at"`
+
+	// 关联
+	User *User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+// TableName 指定表名
+func (LearnerMemory) TableName() string {
+	return "learner_memories"
+}
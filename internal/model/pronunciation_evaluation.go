@@ -52,6 +52,11 @@ type PronunciationEvaluation struct {
 	// ProblemWordAudioURLs 问题单词示范音频 URL (JSON 对象: {"apple": "url1", "oranges": "url2"})
 	ProblemWordAudioURLs StringMap `gorm:"type:json" json:"problem_word_audio_urls,omitempty"`
 
+	// === 单词级结果字段 ===
+	// WordScores 单词级评测结果 (JSON 数组: [{"index":0,"word":"cat","score":85.5,"is_problem":false}])
+	// 用于同一文本多次尝试的逐词对比
+	WordScores WordScoreList `gorm:"type:json" json:"word_scores,omitempty"`
+
 	// === 整句示范字段（C 级使用）===
 	// DemoSentenceAudioURL 整句示范音频 URL（仅 C 级需要）
 	DemoSentenceAudioURL *string `gorm:"type:varchar(500)" json:"demo_sentence_audio_url,omitempty" validate:"omitempty,url,max=500"`
@@ -67,6 +72,10 @@ type PronunciationEvaluation struct {
 	Status string `gorm:"index;type:enum('pending','processing','completed','failed');default:'pending';not null" json:"status" validate:"required,oneof=pending processing completed failed"`
 	// ErrorMessage 错误信息（失败时）
 	ErrorMessage *string `gorm:"type:varchar(500)" json:"error_message,omitempty" validate:"omitempty,max=500"`
+	// LatencyMS 处理延迟（毫秒，用于性能分析；从收到请求到音频上传完成）
+	LatencyMS *int `gorm:"type:int" json:"latency_ms,omitempty" validate:"omitempty,gte=0"`
+	// StageLatencies 各处理阶段耗时（毫秒，JSON 对象：{"assessment": 900, "llm": 1100, ...}）
+	StageLatencies StageLatencies `gorm:"type:json" json:"stage_latencies,omitempty"`
 	// CreatedAt 创建时间
 	CreatedAt time.Time `gorm:"index;autoCreateTime;type:timestamp" json:"created_at"`
 	// UpdatedAt 更新时间
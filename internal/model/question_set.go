@@ -0,0 +1,46 @@
+// Package model 定义问答题集相关数据模型
+package model
+
+import (
+	"time"
+)
+
+// QuestionSet 问答题集表
+// 围绕一篇短文或一个角色扮演场景的一组问题，用于 question_answer 会话
+// 对应数据库表: question_sets
+//
+// 使用方式：
+//   - 孩子选择题集后创建 question_answer 会话，AI 依次提问
+//   - 每个回答由 LLM 判定是否切题、是否符合语法并打分，未通过时给出提示并重问
+type QuestionSet struct {
+	// ID 题集 ID (UUID)
+	ID string `gorm:"primaryKey;type:varchar(36)" json:"id" validate:"required,uuid"`
+	// Code 题集编码（唯一，如 "story_lost_cat"）
+	Code string `gorm:"uniqueIndex:uk_question_sets_code;type:varchar(50);not null" json:"code" validate:"required,max=50"`
+	// Title 题集标题
+	Title string `gorm:"type:varchar(200);not null" json:"title" validate:"required,max=200"`
+	// DifficultyLevel 难度等级：beginner/intermediate/advanced
+	DifficultyLevel string `gorm:"index;type:enum('beginner','intermediate','advanced');default:'beginner';not null" json:"difficulty_level" validate:"required,oneof=beginner intermediate advanced"`
+	// ScenarioID 关联的角色扮演场景 ID（围绕场景提问时）
+	ScenarioID *string `gorm:"index;type:varchar(36)" json:"scenario_id,omitempty" validate:"omitempty,uuid"`
+	// SourceText 关联的短文（围绕短文提问时，客户端展示给孩子阅读）
+	SourceText *string `gorm:"type:text" json:"source_text,omitempty"`
+	// Introduction 开场引导语（AI 在第一个问题前说）
+	Introduction string `gorm:"type:varchar(500);not null" json:"introduction" validate:"required,max=500"`
+	// Questions 题目列表（JSON 数组，按顺序提问）
+	Questions QAQuestionList `gorm:"type:json;not null" json:"questions" validate:"required,min=1"`
+
+	// IsActive 是否上架
+	IsActive bool `gorm:"index;type:tinyint(1);default:1;not null" json:"-"`
+	// SortOrder 排序（升序）
+	SortOrder int `gorm:"type:int;default:0;not null" json:"-"`
+	// CreatedAt 创建时间
+	CreatedAt time.Time `gorm:"autoCreateTime;type:timestamp" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt time.Time `gorm:"autoUpdateTime;type:timestamp" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (QuestionSet) TableName() string {
+	return "question_sets"
+}
@@ -0,0 +1,63 @@
+// Package model 定义间隔复习相关数据模型
+package model
+
+import (
+	"time"
+)
+
+// ReviewItem 单词复习队列表
+// 基于 SM-2 间隔重复算法，记录每个用户问题单词的复习进度
+// 对应数据库表: review_items
+//
+// 入队与调度规则：
+//   - 评测中单词得分 < 60 时入队（已在队列中则降级）
+//   - 后续评测/复习的单词得分决定升级或降级
+//   - 到期（due_at <= 今日结束）的单词进入每日复习列表
+type ReviewItem struct {
+	// ID 复习项 ID (UUID)
+	ID string `gorm:"primaryKey;type:varchar(36)" json:"id" validate:"required,uuid"`
+	// UserID 用户 ID，外键
+	UserID string `gorm:"uniqueIndex:uk_review_items_user_word;index:idx_review_items_user_due,priority:1;type:varchar(36);not null" json:"user_id" validate:"required,uuid"`
+	// Word 单词（小写规范化）
+	Word string `gorm:"uniqueIndex:uk_review_items_user_word;type:varchar(100);not null" json:"word" validate:"required,max=100"`
+
+	// === SM-2 调度字段 ===
+	// EaseFactor 难易度因子（最小 1.3，初始 2.5）
+	EaseFactor float64 `gorm:"type:float;default:2.5;not null" json:"ease_factor" validate:"gte=1.3"`
+	// IntervalDays 当前复习间隔（天）
+	IntervalDays int `gorm:"type:int;default:0;not null" json:"interval_days" validate:"gte=0"`
+	// Repetitions 连续答对次数
+	Repetitions int `gorm:"type:int;default:0;not null" json:"repetitions" validate:"gte=0"`
+	// DueAt 下次复习时间
+	DueAt time.Time `gorm:"index:idx_review_items_user_due,priority:2;type:timestamp;not null" json:"due_at"`
+
+	// === 统计字段 ===
+	// LastScore 最近一次单词得分（0-100）
+	LastScore int `gorm:"type:int;default:0;not null" json:"last_score" validate:"gte=0,lte=100"`
+	// ReviewCount 累计复习次数
+	ReviewCount int `gorm:"type:int;default:0;not null" json:"review_count" validate:"gte=0"`
+	// LapseCount 累计遗忘（降级）次数
+	LapseCount int `gorm:"type:int;default:0;not null" json:"lapse_count" validate:"gte=0"`
+	// Status 状态：learning/reviewing/mastered
+	Status string `gorm:"index;type:enum('learning','reviewing','mastered');default:'learning';not null" json:"status" validate:"required,oneof=learning reviewing mastered"`
+
+	// === 来源与示范 ===
+	// SourceEvaluationID 首次入队的评测 ID
+	SourceEvaluationID *string `gorm:"type:varchar(36)" json:"source_evaluation_id,omitempty" validate:"omitempty,uuid"`
+	// DemoAudioURL 单词示范音频 URL（TTS 生成，懒加载）
+	DemoAudioURL *string `gorm:"type:varchar(500)" json:"demo_audio_url,omitempty" validate:"omitempty,url,max=500"`
+	// LastReviewedAt 最近一次复习时间
+	LastReviewedAt *time.Time `gorm:"type:timestamp" json:"last_reviewed_at,omitempty"`
+	// CreatedAt 创建时间
+	CreatedAt time.Time `gorm:"autoCreateTime;type:timestamp" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt time.Time `gorm:"autoUpdateTime;type:timestamp" json:"updated_at"`
+
+	// 关联
+	User *User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+// TableName 指定表名
+func (ReviewItem) TableName() string {
+	return "review_items"
+}
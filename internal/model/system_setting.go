@@ -57,6 +57,12 @@ const (
 	ConfigDefaultTTSVoice = "default_tts_voice"
 	// ConfigDefaultLLMModel 默认 LLM 模型
 	ConfigDefaultLLMModel = "default_llm_model"
+	// ConfigLearnerMemoryRetentionDays 长期记忆保留天数（超过该天数未再提到的条目自动清除，0 表示关闭长期记忆）
+	ConfigLearnerMemoryRetentionDays = "learner_memory_retention_days"
+	// ConfigConversationIdleTimeoutMinutes 进行中的会话无交互超过该分钟数后自动结束
+	ConfigConversationIdleTimeoutMinutes = "conversation_idle_timeout_minutes"
+	// ConfigConversationPausedTimeoutHours 暂停的会话超过该小时数未恢复时自动结束
+	ConfigConversationPausedTimeoutHours = "conversation_paused_timeout_hours"
 )
 
 // DefaultSystemSettings 默认系统配置
@@ -142,6 +148,30 @@ var DefaultSystemSettings = []SystemSetting{
 		Description: strPtr("默认LLM模型"),
 		IsEditable:  true,
 	},
+	{
+		ID:          "set_011",
+		ConfigKey:   ConfigLearnerMemoryRetentionDays,
+		ConfigValue: "180",
+		ConfigType:  "int",
+		Description: strPtr("长期记忆保留天数（0 表示关闭长期记忆）"),
+		IsEditable:  true,
+	},
+	{
+		ID:          "set_012",
+		ConfigKey:   ConfigConversationIdleTimeoutMinutes,
+		ConfigValue: "15",
+		ConfigType:  "int",
+		Description: strPtr("会话无交互自动结束的分钟数"),
+		IsEditable:  true,
+	},
+	{
+		ID:          "set_013",
+		ConfigKey:   ConfigConversationPausedTimeoutHours,
+		ConfigValue: "24",
+		ConfigType:  "int",
+		Description: strPtr("暂停的会话未恢复自动结束的小时数"),
+		IsEditable:  true,
+	},
 }
 
 // strPtr 字符串指针辅助函数
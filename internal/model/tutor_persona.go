@@ -0,0 +1,49 @@
+// Package model 定义 AI 老师人设相关数据模型
+package model
+
+import (
+	"time"
+)
+
+// TutorPersona AI 老师人设表
+// 人设决定 AI 回复的性格（追加到系统提示词）与声音（TTS 音色、语速）
+// 对应数据库表: tutor_personas
+//
+// 使用方式：
+//   - 学习者在个人设置中选择人设，之后每轮对话都使用该人设的提示词与合成参数
+//   - 未选择人设时使用默认老师提示词与 system_settings.default_tts_voice 音色
+//   - 角色扮演会话由场景决定 AI 扮演的角色，人设只影响声音
+type TutorPersona struct {
+	// ID 人设 ID (UUID)
+	ID string `gorm:"primaryKey;type:varchar(36)" json:"id" validate:"required,uuid"`
+	// Code 人设编码（唯一，如 "sunny_lily"）
+	Code string `gorm:"uniqueIndex:uk_tutor_personas_code;type:varchar(50);not null" json:"code" validate:"required,max=50"`
+	// Name 人设名称（如 "Lily"）
+	Name string `gorm:"type:varchar(50);not null" json:"name" validate:"required,max=50"`
+	// AvatarURL 头像 URL
+	AvatarURL *string `gorm:"type:varchar(500)" json:"avatar_url,omitempty" validate:"omitempty,url,max=500"`
+	// Description 人设简介（面向孩子和家长展示）
+	Description string `gorm:"type:text" json:"description"`
+
+	// === 人设设定 ===
+	// Personality 性格提示词片段（英文，追加到系统提示词，不对外展示）
+	Personality string `gorm:"type:text;not null" json:"-" validate:"required"`
+	// TTSVoice TTS 音色（如 longxiaochun），为空时使用默认音色
+	TTSVoice *string `gorm:"type:varchar(50)" json:"tts_voice,omitempty" validate:"omitempty,max=50"`
+	// SpeakingRate 语速：0.5-2.0，默认 1.0
+	SpeakingRate float64 `gorm:"type:float;default:1.0;not null" json:"speaking_rate" validate:"gte=0.5,lte=2"`
+
+	// IsActive 是否上架
+	IsActive bool `gorm:"index;type:tinyint(1);default:1;not null" json:"-"`
+	// SortOrder 排序（升序）
+	SortOrder int `gorm:"type:int;default:0;not null" json:"-"`
+	// CreatedAt 创建时间
+	CreatedAt time.Time `gorm:"autoCreateTime;type:timestamp" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt time.Time `gorm:"autoUpdateTime;type:timestamp" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TutorPersona) TableName() string {
+	return "tutor_personas"
+}
@@ -7,6 +7,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"time"
 )
 
 // ========== StringArray ==========
@@ -62,3 +63,245 @@ func (sm StringMap) Value() (driver.Value, error) {
 	}
 	return json.Marshal(sm)
 }
+
+// ========== WordScoreList ==========
+
+// WordScore 单词级评测结果
+type WordScore struct {
+	// Index 单词在目标文本中的位置（从 0 开始）
+	Index int `json:"index"`
+	// Word 单词原文
+	Word string `json:"word"`
+	// Score 单词得分（0-100）
+	Score float64 `json:"score"`
+	// IsProblem 是否为问题单词
+	IsProblem bool `json:"is_problem"`
+}
+
+// WordScoreList 单词级评测结果列表（用于 JSON 列的序列化/反序列化）
+// 使用场景：word_scores ([{"index":0,"word":"cat","score":85.5,"is_problem":false}])
+type WordScoreList []WordScore
+
+// Scan 实现 sql.Scanner 接口，从数据库读取 JSON 数据
+func (wl *WordScoreList) Scan(value interface{}) error {
+	if value == nil {
+		*wl = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("WordScoreList.Scan: failed to convert value to []byte")
+	}
+	return json.Unmarshal(bytes, wl)
+}
+
+// Value 实现 driver.Valuer 接口，写入数据库时序列化为 JSON
+func (wl WordScoreList) Value() (driver.Value, error) {
+	if wl == nil {
+		return nil, nil
+	}
+	return json.Marshal(wl)
+}
+
+// ========== QAQuestionList ==========
+
+// QAQuestion 问答题目
+type QAQuestion struct {
+	// Question 题目（AI 向孩子提问的原文）
+	Question string `json:"question"`
+	// ReferenceAnswer 参考答案（用于判定与多次答错后示范）
+	ReferenceAnswer string `json:"reference_answer"`
+	// Hint 提示（答案偏题或不完整时给出）
+	Hint string `json:"hint,omitempty"`
+	// Keywords 答案中期望出现的关键词
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// QAQuestionList 问答题目列表（用于 JSON 列的序列化/反序列化）
+// 使用场景：question_sets.questions ([{"question":"What is the cat's name?","reference_answer":"Its name is Tom.","hint":"...","keywords":["Tom"]}])
+type QAQuestionList []QAQuestion
+
+// Scan 实现 sql.Scanner 接口，从数据库读取 JSON 数据
+func (ql *QAQuestionList) Scan(value interface{}) error {
+	if value == nil {
+		*ql = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("QAQuestionList.Scan: failed to convert value to []byte")
+	}
+	return json.Unmarshal(bytes, ql)
+}
+
+// Value 实现 driver.Valuer 接口，写入数据库时序列化为 JSON
+func (ql QAQuestionList) Value() (driver.Value, error) {
+	if ql == nil {
+		return nil, nil
+	}
+	return json.Marshal(ql)
+}
+
+// ========== QuestionResultList ==========
+
+// QuestionResult 问答会话中单个题目的作答结果
+type QuestionResult struct {
+	// Index 题目在题集中的位置（从 0 开始）
+	Index int `json:"index"`
+	// Question 题目原文
+	Question string `json:"question"`
+	// Answer 孩子的回答（ASR 文本，多次作答时为最后一次）
+	Answer string `json:"answer"`
+	// Relevant 回答是否切题
+	Relevant bool `json:"relevant"`
+	// Grammatical 回答是否符合语法
+	Grammatical bool `json:"grammatical"`
+	// Score 回答得分（0-100）
+	Score int `json:"score"`
+	// Feedback 给孩子的简短反馈
+	Feedback string `json:"feedback"`
+	// Corrected 规范的回答示范（回答有语法问题时给出）
+	Corrected string `json:"corrected,omitempty"`
+	// Attempts 作答次数
+	Attempts int `json:"attempts"`
+	// Passed 是否通过（未通过表示作答次数用尽）
+	Passed bool `json:"passed"`
+}
+
+// QuestionResultList 问答结果列表（用于 JSON 列的序列化/反序列化）
+// 使用场景：voice_conversations.question_results
+type QuestionResultList []QuestionResult
+
+// Scan 实现 sql.Scanner 接口，从数据库读取 JSON 数据
+func (rl *QuestionResultList) Scan(value interface{}) error {
+	if value == nil {
+		*rl = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("QuestionResultList.Scan: failed to convert value to []byte")
+	}
+	return json.Unmarshal(bytes, rl)
+}
+
+// Value 实现 driver.Valuer 接口，写入数据库时序列化为 JSON
+func (rl QuestionResultList) Value() (driver.Value, error) {
+	if rl == nil {
+		return nil, nil
+	}
+	return json.Marshal(rl)
+}
+
+// ========== GrammarAnnotationList ==========
+
+// GrammarAnnotation 用户消息中的一处语法 / 用词错误
+type GrammarAnnotation struct {
+	// Original 原文中的错误片段
+	Original string `json:"original"`
+	// Corrected 修改后的正确说法
+	Corrected string `json:"corrected"`
+	// ErrorType 错误类型（见 GrammarError* 常量）
+	ErrorType string `json:"error_type"`
+	// Explanation 面向孩子的简短解释
+	Explanation string `json:"explanation"`
+}
+
+// GrammarAnnotationList 语法批注列表（用于 JSON 列的序列化/反序列化）
+// 使用场景：conversation_messages.annotations；空数组表示已分析且没有错误，NULL 表示尚未分析
+type GrammarAnnotationList []GrammarAnnotation
+
+// Scan 实现 sql.Scanner 接口，从数据库读取 JSON 数据
+func (al *GrammarAnnotationList) Scan(value interface{}) error {
+	if value == nil {
+		*al = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("GrammarAnnotationList.Scan: failed to convert value to []byte")
+	}
+	return json.Unmarshal(bytes, al)
+}
+
+// Value 实现 driver.Valuer 接口，写入数据库时序列化为 JSON
+func (al GrammarAnnotationList) Value() (driver.Value, error) {
+	if al == nil {
+		return nil, nil
+	}
+	return json.Marshal(al)
+}
+
+// ========== StageLatencies ==========
+
+// StageLatencies 各处理阶段耗时（毫秒，用于 JSON 列的序列化/反序列化）
+// 使用场景：stage_latencies ({"asr": 420, "llm": 1300, "tts": 650, "oss": 80})，阶段名见 LatencyStage* 常量
+type StageLatencies map[string]int
+
+// Scan 实现 sql.Scanner 接口，从数据库读取 JSON 数据
+func (sl *StageLatencies) Scan(value interface{}) error {
+	if value == nil {
+		*sl = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("StageLatencies.Scan: failed to convert value to []byte")
+	}
+	return json.Unmarshal(bytes, sl)
+}
+
+// Value 实现 driver.Valuer 接口，写入数据库时序列化为 JSON
+func (sl StageLatencies) Value() (driver.Value, error) {
+	if sl == nil {
+		return nil, nil
+	}
+	return json.Marshal(sl)
+}
+
+// ========== ReplyVersionList ==========
+
+// ReplyVersion AI 回复被替换前的一个版本
+type ReplyVersion struct {
+	// Text 回复文本（生成失败的版本为空）
+	Text string `json:"text"`
+	// Translation 中文释义
+	Translation *string `json:"translation,omitempty"`
+	// AudioURL 回复音频 URL（合成失败的版本为空）
+	AudioURL *string `json:"audio_url,omitempty"`
+	// PromptVersion 生成回复的提示词版本
+	PromptVersion *string `json:"prompt_version,omitempty"`
+	// FailedStage 该版本失败的处理阶段（见 TurnFailedStage* 常量；成功的版本为空）
+	FailedStage *string `json:"failed_stage,omitempty"`
+	// Reason 被替换的原因（见 ReplyReason* 常量）
+	Reason string `json:"reason"`
+	// CreatedAt 该版本生成时间
+	CreatedAt time.Time `json:"created_at"`
+	// ReplacedAt 被替换的时间
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// ReplyVersionList 被替换的回复版本列表（用于 JSON 列的序列化/反序列化）
+// 使用场景：conversation_messages.reply_versions（仅 AI 消息，按替换先后排列；当前版本号 = 列表长度 + 1）
+type ReplyVersionList []ReplyVersion
+
+// Scan 实现 sql.Scanner 接口，从数据库读取 JSON 数据
+func (rl *ReplyVersionList) Scan(value interface{}) error {
+	if value == nil {
+		*rl = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("ReplyVersionList.Scan: failed to convert value to []byte")
+	}
+	return json.Unmarshal(bytes, rl)
+}
+
+// Value 实现 driver.Valuer 接口，写入数据库时序列化为 JSON
+func (rl ReplyVersionList) Value() (driver.Value, error) {
+	if rl == nil {
+		return nil, nil
+	}
+	return json.Marshal(rl)
+}
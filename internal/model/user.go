@@ -21,6 +21,10 @@ type User struct {
 	AvatarURL *string `gorm:"type:varchar(500)" json:"avatar_url,omitempty" validate:"omitempty,url,max=500"`
 	// Grade 年级 (1-6 代表小学 1-6 年级)
 	Grade *int `gorm:"type:int" json:"grade,omitempty" validate:"omitempty,min=1,max=6"`
+	// Plan 用户套餐：free/premium/admin（决定每日配额）
+	Plan string `gorm:"type:varchar(20);not null;default:'free'" json:"plan" validate:"omitempty,max=20"`
+	// IsAdmin 是否管理员（可访问管理后台，与套餐无关）
+	IsAdmin bool `gorm:"type:tinyint(1);not null;default:0" json:"-"`
 	// CreatedAt 创建时间
 	CreatedAt time.Time `gorm:"autoCreateTime;type:timestamp;index" json:"created_at"`
 	// UpdatedAt 更新时间
@@ -64,6 +68,10 @@ type UserProfile struct {
 	TotalStudyMinutes int `gorm:"type:int;default:0;not null" json:"total_study_minutes" validate:"gte=0"`
 	// AverageEvaluationScore 平均评测分数
 	AverageEvaluationScore float64 `gorm:"type:float;default:0.0;not null" json:"average_evaluation_score" validate:"gte=0,lte=100"`
+	// LanguagePolicy 对话回复语言策略：english_only/chinese_scaffolding/translate_on_demand
+	LanguagePolicy string `gorm:"type:varchar(30);default:'chinese_scaffolding';not null" json:"language_policy" validate:"omitempty,oneof=english_only chinese_scaffolding translate_on_demand"`
+	// PersonaID 选择的 AI 老师人设 ID（为空时使用默认老师）
+	PersonaID *string `gorm:"type:varchar(36);index" json:"persona_id,omitempty" validate:"omitempty,uuid"`
 	// LastConversationAt 上次对话时间
 	LastConversationAt *time.Time `gorm:"type:timestamp;index" json:"last_conversation_at,omitempty"`
 	// LastEvaluationAt 上次评测时间
@@ -0,0 +1,48 @@
+// Package model 定义个人词汇表相关数据模型
+package model
+
+import (
+	"time"
+)
+
+// UserVocabulary 个人词汇表
+// 记录每个用户说出（对话 ASR 文本）或朗读（发音评测目标文本）过的英文单词，按词元累计
+// 对应数据库表: user_vocabulary
+//
+// 每个用户的同一词元只保留一条记录，再次出现时累计次数并更新最后出现时间
+type UserVocabulary struct {
+	// ID 词汇 ID (UUID)
+	ID string `gorm:"primaryKey;type:varchar(36)" json:"id" validate:"required,uuid"`
+	// UserID 用户 ID，外键
+	UserID string `gorm:"uniqueIndex:uk_user_vocabulary_user_word;index:idx_user_vocabulary_user_first_seen,priority:1;type:varchar(36);not null" json:"user_id" validate:"required,uuid"`
+	// Word 词元（小写，如 went → go）
+	Word string `gorm:"uniqueIndex:uk_user_vocabulary_user_word;type:varchar(50);not null" json:"word" validate:"required,max=50"`
+	// Band 词汇等级：A1/A2/B1/B2（来自内置词频表），未收录为 unlisted
+	Band string `gorm:"index;type:varchar(10);not null" json:"band" validate:"required,max=10"`
+	// FrequencyRank 词频表排名（越小越常用，未收录为 0）
+	FrequencyRank int `gorm:"type:int;default:0;not null" json:"frequency_rank" validate:"gte=0"`
+
+	// === 统计字段 ===
+	// Frequency 累计出现次数（说出 + 朗读）
+	Frequency int `gorm:"type:int;default:0;not null" json:"frequency" validate:"gte=0"`
+	// SpokenCount 在对话中说出的次数
+	SpokenCount int `gorm:"type:int;default:0;not null" json:"spoken_count" validate:"gte=0"`
+	// ReadCount 在发音评测中朗读的次数
+	ReadCount int `gorm:"type:int;default:0;not null" json:"read_count" validate:"gte=0"`
+	// FirstSeenAt 首次出现时间
+	FirstSeenAt time.Time `gorm:"index:idx_user_vocabulary_user_first_seen,priority:2;type:timestamp;not null" json:"first_seen_at"`
+	// LastSeenAt 最后出现时间
+	LastSeenAt time.Time `gorm:"type:timestamp;not null" json:"last_seen_at"`
+	// CreatedAt 创建时间
+	CreatedAt time.Time `gorm:"autoCreateTime;type:timestamp" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt time.Time `gorm:"autoUpdateTime;type:timestamp" json:"updated_at"`
+
+	// 关联
+	User *User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+// TableName 指定表名
+func (UserVocabulary) TableName() string {
+	return "user_vocabulary"
+}
@@ -17,11 +17,25 @@ type VoiceConversation struct {
 	Topic string `gorm:"type:varchar(200);not null" json:"topic" validate:"required,max=200"`
 	// DifficultyLevel 难度等级：beginner/intermediate/advanced
 	DifficultyLevel string `gorm:"index;type:enum('beginner','intermediate','advanced');default:'beginner';not null" json:"difficulty_level" validate:"required,oneof=beginner intermediate advanced"`
-	// ConversationType 对话类型：free_talk/question_answer
-	ConversationType string `gorm:"index;type:enum('free_talk','question_answer');default:'free_talk';not null" json:"conversation_type" validate:"required,oneof=free_talk question_answer"`
+	// ConversationType 对话类型：free_talk/question_answer/role_play
+	ConversationType string `gorm:"index;type:enum('free_talk','question_answer','role_play');default:'free_talk';not null" json:"conversation_type" validate:"required,oneof=free_talk question_answer role_play"`
+	// ScenarioID 角色扮演场景 ID（仅 role_play 会话）
+	ScenarioID *string `gorm:"index;type:varchar(36)" json:"scenario_id,omitempty" validate:"omitempty,uuid"`
+	// MetCriteria 已达成的场景成功标准（JSON 数组，元素为场景 SuccessCriteria 原文）
+	MetCriteria StringArray `gorm:"type:json" json:"met_criteria,omitempty"`
+	// GoalAchievedAt 完成场景目标的时间（nil 表示尚未完成）
+	GoalAchievedAt *time.Time `gorm:"type:timestamp" json:"goal_achieved_at,omitempty"`
+	// QuestionSetID 问答题集 ID（仅 question_answer 会话）
+	QuestionSetID *string `gorm:"index;type:varchar(36)" json:"question_set_id,omitempty" validate:"omitempty,uuid"`
+	// QuestionIndex 当前题目序号（从 0 开始，等于题目数时表示已答完）
+	QuestionIndex int `gorm:"type:int;default:0;not null" json:"question_index"`
+	// QuestionAttempts 当前题目已作答次数
+	QuestionAttempts int `gorm:"type:int;default:0;not null" json:"-"`
+	// QuestionResults 已完成题目的作答结果（JSON 数组）
+	QuestionResults QuestionResultList `gorm:"type:json" json:"question_results,omitempty"`
 	// MessageCount 消息总数（包括用户和 AI）
 	MessageCount int `gorm:"type:int;default:0;not null" json:"message_count" validate:"gte=0"`
-	// DurationSeconds 对话时长（秒）
+	// DurationSeconds 对话时长（秒，仅累计 active 状态下的交互时间，暂停与空闲不计入）
 	DurationSeconds int `gorm:"type:int;default:0;not null" json:"duration_seconds" validate:"gte=0"`
 	// Status 状态：active/completed/paused
 	Status string `gorm:"index;type:enum('active','completed','paused');default:'active';not null" json:"status" validate:"required,oneof=active completed paused"`
@@ -31,6 +45,26 @@ type VoiceConversation struct {
 	Score *int `gorm:"type:int" json:"score,omitempty" validate:"omitempty,min=0,max=100"`
 	// Feedback AI 反馈内容（可选）
 	Feedback *string `gorm:"type:text" json:"feedback,omitempty"`
+	// ProblemWords 会话中在多轮发言里都发音不准的单词（JSON 数组，会话总结时汇总）
+	ProblemWords StringArray `gorm:"type:json" json:"problem_words,omitempty"`
+	// ContextSummary 较早轮次的滚动摘要（多轮对话中代替原文放入 LLM 上下文）
+	ContextSummary *string `gorm:"type:text" json:"-"`
+	// SummarizedUntil 已并入 ContextSummary 的最大消息序号（0 表示尚未摘要）
+	SummarizedUntil int `gorm:"type:int;default:0;not null" json:"-"`
+	// LearnerMemory 会话开始时注入系统提示词的长期记忆（同一会话内保持不变，NULL 表示没有可用记忆）
+	LearnerMemory *string `gorm:"type:text" json:"-"`
+	// LastMessageAt 最后一条消息时间（会话列表按此排序）
+	LastMessageAt *time.Time `gorm:"index;type:timestamp" json:"last_message_at,omitempty"`
+	// LastActiveAt 最近一次活跃时间（开始、每轮对话、恢复时更新；空闲超时与时长累计以此为起点）
+	LastActiveAt *time.Time `gorm:"index;type:timestamp" json:"-"`
+	// PausedAt 暂停时间（非暂停状态为 NULL）
+	PausedAt *time.Time `gorm:"type:timestamp" json:"paused_at,omitempty"`
+	// FlaggedAt 命中内容安全审核后标记待复核的时间（nil 表示未标记）
+	FlaggedAt *time.Time `gorm:"index;type:timestamp" json:"-"`
+	// FlagReason 标记原因（如 "input:violence,output:pii"）
+	FlagReason *string `gorm:"type:varchar(255)" json:"-"`
+	// StatsRecordedAt 会话结束后已累计用户学习统计的时间（nil 表示尚未累计，与会话总结分开记录）
+	StatsRecordedAt *time.Time `gorm:"type:timestamp" json:"-"`
 	// CreatedAt 创建时间
 	CreatedAt time.Time `gorm:"index;autoCreateTime;type:timestamp" json:"created_at"`
 	// UpdatedAt 更新时间
@@ -39,8 +73,10 @@ type VoiceConversation struct {
 	DeletedAt *time.Time `gorm:"type:timestamp;index" json:"deleted_at,omitempty"`
 
 	// 关联
-	User     *User                  `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
-	Messages []*ConversationMessage `gorm:"foreignKey:ConversationID" json:"messages,omitempty"`
+	User        *User                  `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	Scenario    *ConversationScenario  `gorm:"foreignKey:ScenarioID;references:ID" json:"scenario,omitempty"`
+	QuestionSet *QuestionSet           `gorm:"foreignKey:QuestionSetID;references:ID" json:"question_set,omitempty"`
+	Messages    []*ConversationMessage `gorm:"foreignKey:ConversationID" json:"messages,omitempty"`
 }
 
 // TableName 指定表名
@@ -66,8 +102,28 @@ type ConversationMessage struct {
 	AudioDuration *int `gorm:"type:int" json:"audio_duration,omitempty" validate:"omitempty,gte=0"`
 	// SequenceNumber 消息序号（对话内的顺序，从 1 开始）
 	SequenceNumber int `gorm:"type:int;not null" json:"sequence_number" validate:"required,gte=1"`
-	// LatencyMS 处理延迟（毫秒，用于性能分析）
+	// Annotations 语法 / 用词批注（仅用户消息，JSON 数组；NULL 表示尚未分析）
+	Annotations GrammarAnnotationList `gorm:"type:json" json:"annotations,omitempty"`
+	// Language 识别文本的语言：zh/en/mixed（仅用户消息；无法判断时为 NULL）
+	Language *string `gorm:"type:varchar(10)" json:"language,omitempty"`
+	// Translation AI 回复的中文释义（仅按语言策略生成时有值）
+	Translation *string `gorm:"type:text" json:"translation,omitempty"`
+	// PronunciationScore 发音综合得分（0-100，仅用户消息；NULL 表示尚未评测或不评测）
+	PronunciationScore *int `gorm:"type:int" json:"pronunciation_score,omitempty" validate:"omitempty,min=0,max=100"`
+	// WordScores 单词级发音得分（仅用户消息，JSON 数组）
+	WordScores WordScoreList `gorm:"type:json" json:"word_scores,omitempty"`
+	// ProblemWords 发音问题单词（得分 < 60，仅用户消息，JSON 数组）
+	ProblemWords StringArray `gorm:"type:json" json:"problem_words,omitempty"`
+	// PromptVersion 生成回复的提示词版本（仅 LLM 生成的 AI 消息）
+	PromptVersion *string `gorm:"type:varchar(20)" json:"prompt_version,omitempty"`
+	// LatencyMS 处理延迟（毫秒，用于性能分析；仅 AI 消息，从收到请求到回复音频上传完成）
 	LatencyMS *int `gorm:"type:int" json:"latency_ms,omitempty" validate:"omitempty,gte=0"`
+	// StageLatencies 各处理阶段耗时（毫秒，仅 AI 消息，JSON 对象：{"asr": 420, "llm": 1300, ...}）
+	StageLatencies StageLatencies `gorm:"type:json" json:"stage_latencies,omitempty"`
+	// FailedStage 处理失败的阶段：llm/tts（仅 AI 消息；NULL 表示成功，失败的轮次可重试）
+	FailedStage *string `gorm:"type:varchar(20)" json:"failed_stage,omitempty"`
+	// ReplyVersions 被重新生成或重试替换的历史回复（仅 AI 消息，JSON 数组；当前版本号 = 数组长度 + 1）
+	ReplyVersions ReplyVersionList `gorm:"type:json" json:"reply_versions,omitempty"`
 	// CreatedAt 创建时间
 	CreatedAt time.Time `gorm:"index;autoCreateTime;type:timestamp" json:"created_at"`
 	// UpdatedAt 更新时间
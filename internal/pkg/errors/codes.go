@@ -12,6 +12,8 @@ const (
 	CodeNotFound      = 1004
 	CodeConflict      = 1005
 	CodeTooManyRequests = 1006
+	CodeQuotaExceeded   = 1007
+	CodeIdempotencyKeyReused = 1008
 
 	// 用户相关错误码 (2000-2999)
 	CodeUserNotFound      = 2000
@@ -46,6 +48,17 @@ const (
 	CodeQueueError    = 7000
 	CodeTaskNotFound  = 7001
 	CodeTaskFailed    = 7002
+
+	// 对话相关错误码 (9000-9999，8000-8999 为数据库错误码)
+	CodeConversationNotFound     = 9000
+	CodeConversationLimitReached = 9001
+	CodeConversationClosed       = 9002
+	CodeScenarioNotFound         = 9003
+	CodeQuestionSetNotFound      = 9004
+	CodePersonaNotFound          = 9005
+	CodeMemoryNotFound           = 9006
+	CodeConversationPaused       = 9007
+	CodeChatTurnFailed           = 9008
 )
 
 // 预定义错误
@@ -57,6 +70,8 @@ var (
 	ErrNotFound      = New(CodeNotFound, "resource not found")
 	ErrConflict      = New(CodeConflict, "resource conflict")
 	ErrTooManyRequests = New(CodeTooManyRequests, "too many requests")
+	ErrQuotaExceeded   = New(CodeQuotaExceeded, "daily quota exceeded")
+	ErrIdempotencyKeyReused = New(CodeIdempotencyKeyReused, "idempotency key reused with a different request")
 
 	ErrUserNotFound      = New(CodeUserNotFound, "user not found")
 	ErrUserAlreadyExists = New(CodeUserAlreadyExists, "user already exists")
@@ -73,4 +88,14 @@ var (
 
 	ErrFeedbackNotFound  = New(CodeFeedbackNotFound, "feedback not found")
 	ErrFeedbackGenFailed = New(CodeFeedbackGenFailed, "feedback generation failed")
+
+	ErrConversationNotFound     = New(CodeConversationNotFound, "conversation not found")
+	ErrConversationLimitReached = New(CodeConversationLimitReached, "conversation message limit reached")
+	ErrConversationClosed       = New(CodeConversationClosed, "conversation is not active")
+	ErrScenarioNotFound         = New(CodeScenarioNotFound, "scenario not found")
+	ErrQuestionSetNotFound      = New(CodeQuestionSetNotFound, "question set not found")
+	ErrPersonaNotFound          = New(CodePersonaNotFound, "persona not found")
+	ErrMemoryNotFound           = New(CodeMemoryNotFound, "memory not found")
+	ErrConversationPaused       = New(CodeConversationPaused, "conversation is paused")
+	ErrChatTurnFailed           = New(CodeChatTurnFailed, "chat turn failed, please retry")
 )
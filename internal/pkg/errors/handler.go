@@ -2,9 +2,20 @@
 package errors
 
 import (
+	stderrors "errors"
 	"net/http"
 )
 
+// PublicError 返回错误对应的 HTTP 状态码与可返回给客户端的消息（支持被 fmt.Errorf %w 包装的 AppError）
+// 非 AppError 统一为 500 与通用消息，原始错误（SQL、第三方服务信息等）只记录日志，不返回给客户端
+func PublicError(err error) (int, string) {
+	var appErr *AppError
+	if !stderrors.As(err, &appErr) {
+		return http.StatusInternalServerError, ErrInternalError.Message
+	}
+	return HTTPStatusCode(appErr), appErr.Message
+}
+
 // HTTPStatusCode 获取 HTTP 状态码
 func HTTPStatusCode(err error) int {
 	if appErr, ok := err.(*AppError); ok {
@@ -17,12 +28,19 @@ func HTTPStatusCode(err error) int {
 			return http.StatusUnauthorized
 		case appErr.Code == CodeForbidden:
 			return http.StatusForbidden
-		case appErr.Code == CodeNotFound, appErr.Code == CodeUserNotFound, appErr.Code == CodeEvaluationNotFound, appErr.Code == CodeFeedbackNotFound:
+		case appErr.Code == CodeNotFound, appErr.Code == CodeUserNotFound, appErr.Code == CodeEvaluationNotFound, appErr.Code == CodeFeedbackNotFound,
+			appErr.Code == CodeConversationNotFound, appErr.Code == CodeScenarioNotFound, appErr.Code == CodeQuestionSetNotFound,
+			appErr.Code == CodePersonaNotFound, appErr.Code == CodeMemoryNotFound:
 			return http.StatusNotFound
-		case appErr.Code == CodeConflict, appErr.Code == CodeUserAlreadyExists:
+		case appErr.Code == CodeConflict, appErr.Code == CodeUserAlreadyExists,
+			appErr.Code == CodeConversationLimitReached, appErr.Code == CodeConversationClosed, appErr.Code == CodeConversationPaused:
 			return http.StatusConflict
-		case appErr.Code == CodeTooManyRequests:
+		case appErr.Code == CodeIdempotencyKeyReused:
+			return http.StatusUnprocessableEntity
+		case appErr.Code == CodeTooManyRequests, appErr.Code == CodeQuotaExceeded:
 			return http.StatusTooManyRequests
+		case appErr.Code == CodeChatTurnFailed:
+			return http.StatusBadGateway
 		default:
 			return http.StatusInternalServerError
 		}
@@ -0,0 +1,27 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestPublicError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{"app error", ErrQuotaExceeded, http.StatusTooManyRequests, ErrQuotaExceeded.Message},
+		{"wrapped app error", fmt.Errorf("resolve conversation: %w", ErrConversationNotFound), http.StatusNotFound, ErrConversationNotFound.Message},
+		{"plain error hides detail", stderrors.New("Error 1054: Unknown column 'foo'"), http.StatusInternalServerError, ErrInternalError.Message},
+	}
+	for _, tt := range tests {
+		status, message := PublicError(tt.err)
+		if status != tt.wantStatus || message != tt.wantMessage {
+			t.Errorf("%s: PublicError() = %d, %q; want %d, %q", tt.name, status, message, tt.wantStatus, tt.wantMessage)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Package sentence 提供按句子边界切分文本的工具
+// 用于流式 LLM 输出：凑满一句即可开始 TTS，无需等待完整回复
+package sentence
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Chunker 增量句子切分器（非并发安全）
+// 持续写入文本增量，每当出现完整句子时返回；过短的句子与下一句合并，减少 TTS 调用次数
+type Chunker struct {
+	minRunes int    // 句子最小长度（字符数）
+	buf      []rune // 尚未输出的文本
+	scanned  int    // buf 中已确认不是句末的位置
+}
+
+// NewChunker 创建句子切分器，minRunes 为单句最小长度（<= 0 时不合并）
+func NewChunker(minRunes int) *Chunker {
+	return &Chunker{minRunes: minRunes}
+}
+
+// Push 写入文本增量，返回其中已完整的句子（可能为空）
+// 句末标点位于缓冲区末尾时暂不切分，等待后续文本确认（如 "3." 之后可能是 "5"）
+func (c *Chunker) Push(delta string) []string {
+	c.buf = append(c.buf, []rune(delta)...)
+
+	var sentences []string
+	for c.scanned < len(c.buf) {
+		i := c.scanned
+		if !IsSentenceEnd(c.buf[i]) {
+			c.scanned++
+			continue
+		}
+
+		// 连续的句末标点（如 "?!"、"..."）及其后的右引号、右括号归入当前句
+		end := i + 1
+		for end < len(c.buf) && (IsSentenceEnd(c.buf[end]) || isClosing(c.buf[end])) {
+			end++
+		}
+		if end == len(c.buf) {
+			break
+		}
+		// 英文句点后紧跟字母或数字（如 3.5、e.g.）不视为句末
+		if c.buf[i] == '.' && end == i+1 && !unicode.IsSpace(c.buf[end]) {
+			c.scanned = end
+			continue
+		}
+
+		sentence := strings.TrimSpace(string(c.buf[:end]))
+		if len([]rune(sentence)) < c.minRunes {
+			c.scanned = end
+			continue
+		}
+		if sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		c.buf = c.buf[end:]
+		c.scanned = 0
+	}
+	return sentences
+}
+
+// Flush 返回缓冲区中剩余的文本（文本结束时调用），并重置切分器
+func (c *Chunker) Flush() string {
+	rest := strings.TrimSpace(string(c.buf))
+	c.buf = nil
+	c.scanned = 0
+	return rest
+}
+
+// Split 将完整文本按句末标点切分，过短的句子与下一句合并
+func Split(text string, minRunes int) []string {
+	c := NewChunker(minRunes)
+	sentences := c.Push(text)
+	if rest := c.Flush(); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
+
+// IsSentenceEnd 判断是否为句末标点（中英文）
+func IsSentenceEnd(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '！', '？', '…', '\n':
+		return true
+	}
+	return false
+}
+
+// isClosing 判断是否为句末标点后可能出现的右引号 / 右括号
+func isClosing(r rune) bool {
+	switch r {
+	case '"', '\'', ')', '”', '’', '）', '】', '」':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package sentence
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunkerPush(t *testing.T) {
+	tests := []struct {
+		name     string
+		minRunes int
+		deltas   []string
+		want     []string
+		rest     string
+	}{
+		{
+			name:   "waits for text after the final period",
+			deltas: []string{"Hello there. How", " are you?"},
+			want:   []string{"Hello there."},
+			rest:   "How are you?",
+		},
+		{
+			name:   "decimal number is not a sentence end",
+			deltas: []string{"It is 3.", "5 meters long. Wow"},
+			want:   []string{"It is 3.5 meters long."},
+			rest:   "Wow",
+		},
+		{
+			name:   "punctuation run and closing quote stay together",
+			deltas: []string{`He said "Really?!" and left. `},
+			want:   []string{`He said "Really?!"`, "and left."},
+		},
+		{
+			name:     "short sentences merge with the next one",
+			minRunes: 12,
+			deltas:   []string{"Hi! Nice to meet you. Bye"},
+			want:     []string{"Hi! Nice to meet you."},
+			rest:     "Bye",
+		},
+		{
+			name:   "chinese punctuation",
+			deltas: []string{"你好。今天", "天气很好！"},
+			want:   []string{"你好。"},
+			rest:   "今天天气很好！",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChunker(tt.minRunes)
+			var got []string
+			for _, delta := range tt.deltas {
+				got = append(got, c.Push(delta)...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sentences = %q, want %q", got, tt.want)
+			}
+			if rest := c.Flush(); rest != tt.rest {
+				t.Errorf("Flush() = %q, want %q", rest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	got := Split("I like cats. Do you? Yes!", 0)
+	want := []string{"I like cats.", "Do you?", "Yes!"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Split = %q, want %q", got, want)
+	}
+	if got := Split("", 0); len(got) != 0 {
+		t.Errorf("Split(\"\") = %q, want empty", got)
+	}
+}
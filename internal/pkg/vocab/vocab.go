@@ -0,0 +1,199 @@
+// Package vocab 提供英文文本的分词、词形还原与词汇等级查询
+// 用于个人词汇表：统计孩子说出 / 朗读过的单词（按词元计数），等级来自内置词频表
+package vocab
+
+import (
+	"strings"
+	"unicode"
+)
+
+// contractions 缩写后缀 → 展开后的单词（'s / 'd 含义不唯一，只保留前面的单词）
+var contractions = []struct {
+	suffix string
+	word   string
+}{
+	{"n't", "not"},
+	{"'re", "are"},
+	{"'m", "am"},
+	{"'ll", "will"},
+	{"'ve", "have"},
+	{"'s", ""},
+	{"'d", ""},
+}
+
+// irregularContractions 需要整体改写的否定缩写
+var irregularContractions = map[string][]string{
+	"can't":  {"can", "not"},
+	"won't":  {"will", "not"},
+	"shan't": {"shall", "not"},
+	"ain't":  {"be", "not"},
+}
+
+// irregularForms 不规则变化 → 词元
+var irregularForms = map[string]string{
+	// be / have / do
+	"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be", "being": "be",
+	"has": "have", "had": "have", "having": "have",
+	"does": "do", "did": "do", "done": "do", "doing": "do",
+	// 不规则动词
+	"went": "go", "gone": "go", "goes": "go",
+	"ate": "eat", "eaten": "eat",
+	"saw": "see", "seen": "see",
+	"came": "come", "got": "get", "gotten": "get",
+	"made": "make", "took": "take", "taken": "take",
+	"gave": "give", "given": "give",
+	"knew": "know", "known": "know",
+	"thought": "think", "bought": "buy", "brought": "bring",
+	"caught": "catch", "taught": "teach", "fought": "fight",
+	"said": "say", "told": "tell", "sold": "sell",
+	"found": "find", "felt": "feel", "kept": "keep", "slept": "sleep",
+	"meant": "mean", "met": "meet", "sent": "send", "spent": "spend",
+	"built": "build", "lent": "lend", "lost": "lose", "held": "hold",
+	"stood": "stand", "understood": "understand",
+	"ran": "run", "swam": "swim", "swum": "swim", "sang": "sing", "sung": "sing",
+	"drank": "drink", "drunk": "drink", "began": "begin", "begun": "begin",
+	"rang": "ring", "rung": "ring",
+	"wrote": "write", "written": "write", "rode": "ride", "ridden": "ride",
+	"drove": "drive", "driven": "drive",
+	"flew": "fly", "flown": "fly", "drew": "draw", "drawn": "draw",
+	"grew": "grow", "grown": "grow", "threw": "throw", "thrown": "throw",
+	"blew": "blow", "blown": "blow",
+	"wore": "wear", "worn": "wear", "tore": "tear", "torn": "tear",
+	"broke": "break", "broken": "break", "spoke": "speak", "spoken": "speak",
+	"woke": "wake", "woken": "wake", "chose": "choose", "chosen": "choose",
+	"froze": "freeze", "frozen": "freeze", "stole": "steal", "stolen": "steal",
+	"forgot": "forget", "forgotten": "forget",
+	"fell": "fall", "fallen": "fall", "hid": "hide", "hidden": "hide",
+	"bitten": "bite", "shook": "shake", "shaken": "shake",
+	"sat": "sit", "won": "win", "dug": "dig", "hung": "hang", "fed": "feed", "led": "lead",
+	"heard": "hear", "paid": "pay",
+	// 不规则名词复数
+	"children": "child", "men": "man", "women": "woman",
+	"feet": "foot", "teeth": "tooth", "mice": "mouse", "geese": "goose",
+	"knives": "knife", "leaves": "leaf",
+	"wolves": "wolf", "lives": "life", "wives": "wife", "halves": "half",
+	"shelves": "shelf", "scarves": "scarf", "loaves": "loaf", "potatoes": "potato",
+	"tomatoes": "tomato", "heroes": "hero",
+	// 不规则比较级
+	"better": "good", "best": "good", "worse": "bad", "worst": "bad",
+	"farther": "far", "farthest": "far",
+}
+
+// Tokenize 提取文本中的英文单词（小写），展开常见缩写；忽略汉字、数字与被打码的内容
+// 单个字母只保留 a 与 i
+func Tokenize(text string) []string {
+	var tokens []string
+	for _, field := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r < unicode.MaxASCII && unicode.IsLetter(r)) && r != '\'' && r != '’'
+	}) {
+		field = strings.Trim(strings.ReplaceAll(field, "’", "'"), "'")
+		if field == "" {
+			continue
+		}
+		tokens = append(tokens, expandContraction(field)...)
+	}
+
+	words := tokens[:0]
+	for _, t := range tokens {
+		if len(t) == 1 && t != "a" && t != "i" {
+			continue
+		}
+		words = append(words, t)
+	}
+	return words
+}
+
+// expandContraction 展开缩写（如 don't → do not，I'm → i am）
+func expandContraction(word string) []string {
+	if parts, ok := irregularContractions[word]; ok {
+		return parts
+	}
+	for _, c := range contractions {
+		if base, ok := strings.CutSuffix(word, c.suffix); ok && base != "" {
+			if c.word == "" {
+				return []string{base}
+			}
+			return []string{base, c.word}
+		}
+	}
+	if strings.Contains(word, "'") && word != "o'clock" {
+		return nil
+	}
+	return []string{word}
+}
+
+// Lemmatize 将单词还原为词元（小写输入）
+// 先查不规则变化表，再按后缀规则生成候选词元，取第一个在词频表中收录的候选；都未收录时返回原词
+func Lemmatize(word string) string {
+	if lemma, ok := irregularForms[word]; ok {
+		return lemma
+	}
+	if _, ok := wordlist[word]; ok {
+		return word
+	}
+	for _, candidate := range lemmaCandidates(word) {
+		if _, ok := wordlist[candidate]; ok {
+			return candidate
+		}
+	}
+	return word
+}
+
+// lemmaCandidates 按常见屈折后缀生成候选词元（复数 / 第三人称、过去式、进行时、比较级与最高级）
+func lemmaCandidates(word string) []string {
+	var candidates []string
+	add := func(stem string, suffixes ...string) {
+		if len(stem) < 2 {
+			return
+		}
+		for _, s := range suffixes {
+			candidates = append(candidates, stem+s)
+		}
+		// 双写辅音（stopped → stop，running → run，bigger → big）
+		if n := len(stem); n >= 3 && stem[n-1] == stem[n-2] && !isVowel(stem[n-1]) {
+			candidates = append(candidates, stem[:n-1])
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		add(strings.TrimSuffix(word, "ies"), "y", "ie")
+	case strings.HasSuffix(word, "es"):
+		add(strings.TrimSuffix(word, "es"), "", "e")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		add(strings.TrimSuffix(word, "s"), "")
+	}
+	switch {
+	case strings.HasSuffix(word, "ied"):
+		add(strings.TrimSuffix(word, "ied"), "y")
+	case strings.HasSuffix(word, "ed"):
+		add(strings.TrimSuffix(word, "ed"), "", "e")
+	case strings.HasSuffix(word, "ing"):
+		add(strings.TrimSuffix(word, "ing"), "", "e")
+	}
+	switch {
+	case strings.HasSuffix(word, "iest"):
+		add(strings.TrimSuffix(word, "iest"), "y")
+	case strings.HasSuffix(word, "ier"):
+		add(strings.TrimSuffix(word, "ier"), "y")
+	case strings.HasSuffix(word, "est"):
+		add(strings.TrimSuffix(word, "est"), "", "e")
+	case strings.HasSuffix(word, "er"):
+		add(strings.TrimSuffix(word, "er"), "", "e")
+	}
+	return candidates
+}
+
+// isVowel 判断小写字母是否为元音
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) >= 0
+}
+
+// CountLemmas 分词并还原词形，返回词元 → 出现次数
+func CountLemmas(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, token := range Tokenize(text) {
+		counts[Lemmatize(token)]++
+	}
+	return counts
+}
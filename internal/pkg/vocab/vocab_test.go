@@ -0,0 +1,84 @@
+package vocab
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"I don't like cats!", []string{"i", "do", "not", "like", "cats"}},
+		{"We're happy, I'm happy", []string{"we", "are", "happy", "i", "am", "happy"}},
+		{"It’s Tom's dog", []string{"it", "tom", "dog"}},
+		{"I can't swim and won't run", []string{"i", "can", "not", "swim", "and", "will", "not", "run"}},
+		{"我喜欢 apple 123", []string{"apple"}},
+		{"this is ***", []string{"this", "is"}},
+		{"b c a x", []string{"a"}},
+		{"'hello' at five o'clock", []string{"hello", "at", "five", "o'clock"}},
+		{"rock'n'roll", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLemmatize(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"went", "go"},
+		{"children", "child"},
+		{"better", "good"},
+		{"cat", "cat"},
+		{"cats", "cat"},
+		{"babies", "baby"},
+		{"liked", "like"},
+		{"stopped", "stop"},
+		{"running", "run"},
+		{"making", "make"},
+		{"bigger", "big"},
+		{"happiest", "happy"},
+		{"zorbles", "zorbles"}, // 候选都未收录时返回原词
+	}
+	for _, tt := range tests {
+		if got := Lemmatize(tt.word); got != tt.want {
+			t.Errorf("Lemmatize(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestLemmaCandidates(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"tries", []string{"try", "trie"}},
+		{"boxes", []string{"box", "boxe"}},
+		{"stopped", []string{"stopp", "stoppe", "stop"}},
+		{"hoping", []string{"hop", "hope"}},
+		{"taller", []string{"tall", "talle", "tal"}}, // 双写候选排在后面，tall 先命中词频表
+		{"class", nil},
+		{"is", nil}, // 词干过短
+	}
+	for _, tt := range tests {
+		if got := lemmaCandidates(tt.word); !slices.Equal(got, tt.want) {
+			t.Errorf("lemmaCandidates(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestCountLemmas(t *testing.T) {
+	got := CountLemmas("The cats ran and the cat runs.")
+	want := map[string]int{"the": 2, "cat": 2, "run": 2, "and": 1}
+	if !maps.Equal(got, want) {
+		t.Errorf("CountLemmas() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package vocab
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// 词汇等级（按词频表分段，未收录的单词为 unlisted）
+const (
+	BandA1       = "A1"
+	BandA2       = "A2"
+	BandB1       = "B1"
+	BandB2       = "B2"
+	BandUnlisted = "unlisted"
+)
+
+// Bands 词频表收录的等级（由易到难）
+var Bands = []string{BandA1, BandA2, BandB1, BandB2}
+
+// Entry 词频表条目
+type Entry struct {
+	Band string // 等级 A1 / A2 / B1 / B2
+	Rank int    // 常用程度排名（从 1 开始，越小越常用）
+}
+
+//go:embed wordlist.txt
+var wordlistData string
+
+// wordlist 词元 → 词频表条目（包初始化时解析）
+var wordlist = parseWordlist(wordlistData)
+
+// parseWordlist 解析词频表：[A1] 等行开始一个等级段，# 开头为注释，其余行为空格分隔的单词
+// 重复出现的单词以首次出现为准
+func parseWordlist(data string) map[string]Entry {
+	entries := make(map[string]Entry, 2048)
+	band := ""
+	rank := 0
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			band = strings.Trim(line, "[]")
+			continue
+		}
+		for _, word := range strings.Fields(line) {
+			if _, ok := entries[word]; ok || band == "" {
+				continue
+			}
+			rank++
+			entries[word] = Entry{Band: band, Rank: rank}
+		}
+	}
+	return entries
+}
+
+// Lookup 查询词元在词频表中的等级与排名
+func Lookup(lemma string) (Entry, bool) {
+	entry, ok := wordlist[lemma]
+	return entry, ok
+}
+
+// BandOf 词元的等级，未收录时返回 BandUnlisted
+func BandOf(lemma string) string {
+	if entry, ok := wordlist[lemma]; ok {
+		return entry.Band
+	}
+	return BandUnlisted
+}
@@ -0,0 +1,21 @@
+// Package router 提供管理后台路由
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/handler"
+	"pronunciation-correction-system/internal/handler/middleware"
+)
+
+// setupAdminRoutes 注册管理后台路由（需认证 + 管理员权限）
+// M-1 ~ M-3
+func setupAdminRoutes(rg *gin.RouterGroup, h *handler.AdminHandler, mw *middleware.Middlewares) {
+	admin := rg.Group("/admin")
+	admin.Use(mw.Admin.Require())
+	{
+		admin.GET("/chat/feedback", h.GetFeedbackAnalytics)    // M-1
+		admin.GET("/chat/flagged", h.ListFlaggedConversations) // M-2
+		admin.GET("/latency", h.GetLatencyStats)               // M-3
+	}
+}
@@ -5,16 +5,18 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"pronunciation-correction-system/internal/handler"
+	"pronunciation-correction-system/internal/handler/middleware"
 )
 
 // setupAuthRoutes 注册认证路由（无需登录）
 // A-1 ~ A-4
-func setupAuthRoutes(rg *gin.RouterGroup, h *handler.AuthHandler) {
+// 登录 / 注册按客户端 IP 限流
+func setupAuthRoutes(rg *gin.RouterGroup, h *handler.AuthHandler, mw *middleware.Middlewares) {
 	auth := rg.Group("/auth")
 	{
-		auth.POST("/login", h.Login)          // A-1
-		auth.POST("/register", h.Register)    // A-2
-		auth.POST("/logout", h.Logout)        // A-3
-		auth.POST("/refresh", h.RefreshToken) // A-4
+		auth.POST("/login", mw.RateLimit.Limit("login"), h.Login)          // A-1
+		auth.POST("/register", mw.RateLimit.Limit("register"), h.Register) // A-2
+		auth.POST("/logout", h.Logout)                                     // A-3
+		auth.POST("/refresh", h.RefreshToken)                              // A-4
 	}
 }
@@ -5,19 +5,51 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"pronunciation-correction-system/internal/handler"
+	"pronunciation-correction-system/internal/handler/middleware"
+	"pronunciation-correction-system/internal/model"
 )
 
 // setupChatRoutes 注册 AI 语音对话路由（需认证）
-// C-0 ~ C-6
-func setupChatRoutes(rg *gin.RouterGroup, h *handler.ChatHandler) {
+// C-0 ~ C-6、C-8 ~ C-24
+// 调用 ASR / LLM / TTS 的接口先限流、再消耗 chat 配额；音频提交与重新生成 / 重试接口支持 Idempotency-Key
+func setupChatRoutes(rg *gin.RouterGroup, h *handler.ChatHandler, mw *middleware.Middlewares) {
+	idem := mw.Idempotency.Handle()
+	chatLimit := mw.RateLimit.Limit("chat")
+	chatQuota := mw.Quota.Require(model.QuotaFeatureChat)
+
 	chat := rg.Group("/chat")
 	{
-		chat.POST("/MVP", h.ChatMVP)                          // C-0
-		chat.POST("/submit", h.SubmitChat)                    // C-1
-		chat.GET("/result/:task_id", h.GetChatResult)         // C-2
-		chat.GET("/history/:session_id", h.GetChatHistory)    // C-3
-		chat.DELETE("/session/:session_id", h.DeleteSession)  // C-4
-		chat.GET("/sessions", h.GetSessions)                  // C-5
-		chat.POST("/feedback", h.SubmitChatFeedback)          // C-6
+		chat.POST("/MVP", idem, chatLimit, chatQuota, h.ChatMVP)       // C-0
+		chat.POST("/stream", idem, chatLimit, chatQuota, h.ChatStream) // C-8
+		chat.POST("/submit", idem, chatLimit, chatQuota, h.SubmitChat) // C-1
+		chat.GET("/result/:task_id", h.GetChatResult)                  // C-2
+		chat.GET("/history/:session_id", h.GetChatHistory)             // C-3
+		chat.DELETE("/session/:session_id", h.DeleteSession)           // C-4
+		chat.GET("/sessions", h.GetSessions)                           // C-5
+		chat.POST("/feedback", h.SubmitChatFeedback)                   // C-6
+		chat.POST("/session/:session_id/end", h.EndSession)            // C-15
+		chat.POST("/session/:session_id/pause", h.PauseSession)        // C-21
+		chat.POST("/session/:session_id/resume", h.ResumeSession)      // C-22
+		chat.GET("/language-policy", h.GetLanguagePolicy)              // C-16
+		chat.PUT("/language-policy", h.UpdateLanguagePolicy)           // C-17
+
+		// 重新生成最后一轮回复 / 重试失败的轮次
+		chat.POST("/session/:session_id/regenerate", idem, chatLimit, chatQuota, h.RegenerateReply) // C-23
+		chat.POST("/session/:session_id/retry", idem, chatLimit, chatQuota, h.RetryTurn)            // C-24
+
+		// AI 老师人设
+		chat.GET("/personas", h.ListPersonas) // C-18
+		chat.GET("/persona", h.GetPersona)    // C-19
+		chat.PUT("/persona", h.UpdatePersona) // C-20
+
+		// 角色扮演场景
+		chat.GET("/scenarios", h.ListScenarios)                                // C-9
+		chat.GET("/scenarios/:scenario_id", h.GetScenario)                     // C-10
+		chat.POST("/scenarios/:scenario_id/start", chatLimit, h.StartScenario) // C-11
+
+		// 问答题集
+		chat.GET("/question-sets", h.ListQuestionSets)                                    // C-12
+		chat.POST("/question-sets/:question_set_id/start", chatLimit, h.StartQuestionSet) // C-13
+		chat.GET("/session/:session_id/questions", h.GetQuestionProgress)                 // C-14
 	}
 }
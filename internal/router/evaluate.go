@@ -5,22 +5,33 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"pronunciation-correction-system/internal/handler"
+	"pronunciation-correction-system/internal/handler/middleware"
+	"pronunciation-correction-system/internal/model"
 )
 
 // setupEvaluateRoutes 注册 AI 发音纠正路由（需认证）
-// E-0 ~ E-6
-func setupEvaluateRoutes(rg *gin.RouterGroup, h *handler.EvaluateHandler) {
+// E-0 ~ E-6, E-9 ~ E-12
+// 调用讯飞评测的接口先限流、再消耗 evaluate 配额；音频提交接口支持 Idempotency-Key
+func setupEvaluateRoutes(rg *gin.RouterGroup, h *handler.EvaluateHandler, mw *middleware.Middlewares) {
+	idem := mw.Idempotency.Handle()
+	evalLimit := mw.RateLimit.Limit("evaluate")
+	evalQuota := mw.Quota.Require(model.QuotaFeatureEvaluate)
+
 	eval := rg.Group("/evaluate")
 	{
 		// ── 静态路径（优先匹配）──
-		eval.POST("/MVP", h.EvaluateMVP)                          // E-0
-		eval.POST("/submit", h.SubmitEvaluation)                  // E-1
-		eval.GET("/history", h.GetEvaluationHistory)              // E-3
-		eval.GET("/reference-audio/:text_id", h.GetReferenceAudio) // E-6
+		eval.POST("/MVP", idem, evalLimit, evalQuota, h.EvaluateMVP)                 // E-0
+		eval.POST("/submit", idem, evalLimit, evalQuota, h.SubmitEvaluation)         // E-1
+		eval.GET("/history", h.GetEvaluationHistory)                                 // E-3
+		eval.GET("/reference-audio/:text_id", h.GetReferenceAudio)                   // E-6
+		eval.GET("/minimal-pair", h.GetMinimalPairExercises)                         // E-9
+		eval.POST("/minimal-pair/submit", evalLimit, evalQuota, h.SubmitMinimalPair) // E-10
+		eval.GET("/export", h.ExportEvaluationHistory)                               // E-11
+		eval.GET("/compare", h.CompareEvaluations)                                   // E-12
 
 		// ── 参数路径 ──
-		eval.GET("/result/:eval_id", h.GetEvaluationResult)       // E-2
-		eval.GET("/:eval_id/detail", h.GetEvaluationDetail)       // E-4
-		eval.DELETE("/:eval_id", h.DeleteEvaluation)              // E-5
+		eval.GET("/result/:eval_id", h.GetEvaluationResult) // E-2
+		eval.GET("/:eval_id/detail", h.GetEvaluationDetail) // E-4
+		eval.DELETE("/:eval_id", h.DeleteEvaluation)        // E-5
 	}
 }
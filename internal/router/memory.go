@@ -0,0 +1,19 @@
+// Package router 提供学习者长期记忆路由
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/handler"
+)
+
+// setupLearnerMemoryRoutes 注册学习者长期记忆路由（需认证）
+// U-4 ~ U-6
+func setupLearnerMemoryRoutes(rg *gin.RouterGroup, h *handler.LearnerMemoryHandler) {
+	memories := rg.Group("/user/memories")
+	{
+		memories.GET("", h.ListMemories)               // U-4
+		memories.DELETE("/:memory_id", h.DeleteMemory) // U-5
+		memories.DELETE("", h.ClearMemories)           // U-6
+	}
+}
@@ -0,0 +1,23 @@
+// Package router 提供单词间隔复习路由
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/handler"
+	"pronunciation-correction-system/internal/handler/middleware"
+	"pronunciation-correction-system/internal/model"
+)
+
+// setupReviewRoutes 注册单词间隔复习路由（需认证）
+// E-7 ~ E-8
+func setupReviewRoutes(rg *gin.RouterGroup, h *handler.ReviewHandler, mw *middleware.Middlewares) {
+	evalLimit := mw.RateLimit.Limit("evaluate")
+	evalQuota := mw.Quota.Require(model.QuotaFeatureEvaluate)
+
+	review := rg.Group("/evaluate/review")
+	{
+		review.GET("/today", h.GetTodayReviews)                      // E-7
+		review.POST("/submit", evalLimit, evalQuota, h.SubmitReview) // E-8
+	}
+}
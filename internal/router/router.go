@@ -3,6 +3,8 @@
 package router
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 
 	"pronunciation-correction-system/internal/config"
@@ -12,13 +14,20 @@ import (
 
 // Setup 初始化并返回路由引擎
 // handlers: 通过依赖注入传入的所有 Handler 实例
-func Setup(cfg *config.Config, handlers *handler.Handlers) *gin.Engine {
+// mw: 通过依赖注入传入的路由级中间件（配额、限流等）
+func Setup(cfg *config.Config, handlers *handler.Handlers, mw *middleware.Middlewares) (*gin.Engine, error) {
 	// 设置运行模式
 	gin.SetMode(cfg.Server.Mode)
 
 	// 创建路由引擎
 	r := gin.New()
 
+	// 客户端 IP 来源：只信任配置的反向代理转发的请求头（按 IP 限流依赖 ClientIP）
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid server.trusted_proxies: %w", err)
+	}
+	r.TrustedPlatform = cfg.Server.TrustedPlatform
+
 	// ── 全局中间件（顺序重要）──
 	r.Use(middleware.RecoveryMiddleware())     // 1. Panic 恢复（最外层）
 	r.Use(middleware.TraceMiddleware())        // 2. 生成 TraceID
@@ -29,11 +38,14 @@ func Setup(cfg *config.Config, handlers *handler.Handlers) *gin.Engine {
 	// ── 公开路由（无需认证）──
 	setupHealthRoutes(r)
 
+	// WebSocket 路由（实时语音对话）
+	setupWebSocketRoutes(r, cfg, handlers.Chat, mw)
+
 	// API v1 路由组
 	v1 := r.Group("/api/v1")
 	{
 		// 认证路由（无需登录）
-		setupAuthRoutes(v1, handlers.Auth)
+		setupAuthRoutes(v1, handlers.Auth, mw)
 
 		// 系统状态路由（无需登录）
 		setupSystemRoutes(v1, handlers.System)
@@ -41,14 +53,19 @@ func Setup(cfg *config.Config, handlers *handler.Handlers) *gin.Engine {
 		// ── 需要认证的路由 ──
 		authed := v1.Group("")
 		authed.Use(middleware.Auth(cfg))
+		authed.Use(mw.RateLimit.Limit("api")) // 通用限流（按用户，依赖 Auth）
 		{
-			setupChatRoutes(authed, handlers.Chat)         // AI 语音对话
-			setupEvaluateRoutes(authed, handlers.Evaluate) // AI 发音纠正
-			setupReportRoutes(authed, handlers.Report)     // 智能学习报告
-			setupUserRoutes(authed, handlers.User)         // 用户信息
-			setupResourceRoutes(authed, handlers.System)   // 学习资源
+			setupChatRoutes(authed, handlers.Chat, mw)               // AI 语音对话
+			setupEvaluateRoutes(authed, handlers.Evaluate, mw)       // AI 发音纠正
+			setupReviewRoutes(authed, handlers.Review, mw)           // 单词间隔复习
+			setupReportRoutes(authed, handlers.Report)               // 智能学习报告
+			setupVocabularyRoutes(authed, handlers.Vocabulary)       // 个人词汇表
+			setupUserRoutes(authed, handlers.User)                   // 用户信息
+			setupLearnerMemoryRoutes(authed, handlers.LearnerMemory) // 长期记忆
+			setupResourceRoutes(authed, handlers.System)             // 学习资源
+			setupAdminRoutes(authed, handlers.Admin, mw)             // 管理后台
 		}
 	}
 
-	return r
+	return r, nil
 }
@@ -8,11 +8,12 @@ import (
 )
 
 // setupUserRoutes 注册用户路由（需认证）
-// U-1 ~ U-2
+// U-1 ~ U-3
 func setupUserRoutes(rg *gin.RouterGroup, h *handler.UserHandler) {
 	user := rg.Group("/user")
 	{
 		user.GET("/profile", h.GetProfile)    // U-1
 		user.PUT("/profile", h.UpdateProfile) // U-2
+		user.GET("/quota", h.GetQuota)        // U-3
 	}
 }
@@ -0,0 +1,18 @@
+// Package router 提供个人词汇表路由
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/handler"
+)
+
+// setupVocabularyRoutes 注册个人词汇表路由（需认证）
+// R-7 ~ R-8
+func setupVocabularyRoutes(rg *gin.RouterGroup, h *handler.VocabularyHandler) {
+	vocabulary := rg.Group("/report/vocabulary")
+	{
+		vocabulary.GET("/known", h.GetKnownWords) // R-7
+		vocabulary.GET("/new", h.GetNewWords)     // R-8
+	}
+}
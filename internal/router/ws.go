@@ -0,0 +1,21 @@
+// Package router 提供 WebSocket 路由
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"pronunciation-correction-system/internal/config"
+	"pronunciation-correction-system/internal/handler"
+	"pronunciation-correction-system/internal/handler/middleware"
+)
+
+// setupWebSocketRoutes 注册 WebSocket 路由（升级前校验 JWT，支持 ?token= 传参）
+// C-7
+// 连接建立受 api 规则限流；每轮对话在连接内按 chat 规则限流并消耗 chat 配额
+func setupWebSocketRoutes(r *gin.Engine, cfg *config.Config, h *handler.ChatHandler, mw *middleware.Middlewares) {
+	ws := r.Group("/ws")
+	ws.Use(middleware.WebSocketAuth(cfg))
+	{
+		ws.GET("/chat", mw.RateLimit.Limit("api"), h.ChatWebSocket) // C-7
+	}
+}
@@ -0,0 +1,419 @@
+// Package service 提供管理后台统计分析与内容复核业务逻辑
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+)
+
+// 反馈分析默认参数
+const (
+	feedbackAnalyticsDefaultDays      = 30 // 未指定日期范围时统计最近 30 天
+	feedbackAnalyticsDefaultLimit     = 20 // 低分回复默认条数
+	feedbackAnalyticsMaxLimit         = 100
+	feedbackAnalyticsDefaultMaxRating = 2 // 评分不高于该值视为低分
+)
+
+// 耗时统计默认参数
+const (
+	latencyStatsDefaultDays = 7     // 未指定日期范围时统计最近 7 天
+	latencyStatsMaxSamples  = 50000 // 每条链路最多统计的记录数（超出时仅统计最近的记录）
+)
+
+// ===== 请求结构 =====
+
+// FeedbackAnalyticsRequest 对话反馈分析请求
+type FeedbackAnalyticsRequest struct {
+	DateFrom  string // YYYY-MM-DD，为空时为 date_to 前 30 天
+	DateTo    string // YYYY-MM-DD（包含当天），为空时为今天
+	Limit     int    // 低分回复条数
+	MaxRating int    // 低分阈值（1-5）
+}
+
+// LatencyStatsRequest 分阶段耗时统计请求
+type LatencyStatsRequest struct {
+	DateFrom string // YYYY-MM-DD，为空时为 date_to 前 7 天
+	DateTo   string // YYYY-MM-DD（包含当天），为空时为今天
+}
+
+// ===== 响应结构 =====
+
+// FeedbackAnalyticsResponse 对话反馈分析结果
+// 分组统计按平均评分升序，最需改进的在前；value 为空字符串表示未记录版本或非角色扮演会话
+type FeedbackAnalyticsResponse struct {
+	DateFrom        string                 `json:"date_from"`
+	DateTo          string                 `json:"date_to"`
+	Overall         *db.ChatFeedbackStat   `json:"overall"`
+	ByPromptVersion []*db.ChatFeedbackStat `json:"by_prompt_version"`
+	ByScenario      []*db.ChatFeedbackStat `json:"by_scenario"`
+	ByDifficulty    []*db.ChatFeedbackStat `json:"by_difficulty"`
+	ByReplyVersion  []*db.ChatFeedbackStat `json:"by_reply_version"`
+	LowestRated     []*LowRatedReply       `json:"lowest_rated"`
+}
+
+// LowRatedReply 低分 AI 回复及其上下文
+type LowRatedReply struct {
+	FeedbackID      string `json:"feedback_id"`
+	SessionID       string `json:"session_id"`
+	Turn            int    `json:"turn"`
+	Rating          int    `json:"rating"`
+	Helpful         *bool  `json:"helpful,omitempty"`
+	Comment         string `json:"comment,omitempty"`
+	PromptVersion   string `json:"prompt_version,omitempty"`
+	ReplyVersion    int    `json:"reply_version"`
+	ScenarioCode    string `json:"scenario_code,omitempty"`
+	DifficultyLevel string `json:"difficulty_level"`
+	UserText        string `json:"user_text"`
+	AIText          string `json:"ai_text"`
+	CreatedAt       string `json:"created_at"`
+}
+
+// FlaggedConversation 命中内容安全审核、待人工复核的会话
+type FlaggedConversation struct {
+	SessionID        string `json:"session_id"`
+	UserID           string `json:"user_id"`
+	ConversationType string `json:"conversation_type"`
+	DifficultyLevel  string `json:"difficulty_level"`
+	Status           string `json:"status"`
+	MessageCount     int    `json:"message_count"`
+	FlagReason       string `json:"flag_reason"`
+	FlaggedAt        string `json:"flagged_at"`
+	CreatedAt        string `json:"created_at"`
+}
+
+// LatencyStatsResponse 分阶段耗时统计结果
+type LatencyStatsResponse struct {
+	DateFrom   string                `json:"date_from"`
+	DateTo     string                `json:"date_to"`
+	Chat       *PipelineLatencyStats `json:"chat"`       // 语音对话（AI 消息）
+	Evaluation *PipelineLatencyStats `json:"evaluation"` // 发音评测
+}
+
+// PipelineLatencyStats 一条处理链路的耗时分位数
+type PipelineLatencyStats struct {
+	SampleCount int                            `json:"sample_count"`
+	Truncated   bool                           `json:"truncated"` // 记录数超过上限，仅统计了最近的记录
+	Total       *LatencyPercentiles            `json:"total"`
+	Stages      map[string]*LatencyPercentiles `json:"stages"` // 阶段名见 model.LatencyStage*
+}
+
+// LatencyPercentiles 耗时分位数（毫秒）
+type LatencyPercentiles struct {
+	Count int `json:"count"`
+	P50MS int `json:"p50_ms"`
+	P95MS int `json:"p95_ms"`
+}
+
+// ===== Service 接口 =====
+
+// AdminService 管理后台统计分析业务接口
+type AdminService interface {
+	// GetFeedbackAnalytics 统计对话反馈评分（按提示词版本 / 场景 / 难度），并列出评分最低的 AI 回复
+	GetFeedbackAnalytics(ctx context.Context, req *FeedbackAnalyticsRequest) (*FeedbackAnalyticsResponse, error)
+
+	// ListFlaggedConversations 分页获取命中内容安全审核、待人工复核的会话（按标记时间降序）
+	ListFlaggedConversations(ctx context.Context, page, pageSize int) ([]*FlaggedConversation, int64, error)
+
+	// GetLatencyStats 统计语音对话与发音评测各处理阶段耗时的 p50 / p95
+	GetLatencyStats(ctx context.Context, req *LatencyStatsRequest) (*LatencyStatsResponse, error)
+
+	// IsAdmin 判断用户是否为管理员（每次读取数据库，撤销权限立即生效；查询失败时按非管理员处理）
+	IsAdmin(ctx context.Context, userID string) bool
+}
+
+// ===== 实现 =====
+
+// adminServiceImpl Admin Service 实现
+type adminServiceImpl struct {
+	feedbackRepo     db.ChatFeedbackRepository
+	conversationRepo db.VoiceConversationRepository
+	messageRepo      db.ConversationMessageRepository
+	evaluationRepo   db.PronunciationEvaluationRepository
+	userRepo         db.UserRepository
+	logger           *slog.Logger
+}
+
+// NewAdminService 创建 AdminService
+func NewAdminService(repos *db.Repositories, logger *slog.Logger) AdminService {
+	var feedbackRepo db.ChatFeedbackRepository
+	var conversationRepo db.VoiceConversationRepository
+	var messageRepo db.ConversationMessageRepository
+	var evaluationRepo db.PronunciationEvaluationRepository
+	var userRepo db.UserRepository
+	if repos != nil {
+		feedbackRepo = repos.ChatFeedback
+		conversationRepo = repos.VoiceConversation
+		messageRepo = repos.ConversationMessage
+		evaluationRepo = repos.PronunciationEvaluation
+		userRepo = repos.User
+	}
+	return &adminServiceImpl{
+		feedbackRepo:     feedbackRepo,
+		conversationRepo: conversationRepo,
+		messageRepo:      messageRepo,
+		evaluationRepo:   evaluationRepo,
+		userRepo:         userRepo,
+		logger:           logger,
+	}
+}
+
+func (s *adminServiceImpl) IsAdmin(ctx context.Context, userID string) bool {
+	if s.userRepo == nil || userID == "" {
+		return false
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if !db.IsNotFound(err) {
+			s.logger.ErrorContext(ctx, "admin resolve user failed", "user_id", userID, "error", err)
+		}
+		return false
+	}
+	return user.IsAdmin
+}
+
+func (s *adminServiceImpl) GetFeedbackAnalytics(ctx context.Context, req *FeedbackAnalyticsRequest) (*FeedbackAnalyticsResponse, error) {
+	if req == nil {
+		req = &FeedbackAnalyticsRequest{}
+	}
+	if s.feedbackRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("feedback repository not initialized")
+	}
+
+	// ─── 1. 解析参数 ───
+	start, end, err := parseAnalyticsDateRange(req.DateFrom, req.DateTo, feedbackAnalyticsDefaultDays)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = feedbackAnalyticsDefaultLimit
+	}
+	if limit > feedbackAnalyticsMaxLimit {
+		limit = feedbackAnalyticsMaxLimit
+	}
+	maxRating := req.MaxRating
+	if maxRating == 0 {
+		maxRating = feedbackAnalyticsDefaultMaxRating
+	}
+	if maxRating < 1 || maxRating > 5 {
+		return nil, apperr.ErrInvalidParam.WithMessage("max_rating must be between 1 and 5")
+	}
+
+	resp := &FeedbackAnalyticsResponse{
+		DateFrom: start.Format(historyDateLayout),
+		DateTo:   end.AddDate(0, 0, -1).Format(historyDateLayout),
+	}
+
+	// ─── 2. 分维度统计 ───
+	if resp.ByPromptVersion, err = s.feedbackRepo.GetRatingStats(ctx, db.FeedbackDimensionPromptVersion, start, end); err != nil {
+		return nil, fmt.Errorf("get feedback stats by prompt version failed: %w", err)
+	}
+	if resp.ByScenario, err = s.feedbackRepo.GetRatingStats(ctx, db.FeedbackDimensionScenario, start, end); err != nil {
+		return nil, fmt.Errorf("get feedback stats by scenario failed: %w", err)
+	}
+	if resp.ByDifficulty, err = s.feedbackRepo.GetRatingStats(ctx, db.FeedbackDimensionDifficulty, start, end); err != nil {
+		return nil, fmt.Errorf("get feedback stats by difficulty failed: %w", err)
+	}
+	if resp.ByReplyVersion, err = s.feedbackRepo.GetRatingStats(ctx, db.FeedbackDimensionReplyVersion, start, end); err != nil {
+		return nil, fmt.Errorf("get feedback stats by reply version failed: %w", err)
+	}
+	resp.Overall = mergeFeedbackStats(resp.ByDifficulty)
+
+	// ─── 3. 评分最低的回复 ───
+	details, err := s.feedbackRepo.ListLowestRated(ctx, start, end, maxRating, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list lowest rated replies failed: %w", err)
+	}
+	resp.LowestRated = make([]*LowRatedReply, 0, len(details))
+	for _, d := range details {
+		resp.LowestRated = append(resp.LowestRated, toLowRatedReply(d))
+	}
+	return resp, nil
+}
+
+func (s *adminServiceImpl) ListFlaggedConversations(ctx context.Context, page, pageSize int) ([]*FlaggedConversation, int64, error) {
+	if s.conversationRepo == nil {
+		return nil, 0, apperr.ErrInternalError.WithMessage("conversation repository not initialized")
+	}
+	conversations, total, err := s.conversationRepo.GetFlagged(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list flagged conversations failed: %w", err)
+	}
+	items := make([]*FlaggedConversation, 0, len(conversations))
+	for _, c := range conversations {
+		items = append(items, toFlaggedConversation(c))
+	}
+	return items, total, nil
+}
+
+func (s *adminServiceImpl) GetLatencyStats(ctx context.Context, req *LatencyStatsRequest) (*LatencyStatsResponse, error) {
+	if req == nil {
+		req = &LatencyStatsRequest{}
+	}
+	if s.messageRepo == nil || s.evaluationRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("latency repository not initialized")
+	}
+
+	// ─── 1. 解析参数 ───
+	start, end, err := parseAnalyticsDateRange(req.DateFrom, req.DateTo, latencyStatsDefaultDays)
+	if err != nil {
+		return nil, err
+	}
+	resp := &LatencyStatsResponse{
+		DateFrom: start.Format(historyDateLayout),
+		DateTo:   end.AddDate(0, 0, -1).Format(historyDateLayout),
+	}
+
+	// ─── 2. 语音对话 ───
+	chatSamples, err := s.messageRepo.ListLatencies(ctx, start, end, latencyStatsMaxSamples)
+	if err != nil {
+		return nil, fmt.Errorf("list chat latencies failed: %w", err)
+	}
+	resp.Chat = summarizeLatencies(chatSamples)
+
+	// ─── 3. 发音评测 ───
+	evalSamples, err := s.evaluationRepo.ListLatencies(ctx, start, end, latencyStatsMaxSamples)
+	if err != nil {
+		return nil, fmt.Errorf("list evaluation latencies failed: %w", err)
+	}
+	resp.Evaluation = summarizeLatencies(evalSamples)
+	return resp, nil
+}
+
+// parseAnalyticsDateRange 解析统计日期范围，返回 [start, end)；未指定时为截至今天的最近 defaultDays 天
+func parseAnalyticsDateRange(dateFrom, dateTo string, defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	if dateTo != "" {
+		t, err := time.ParseInLocation(historyDateLayout, dateTo, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperr.ErrInvalidParam.WithMessage("date_to must be YYYY-MM-DD")
+		}
+		end = t.AddDate(0, 0, 1)
+	}
+	start := end.AddDate(0, 0, -defaultDays)
+	if dateFrom != "" {
+		t, err := time.ParseInLocation(historyDateLayout, dateFrom, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperr.ErrInvalidParam.WithMessage("date_from must be YYYY-MM-DD")
+		}
+		start = t
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, apperr.ErrInvalidParam.WithMessage("date_from must not be after date_to")
+	}
+	return start, end, nil
+}
+
+// mergeFeedbackStats 合并分组统计为总体统计（平均评分按反馈数加权）
+func mergeFeedbackStats(stats []*db.ChatFeedbackStat) *db.ChatFeedbackStat {
+	overall := &db.ChatFeedbackStat{}
+	var ratingSum float64
+	for _, st := range stats {
+		overall.Count += st.Count
+		overall.HelpfulCount += st.HelpfulCount
+		overall.UnhelpfulCount += st.UnhelpfulCount
+		ratingSum += st.AverageRating * float64(st.Count)
+	}
+	if overall.Count > 0 {
+		overall.AverageRating = ratingSum / float64(overall.Count)
+	}
+	return overall
+}
+
+// summarizeLatencies 计算总耗时与各阶段耗时的分位数（阶段只统计记录了该阶段的样本）
+func summarizeLatencies(samples []*db.LatencySample) *PipelineLatencyStats {
+	totals := make([]int, 0, len(samples))
+	stages := make(map[string][]int)
+	for _, sample := range samples {
+		totals = append(totals, sample.LatencyMS)
+		for stage, ms := range sample.StageLatencies {
+			stages[stage] = append(stages[stage], ms)
+		}
+	}
+	stats := &PipelineLatencyStats{
+		SampleCount: len(samples),
+		Truncated:   len(samples) >= latencyStatsMaxSamples,
+		Total:       latencyPercentiles(totals),
+		Stages:      make(map[string]*LatencyPercentiles, len(stages)),
+	}
+	for stage, values := range stages {
+		stats.Stages[stage] = latencyPercentiles(values)
+	}
+	return stats
+}
+
+// latencyPercentiles 按最近秩法计算 p50 / p95（会对 values 排序）
+func latencyPercentiles(values []int) *LatencyPercentiles {
+	result := &LatencyPercentiles{Count: len(values)}
+	if len(values) == 0 {
+		return result
+	}
+	sort.Ints(values)
+	rank := func(p float64) int {
+		return values[int(math.Ceil(p*float64(len(values))))-1]
+	}
+	result.P50MS = rank(0.50)
+	result.P95MS = rank(0.95)
+	return result
+}
+
+// toLowRatedReply 转换为低分回复
+func toLowRatedReply(d *db.ChatFeedbackDetail) *LowRatedReply {
+	reply := &LowRatedReply{
+		FeedbackID:      d.FeedbackID,
+		SessionID:       d.ConversationID,
+		Turn:            d.Turn,
+		Rating:          d.Rating,
+		Helpful:         d.Helpful,
+		ReplyVersion:    d.ReplyVersion,
+		DifficultyLevel: d.DifficultyLevel,
+		AIText:          d.AIText,
+		CreatedAt:       d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.Comment != nil {
+		reply.Comment = *d.Comment
+	}
+	if d.PromptVersion != nil {
+		reply.PromptVersion = *d.PromptVersion
+	}
+	if d.ScenarioCode != nil {
+		reply.ScenarioCode = *d.ScenarioCode
+	}
+	if d.UserText != nil {
+		reply.UserText = *d.UserText
+	}
+	// 评价的是已被替换的版本时展示当时的回复
+	if d.ReplyVersion >= 1 && d.ReplyVersion <= len(d.ReplyVersions) {
+		reply.AIText = d.ReplyVersions[d.ReplyVersion-1].Text
+	}
+	return reply
+}
+
+// toFlaggedConversation 转换为待复核会话
+func toFlaggedConversation(c *model.VoiceConversation) *FlaggedConversation {
+	item := &FlaggedConversation{
+		SessionID:        c.ID,
+		UserID:           c.UserID,
+		ConversationType: c.ConversationType,
+		DifficultyLevel:  c.DifficultyLevel,
+		Status:           c.Status,
+		MessageCount:     c.MessageCount,
+		CreatedAt:        c.CreatedAt.Format(time.RFC3339),
+	}
+	if c.FlagReason != nil {
+		item.FlagReason = *c.FlagReason
+	}
+	if c.FlaggedAt != nil {
+		item.FlaggedAt = c.FlaggedAt.Format(time.RFC3339)
+	}
+	return item
+}
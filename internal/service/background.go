@@ -0,0 +1,25 @@
+// Package service 提供后台任务执行（对话批注、发音评测、会话总结等本轮返回后继续的处理）
+package service
+
+import (
+	"context"
+	"time"
+
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// runBackground 在后台执行 fn：脱离请求生命周期（保留 trace 信息），限定超时并捕获 panic
+// fn 自行记录失败日志；name 用于标识 panic 日志
+func runBackground(ctx context.Context, name string, timeout time.Duration, fn func(ctx context.Context)) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				logger.ErrorContext(ctx, "background task panic", "task", name, "panic", r)
+			}
+		}()
+		fn(ctx)
+	}()
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunBackground(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		fn      func(ctx context.Context) error
+		want    error
+	}{
+		{"outlives request", time.Second, func(ctx context.Context) error { return ctx.Err() }, nil},
+		{"applies timeout", time.Millisecond, func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() }, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel() // 请求已结束
+			done := make(chan error, 1)
+			runBackground(ctx, tt.name, tt.timeout, func(ctx context.Context) { done <- tt.fn(ctx) })
+			select {
+			case got := <-done:
+				if got != tt.want {
+					t.Errorf("background ctx err = %v, want %v", got, tt.want)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("background task did not finish")
+			}
+		})
+	}
+}
+
+func TestRunBackgroundRecoversPanic(t *testing.T) {
+	done := make(chan struct{})
+	runBackground(context.Background(), "panic", time.Second, func(ctx context.Context) {
+		defer close(done)
+		panic("boom")
+	})
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("background task did not finish")
+	}
+}
@@ -0,0 +1,122 @@
+// Package service 提供对话消息语法批注（异步分析用户消息中的语法 / 用词错误）
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	llmPrompts "pronunciation-correction-system/internal/infrastructure/llm"
+	"pronunciation-correction-system/internal/model"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// 语法批注限制
+const (
+	grammarAnnotationTimeout  = 30 * time.Second // 单条消息分析超时（与请求生命周期无关）
+	grammarAnnotationMaxItems = 3                // 每条消息最多保留的批注数
+)
+
+// grammarErrorTypes 合法的错误类型（LLM 返回其他值时归为 other）
+var grammarErrorTypes = map[string]bool{
+	model.GrammarErrorTense:       true,
+	model.GrammarErrorAgreement:   true,
+	model.GrammarErrorArticle:     true,
+	model.GrammarErrorPreposition: true,
+	model.GrammarErrorPlural:      true,
+	model.GrammarErrorWordOrder:   true,
+	model.GrammarErrorMissingWord: true,
+	model.GrammarErrorWordChoice:  true,
+	model.GrammarErrorOther:       true,
+}
+
+// annotateMessageAsync 在后台分析已保存的用户消息并写回批注，不阻塞本轮回复
+// 不含英文字母的消息（如纯中文）不分析，批注保持 NULL
+func (s *chatServiceImpl) annotateMessageAsync(ctx context.Context, message *model.ConversationMessage, difficulty string) {
+	if !s.cfg.AnnotateGrammar || s.llmProvider == nil || s.messageRepo == nil {
+		return
+	}
+	if !containsLatinLetter(message.MessageText) {
+		return
+	}
+
+	// 脱离请求生命周期在后台执行（HTTP 响应返回或 WebSocket 轮次结束后继续）
+	runBackground(ctx, "grammar annotation", grammarAnnotationTimeout, func(ctx context.Context) {
+		annotations, err := s.annotateMessage(ctx, message, difficulty)
+		if err != nil {
+			logger.WarnContext(ctx, "chat grammar annotation failed", "message_id", message.ID, "error", err)
+			return
+		}
+		if err := s.messageRepo.UpdateAnnotations(ctx, message.ID, annotations); err != nil {
+			logger.ErrorContext(ctx, "chat save grammar annotations failed", "message_id", message.ID, "error", err)
+			return
+		}
+		logger.InfoContext(ctx, "chat grammar annotated", "message_id", message.ID, "count", len(annotations))
+	})
+}
+
+// annotateMessage 调用 LLM 分析用户消息，返回规范化后的批注（没有错误时返回空列表）
+// 上一句 AI 回复作为上下文，帮助判断时态等依赖语境的错误
+func (s *chatServiceImpl) annotateMessage(ctx context.Context, message *model.ConversationMessage, difficulty string) (model.GrammarAnnotationList, error) {
+	previousReply := ""
+	if message.SequenceNumber > 1 {
+		previous, err := s.messageRepo.GetBySequenceRange(ctx, message.ConversationID, message.SequenceNumber-1, message.SequenceNumber-1)
+		if err == nil && len(previous) > 0 && previous[0].SenderType == model.SenderTypeAI {
+			previousReply = previous[0].MessageText
+		}
+	}
+
+	systemPrompt, userMessage := llmPrompts.BuildGrammarAnnotationPrompt(difficulty, previousReply, message.MessageText)
+	reply, err := s.llmProvider.Chat(ctx, systemPrompt, userMessage)
+	if err != nil {
+		return nil, err
+	}
+	return parseGrammarAnnotations(reply)
+}
+
+// parseGrammarAnnotations 解析 LLM 返回的 {"errors": [...]}
+// 截取 JSON 对象部分，容忍多余文字；丢弃缺少原文 / 改正或未做修改的条目，未知类型归为 other
+func parseGrammarAnnotations(reply string) (model.GrammarAnnotationList, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("no json object in reply")
+	}
+	var raw struct {
+		Errors []model.GrammarAnnotation `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return nil, err
+	}
+
+	annotations := make(model.GrammarAnnotationList, 0, len(raw.Errors))
+	for _, a := range raw.Errors {
+		a.Original = strings.TrimSpace(a.Original)
+		a.Corrected = strings.TrimSpace(a.Corrected)
+		a.Explanation = strings.TrimSpace(a.Explanation)
+		a.ErrorType = strings.ToLower(strings.TrimSpace(a.ErrorType))
+		if a.Original == "" || a.Corrected == "" || strings.EqualFold(a.Original, a.Corrected) {
+			continue
+		}
+		if !grammarErrorTypes[a.ErrorType] {
+			a.ErrorType = model.GrammarErrorOther
+		}
+		annotations = append(annotations, a)
+		if len(annotations) == grammarAnnotationMaxItems {
+			break
+		}
+	}
+	return annotations, nil
+}
+
+// containsLatinLetter 判断文本是否包含英文字母
+func containsLatinLetter(text string) bool {
+	for _, r := range text {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+// Package service 提供异步语音对话（提交任务后轮询结果）
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"pronunciation-correction-system/internal/cache"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/uuid"
+)
+
+// chatTaskTimeout 异步语音对话单个任务的处理时限（与同步接口一致）
+const chatTaskTimeout = 60 * time.Second
+
+// ===== 请求结构 =====
+
+// SubmitChatRequest 异步语音对话提交请求（表单与同步接口相同）
+type SubmitChatRequest struct {
+	ChatMVPRequest
+	// OnServerError 后台处理因服务端原因失败时调用（handler 用于退还提交时消耗的配额），可为 nil
+	OnServerError func(ctx context.Context)
+}
+
+// ===== 响应结构 =====
+
+// ChatSubmitResponse 异步语音对话提交结果
+type ChatSubmitResponse struct {
+	TaskID    string `json:"task_id"`
+	SessionID string `json:"session_id,omitempty"` // 提交时指定的会话（新会话在处理完成后通过结果返回）
+	Status    string `json:"status"`
+}
+
+// SubmitChat 校验会话后写入任务状态，在后台执行与 ChatMVP 相同的流程（本轮保存到会话），客户端轮询结果
+func (s *chatServiceImpl) SubmitChat(ctx context.Context, req *SubmitChatRequest) (*ChatSubmitResponse, error) {
+	// 步骤 1：基础校验（付费服务调用前完成，校验失败不创建任务）
+	if req == nil || req.UserID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+	if len(req.AudioData) == 0 {
+		return nil, apperr.ErrAudioInvalid.WithMessage("audio data is empty")
+	}
+	if s.taskCache == nil {
+		return nil, apperr.ErrInternalError.WithMessage("chat task store not initialized")
+	}
+	conversationType := strings.TrimSpace(req.ConversationType)
+	if conversationType == "" {
+		conversationType = model.ConversationTypeFreeTalk
+	}
+	sessionID := strings.TrimSpace(req.SessionID)
+	if _, err := s.resolveConversation(ctx, sessionID, req.UserID, conversationType, s.maxConversationMessages(ctx)); err != nil {
+		return nil, err
+	}
+
+	// 步骤 2：写入任务状态
+	record := &cache.ChatTaskRecord{
+		TaskID:    uuid.New(),
+		UserID:    req.UserID,
+		Status:    cache.ChatTaskStatusPending,
+		SessionID: sessionID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.taskCache.Set(ctx, record); err != nil {
+		return nil, fmt.Errorf("save chat task failed: %w", err)
+	}
+
+	// 步骤 3：后台处理（脱离请求生命周期，与同步接口相同的处理时限）
+	runBackground(ctx, "chat task", chatTaskTimeout, func(ctx context.Context) {
+		s.runChatTask(ctx, record, req)
+	})
+
+	logger.InfoContext(ctx, "chat task submitted", "task_id", record.TaskID, "session_id", sessionID)
+	return &ChatSubmitResponse{TaskID: record.TaskID, SessionID: sessionID, Status: record.Status}, nil
+}
+
+// runChatTask 执行异步语音对话任务并更新任务状态（panic 时同样记录任务失败）
+func (s *chatServiceImpl) runChatTask(ctx context.Context, record *cache.ChatTaskRecord, req *SubmitChatRequest) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ErrorContext(ctx, "chat task panic", "task_id", record.TaskID, "panic", r)
+			s.finishChatTask(ctx, record, req, nil, fmt.Errorf("chat task panic: %v", r))
+		}
+	}()
+
+	record.Status = cache.ChatTaskStatusProcessing
+	if err := s.taskCache.Set(ctx, record); err != nil {
+		logger.ErrorContext(ctx, "chat task update status failed", "task_id", record.TaskID, "error", err)
+	}
+
+	reply, err := s.ChatMVP(ctx, &req.ChatMVPRequest)
+	var result *ChatTurnResult
+	if reply != nil {
+		result = &reply.ChatTurnResult
+	}
+	s.finishChatTask(ctx, record, req, result, err)
+}
+
+// finishChatTask 保存任务的最终状态；本轮保存失败、未能保存或服务端原因失败时通知退还配额
+func (s *chatServiceImpl) finishChatTask(ctx context.Context, record *cache.ChatTaskRecord, req *SubmitChatRequest, result *ChatTurnResult, taskErr error) {
+	ctx = context.WithoutCancel(ctx)
+	record.Status = cache.ChatTaskStatusSuccess
+	if result != nil {
+		record.SessionID = result.SessionID
+		if data, err := json.Marshal(result); err == nil {
+			record.Result = data
+		}
+	}
+	if taskErr == nil && (result == nil || result.Turn == 0) {
+		taskErr = apperr.ErrInternalError.WithMessage("save chat turn failed")
+	}
+	if taskErr != nil {
+		record.Status = cache.ChatTaskStatusFailed
+		record.ErrorStage, record.ErrorMessage = chatTaskError(result, taskErr)
+		logger.ErrorContext(ctx, "chat task failed", "task_id", record.TaskID, "stage", record.ErrorStage, "error", taskErr)
+	}
+	if err := s.taskCache.Set(ctx, record); err != nil {
+		logger.ErrorContext(ctx, "chat task save result failed", "task_id", record.TaskID, "error", err)
+	}
+	if taskErr != nil && req.OnServerError != nil {
+		if status, _ := apperr.PublicError(taskErr); status >= http.StatusInternalServerError {
+			req.OnServerError(ctx)
+		}
+	}
+}
+
+// chatTaskError 任务失败时返回给客户端的阶段与原因（未预期的错误不暴露细节）
+func chatTaskError(result *ChatTurnResult, err error) (stage, message string) {
+	if result != nil {
+		stage = result.FailedStage
+	}
+	_, message = apperr.PublicError(err)
+	return stage, message
+}
+
+// GetChatResult 查询异步语音对话任务：处理完成时从会话读取本轮的识别文本、AI 回复与音频
+func (s *chatServiceImpl) GetChatResult(ctx context.Context, taskID, userID string) (*ChatResultResponse, error) {
+	if strings.TrimSpace(taskID) == "" || userID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("task_id is required")
+	}
+	if s.taskCache == nil {
+		return nil, apperr.ErrInternalError.WithMessage("chat task store not initialized")
+	}
+
+	// 步骤 1：读取任务状态（不属于当前用户时同样按不存在处理）
+	record, err := s.taskCache.Get(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get chat task failed: %w", err)
+	}
+	if record == nil || record.UserID != userID {
+		return nil, apperr.ErrNotFound.WithMessage("chat task not found or expired")
+	}
+	resp := &ChatResultResponse{
+		TaskID:       record.TaskID,
+		Status:       record.Status,
+		ErrorStage:   record.ErrorStage,
+		ErrorMessage: record.ErrorMessage,
+		CreatedAt:    record.CreatedAt.Format(time.RFC3339),
+	}
+	if len(record.Result) == 0 {
+		return resp, nil
+	}
+	var result ChatTurnResult
+	if err := json.Unmarshal(record.Result, &result); err != nil {
+		return nil, fmt.Errorf("decode chat task result failed: %w", err)
+	}
+	resp.ChatTurnResult = &result
+
+	// 步骤 2：从会话读取本轮消息（失败轮次同样返回识别文本，可调用重试接口继续）
+	if result.Turn == 0 || s.messageRepo == nil {
+		return resp, nil
+	}
+	messages, err := s.messageRepo.GetBySequenceRange(ctx, result.SessionID, 2*result.Turn-1, 2*result.Turn)
+	if err != nil {
+		return nil, fmt.Errorf("get chat turn messages failed: %w", err)
+	}
+	for _, turn := range toConversationTurns(messages, false) {
+		resp.UserInput = &AudioInput{Text: turn.UserText, AudioURL: turn.UserAudioURL}
+		if turn.FailedStage == "" {
+			resp.AIResponse = &AudioReply{Text: turn.AIText, AudioURL: turn.AIAudioURL}
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,226 @@
+// Package service 提供对话的中英双语处理（识别消息语言、按学习者的语言策略构建提示词与中文释义）
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"pronunciation-correction-system/internal/db"
+	llmPrompts "pronunciation-correction-system/internal/infrastructure/llm"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/uuid"
+)
+
+// defaultLanguagePolicy 未设置语言策略时使用的策略
+const defaultLanguagePolicy = model.LanguagePolicyChineseScaffolding
+
+// validLanguagePolicies 合法的语言策略
+var validLanguagePolicies = map[string]bool{
+	model.LanguagePolicyEnglishOnly:        true,
+	model.LanguagePolicyChineseScaffolding: true,
+	model.LanguagePolicyTranslateOnDemand:  true,
+}
+
+// 语言策略提示词（追加在系统提示词之后）
+const (
+	englishOnlyPrompt = `
+LANGUAGE (English only):
+- Reply in English only. Never write Chinese characters.
+- Child speaks Chinese → Gently prompt in English: "Let's try English! You can say..."
+  Example: Child: "这个怎么说？" You: "Let's say it in English! You can ask: How do you say this?"
+`
+	chineseScaffoldingPrompt = `
+LANGUAGE (Chinese scaffolding):
+- Primary language: English.
+- Child speaks Chinese or mixes Chinese → Give the English way to say it, then invite the child to try: "You can say..."
+- %s
+`
+	chineseScaffoldingBeginnerHint = `You may add ONE short Chinese hint in 【】 brackets for a new word or difficult grammar, e.g. "It is a turtle 【乌龟】."`
+	chineseScaffoldingDefaultHint  = `Only add a short Chinese hint in 【】 brackets when the child spoke Chinese.`
+	translateOnDemandPrompt        = `
+LANGUAGE (English replies, translation on request):
+- Reply in English only. Never write Chinese characters (a Chinese translation is shown separately when the child asks for it).
+- Child speaks Chinese or asks how to say something → Give the English phrase: "You can say..."
+`
+)
+
+// ===== 请求结构 =====
+
+// UpdateLanguagePolicyRequest 设置回复语言策略请求
+type UpdateLanguagePolicyRequest struct {
+	UserID string
+	Policy string // english_only / chinese_scaffolding / translate_on_demand
+}
+
+// ===== 响应结构 =====
+
+// LanguagePolicyResponse 回复语言策略
+type LanguagePolicyResponse struct {
+	Policy string `json:"policy"`
+}
+
+// replyLanguage 一轮对话的回复语言设置
+type replyLanguage struct {
+	policy       string // 学习者的语言策略
+	difficulty   string // 会话难度（chinese_scaffolding 按难度决定中文提示的使用方式）
+	userLanguage string // 本轮孩子说话的语言（zh / en / mixed，无法判断时为空）
+	translate    bool   // 本轮请求是否要求中文释义（translate_on_demand 使用）
+}
+
+// needsTranslation 本轮是否返回中文释义
+// chinese_scaffolding：孩子说中文或中英混合时返回；translate_on_demand：请求要求时返回；english_only：不返回
+func (l *replyLanguage) needsTranslation() bool {
+	switch l.policy {
+	case model.LanguagePolicyChineseScaffolding:
+		return l.userLanguage == model.MessageLanguageChinese || l.userLanguage == model.MessageLanguageMixed
+	case model.LanguagePolicyTranslateOnDemand:
+		return l.translate
+	}
+	return false
+}
+
+// detectLanguage 按汉字与英文字母判断文本语言：只有汉字为 zh，只有英文字母为 en，两者都有为 mixed
+// 两者都没有（如只有数字、标点）时返回空字符串
+func detectLanguage(text string) string {
+	hasHan, hasLatin := false, false
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			hasHan = true
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			hasLatin = true
+		}
+	}
+	switch {
+	case hasHan && hasLatin:
+		return model.MessageLanguageMixed
+	case hasHan:
+		return model.MessageLanguageChinese
+	case hasLatin:
+		return model.MessageLanguageEnglish
+	}
+	return ""
+}
+
+// languagePolicyPrompt 语言策略对应的系统提示词片段
+func languagePolicyPrompt(policy, difficulty string) string {
+	switch policy {
+	case model.LanguagePolicyEnglishOnly:
+		return englishOnlyPrompt
+	case model.LanguagePolicyTranslateOnDemand:
+		return translateOnDemandPrompt
+	}
+	hint := chineseScaffoldingDefaultHint
+	if difficulty == "" || difficulty == "beginner" {
+		hint = chineseScaffoldingBeginnerHint
+	}
+	return fmt.Sprintf(chineseScaffoldingPrompt, hint)
+}
+
+// resolveReplyLanguage 组装本轮的回复语言设置
+// 已有会话以会话难度为准；语言策略读取失败时使用默认策略
+func (s *chatServiceImpl) resolveReplyLanguage(ctx context.Context, userID string, conversation *model.VoiceConversation, difficulty, userText string, translate bool) *replyLanguage {
+	if conversation != nil {
+		difficulty = conversation.DifficultyLevel
+	}
+	return &replyLanguage{
+		policy:       s.languagePolicy(ctx, userID),
+		difficulty:   difficulty,
+		userLanguage: detectLanguage(userText),
+		translate:    translate,
+	}
+}
+
+// languagePolicy 读取学习者的语言策略，未设置或读取失败时返回默认策略
+func (s *chatServiceImpl) languagePolicy(ctx context.Context, userID string) string {
+	if s.profileRepo == nil {
+		return defaultLanguagePolicy
+	}
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if !db.IsNotFound(err) {
+			logger.WarnContext(ctx, "chat read language policy failed, use default", "user_id", userID, "error", err)
+		}
+		return defaultLanguagePolicy
+	}
+	if !validLanguagePolicies[profile.LanguagePolicy] {
+		return defaultLanguagePolicy
+	}
+	return profile.LanguagePolicy
+}
+
+// translateReply 按语言策略生成 AI 回复的中文释义，释义与回复同样经过内容安全审核（命中记入本轮审核结果）
+// 不需要释义、生成失败或释义被拦截时返回空字符串（仅记录日志，不影响本轮回复）
+func (s *chatServiceImpl) translateReply(ctx context.Context, lang *replyLanguage, moderation *turnModeration, replyText string) string {
+	if lang == nil || !lang.needsTranslation() || s.llmProvider == nil {
+		return ""
+	}
+	systemPrompt, userMessage := llmPrompts.BuildReplyTranslationPrompt(replyText)
+	reply, err := s.llmProvider.Chat(ctx, systemPrompt, userMessage)
+	if err != nil {
+		logger.WarnContext(ctx, "chat reply translation failed", "error", err)
+		return ""
+	}
+	translation, err := parseReplyTranslation(reply)
+	if err != nil {
+		logger.WarnContext(ctx, "chat parse reply translation failed", "reply_length", len(reply), "error", err)
+		logger.DebugContext(ctx, "chat reply translation raw reply", "reply", reply)
+		return ""
+	}
+	translation, blocked := s.moderateReply(ctx, moderation, translation)
+	if blocked {
+		return "" // 不展示兜底回复的释义
+	}
+	return translation
+}
+
+// parseReplyTranslation 解析 LLM 返回的 {"translation": "..."}
+func parseReplyTranslation(reply string) (string, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end <= start {
+		return "", fmt.Errorf("no json object in reply")
+	}
+	var raw struct {
+		Translation string `json:"translation"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return "", err
+	}
+	translation := strings.TrimSpace(raw.Translation)
+	if translation == "" {
+		return "", fmt.Errorf("empty translation")
+	}
+	return translation, nil
+}
+
+func (s *chatServiceImpl) GetLanguagePolicy(ctx context.Context, userID string) (*LanguagePolicyResponse, error) {
+	return &LanguagePolicyResponse{Policy: s.languagePolicy(ctx, userID)}, nil
+}
+
+func (s *chatServiceImpl) UpdateLanguagePolicy(ctx context.Context, req *UpdateLanguagePolicyRequest) (*LanguagePolicyResponse, error) {
+	if req == nil || req.UserID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user_id is required")
+	}
+	policy := strings.TrimSpace(req.Policy)
+	if !validLanguagePolicies[policy] {
+		return nil, apperr.ErrInvalidParam.WithMessage("policy must be english_only, chinese_scaffolding or translate_on_demand")
+	}
+	if s.profileRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("profile repository not initialized")
+	}
+	profile := &model.UserProfile{
+		ID:             uuid.New(),
+		UserID:         req.UserID,
+		LanguagePolicy: policy,
+	}
+	if err := s.profileRepo.UpsertLanguagePolicy(ctx, profile); err != nil {
+		return nil, fmt.Errorf("save language policy failed: %w", err)
+	}
+	logger.InfoContext(ctx, "chat language policy updated", "user_id", req.UserID, "policy", policy)
+	return &LanguagePolicyResponse{Policy: policy}, nil
+}
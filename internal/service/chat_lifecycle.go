@@ -0,0 +1,118 @@
+// Package service 提供会话生命周期（暂停、恢复、结束与空闲超时）
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// 会话生命周期规则
+const (
+	defaultIdleTimeoutMinutes = 15 // 未配置 conversation_idle_timeout_minutes 时的空闲超时
+	defaultPausedTimeoutHours = 24 // 未配置 conversation_paused_timeout_hours 时的暂停超时
+	idleSweepBatchSize        = 50 // 每次扫描每种状态最多结束的会话数（其余留到下次扫描）
+)
+
+func (s *chatServiceImpl) PauseSession(ctx context.Context, sessionID, userID string) (*SessionSummary, error) {
+	return s.transitionSession(ctx, sessionID, userID, model.ConversationStatusActive, model.ConversationStatusPaused)
+}
+
+func (s *chatServiceImpl) ResumeSession(ctx context.Context, sessionID, userID string) (*SessionSummary, error) {
+	return s.transitionSession(ctx, sessionID, userID, model.ConversationStatusPaused, model.ConversationStatusActive)
+}
+
+func (s *chatServiceImpl) CompleteIdleSessions(ctx context.Context) (int, error) {
+	if s.conversationRepo == nil {
+		return 0, apperr.ErrInternalError.WithMessage("conversation repository not initialized")
+	}
+
+	// 进行中的会话按空闲超时、暂停的会话按暂停超时结束；空闲 / 暂停时间不计入时长
+	now := time.Now()
+	rules := []struct {
+		status string
+		before time.Time
+	}{
+		{model.ConversationStatusActive, now.Add(-s.idleTimeout(ctx))},
+		{model.ConversationStatusPaused, now.Add(-s.pausedTimeout(ctx))},
+	}
+	completed := 0
+	for _, rule := range rules {
+		ids, err := s.conversationRepo.ListIdleIDs(ctx, rule.status, rule.before, idleSweepBatchSize)
+		if err != nil {
+			return completed, fmt.Errorf("list idle %s conversations failed: %w", rule.status, err)
+		}
+		for _, id := range ids {
+			ok, err := s.conversationRepo.CompleteIdle(ctx, id, rule.status, rule.before)
+			if err != nil {
+				logger.ErrorContext(ctx, "chat complete idle session failed", "session_id", id, "error", err)
+				continue
+			}
+			if !ok {
+				continue // 扫描期间有新交互或已被其他请求结束
+			}
+			completed++
+			logger.InfoContext(ctx, "chat session completed by timeout", "session_id", id, "status", rule.status)
+			s.reviewSessionAsync(ctx, id)
+		}
+	}
+	return completed, nil
+}
+
+// transitionSession 将会话从 from 切换到 to；已处于 to 状态时直接返回（重复调用幂等），已结束的会话返回 ErrConversationClosed
+func (s *chatServiceImpl) transitionSession(ctx context.Context, sessionID, userID, from, to string) (*SessionSummary, error) {
+	// ─── 1. 验证会话归属与当前状态 ───
+	conversation, err := s.getOwnedConversation(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.Status == to {
+		return toSessionSummary(conversation, nil), nil
+	}
+	if conversation.Status != from {
+		return nil, apperr.ErrConversationClosed.WithMessage("status " + conversation.Status)
+	}
+
+	// ─── 2. 切换状态（离开 active 时累计本段活跃时间） ───
+	updated, err := s.conversationRepo.TransitionStatus(ctx, conversation.ID, from, to, s.idleTimeout(ctx))
+	if err != nil {
+		if errors.Is(err, db.ErrConversationStatusStale) {
+			return nil, apperr.ErrConversationClosed.WithMessage("status changed, please retry")
+		}
+		return nil, fmt.Errorf("transition conversation failed: %w", err)
+	}
+	logger.InfoContext(ctx, "chat session status changed", "session_id", conversation.ID, "from", from, "to", to)
+	return toSessionSummary(updated, nil), nil
+}
+
+// idleTimeout 从 system_settings 读取空闲超时，失败时使用默认值
+// 同时作为单次交互间隔计入时长的上限
+func (s *chatServiceImpl) idleTimeout(ctx context.Context) time.Duration {
+	return time.Duration(s.lifecycleSetting(ctx, model.ConfigConversationIdleTimeoutMinutes, defaultIdleTimeoutMinutes)) * time.Minute
+}
+
+// pausedTimeout 从 system_settings 读取暂停超时，失败时使用默认值
+func (s *chatServiceImpl) pausedTimeout(ctx context.Context) time.Duration {
+	return time.Duration(s.lifecycleSetting(ctx, model.ConfigConversationPausedTimeoutHours, defaultPausedTimeoutHours)) * time.Hour
+}
+
+// lifecycleSetting 读取正整数配置，未配置、非法或读取失败时返回 fallback
+func (s *chatServiceImpl) lifecycleSetting(ctx context.Context, key string, fallback int) int {
+	if s.settingRepo == nil {
+		return fallback
+	}
+	value, err := s.settingRepo.GetIntValue(ctx, key)
+	if err != nil || value <= 0 {
+		if err != nil && !db.IsNotFound(err) {
+			logger.WarnContext(ctx, "chat read lifecycle setting failed", "key", key, "error", err)
+		}
+		return fallback
+	}
+	return value
+}
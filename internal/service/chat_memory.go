@@ -0,0 +1,61 @@
+// Package service 提供对话中的学习者长期记忆（新会话注入、会话结束后提取）
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"pronunciation-correction-system/internal/model"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// learnerMemoryExtractionTimeout 单个会话提取记忆超时（与请求生命周期无关）
+const learnerMemoryExtractionTimeout = 60 * time.Second
+
+// resolveLearnerMemory 获取本轮使用的长期记忆（每行一条事实）
+// 已有会话沿用会话开始时保存的记忆；新会话按相关度选取，随第一轮保存到会话；读取失败时不注入
+func (s *chatServiceImpl) resolveLearnerMemory(ctx context.Context, userID string, conversation *model.VoiceConversation) string {
+	if conversation != nil {
+		if conversation.LearnerMemory == nil {
+			return ""
+		}
+		return *conversation.LearnerMemory
+	}
+	if s.memoryService == nil {
+		return ""
+	}
+	memories, err := s.memoryService.Recall(ctx, userID)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat recall learner memory failed, continue without memory", "user_id", userID, "error", err)
+		return ""
+	}
+	lines := make([]string, 0, len(memories))
+	for _, m := range memories {
+		lines = append(lines, "- "+m.Content)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// learnerMemoryPrompt 长期记忆提示词片段（没有记忆时为空）
+func learnerMemoryPrompt(memory string) string {
+	if memory == "" {
+		return ""
+	}
+	return "\nWhat you remember about this child from earlier sessions (bring up at most one of these naturally when it fits; " +
+		"never ask for personal details such as address, phone number or school name):\n" + memory + "\n"
+}
+
+// extractLearnerMemoryAsync 会话总结后在后台提取孩子的个人信息到长期记忆
+func (s *chatServiceImpl) extractLearnerMemoryAsync(ctx context.Context, conversation *model.VoiceConversation, messages []*model.ConversationMessage) {
+	if s.memoryService == nil {
+		return
+	}
+
+	// 脱离请求生命周期在后台执行（HTTP 响应返回或 WebSocket 轮次结束后继续）
+	runBackground(ctx, "learner memory extraction", learnerMemoryExtractionTimeout, func(ctx context.Context) {
+		if err := s.memoryService.ExtractFromConversation(ctx, conversation, messages); err != nil {
+			logger.ErrorContext(ctx, "chat extract learner memory failed", "session_id", conversation.ID, "error", err)
+		}
+	})
+}
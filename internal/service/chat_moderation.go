@@ -0,0 +1,153 @@
+// Package service 提供对话内容安全审核（孩子输入与 AI 回复在合成语音和保存前审核）
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"pronunciation-correction-system/internal/domain"
+	"pronunciation-correction-system/internal/model"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// 审核方向（写入日志与会话标记原因）
+const (
+	moderationDirectionInput  = "input"
+	moderationDirectionOutput = "output"
+)
+
+// moderationFlagReasonMaxRunes 会话标记原因的最大长度（与 flag_reason 列一致）
+const moderationFlagReasonMaxRunes = 255
+
+// blockedInputPlaceholder 孩子输入被拦截时保存到会话历史的文本（不含英文字母，不进入语法批注）
+const blockedInputPlaceholder = "【内容已屏蔽】"
+
+// 拦截后的兜底回复（替代 LLM 回复，语气温和并引导回英语练习）
+const (
+	moderationFallbackReply = "Hmm, let's talk about something else! What is your favorite animal?"
+	moderationSelfHarmReply = "I'm sorry you feel this way. Please talk to a grown-up you trust, like your mom, dad or teacher. I'm here to chat with you too."
+)
+
+// turnModeration 一轮对话的审核状态
+type turnModeration struct {
+	sessionID      string
+	inputBlocked   bool     // 孩子输入被拦截，本轮不调用 LLM
+	inputRewritten bool     // 孩子输入被打码改写，保存的文本与原始语音不一致
+	reasons        []string // 命中记录（"方向:类别"，去重），非空时标记会话待复核
+}
+
+// flagReason 会话标记原因
+func (m *turnModeration) flagReason() string {
+	reason := strings.Join(m.reasons, ",")
+	if utf8.RuneCountInString(reason) > moderationFlagReasonMaxRunes {
+		reason = string([]rune(reason)[:moderationFlagReasonMaxRunes])
+	}
+	return reason
+}
+
+// fallbackReply 拦截后的兜底回复：本轮命中自伤类时引导孩子向信任的大人求助
+func (m *turnModeration) fallbackReply() string {
+	for _, reason := range m.reasons {
+		if strings.HasSuffix(reason, ":"+domain.ModerationCategorySelfHarm) {
+			return moderationSelfHarmReply
+		}
+	}
+	return moderationFallbackReply
+}
+
+// record 记录命中类别
+func (m *turnModeration) record(direction string, result *domain.ModerationResult) {
+	for _, category := range result.Categories {
+		reason := direction + ":" + category
+		if !slices.Contains(m.reasons, reason) {
+			m.reasons = append(m.reasons, reason)
+		}
+	}
+}
+
+// moderateInput 审核孩子输入，返回可保存与送入 LLM 的文本
+// 拦截时返回占位文本并标记 inputBlocked；未启用审核或审核服务出错时原样放行（仅记录日志）
+func (s *chatServiceImpl) moderateInput(ctx context.Context, conversation *model.VoiceConversation, userText string) (string, *turnModeration) {
+	moderation := &turnModeration{}
+	if conversation != nil {
+		moderation.sessionID = conversation.ID
+	}
+	if s.moderationProvider == nil {
+		return userText, moderation
+	}
+
+	result, err := s.moderationProvider.ModerateInput(ctx, userText)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat moderation failed, allow content", "direction", moderationDirectionInput, "session_id", moderation.sessionID, "error", err)
+		return userText, moderation
+	}
+	s.logModerationDecision(ctx, moderation, moderationDirectionInput, result)
+	moderation.record(moderationDirectionInput, result)
+
+	switch result.Action {
+	case domain.ModerationActionBlock:
+		moderation.inputBlocked = true
+		return blockedInputPlaceholder, moderation
+	case domain.ModerationActionRewrite:
+		moderation.inputRewritten = true
+		return result.Rewritten, moderation
+	}
+	return userText, moderation
+}
+
+// moderateReply 审核 AI 回复（完整回复或流式生成中的一个句子），返回可合成与保存的文本
+// 拦截时返回兜底回复，blocked 为 true；未启用审核或审核服务出错时原样放行
+func (s *chatServiceImpl) moderateReply(ctx context.Context, moderation *turnModeration, replyText string) (string, bool) {
+	if s.moderationProvider == nil || moderation == nil {
+		return replyText, false
+	}
+
+	result, err := s.moderationProvider.ModerateOutput(ctx, replyText)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat moderation failed, allow content", "direction", moderationDirectionOutput, "session_id", moderation.sessionID, "error", err)
+		return replyText, false
+	}
+	s.logModerationDecision(ctx, moderation, moderationDirectionOutput, result)
+	moderation.record(moderationDirectionOutput, result)
+
+	switch result.Action {
+	case domain.ModerationActionBlock:
+		return moderation.fallbackReply(), true
+	case domain.ModerationActionRewrite:
+		return result.Rewritten, false
+	}
+	return replyText, false
+}
+
+// logModerationDecision 记录审核决策（命中内容仅记录条数，不写入日志原文）
+func (s *chatServiceImpl) logModerationDecision(ctx context.Context, moderation *turnModeration, direction string, result *domain.ModerationResult) {
+	attrs := []any{
+		"direction", direction,
+		"action", result.Action,
+		"categories", result.Categories,
+		"matches", len(result.Matches),
+		"session_id", moderation.sessionID,
+	}
+	switch result.Action {
+	case domain.ModerationActionBlock:
+		logger.WarnContext(ctx, "chat moderation decision", attrs...)
+	case domain.ModerationActionRewrite:
+		logger.InfoContext(ctx, "chat moderation decision", attrs...)
+	default:
+		logger.DebugContext(ctx, "chat moderation decision", attrs...)
+	}
+}
+
+// flagConversation 本轮命中审核时标记会话待人工复核（失败仅记录日志）
+func (s *chatServiceImpl) flagConversation(ctx context.Context, conversationID string, moderation *turnModeration) {
+	if moderation == nil || len(moderation.reasons) == 0 || s.conversationRepo == nil {
+		return
+	}
+	if err := s.conversationRepo.FlagForReview(ctx, conversationID, moderation.flagReason()); err != nil {
+		logger.ErrorContext(ctx, "chat flag conversation failed", "session_id", conversationID, "error", err)
+		return
+	}
+	logger.InfoContext(ctx, "chat conversation flagged for review", "session_id", conversationID, "reason", moderation.flagReason())
+}
@@ -0,0 +1,215 @@
+// Package service 提供 AI 老师人设（学习者选择人设，每轮对话应用人设的性格提示词与 TTS 音色、语速）
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/domain"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/uuid"
+)
+
+// 人设语速范围（与 TTS 服务一致）
+const (
+	minPersonaSpeakingRate = 0.5
+	maxPersonaSpeakingRate = 2.0
+)
+
+// personaPrompt 人设性格提示词（追加在默认老师提示词之后，核心规则不变）
+const personaPrompt = `
+PERSONA:
+%s
+- Stay in this character in every reply, but keep all the rules above (short, simple, encouraging).
+`
+
+// ===== 请求结构 =====
+
+// UpdatePersonaRequest 选择 AI 老师人设请求
+type UpdatePersonaRequest struct {
+	UserID    string
+	PersonaID string // 人设 ID 或人设编码，为空时恢复默认老师
+}
+
+// ===== 响应结构 =====
+
+// PersonaResponse 学习者当前的 AI 老师人设
+type PersonaResponse struct {
+	Persona *model.TutorPersona `json:"persona"` // 为 null 时使用默认老师
+}
+
+// turnPersona 一轮对话使用的人设设置
+type turnPersona struct {
+	persona *model.TutorPersona       // 学习者选择的人设，为 nil 时使用默认老师
+	tts     *domain.SynthesizeOptions // 合成参数，为 nil 时使用 TTS 默认参数
+}
+
+// prompt 人设的系统提示词片段（默认老师为空）
+func (p *turnPersona) prompt() string {
+	if p == nil || p.persona == nil || strings.TrimSpace(p.persona.Personality) == "" {
+		return ""
+	}
+	return fmt.Sprintf(personaPrompt, strings.TrimSpace(p.persona.Personality))
+}
+
+// synthesizeOptions 本轮的 TTS 合成参数
+func (p *turnPersona) synthesizeOptions() *domain.SynthesizeOptions {
+	if p == nil {
+		return nil
+	}
+	return p.tts
+}
+
+// resolvePersona 组装本轮的人设设置：学习者选择的人设（已下架或读取失败时视为默认老师）
+// 音色优先使用人设音色，其次使用 system_settings.default_tts_voice
+func (s *chatServiceImpl) resolvePersona(ctx context.Context, userID string) *turnPersona {
+	persona := s.selectedPersona(ctx, userID)
+	voice := ""
+	rate := 0.0
+	if persona != nil {
+		if persona.TTSVoice != nil {
+			voice = strings.TrimSpace(*persona.TTSVoice)
+		}
+		rate = clampSpeakingRate(persona.SpeakingRate)
+	}
+	if voice == "" {
+		voice = s.defaultTTSVoice(ctx)
+	}
+
+	tp := &turnPersona{persona: persona}
+	if voice != "" || rate != 0 {
+		tp.tts = &domain.SynthesizeOptions{Voice: voice, Rate: rate}
+	}
+	return tp
+}
+
+// selectedPersona 读取学习者选择的人设，未选择、已下架或读取失败时返回 nil
+func (s *chatServiceImpl) selectedPersona(ctx context.Context, userID string) *model.TutorPersona {
+	if s.profileRepo == nil || s.personaRepo == nil {
+		return nil
+	}
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if !db.IsNotFound(err) {
+			logger.WarnContext(ctx, "chat read persona failed, use default", "user_id", userID, "error", err)
+		}
+		return nil
+	}
+	if profile.PersonaID == nil || *profile.PersonaID == "" {
+		return nil
+	}
+	persona, err := s.personaRepo.GetByID(ctx, *profile.PersonaID)
+	if err != nil {
+		if !db.IsNotFound(err) {
+			logger.WarnContext(ctx, "chat load persona failed, use default", "persona_id", *profile.PersonaID, "error", err)
+		}
+		return nil
+	}
+	if !persona.IsActive {
+		return nil
+	}
+	return persona
+}
+
+// defaultTTSVoice 从 system_settings 读取默认音色，读取失败时返回空字符串（使用 TTS 配置的默认音色）
+func (s *chatServiceImpl) defaultTTSVoice(ctx context.Context) string {
+	if s.settingRepo == nil {
+		return ""
+	}
+	voice, err := s.settingRepo.GetValue(ctx, model.ConfigDefaultTTSVoice)
+	if err != nil {
+		if !db.IsNotFound(err) {
+			logger.WarnContext(ctx, "chat read default tts voice failed", "error", err)
+		}
+		return ""
+	}
+	return strings.TrimSpace(voice)
+}
+
+// clampSpeakingRate 将语速限制在 TTS 支持的范围内（未设置时返回 0，使用默认语速）
+func clampSpeakingRate(rate float64) float64 {
+	switch {
+	case rate <= 0:
+		return 0
+	case rate < minPersonaSpeakingRate:
+		return minPersonaSpeakingRate
+	case rate > maxPersonaSpeakingRate:
+		return maxPersonaSpeakingRate
+	}
+	return rate
+}
+
+// getActivePersona 根据人设 ID 或人设编码获取已上架的人设
+func (s *chatServiceImpl) getActivePersona(ctx context.Context, idOrCode string) (*model.TutorPersona, error) {
+	if s.personaRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("persona repository not initialized")
+	}
+
+	var persona *model.TutorPersona
+	var err error
+	if uuid.IsValid(idOrCode) {
+		persona, err = s.personaRepo.GetByID(ctx, idOrCode)
+	} else {
+		persona, err = s.personaRepo.GetByCode(ctx, idOrCode)
+	}
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, apperr.ErrPersonaNotFound
+		}
+		return nil, fmt.Errorf("get persona failed: %w", err)
+	}
+	if !persona.IsActive {
+		return nil, apperr.ErrPersonaNotFound
+	}
+	return persona, nil
+}
+
+func (s *chatServiceImpl) ListPersonas(ctx context.Context) ([]*model.TutorPersona, error) {
+	if s.personaRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("persona repository not initialized")
+	}
+	return s.personaRepo.ListActive(ctx)
+}
+
+func (s *chatServiceImpl) GetPersona(ctx context.Context, userID string) (*PersonaResponse, error) {
+	return &PersonaResponse{Persona: s.selectedPersona(ctx, userID)}, nil
+}
+
+func (s *chatServiceImpl) UpdatePersona(ctx context.Context, req *UpdatePersonaRequest) (*PersonaResponse, error) {
+	if req == nil || req.UserID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user_id is required")
+	}
+	if s.profileRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("profile repository not initialized")
+	}
+
+	// 步骤 1：校验人设（为空表示恢复默认老师）
+	var persona *model.TutorPersona
+	if idOrCode := strings.TrimSpace(req.PersonaID); idOrCode != "" {
+		var err error
+		persona, err = s.getActivePersona(ctx, idOrCode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 步骤 2：保存到学习者扩展信息
+	profile := &model.UserProfile{
+		ID:     uuid.New(),
+		UserID: req.UserID,
+	}
+	personaCode := ""
+	if persona != nil {
+		profile.PersonaID = &persona.ID
+		personaCode = persona.Code
+	}
+	if err := s.profileRepo.UpsertPersona(ctx, profile); err != nil {
+		return nil, fmt.Errorf("save persona failed: %w", err)
+	}
+	logger.InfoContext(ctx, "chat persona updated", "user_id", req.UserID, "persona", personaCode)
+	return &PersonaResponse{Persona: persona}, nil
+}
@@ -0,0 +1,158 @@
+// Package service 提供对话发音评测（异步评测用户语音，汇总会话中反复发音不准的单词）
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"pronunciation-correction-system/internal/domain"
+	"pronunciation-correction-system/internal/model"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// 对话发音评测规则
+const (
+	pronunciationAssessTimeout = 30 * time.Second // 单条消息评测超时（与请求生命周期无关）
+	pronunciationSampleRate    = 16000            // 语音评测服务要求的采样率
+	pronunciationProblemScore  = 60               // 单词得分低于该值记为问题单词（与发音评测一致）
+	sessionProblemWordMinTurns = 2                // 在至少该轮数中发音不准的单词计入会话问题单词
+	sessionProblemWordMaxItems = 10               // 会话问题单词最多保留数
+)
+
+// pronunciationAudioTypes 语音评测服务支持的音频格式（16bit 单声道 PCM，WAV 去掉 header 后送评）
+var pronunciationAudioTypes = map[string]bool{
+	"pcm": true,
+	"wav": true,
+}
+
+// sessionProblemWord 会话中单词的发音统计
+type sessionProblemWord struct {
+	word     string
+	turns    int     // 发音不准的轮数
+	minScore float64 // 会话中的最低得分
+}
+
+// assessPronunciationAsync 在后台评测已保存的用户消息发音并写回结果，不阻塞本轮回复
+// 以 ASR 识别文本为参考文本；只评测英文消息，输入被审核拦截或打码（文本与语音不一致）、音频不支持时不评测
+// 返回的 channel 在评测结束（或无需评测）时关闭，会话在本轮结束时据此等待最后一轮的结果
+func (s *chatServiceImpl) assessPronunciationAsync(ctx context.Context, message *model.ConversationMessage, t *chatTurn) <-chan struct{} {
+	done := make(chan struct{})
+	if !s.cfg.AssessPronunciation || s.evaluationProvider == nil || s.messageRepo == nil {
+		close(done)
+		return done
+	}
+	if t.userLanguage != model.MessageLanguageEnglish || len(t.userAudio) == 0 ||
+		!pronunciationAudioTypes[t.audioType] || t.sampleRate != pronunciationSampleRate {
+		close(done)
+		return done
+	}
+	if t.moderation != nil && (t.moderation.inputBlocked || t.moderation.inputRewritten) {
+		close(done)
+		return done
+	}
+
+	// 脱离请求生命周期在后台执行（HTTP 响应返回或 WebSocket 轮次结束后继续）
+	runBackground(ctx, "pronunciation assess", pronunciationAssessTimeout, func(ctx context.Context) {
+		defer close(done)
+		// WAV 格式去掉 44 字节 header（讯飞评测需 PCM 裸数据）
+		audio := t.userAudio
+		if t.audioType == "wav" && len(audio) > 44 {
+			audio = audio[44:]
+		}
+		result, err := s.evaluationProvider.Assess(ctx, message.MessageText, audio, domain.AssessCategorySentence)
+		if err != nil {
+			logger.WarnContext(ctx, "chat pronunciation assess failed", "message_id", message.ID, "error", err)
+			return
+		}
+
+		wordDetails := make([]WordDetail, 0, len(result.Words))
+		problemWords := model.StringArray{}
+		for _, w := range result.Words {
+			isProblem := w.Score < pronunciationProblemScore
+			wordDetails = append(wordDetails, WordDetail{Word: w.Word, Score: w.Score, IsProblem: isProblem})
+			if isProblem {
+				problemWords = append(problemWords, w.Word)
+			}
+		}
+		score := clampScore(result.TotalScore)
+		if err := s.messageRepo.UpdatePronunciation(ctx, message.ID, score, toWordScoreList(wordDetails), problemWords); err != nil {
+			logger.ErrorContext(ctx, "chat save pronunciation failed", "message_id", message.ID, "error", err)
+			return
+		}
+		logger.InfoContext(ctx, "chat pronunciation assessed", "message_id", message.ID, "score", score, "problem_words", len(problemWords))
+	})
+	return done
+}
+
+// sessionProblemWords 汇总会话中在至少 sessionProblemWordMinTurns 轮发言里都发音不准的单词
+// 按出现轮数降序、最低得分升序排列；会话中没有评测过的消息时返回 nil
+func sessionProblemWords(messages []*model.ConversationMessage) []*sessionProblemWord {
+	assessed := false
+	stats := make(map[string]*sessionProblemWord)
+	for _, m := range messages {
+		if m.SenderType != model.SenderTypeUser || m.PronunciationScore == nil {
+			continue
+		}
+		assessed = true
+		counted := make(map[string]bool)
+		for _, ws := range m.WordScores {
+			word := normalizeReviewWord(ws.Word)
+			if word == "" || !ws.IsProblem {
+				continue
+			}
+			stat, ok := stats[word]
+			if !ok {
+				stat = &sessionProblemWord{word: word, minScore: ws.Score}
+				stats[word] = stat
+			}
+			if ws.Score < stat.minScore {
+				stat.minScore = ws.Score
+			}
+			if !counted[word] {
+				counted[word] = true
+				stat.turns++
+			}
+		}
+	}
+	if !assessed {
+		return nil
+	}
+
+	words := make([]*sessionProblemWord, 0, len(stats))
+	for _, stat := range stats {
+		if stat.turns >= sessionProblemWordMinTurns {
+			words = append(words, stat)
+		}
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].turns != words[j].turns {
+			return words[i].turns > words[j].turns
+		}
+		if words[i].minScore != words[j].minScore {
+			return words[i].minScore < words[j].minScore
+		}
+		return words[i].word < words[j].word
+	})
+	if len(words) > sessionProblemWordMaxItems {
+		words = words[:sessionProblemWordMaxItems]
+	}
+	return words
+}
+
+// recordSessionProblemWords 将会话问题单词写入学习者的单词复习队列（失败仅记录日志）
+func (s *chatServiceImpl) recordSessionProblemWords(ctx context.Context, conversation *model.VoiceConversation, words []*sessionProblemWord) {
+	if s.reviewService == nil || len(words) == 0 {
+		return
+	}
+	details := make([]WordDetail, 0, len(words))
+	for _, w := range words {
+		details = append(details, WordDetail{Word: w.word, Score: w.minScore, IsProblem: true})
+	}
+	if err := s.reviewService.RecordWordScores(ctx, &RecordWordScoresRequest{
+		UserID: conversation.UserID,
+		Words:  details,
+	}); err != nil {
+		logger.ErrorContext(ctx, "chat record problem words failed", "session_id", conversation.ID, "error", err)
+	}
+}
@@ -0,0 +1,354 @@
+// Package service 提供问答模式（question_answer）对话业务逻辑
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	llmPrompts "pronunciation-correction-system/internal/infrastructure/llm"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/uuid"
+)
+
+// 问答判定规则
+const (
+	questionPassScore   = 60 // 切题且得分 >= 60 视为通过
+	questionMaxAttempts = 2  // 每题最多作答次数，用尽后示范参考答案并进入下一题
+)
+
+// ===== 请求结构 =====
+
+// QuestionSetListRequest 题集列表查询请求
+type QuestionSetListRequest struct {
+	ScenarioID      string
+	DifficultyLevel string
+	Page            int
+	PageSize        int
+}
+
+// ===== 响应结构 =====
+
+// StartQuestionSetResponse 开始问答会话结果
+type StartQuestionSetResponse struct {
+	SessionID       string             `json:"session_id"`
+	QuestionSet     *model.QuestionSet `json:"question_set"`
+	OpeningLine     string             `json:"opening_line"`                // 引导语 + 第一个问题
+	OpeningAudioURL string             `json:"opening_audio_url,omitempty"` // 开场白语音（合成失败时为空）
+	QuestionTotal   int                `json:"question_total"`
+	RemainingTurns  int                `json:"remaining_turns"`
+}
+
+// QuestionProgressResponse 问答会话进度与每题结果
+type QuestionProgressResponse struct {
+	SessionID       string                   `json:"session_id"`
+	QuestionSetID   string                   `json:"question_set_id"`
+	Title           string                   `json:"title"`
+	Status          string                   `json:"status"`
+	QuestionIndex   int                      `json:"question_index"` // 当前题目序号（从 0 开始，等于 question_total 表示已答完）
+	QuestionTotal   int                      `json:"question_total"`
+	CurrentQuestion string                   `json:"current_question,omitempty"`
+	AverageScore    int                      `json:"average_score"` // 已完成题目的平均分
+	Results         model.QuestionResultList `json:"results"`
+}
+
+// questionTurn 问答模式一轮的判定结果与进度（随本轮消息一起保存，被打断的轮次不生效）
+type questionTurn struct {
+	check     model.QuestionResult     // 本轮作答判定（需重答时 Passed 为 false）
+	index     int                      // 本轮后的当前题目序号
+	attempts  int                      // 本轮后当前题目的作答次数
+	results   model.QuestionResultList // 本轮后已完成题目的结果
+	total     int                      // 题目总数
+	completed bool                     // 全部题目已答完
+}
+
+// answerCheck LLM 答案判定结果
+type answerCheck struct {
+	Relevant    bool   `json:"relevant"`
+	Grammatical bool   `json:"grammatical"`
+	Score       int    `json:"score"`
+	Feedback    string `json:"feedback"`
+	Corrected   string `json:"corrected"`
+}
+
+func (s *chatServiceImpl) ListQuestionSets(ctx context.Context, req *QuestionSetListRequest) ([]*model.QuestionSet, int64, error) {
+	if s.questionSetRepo == nil {
+		return nil, 0, apperr.ErrInternalError.WithMessage("question set repository not initialized")
+	}
+	return s.questionSetRepo.ListActive(ctx, &db.QuestionSetQuery{
+		ScenarioID:      strings.TrimSpace(req.ScenarioID),
+		DifficultyLevel: strings.TrimSpace(req.DifficultyLevel),
+	}, req.Page, req.PageSize)
+}
+
+func (s *chatServiceImpl) StartQuestionSet(ctx context.Context, questionSetID, userID string) (*StartQuestionSetResponse, error) {
+	// 步骤 1：查询题集
+	set, err := s.getActiveQuestionSet(ctx, questionSetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(set.Questions) == 0 {
+		return nil, apperr.ErrInvalidParam.WithMessage("question set has no questions")
+	}
+	if s.conversationRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("conversation repository not initialized")
+	}
+
+	// 步骤 2：创建 question_answer 会话（开场白不计入消息）
+	now := time.Now()
+	conversation := &model.VoiceConversation{
+		ID:               uuid.New(),
+		UserID:           userID,
+		Topic:            set.Title,
+		DifficultyLevel:  set.DifficultyLevel,
+		ConversationType: model.ConversationTypeQuestionAnswer,
+		QuestionSetID:    &set.ID,
+		Status:           model.ConversationStatusActive,
+		LastActiveAt:     &now,
+	}
+	if err := s.conversationRepo.Create(ctx, conversation); err != nil {
+		return nil, fmt.Errorf("create question conversation failed: %w", err)
+	}
+
+	// 步骤 3：开场白语音（按题集缓存，失败不影响会话创建）
+	openingLine := joinReply(set.Introduction, set.Questions[0].Question)
+	openingAudioURL, err := ensureQuestionSetOpeningAudio(ctx, s.ttsProvider, s.ossProvider, set, openingLine)
+	if err != nil {
+		logger.WarnContext(ctx, "question set opening audio failed", "question_set_id", set.ID, "error", err)
+	}
+
+	logger.InfoContext(ctx, "question conversation started", "session_id", conversation.ID, "question_set", set.Code)
+	return &StartQuestionSetResponse{
+		SessionID:       conversation.ID,
+		QuestionSet:     set,
+		OpeningLine:     openingLine,
+		OpeningAudioURL: openingAudioURL,
+		QuestionTotal:   len(set.Questions),
+		RemainingTurns:  s.maxConversationMessages(ctx) / 2,
+	}, nil
+}
+
+func (s *chatServiceImpl) GetQuestionProgress(ctx context.Context, sessionID, userID string) (*QuestionProgressResponse, error) {
+	conversation, err := s.getOwnedConversation(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.QuestionSetID == nil {
+		return nil, apperr.ErrInvalidParam.WithMessage("session is not a question_answer session")
+	}
+	set := s.loadQuestionSet(ctx, conversation)
+	if set == nil {
+		return nil, apperr.ErrQuestionSetNotFound
+	}
+
+	resp := &QuestionProgressResponse{
+		SessionID:     conversation.ID,
+		QuestionSetID: set.ID,
+		Title:         set.Title,
+		Status:        conversation.Status,
+		QuestionIndex: conversation.QuestionIndex,
+		QuestionTotal: len(set.Questions),
+		Results:       conversation.QuestionResults,
+	}
+	if resp.Results == nil {
+		resp.Results = model.QuestionResultList{}
+	}
+	if conversation.QuestionIndex < len(set.Questions) {
+		resp.CurrentQuestion = set.Questions[conversation.QuestionIndex].Question
+	}
+	if len(resp.Results) > 0 {
+		total := 0
+		for _, r := range resp.Results {
+			total += r.Score
+		}
+		resp.AverageScore = total / len(resp.Results)
+	}
+	return resp, nil
+}
+
+// getActiveQuestionSet 按 ID 或题集编码获取已上架的题集
+func (s *chatServiceImpl) getActiveQuestionSet(ctx context.Context, idOrCode string) (*model.QuestionSet, error) {
+	idOrCode = strings.TrimSpace(idOrCode)
+	if idOrCode == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("question_set_id is required")
+	}
+	if s.questionSetRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("question set repository not initialized")
+	}
+
+	var set *model.QuestionSet
+	var err error
+	if uuid.IsValid(idOrCode) {
+		set, err = s.questionSetRepo.GetByID(ctx, idOrCode)
+	} else {
+		set, err = s.questionSetRepo.GetByCode(ctx, idOrCode)
+	}
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, apperr.ErrQuestionSetNotFound
+		}
+		return nil, fmt.Errorf("get question set failed: %w", err)
+	}
+	if !set.IsActive {
+		return nil, apperr.ErrQuestionSetNotFound
+	}
+	return set, nil
+}
+
+// loadQuestionSet 加载问答会话关联的题集（缓存在 conversation.QuestionSet 上），非问答会话或加载失败时返回 nil
+func (s *chatServiceImpl) loadQuestionSet(ctx context.Context, conversation *model.VoiceConversation) *model.QuestionSet {
+	if conversation == nil || conversation.QuestionSetID == nil || s.questionSetRepo == nil {
+		return nil
+	}
+	if conversation.QuestionSet != nil {
+		return conversation.QuestionSet
+	}
+	set, err := s.questionSetRepo.GetByID(ctx, *conversation.QuestionSetID)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat load question set failed", "session_id", conversation.ID, "error", err)
+		return nil
+	}
+	conversation.QuestionSet = set
+	return set
+}
+
+// isQuestionSession 判断会话是否为基于题集的问答会话
+func isQuestionSession(conversation *model.VoiceConversation) bool {
+	return conversation != nil && conversation.QuestionSetID != nil
+}
+
+// answerQuestion 判定孩子对当前题目的回答，生成回复文本（反馈 + 提示重问 / 下一题 / 结束语）
+// 只计算进度，不写库；进度随本轮消息在 persistTurn 中保存
+func (s *chatServiceImpl) answerQuestion(ctx context.Context, conversation *model.VoiceConversation, userText string) (string, *questionTurn, error) {
+	set := s.loadQuestionSet(ctx, conversation)
+	if set == nil {
+		return "", nil, apperr.ErrQuestionSetNotFound
+	}
+	index := conversation.QuestionIndex
+	if index >= len(set.Questions) {
+		return "", nil, apperr.ErrConversationClosed.WithMessage("all questions answered")
+	}
+	question := set.Questions[index]
+
+	// 步骤 1：LLM 判定是否切题、是否符合语法并打分
+	sourceText := ""
+	if set.SourceText != nil {
+		sourceText = *set.SourceText
+	}
+	systemPrompt, userMessage := llmPrompts.BuildAnswerCheckPrompt(sourceText, question.Question, question.ReferenceAnswer,
+		question.Keywords, conversation.DifficultyLevel, userText)
+	reply, err := s.llmProvider.Chat(ctx, systemPrompt, userMessage)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat answer check llm failed", "session_id", conversation.ID, "error", err)
+		return "", nil, err
+	}
+	check, err := parseAnswerCheck(reply)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat answer check parse failed", "session_id", conversation.ID, "reply_length", len(reply), "error", err)
+		logger.DebugContext(ctx, "chat answer check raw reply", "session_id", conversation.ID, "reply", reply)
+		return "", nil, fmt.Errorf("parse answer check failed: %w", err)
+	}
+
+	// 步骤 2：计算本轮后的进度
+	turn := &questionTurn{
+		check: model.QuestionResult{
+			Index:       index,
+			Question:    question.Question,
+			Answer:      userText,
+			Relevant:    check.Relevant,
+			Grammatical: check.Grammatical,
+			Score:       check.Score,
+			Feedback:    check.Feedback,
+			Corrected:   check.Corrected,
+			Attempts:    conversation.QuestionAttempts + 1,
+			Passed:      check.Relevant && check.Score >= questionPassScore,
+		},
+		index:    index,
+		attempts: conversation.QuestionAttempts + 1,
+		results:  append(model.QuestionResultList{}, conversation.QuestionResults...),
+		total:    len(set.Questions),
+	}
+
+	// 步骤 3：生成回复（未通过且仍可重答：提示 + 重问；否则：必要时示范答案 + 下一题 / 结束语）
+	feedback := check.Feedback
+	if feedback == "" {
+		feedback = "Nice try!"
+		if turn.check.Passed {
+			feedback = "Well done!"
+		}
+	}
+	if !turn.check.Passed && turn.attempts < questionMaxAttempts {
+		hint := question.Hint
+		if hint == "" {
+			hint = "Listen to the question again."
+		}
+		return joinReply(feedback, "Here is a hint: "+hint, question.Question), turn, nil
+	}
+
+	var modelAnswer string
+	if !turn.check.Passed {
+		modelAnswer = "A good answer is: " + question.ReferenceAnswer
+	} else if !check.Grammatical && check.Corrected != "" {
+		modelAnswer = "You can also say: " + check.Corrected
+	}
+	turn.results = append(turn.results, turn.check)
+	turn.index = index + 1
+	turn.attempts = 0
+	if turn.index >= len(set.Questions) {
+		turn.completed = true
+		return joinReply(feedback, modelAnswer, "You finished all the questions. Great job!"), turn, nil
+	}
+	return joinReply(feedback, modelAnswer, "Next question: "+set.Questions[turn.index].Question), turn, nil
+}
+
+// saveQuestionProgress 保存问答进度，全部答完时结束会话；失败仅记录日志
+func (s *chatServiceImpl) saveQuestionProgress(ctx context.Context, conversation *model.VoiceConversation, turn *questionTurn) {
+	if err := s.conversationRepo.UpdateQuestionProgress(ctx, conversation.ID, turn.index, turn.attempts, turn.results, turn.completed); err != nil {
+		logger.ErrorContext(ctx, "chat save question progress failed", "session_id", conversation.ID, "error", err)
+		return
+	}
+	conversation.QuestionIndex = turn.index
+	conversation.QuestionAttempts = turn.attempts
+	conversation.QuestionResults = turn.results
+	if turn.completed {
+		conversation.Status = model.ConversationStatusCompleted
+		logger.InfoContext(ctx, "question conversation completed", "session_id", conversation.ID, "answered", len(turn.results))
+	}
+}
+
+// parseAnswerCheck 解析 LLM 返回的判定 JSON（截取 JSON 对象部分，容忍多余文字），分数限制在 0-100
+func parseAnswerCheck(reply string) (*answerCheck, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("no json object in reply")
+	}
+	var check answerCheck
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &check); err != nil {
+		return nil, err
+	}
+	if check.Score < 0 {
+		check.Score = 0
+	}
+	if check.Score > 100 {
+		check.Score = 100
+	}
+	check.Feedback = strings.TrimSpace(check.Feedback)
+	check.Corrected = strings.TrimSpace(check.Corrected)
+	return &check, nil
+}
+
+// joinReply 以空格拼接回复片段，忽略空片段
+func joinReply(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
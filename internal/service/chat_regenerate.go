@@ -0,0 +1,248 @@
+// Package service 提供 AI 回复的重新生成与失败轮次的重试（被替换的回复保留为版本）
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// maxReplyVersions 同一条回复最多保留的被替换版本数（达到后不能再重新生成，失败轮次仍可重试）
+const maxReplyVersions = 5
+
+// ===== 请求结构 =====
+
+// ReplyRequest 重新生成最后一轮回复 / 重试失败轮次请求
+type ReplyRequest struct {
+	SessionID string
+	Translate bool // 本轮是否要求中文释义（translate_on_demand 策略使用）
+	UserID    string
+	Debug     bool // 结果中附带各阶段耗时
+}
+
+// ===== 响应结构 =====
+
+// ReplyResponse 替换后的 AI 回复及会话信息
+type ReplyResponse struct {
+	ReplyText    string `json:"reply_text"`
+	AudioURL     string `json:"audio_url"`
+	ReplyVersion int    `json:"reply_version"` // 当前版本号（1 为首次生成，每次重新生成或重试后递增）
+	ChatTurnResult
+}
+
+func (s *chatServiceImpl) RegenerateReply(ctx context.Context, req *ReplyRequest) (*ReplyResponse, error) {
+	return s.replaceLastReply(ctx, req, model.ReplyReasonRegenerate)
+}
+
+func (s *chatServiceImpl) RetryTurn(ctx context.Context, req *ReplyRequest) (*ReplyResponse, error) {
+	return s.replaceLastReply(ctx, req, model.ReplyReasonRetry)
+}
+
+// failTurn 保存回复处理失败的一轮：孩子的语音与识别文本照常保存，AI 消息标记失败阶段，之后可通过重试接口继续
+// 请求可能已被取消（如客户端断开），persistTurn 脱离请求生命周期保存；保存失败时无法重试，返回原错误
+func (s *chatServiceImpl) failTurn(ctx context.Context, t *chatTurn, stage string, cause error) (*ChatMVPResponse, error) {
+	t.failedStage = stage
+	result := s.persistTurn(ctx, t)
+	if result.Turn == 0 {
+		return nil, cause
+	}
+	result.FailedStage = stage
+	logger.WarnContext(ctx, "chat turn saved as failed", "session_id", result.SessionID, "turn", result.Turn, "stage", stage)
+	return &ChatMVPResponse{ChatTurnResult: *result},
+		apperr.ErrChatTurnFailed.WithMessage(fmt.Sprintf("%s failed, retry turn %d of session %s", stage, result.Turn, result.SessionID)).WithError(cause)
+}
+
+// replaceLastReply 为会话最后一轮生成新的回复并替换原回复（原回复保留到 reply_versions）
+// regenerate：换一种说法重新生成成功的回复；retry：从失败阶段继续（llm 失败重新生成，tts 失败合成已有文本），均复用保存的识别文本
+func (s *chatServiceImpl) replaceLastReply(ctx context.Context, req *ReplyRequest, reason string) (*ReplyResponse, error) {
+	// 步骤 1：基础校验
+	if req == nil || req.UserID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+	if s.messageRepo == nil || s.llmProvider == nil || s.ttsProvider == nil || s.ossProvider == nil {
+		return nil, apperr.ErrInternalError.WithMessage("chat provider not initialized")
+	}
+
+	// 步骤 2：验证会话归属与状态（暂停与已结束的会话不能替换回复）
+	timer := newStageTimer()
+	conversation, err := s.getOwnedConversation(ctx, strings.TrimSpace(req.SessionID), req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.Status == model.ConversationStatusPaused {
+		return nil, apperr.ErrConversationPaused.WithMessage("resume the session before continuing")
+	}
+	if conversation.Status != model.ConversationStatusActive {
+		return nil, apperr.ErrConversationClosed.WithMessage("status " + conversation.Status)
+	}
+	if isQuestionSession(conversation) {
+		return nil, apperr.ErrInvalidParam.WithMessage("question_answer sessions do not support " + reason)
+	}
+
+	// 步骤 3：定位最后一轮（用户消息 + AI 回复）并检查能否替换
+	userMessage, reply, err := s.lastTurn(ctx, conversation.ID)
+	if err != nil {
+		return nil, err
+	}
+	if reason == model.ReplyReasonRetry && reply.FailedStage == nil {
+		return nil, apperr.ErrConflict.WithMessage("last turn did not fail, use regenerate instead")
+	}
+	if reason == model.ReplyReasonRegenerate {
+		switch {
+		case reply.FailedStage != nil:
+			return nil, apperr.ErrConflict.WithMessage("last turn failed, use retry instead")
+		case userMessage.MessageText == blockedInputPlaceholder:
+			return nil, apperr.ErrConflict.WithMessage("reply to blocked input cannot be regenerated")
+		case len(reply.ReplyVersions) >= maxReplyVersions:
+			return nil, apperr.ErrConflict.WithMessage(fmt.Sprintf("reply can be regenerated at most %d times", maxReplyVersions))
+		}
+	}
+
+	// 步骤 4：读取语言策略、人设与会话记忆，生成回复（tts 失败的轮次沿用已生成并审核过的文本）
+	lang := s.resolveReplyLanguage(ctx, req.UserID, conversation, conversation.DifficultyLevel, userMessage.MessageText, req.Translate)
+	persona := s.resolvePersona(ctx, req.UserID)
+	moderation := &turnModeration{sessionID: conversation.ID}
+	replyText := reply.MessageText
+	translation := ""
+	if reply.FailedStage != nil && *reply.FailedStage == model.TurnFailedStageTTS {
+		if reply.Translation != nil {
+			translation = *reply.Translation
+		}
+	} else {
+		memory := s.resolveLearnerMemory(ctx, req.UserID, conversation)
+		chatMessages := s.buildChatContext(ctx, conversation, userMessage.MessageText, lang, persona, memory, userMessage.SequenceNumber)
+		if reason == model.ReplyReasonRegenerate {
+			chatMessages[0].Content += regenerateReplyPrompt(reply.MessageText)
+		}
+		llmDone := timer.track(model.LatencyStageLLM)
+		replyText, err = s.llmProvider.ChatWithHistory(ctx, chatMessages)
+		llmDone()
+		if err != nil {
+			logger.ErrorContext(ctx, "chat "+reason+" llm failed", "session_id", conversation.ID, "error", err)
+			return nil, apperr.ErrChatTurnFailed.WithMessage(model.TurnFailedStageLLM + " failed, please retry").WithError(err)
+		}
+		replyText, _ = s.moderateReply(ctx, moderation, replyText)
+	}
+
+	// 步骤 5：按人设合成语音（需要时同时生成中文释义）
+	translationDone := make(chan string, 1)
+	go func() {
+		if translation != "" {
+			translationDone <- translation
+			return
+		}
+		translationDone <- s.translateReply(ctx, lang, moderation, replyText)
+	}()
+	ttsDone := timer.track(model.LatencyStageTTS)
+	ttsAudio, err := s.ttsProvider.Synthesize(ctx, replyText, persona.synthesizeOptions())
+	ttsDone()
+	translation = <-translationDone
+	if err != nil {
+		logger.ErrorContext(ctx, "chat "+reason+" tts failed", "session_id", conversation.ID, "error", err)
+		return nil, apperr.ErrChatTurnFailed.WithMessage(model.TurnFailedStageTTS + " failed, please retry").WithError(err)
+	}
+
+	// 步骤 6：上传新版本音频（每个版本单独保存，历史版本的音频仍可访问）
+	version := len(reply.ReplyVersions) + 2
+	audioKey := fmt.Sprintf("chat/%s/ai_%s_v%d.mp3", conversation.ID, reply.ID, version)
+	ossDone := timer.track(model.LatencyStageOSS)
+	audioURL, err := s.ossProvider.UploadAudio(ctx, audioKey, ttsAudio)
+	ossDone()
+	if err != nil {
+		return nil, fmt.Errorf("upload reply audio failed: %w", err)
+	}
+
+	// 步骤 7：替换回复，原回复追加到版本列表
+	promptVersion := chatPromptVersion
+	latency := timer.breakdown()
+	replacement := &model.ConversationMessage{
+		MessageText:    replyText,
+		AudioURL:       &audioURL,
+		PromptVersion:  &promptVersion,
+		LatencyMS:      &latency.TotalMS,
+		StageLatencies: latency.Stages,
+	}
+	if translation != "" {
+		replacement.Translation = &translation
+	}
+	updated, err := s.messageRepo.ReplaceReply(ctx, reply.ID, len(reply.ReplyVersions), replacement, reason)
+	if err != nil {
+		if errors.Is(err, db.ErrReplyStale) {
+			return nil, apperr.ErrConflict.WithMessage("reply changed, please reload the conversation")
+		}
+		return nil, fmt.Errorf("replace reply failed: %w", err)
+	}
+	s.flagConversation(ctx, conversation.ID, moderation)
+	logger.InfoContext(ctx, "chat reply replaced", "session_id", conversation.ID, "reason", reason, "version", len(updated.ReplyVersions)+1)
+
+	resp := &ReplyResponse{
+		ReplyText:    updated.MessageText,
+		AudioURL:     audioURL,
+		ReplyVersion: len(updated.ReplyVersions) + 1,
+		ChatTurnResult: ChatTurnResult{
+			SessionID:        conversation.ID,
+			Turn:             (updated.SequenceNumber + 1) / 2,
+			UserLanguage:     lang.userLanguage,
+			ReplyTranslation: translation,
+			MetCriteria:      conversation.MetCriteria,
+		},
+	}
+	if req.Debug {
+		resp.Latency = latency
+	}
+
+	// 步骤 8：重试成功后补做失败时跳过的场景判定与消息上限检查，会话结束时后台生成总结
+	maxMessages := s.maxConversationMessages(ctx)
+	resp.RemainingTurns = (maxMessages - conversation.MessageCount) / 2
+	if reason == model.ReplyReasonRetry {
+		if conversation.ScenarioID != nil {
+			resp.GoalAchieved = s.checkScenarioGoal(ctx, conversation)
+			resp.MetCriteria = conversation.MetCriteria
+			resp.SessionCompleted = resp.GoalAchieved
+		}
+		if !resp.SessionCompleted && conversation.MessageCount+2 > maxMessages {
+			if statusErr := s.conversationRepo.UpdateStatus(ctx, conversation.ID, model.ConversationStatusCompleted); statusErr != nil {
+				logger.ErrorContext(ctx, "chat complete session failed", "session_id", conversation.ID, "error", statusErr)
+			}
+			resp.SessionCompleted = true
+		}
+		if resp.SessionCompleted {
+			s.reviewSessionAsync(ctx, conversation.ID)
+		}
+	}
+	return resp, nil
+}
+
+// lastTurn 获取会话最后一轮的用户消息与 AI 回复（第 N 轮为序号 2N-1 与 2N）
+func (s *chatServiceImpl) lastTurn(ctx context.Context, conversationID string) (*model.ConversationMessage, *model.ConversationMessage, error) {
+	last, err := s.messageRepo.GetLastMessage(ctx, conversationID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil, apperr.ErrNotFound.WithMessage("conversation has no reply yet")
+		}
+		return nil, nil, fmt.Errorf("get last message failed: %w", err)
+	}
+	if last.SenderType != model.SenderTypeAI || last.SequenceNumber < 2 {
+		return nil, nil, apperr.ErrNotFound.WithMessage("conversation has no reply yet")
+	}
+	messages, err := s.messageRepo.GetBySequenceRange(ctx, conversationID, last.SequenceNumber-1, last.SequenceNumber-1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get conversation message failed: %w", err)
+	}
+	if len(messages) == 0 || messages[0].SenderType != model.SenderTypeUser {
+		return nil, nil, apperr.ErrNotFound.WithMessage("conversation turn not found")
+	}
+	return messages[0], last, nil
+}
+
+// regenerateReplyPrompt 重新生成时追加的提示词：保持原意，换一种更容易理解的说法
+func regenerateReplyPrompt(previous string) string {
+	return "\nThe child asked to hear your last reply in a different way. Your previous reply was:\n\"" + previous + "\"\n" +
+		"Reply to the child's last message again with the same meaning, but use different and simpler words.\n"
+}
@@ -0,0 +1,226 @@
+// Package service 提供会话结束后的总结、评分与学习统计
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	llmPrompts "pronunciation-correction-system/internal/infrastructure/llm"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// 会话总结规则
+const (
+	sessionReviewTimeout   = 60 * time.Second // 单个会话总结超时（与请求生命周期无关）
+	sessionReviewGoodWords = 8                // 孩子平均每次回答达到该词数时，回答长度得满分
+)
+
+// sessionReviewFallbackFeedback 反馈被内容安全审核拦截时保存的通用鼓励语
+const sessionReviewFallbackFeedback = "Great job talking in English today! Keep practicing and try to say a little more next time."
+
+// sessionReview LLM 会话总结结果
+type sessionReview struct {
+	Summary    string `json:"summary"`
+	Fluency    int    `json:"fluency"`
+	Relevance  int    `json:"relevance"`
+	Vocabulary int    `json:"vocabulary"`
+	Feedback   string `json:"feedback"`
+}
+
+func (s *chatServiceImpl) EndSession(ctx context.Context, sessionID, userID string) (*SessionSummary, error) {
+	// ─── 1. 验证会话归属 ───
+	conversation, err := s.getOwnedConversation(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ─── 2. 结束会话并累计最后一段活跃时间（已结束的会话直接返回，未生成总结时补触发） ───
+	if conversation.Status != model.ConversationStatusCompleted {
+		updated, err := s.conversationRepo.TransitionStatus(ctx, conversation.ID, conversation.Status, model.ConversationStatusCompleted, s.idleTimeout(ctx))
+		switch {
+		case errors.Is(err, db.ErrConversationStatusStale):
+			// 期间被暂停 / 恢复或已超时结束：重新读取，仍未结束时由用户重试
+			if conversation, err = s.getOwnedConversation(ctx, sessionID, userID); err != nil {
+				return nil, err
+			}
+			if conversation.Status != model.ConversationStatusCompleted {
+				return nil, apperr.ErrConversationClosed.WithMessage("status changed, please retry")
+			}
+		case err != nil:
+			return nil, fmt.Errorf("complete conversation failed: %w", err)
+		default:
+			conversation = updated
+			logger.InfoContext(ctx, "chat session ended by user", "session_id", conversation.ID)
+		}
+	}
+	if conversation.Summary == nil || conversation.StatsRecordedAt == nil {
+		s.reviewSessionAsync(ctx, conversation.ID)
+	}
+	return toSessionSummary(conversation, nil), nil
+}
+
+// reviewSessionAsync 会话结束后在后台生成总结、评分与反馈，并累计用户学习统计
+func (s *chatServiceImpl) reviewSessionAsync(ctx context.Context, conversationID string) {
+	if s.conversationRepo == nil || s.messageRepo == nil || s.llmProvider == nil {
+		return
+	}
+
+	// 脱离请求生命周期在后台执行（HTTP 响应返回或 WebSocket 轮次结束后继续）
+	runBackground(ctx, "session review", sessionReviewTimeout, func(ctx context.Context) {
+		if err := s.reviewSession(ctx, conversationID); err != nil {
+			logger.ErrorContext(ctx, "chat session review failed", "session_id", conversationID, "error", err)
+		}
+	})
+}
+
+// reviewSession 先累计用户的对话数、学习时长与最后对话时间（独立标记，仅累计一次，不依赖 LLM 总结），
+// 再生成会话总结、评分、反馈与问题单词；总结首次写入成功时将问题单词写入单词复习队列、在后台提取长期记忆
+// 没有任何消息的会话不总结、不计入统计
+func (s *chatServiceImpl) reviewSession(ctx context.Context, conversationID string) error {
+	// 步骤 1：加载会话，累计用户学习统计（LLM 调用失败或回复解析失败时同样计入）
+	conversation, err := s.conversationRepo.GetByID(ctx, conversationID)
+	if err != nil {
+		return fmt.Errorf("get conversation failed: %w", err)
+	}
+	if conversation.MessageCount == 0 {
+		return nil
+	}
+	s.recordSessionStats(ctx, conversation)
+	if conversation.Summary != nil {
+		return nil
+	}
+	messages, err := s.messageRepo.GetAfterSequence(ctx, conversation.ID, 0)
+	if err != nil {
+		return fmt.Errorf("get conversation messages failed: %w", err)
+	}
+
+	// 步骤 2：整理对话原文，统计孩子平均每次回答的词数
+	var transcript strings.Builder
+	userTurns, userWords := 0, 0
+	for _, m := range messages {
+		speaker := "Teacher"
+		if m.SenderType == model.SenderTypeUser {
+			speaker = "Child"
+			userTurns++
+			userWords += len(strings.Fields(m.MessageText))
+		}
+		transcript.WriteString(speaker + ": " + m.MessageText + "\n")
+	}
+	if userTurns == 0 {
+		return nil
+	}
+
+	// 步骤 3：LLM 生成摘要、分项评分与反馈
+	systemPrompt, userMessage := llmPrompts.BuildSessionReviewPrompt(conversation.Topic, conversation.DifficultyLevel, transcript.String())
+	reply, err := s.llmProvider.Chat(ctx, systemPrompt, userMessage)
+	if err != nil {
+		return fmt.Errorf("llm review failed: %w", err)
+	}
+	review, err := parseSessionReview(reply)
+	if err != nil {
+		logger.DebugContext(ctx, "chat session review raw reply", "session_id", conversation.ID, "reply", reply)
+		return fmt.Errorf("parse review failed (reply_length %d): %w", len(reply), err)
+	}
+
+	// 步骤 4：综合评分 = 流利度、相关性、词汇量、回答长度四项平均
+	turnLength := turnLengthScore(float64(userWords) / float64(userTurns))
+	score := clampScore(float64(review.Fluency+review.Relevance+review.Vocabulary+turnLength) / 4)
+
+	// 步骤 5：汇总多轮发言中都发音不准的单词（未开启发音评测时为 nil）
+	var problemWords model.StringArray
+	problems := sessionProblemWords(messages)
+	if problems != nil {
+		problemWords = make(model.StringArray, 0, len(problems))
+		for _, p := range problems {
+			problemWords = append(problemWords, p.word)
+		}
+	}
+
+	// 步骤 6：给孩子的反馈与对话回复同样审核（拦截时使用通用鼓励语），命中时标记会话待复核
+	moderation := &turnModeration{sessionID: conversation.ID}
+	feedback, blocked := s.moderateReply(ctx, moderation, review.Feedback)
+	if blocked {
+		feedback = sessionReviewFallbackFeedback
+	}
+	review.Feedback = feedback
+	s.flagConversation(ctx, conversation.ID, moderation)
+
+	// 步骤 7：保存（重复触发时不再写入问题单词与长期记忆）
+	saved, err := s.conversationRepo.SaveSessionReview(ctx, conversation.ID, review.Summary, score, review.Feedback, problemWords)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return nil
+	}
+	logger.InfoContext(ctx, "chat session reviewed", "session_id", conversation.ID, "score", score,
+		"fluency", review.Fluency, "relevance", review.Relevance, "vocabulary", review.Vocabulary, "turn_length", turnLength,
+		"problem_words", len(problemWords))
+
+	// 步骤 8：问题单词写入单词复习队列（失败仅记录日志）
+	s.recordSessionProblemWords(ctx, conversation, problems)
+
+	// 步骤 9：后台提取孩子的个人信息到长期记忆
+	s.extractLearnerMemoryAsync(ctx, conversation, messages)
+	return nil
+}
+
+// recordSessionStats 累计用户对话数、学习时长（按会话时长向上取整到分钟）与最后对话时间
+// 先条件写入会话的统计标记，仅标记成功的一次累计，重复触发（空闲清理、消息上限、用户结束）不重复计数
+func (s *chatServiceImpl) recordSessionStats(ctx context.Context, conversation *model.VoiceConversation) {
+	if s.profileRepo == nil || conversation.StatsRecordedAt != nil {
+		return
+	}
+	marked, err := s.conversationRepo.MarkStatsRecorded(ctx, conversation.ID)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat mark session stats recorded failed", "session_id", conversation.ID, "error", err)
+		return
+	}
+	if !marked {
+		return
+	}
+	if err := s.profileRepo.IncrementConversations(ctx, conversation.UserID); err != nil {
+		logger.ErrorContext(ctx, "chat increment user conversations failed", "user_id", conversation.UserID, "error", err)
+	}
+	if err := s.profileRepo.UpdateLastConversationAt(ctx, conversation.UserID); err != nil {
+		logger.ErrorContext(ctx, "chat update last conversation at failed", "user_id", conversation.UserID, "error", err)
+	}
+	if minutes := (conversation.DurationSeconds + 59) / 60; minutes > 0 {
+		if err := s.profileRepo.AddStudyMinutes(ctx, conversation.UserID, minutes); err != nil {
+			logger.ErrorContext(ctx, "chat add study minutes failed", "user_id", conversation.UserID, "error", err)
+		}
+	}
+}
+
+// parseSessionReview 解析 LLM 返回的总结 JSON（截取 JSON 对象部分，容忍多余文字），分项评分限制在 0-100
+func parseSessionReview(reply string) (*sessionReview, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("no json object in reply")
+	}
+	var review sessionReview
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &review); err != nil {
+		return nil, err
+	}
+	review.Summary = strings.TrimSpace(review.Summary)
+	review.Feedback = strings.TrimSpace(review.Feedback)
+	if review.Summary == "" || review.Feedback == "" {
+		return nil, apperr.ErrInternalError.WithMessage("review summary or feedback is empty")
+	}
+	review.Fluency = clampScore(float64(review.Fluency))
+	review.Relevance = clampScore(float64(review.Relevance))
+	review.Vocabulary = clampScore(float64(review.Vocabulary))
+	return &review, nil
+}
+
+// turnLengthScore 按孩子平均每次回答的词数计算回答长度得分（达到 sessionReviewGoodWords 词为满分）
+func turnLengthScore(avgWords float64) int {
+	return clampScore(avgWords / sessionReviewGoodWords * 100)
+}
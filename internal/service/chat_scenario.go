@@ -0,0 +1,282 @@
+// Package service 提供角色扮演场景对话业务逻辑
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	llmPrompts "pronunciation-correction-system/internal/infrastructure/llm"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/uuid"
+)
+
+// ===== 请求结构 =====
+
+// ScenarioListRequest 场景列表查询请求
+type ScenarioListRequest struct {
+	Category        string
+	DifficultyLevel string
+	Page            int
+	PageSize        int
+}
+
+// ===== 响应结构 =====
+
+// StartScenarioResponse 开始角色扮演会话结果
+type StartScenarioResponse struct {
+	SessionID       string                      `json:"session_id"`
+	Scenario        *model.ConversationScenario `json:"scenario"`
+	OpeningLine     string                      `json:"opening_line"`
+	OpeningAudioURL string                      `json:"opening_audio_url,omitempty"` // 开场白语音（合成失败时为空）
+	RemainingTurns  int                         `json:"remaining_turns"`
+}
+
+func (s *chatServiceImpl) ListScenarios(ctx context.Context, req *ScenarioListRequest) ([]*model.ConversationScenario, int64, error) {
+	if s.scenarioRepo == nil {
+		return nil, 0, apperr.ErrInternalError.WithMessage("scenario repository not initialized")
+	}
+	return s.scenarioRepo.ListActive(ctx, &db.ScenarioQuery{
+		Category:        strings.TrimSpace(req.Category),
+		DifficultyLevel: strings.TrimSpace(req.DifficultyLevel),
+	}, req.Page, req.PageSize)
+}
+
+func (s *chatServiceImpl) GetScenario(ctx context.Context, scenarioID string) (*model.ConversationScenario, error) {
+	return s.getActiveScenario(ctx, scenarioID)
+}
+
+func (s *chatServiceImpl) StartScenario(ctx context.Context, scenarioID, userID string) (*StartScenarioResponse, error) {
+	// 步骤 1：查询场景
+	scenario, err := s.getActiveScenario(ctx, scenarioID)
+	if err != nil {
+		return nil, err
+	}
+	if s.conversationRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("conversation repository not initialized")
+	}
+
+	// 步骤 2：创建 role_play 会话（开场白不计入消息，由构建上下文时补上）
+	now := time.Now()
+	conversation := &model.VoiceConversation{
+		ID:               uuid.New(),
+		UserID:           userID,
+		Topic:            scenario.Title,
+		DifficultyLevel:  scenario.DifficultyLevel,
+		ConversationType: model.ConversationTypeRolePlay,
+		ScenarioID:       &scenario.ID,
+		Status:           model.ConversationStatusActive,
+		LastActiveAt:     &now,
+	}
+	if err := s.conversationRepo.Create(ctx, conversation); err != nil {
+		return nil, fmt.Errorf("create scenario conversation failed: %w", err)
+	}
+
+	// 步骤 3：开场白语音（按场景缓存，失败不影响会话创建）
+	openingAudioURL, err := ensureScenarioOpeningAudio(ctx, s.ttsProvider, s.ossProvider, scenario)
+	if err != nil {
+		logger.WarnContext(ctx, "scenario opening audio failed", "scenario_id", scenario.ID, "error", err)
+	}
+
+	logger.InfoContext(ctx, "scenario conversation started", "session_id", conversation.ID, "scenario", scenario.Code)
+	return &StartScenarioResponse{
+		SessionID:       conversation.ID,
+		Scenario:        scenario,
+		OpeningLine:     scenario.OpeningLine,
+		OpeningAudioURL: openingAudioURL,
+		RemainingTurns:  s.maxConversationMessages(ctx) / 2,
+	}, nil
+}
+
+// getActiveScenario 按 ID 或场景编码获取已上架的场景
+func (s *chatServiceImpl) getActiveScenario(ctx context.Context, idOrCode string) (*model.ConversationScenario, error) {
+	idOrCode = strings.TrimSpace(idOrCode)
+	if idOrCode == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("scenario_id is required")
+	}
+	if s.scenarioRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("scenario repository not initialized")
+	}
+
+	var scenario *model.ConversationScenario
+	var err error
+	if uuid.IsValid(idOrCode) {
+		scenario, err = s.scenarioRepo.GetByID(ctx, idOrCode)
+	} else {
+		scenario, err = s.scenarioRepo.GetByCode(ctx, idOrCode)
+	}
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, apperr.ErrScenarioNotFound
+		}
+		return nil, fmt.Errorf("get scenario failed: %w", err)
+	}
+	if !scenario.IsActive {
+		return nil, apperr.ErrScenarioNotFound
+	}
+	return scenario, nil
+}
+
+// loadScenario 加载 role_play 会话关联的场景（缓存在 conversation.Scenario 上），非场景会话或加载失败时返回 nil
+func (s *chatServiceImpl) loadScenario(ctx context.Context, conversation *model.VoiceConversation) *model.ConversationScenario {
+	if conversation == nil || conversation.ScenarioID == nil || s.scenarioRepo == nil {
+		return nil
+	}
+	if conversation.Scenario != nil {
+		return conversation.Scenario
+	}
+	scenario, err := s.scenarioRepo.GetByID(ctx, *conversation.ScenarioID)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat load scenario failed", "session_id", conversation.ID, "error", err)
+		return nil
+	}
+	conversation.Scenario = scenario
+	return scenario
+}
+
+// scenarioGoalCheck 一次场景成功标准判定的结果
+type scenarioGoalCheck struct {
+	scenario *model.ConversationScenario
+	met      []string // LLM 判定本次已达成的成功标准
+}
+
+// checkScenarioGoal 按会话已保存的对话判定场景成功标准，全部达成时记录完成并结束会话（重试回复时使用）
+// 判定失败仅记录日志，保留已有进度；返回本轮是否完成目标
+func (s *chatServiceImpl) checkScenarioGoal(ctx context.Context, conversation *model.VoiceConversation) bool {
+	return s.applyScenarioGoal(ctx, conversation, s.judgeScenarioGoal(ctx, conversation, "", ""))
+}
+
+// startScenarioGoalCheck 回复确定后即在后台判定场景成功标准（与 TTS、释义、上传并行），persistTurn 保存本轮后读取结果
+// 非场景会话或已完成目标时返回 nil
+func (s *chatServiceImpl) startScenarioGoalCheck(ctx context.Context, conversation *model.VoiceConversation, userText, replyText string) <-chan *scenarioGoalCheck {
+	if conversation == nil || conversation.ScenarioID == nil || conversation.GoalAchievedAt != nil {
+		return nil
+	}
+	done := make(chan *scenarioGoalCheck, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.ErrorContext(ctx, "scenario goal check panic", "session_id", conversation.ID, "panic", r)
+				done <- nil
+			}
+		}()
+		done <- s.judgeScenarioGoal(ctx, conversation, userText, replyText)
+	}()
+	return done
+}
+
+// judgeScenarioGoal 由 LLM 判定孩子已达成的场景成功标准
+// userText / replyText 为尚未保存的本轮对话（为空时仅使用已保存的消息）；无需判定或判定失败时返回 nil
+func (s *chatServiceImpl) judgeScenarioGoal(ctx context.Context, conversation *model.VoiceConversation, userText, replyText string) *scenarioGoalCheck {
+	scenario := s.loadScenario(ctx, conversation)
+	if scenario == nil || len(scenario.SuccessCriteria) == 0 || conversation.GoalAchievedAt != nil {
+		return nil
+	}
+	if s.llmProvider == nil || s.messageRepo == nil {
+		return nil
+	}
+
+	// 步骤 1：整理对话原文（含开场白与本轮）
+	messages, err := s.messageRepo.GetAfterSequence(ctx, conversation.ID, 0)
+	if err != nil {
+		logger.ErrorContext(ctx, "scenario goal check load messages failed", "session_id", conversation.ID, "error", err)
+		return nil
+	}
+	var transcript strings.Builder
+	transcript.WriteString("AI: " + scenario.OpeningLine + "\n")
+	for _, m := range messages {
+		if m.FailedStage != nil {
+			continue // 处理失败、孩子没有听到的回复
+		}
+		speaker := "Child"
+		if m.SenderType == model.SenderTypeAI {
+			speaker = "AI"
+		}
+		transcript.WriteString(speaker + ": " + m.MessageText + "\n")
+	}
+	if userText != "" {
+		transcript.WriteString("Child: " + userText + "\n")
+		transcript.WriteString("AI: " + replyText + "\n")
+	}
+
+	// 步骤 2：LLM 判定已达成的成功标准
+	systemPrompt, userMessage := llmPrompts.BuildScenarioGoalCheckPrompt(scenario.Goal, scenario.SuccessCriteria, transcript.String())
+	reply, err := s.llmProvider.Chat(ctx, systemPrompt, userMessage)
+	if err != nil {
+		logger.ErrorContext(ctx, "scenario goal check llm failed", "session_id", conversation.ID, "error", err)
+		return nil
+	}
+	met, err := parseMetCriteria(reply, scenario.SuccessCriteria)
+	if err != nil {
+		logger.ErrorContext(ctx, "scenario goal check parse failed", "session_id", conversation.ID, "reply_length", len(reply), "error", err)
+		logger.DebugContext(ctx, "scenario goal check raw reply", "session_id", conversation.ID, "reply", reply)
+		return nil
+	}
+	return &scenarioGoalCheck{scenario: scenario, met: met}
+}
+
+// applyScenarioGoal 将判定结果与已有进度合并（已达成的标准不会回退），全部达成时记录完成并结束会话
+// check 为 nil 时保留已有进度；返回本轮是否完成目标
+func (s *chatServiceImpl) applyScenarioGoal(ctx context.Context, conversation *model.VoiceConversation, check *scenarioGoalCheck) bool {
+	if check == nil || conversation.GoalAchievedAt != nil {
+		return false
+	}
+	scenario := check.scenario
+
+	// 按场景标准顺序合并保存
+	metSet := make(map[string]bool, len(scenario.SuccessCriteria))
+	for _, c := range conversation.MetCriteria {
+		metSet[c] = true
+	}
+	for _, c := range check.met {
+		metSet[c] = true
+	}
+	merged := make(model.StringArray, 0, len(metSet))
+	for _, c := range scenario.SuccessCriteria {
+		if metSet[c] {
+			merged = append(merged, c)
+		}
+	}
+	achieved := len(merged) == len(scenario.SuccessCriteria)
+	if len(merged) == len(conversation.MetCriteria) && !achieved {
+		return false
+	}
+
+	if err := s.conversationRepo.UpdateScenarioProgress(ctx, conversation.ID, merged, achieved); err != nil {
+		logger.ErrorContext(ctx, "scenario goal progress save failed", "session_id", conversation.ID, "error", err)
+		return false
+	}
+	conversation.MetCriteria = merged
+	if achieved {
+		conversation.Status = model.ConversationStatusCompleted
+		logger.InfoContext(ctx, "scenario goal achieved", "session_id", conversation.ID, "scenario", scenario.Code)
+	}
+	return achieved
+}
+
+// parseMetCriteria 解析 LLM 返回的 {"met": [1, 3]}，转换为对应的成功标准原文
+// 截取 JSON 对象部分，容忍 LLM 输出多余文字；越界编号忽略
+func parseMetCriteria(reply string, criteria []string) ([]string, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("no json object in reply")
+	}
+	var raw struct {
+		Met []int `json:"met"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return nil, err
+	}
+	met := make([]string, 0, len(raw.Met))
+	for _, n := range raw.Met {
+		if n >= 1 && n <= len(criteria) {
+			met = append(met, criteria[n-1])
+		}
+	}
+	return met, nil
+}
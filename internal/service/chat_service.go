@@ -7,33 +7,33 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
+	"pronunciation-correction-system/internal/cache"
+	"pronunciation-correction-system/internal/config"
 	"pronunciation-correction-system/internal/db"
 	"pronunciation-correction-system/internal/domain"
 	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
 	"pronunciation-correction-system/internal/pkg/logger"
 	"pronunciation-correction-system/internal/pkg/uuid"
 )
 
+// turnPersistTimeout 保存一轮对话（上传音频、写入会话）的超时（与请求生命周期无关）
+const turnPersistTimeout = 30 * time.Second
+
 // ===== 请求结构 =====
 
 // ChatMVPRequest MVP 同步语音对话请求
 type ChatMVPRequest struct {
 	AudioData        []byte
+	SessionID        string // 为空时新建会话
 	AudioType        string // wav / mp3
-	ConversationType string // free_talk / question_answer
+	ConversationType string // free_talk（question_answer / role_play 需先通过题集 / 场景创建会话）
 	DifficultyLevel  string // beginner / intermediate / advanced
+	Translate        bool   // 本轮是否要求中文释义（translate_on_demand 策略使用）
 	UserID           string
-}
-
-// SubmitChatRequest 异步语音对话提交请求
-type SubmitChatRequest struct {
-	AudioData    []byte
-	AudioType    string
-	SessionID    string
-	UserLanguage string
-	TopicID      string
-	UserID       string
+	Debug            bool // 结果中附带各阶段耗时
 }
 
 // ChatHistoryRequest 对话历史查询请求
@@ -47,121 +47,265 @@ type ChatHistoryRequest struct {
 
 // SubmitFeedbackRequest 对话反馈提交请求
 type SubmitFeedbackRequest struct {
-	TaskID    string
+	TaskID    string // 可选，异步提交的任务 ID（须属于当前用户与会话）
 	SessionID string
 	Turn      int
-	Rating    int
-	Comment   string
-	Helpful   bool
+	Rating    int    // 1-5
+	Comment   string // 可选，最多 500 字
+	Helpful   *bool  // nil 表示未选择
 	UserID    string
 }
 
 // ===== 响应结构 =====
 
-// ChatResultResponse 语音对话处理结果
+// ChatTurnResult 单轮对话的会话信息
+type ChatTurnResult struct {
+	SessionID        string `json:"session_id"`        // 本轮所属会话 ID（首轮为新建会话）
+	Turn             int    `json:"turn"`              // 本轮轮次（会话保存失败时为 0）
+	RemainingTurns   int    `json:"remaining_turns"`   // 会话剩余可对话轮数
+	SessionCompleted bool   `json:"session_completed"` // 已达到消息上限、完成场景目标或答完全部题目，会话已结束
+
+	// 中英双语
+	UserLanguage     string `json:"user_language,omitempty"`     // 本轮孩子说话的语言：zh / en / mixed
+	ReplyTranslation string `json:"reply_translation,omitempty"` // AI 回复的中文释义（按语言策略生成）
+
+	// 角色扮演会话
+	GoalAchieved bool     `json:"goal_achieved,omitempty"` // 本轮完成了场景目标
+	MetCriteria  []string `json:"met_criteria,omitempty"`  // 已达成的场景成功标准
+
+	// 问答会话
+	AnswerCheck       *model.QuestionResult `json:"answer_check,omitempty"`       // 本轮回答的判定结果（需重答时 passed 为 false）
+	QuestionsAnswered int                   `json:"questions_answered,omitempty"` // 已完成的题目数
+	QuestionTotal     int                   `json:"question_total,omitempty"`     // 题目总数
+
+	// 失败轮次
+	FailedStage string `json:"failed_stage,omitempty"` // 本轮处理失败的阶段：llm / tts（已保存，可通过重试接口继续）
+
+	// 调试
+	Latency *LatencyBreakdown `json:"latency,omitempty"` // 各阶段耗时（仅 debug 请求返回）
+}
+
+// ChatMVPResponse MVP 同步语音对话结果
+type ChatMVPResponse struct {
+	Audio []byte // AI 回复音频（mp3）
+	ChatTurnResult
+}
+
+// ChatResultResponse 异步语音对话处理结果
 type ChatResultResponse struct {
-	TaskID       string      `json:"task_id"`
-	Status       string      `json:"status"`
-	Progress     int         `json:"progress,omitempty"`
-	CurrentStage string      `json:"current_stage,omitempty"`
-	UserInput    *AudioInput `json:"user_input,omitempty"`
-	AIResponse   *AudioReply `json:"ai_response,omitempty"`
-	ErrorStage   string      `json:"error_stage,omitempty"`
-	ErrorMessage string      `json:"error_message,omitempty"`
+	TaskID          string      `json:"task_id"`
+	Status          string      `json:"status"` // pending / processing / success / failed
+	UserInput       *AudioInput `json:"user_input,omitempty"`
+	AIResponse      *AudioReply `json:"ai_response,omitempty"`
+	ErrorStage      string      `json:"error_stage,omitempty"` // 失败阶段：llm / tts（本轮已保存，可调用重试接口），其他原因失败时为空
+	ErrorMessage    string      `json:"error_message,omitempty"`
+	CreatedAt       string      `json:"created_at"`
+	*ChatTurnResult             // 本轮会话信息（处理完成，或失败但本轮已保存时）
 }
 
 // AudioInput 用户语音识别结果
 type AudioInput struct {
-	Text       string `json:"text"`
-	DurationMs int    `json:"duration_ms"`
+	Text     string `json:"text"`
+	AudioURL string `json:"audio_url"`
 }
 
 // AudioReply AI 回复（文本 + 音频）
 type AudioReply struct {
-	Text       string `json:"text"`
-	AudioURL   string `json:"audio_url"`
-	DurationMs int    `json:"duration_ms"`
+	Text     string `json:"text"`
+	AudioURL string `json:"audio_url"`
 }
 
 // ConversationTurn 单轮对话记录
 type ConversationTurn struct {
-	Turn         int    `json:"turn"`
-	UserText     string `json:"user_text"`
-	UserAudioURL string `json:"user_audio_url"`
-	AIText       string `json:"ai_text"`
-	AIAudioURL   string `json:"ai_audio_url"`
-	CreatedAt    string `json:"created_at"`
+	Turn               int                         `json:"turn"`
+	UserText           string                      `json:"user_text"`
+	UserAudioURL       string                      `json:"user_audio_url"`
+	UserLanguage       string                      `json:"user_language,omitempty"`       // 用户消息的语言：zh / en / mixed
+	Annotations        model.GrammarAnnotationList `json:"annotations,omitempty"`         // 用户消息的语法 / 用词批注（后台分析，刚保存的轮次可能暂无）
+	PronunciationScore *int                        `json:"pronunciation_score,omitempty"` // 用户语音的发音得分（后台评测，未开启或刚保存的轮次可能暂无）
+	ProblemWords       []string                    `json:"problem_words,omitempty"`       // 用户语音中发音不准的单词
+	AIText             string                      `json:"ai_text"`
+	AITranslation      string                      `json:"ai_translation,omitempty"` // AI 回复的中文释义（按语言策略生成）
+	AIAudioURL         string                      `json:"ai_audio_url"`
+	FailedStage        string                      `json:"failed_stage,omitempty"`  // AI 回复处理失败的阶段：llm / tts（可重试）
+	ReplyVersion       int                         `json:"reply_version,omitempty"` // AI 回复版本号（1 为首次生成，重新生成或重试后递增）
+	CreatedAt          string                      `json:"created_at"`
 }
 
 // SessionSummary 会话摘要
 type SessionSummary struct {
 	SessionID         string `json:"session_id"`
+	Topic             string `json:"topic"`
+	Status            string `json:"status"`
 	CreatedAt         string `json:"created_at"`
 	LastMessage       string `json:"last_message"`
 	MessageCount      int    `json:"message_count"`
+	DurationSeconds   int    `json:"duration_seconds"` // 活跃时长（秒，暂停与空闲不计入）
 	LastInteractionAt string `json:"last_interaction_at"`
+	PausedAt          string `json:"paused_at,omitempty"` // 暂停时间（仅 paused 会话）
+
+	// 会话结束后后台生成，生成前不返回
+	Summary      string   `json:"summary,omitempty"`       // 会话摘要（中文，面向家长 / 老师）
+	Score        *int     `json:"score,omitempty"`         // 口语综合评分（0-100）
+	Feedback     string   `json:"feedback,omitempty"`      // 给孩子的英文反馈
+	ProblemWords []string `json:"problem_words,omitempty"` // 在多轮发言中都发音不准的单词（开启发音评测时）
 }
 
 // ===== Service 接口 =====
 
 // ChatService AI 语音对话业务接口
 type ChatService interface {
-	// ChatMVP 同步语音对话 MVP（ASR → LLM → TTS）
-	ChatMVP(ctx context.Context, req *ChatMVPRequest) ([]byte, error)
+	// ChatMVP 同步语音对话 MVP（ASR → LLM(会话历史) → TTS），本轮消息追加到会话
+	ChatMVP(ctx context.Context, req *ChatMVPRequest) (*ChatMVPResponse, error)
+
+	// ChatStream 实时语音对话单轮处理（流式 ASR → LLM(会话历史) → 逐句 TTS）
+	// 中间结果与音频块通过 emit 推送；ctx 取消（如用户打断）时立即停止，本轮不保存
+	ChatStream(ctx context.Context, req *ChatStreamRequest, emit ChatStreamEmitter) (*ChatTurnResult, error)
+
+	// ChatTextStream 语音对话的流式文本变体（ASR → LLM 流式回复），推送文本增量与整句，不合成语音
+	ChatTextStream(ctx context.Context, req *ChatMVPRequest, emit ChatStreamEmitter) (*ChatTurnResult, error)
 
-	// SubmitChat 提交异步语音对话任务
-	SubmitChat(ctx context.Context, req *SubmitChatRequest) (taskID string, err error)
+	// SubmitChat 提交异步语音对话任务（后台执行 ChatMVP 流程，本轮保存到会话）
+	SubmitChat(ctx context.Context, req *SubmitChatRequest) (*ChatSubmitResponse, error)
 
 	// GetChatResult 查询异步语音对话处理结果
-	GetChatResult(ctx context.Context, taskID string) (*ChatResultResponse, error)
+	GetChatResult(ctx context.Context, taskID, userID string) (*ChatResultResponse, error)
 
-	// GetChatHistory 获取指定会话的对话历史
+	// RegenerateReply 换一种说法重新生成会话最后一轮的 AI 回复（新的文本与音频），原回复保留为历史版本
+	RegenerateReply(ctx context.Context, req *ReplyRequest) (*ReplyResponse, error)
+
+	// RetryTurn 从失败阶段重试会话最后一轮（复用保存的识别文本，无需重新上传音频），失败的回复保留为历史版本
+	RetryTurn(ctx context.Context, req *ReplyRequest) (*ReplyResponse, error)
+
+	// GetChatHistory 获取指定会话的对话历史（按轮次分页）
 	GetChatHistory(ctx context.Context, req *ChatHistoryRequest) ([]*ConversationTurn, int64, error)
 
 	// DeleteSession 删除对话会话及其所有消息
 	DeleteSession(ctx context.Context, sessionID, userID string) (int64, error)
 
-	// GetSessions 获取用户的会话列表
+	// GetSessions 获取用户的会话列表（按最后交互时间降序）
 	GetSessions(ctx context.Context, userID string, page, pageSize int) ([]*SessionSummary, int64, error)
 
-	// SubmitChatFeedback 提交对话反馈
+	// SubmitChatFeedback 提交对 AI 回复的反馈（按会话 + 轮次定位回复，重复提交时覆盖）
 	SubmitChatFeedback(ctx context.Context, req *SubmitFeedbackRequest) error
+
+	// ListScenarios 分页获取已上架的角色扮演场景
+	ListScenarios(ctx context.Context, req *ScenarioListRequest) ([]*model.ConversationScenario, int64, error)
+
+	// GetScenario 获取场景详情（支持场景 ID 或场景编码）
+	GetScenario(ctx context.Context, scenarioID string) (*model.ConversationScenario, error)
+
+	// StartScenario 基于场景创建 role_play 会话，返回会话 ID 与 AI 开场白
+	StartScenario(ctx context.Context, scenarioID, userID string) (*StartScenarioResponse, error)
+
+	// ListQuestionSets 分页获取已上架的问答题集
+	ListQuestionSets(ctx context.Context, req *QuestionSetListRequest) ([]*model.QuestionSet, int64, error)
+
+	// StartQuestionSet 基于题集创建 question_answer 会话，返回会话 ID 与第一个问题
+	StartQuestionSet(ctx context.Context, questionSetID, userID string) (*StartQuestionSetResponse, error)
+
+	// GetQuestionProgress 获取问答会话的当前进度与每题作答结果
+	GetQuestionProgress(ctx context.Context, sessionID, userID string) (*QuestionProgressResponse, error)
+
+	// EndSession 结束会话，后台生成会话总结、评分与反馈（重复调用不会重复生成）
+	EndSession(ctx context.Context, sessionID, userID string) (*SessionSummary, error)
+
+	// PauseSession 暂停进行中的会话，暂停期间不能对话、不计入时长（重复调用幂等）
+	PauseSession(ctx context.Context, sessionID, userID string) (*SessionSummary, error)
+
+	// ResumeSession 恢复暂停的会话（重复调用幂等）
+	ResumeSession(ctx context.Context, sessionID, userID string) (*SessionSummary, error)
+
+	// CompleteIdleSessions 结束空闲超时的进行中会话与暂停超时的会话，后台生成总结，返回结束的会话数（由定时任务调用）
+	CompleteIdleSessions(ctx context.Context) (int, error)
+
+	// GetLanguagePolicy 获取学习者的回复语言策略（未设置时返回默认策略）
+	GetLanguagePolicy(ctx context.Context, userID string) (*LanguagePolicyResponse, error)
+
+	// UpdateLanguagePolicy 设置学习者的回复语言策略
+	UpdateLanguagePolicy(ctx context.Context, req *UpdateLanguagePolicyRequest) (*LanguagePolicyResponse, error)
+
+	// ListPersonas 获取已上架的 AI 老师人设
+	ListPersonas(ctx context.Context) ([]*model.TutorPersona, error)
+
+	// GetPersona 获取学习者选择的 AI 老师人设（未选择时为 null，使用默认老师）
+	GetPersona(ctx context.Context, userID string) (*PersonaResponse, error)
+
+	// UpdatePersona 选择 AI 老师人设（支持人设 ID 或人设编码，为空时恢复默认老师）
+	UpdatePersona(ctx context.Context, req *UpdatePersonaRequest) (*PersonaResponse, error)
 }
 
-// ===== 空实现 =====
+// ===== 实现 =====
 
-// chatServiceImpl Chat Service 实现（暂为空实现）
+// chatServiceImpl Chat Service 实现
 type chatServiceImpl struct {
-	// Step2 注入依赖
-	conversationRepo db.VoiceConversationRepository
-	messageRepo      db.ConversationMessageRepository
-	asrProvider      domain.ASRProvider
-	llmProvider      domain.LLMProvider
-	ttsProvider      domain.TTSProvider
-	ossProvider      domain.OSSProvider
-	logger           *slog.Logger
+	conversationRepo   db.VoiceConversationRepository
+	messageRepo        db.ConversationMessageRepository
+	settingRepo        db.SystemSettingRepository
+	scenarioRepo       db.ConversationScenarioRepository
+	questionSetRepo    db.QuestionSetRepository
+	personaRepo        db.TutorPersonaRepository
+	profileRepo        db.UserProfileRepository
+	feedbackRepo       db.ChatFeedbackRepository
+	asrProvider        domain.ASRProvider
+	llmProvider        domain.LLMProvider
+	ttsProvider        domain.TTSProvider
+	ossProvider        domain.OSSProvider
+	moderationProvider domain.ModerationProvider // 为 nil 时不审核
+	evaluationProvider domain.EvaluationProvider // 为 nil 时不评测发音
+	reviewService      ReviewService             // 会话问题单词写入单词复习队列
+	vocabularyService  VocabularyService         // 孩子说出的单词累计到个人词汇表
+	memoryService      LearnerMemoryService      // 新会话注入长期记忆，会话结束后提取
+	taskCache          *cache.ChatTaskCache      // 异步语音对话任务状态（为 nil 时不支持异步提交）
+	cfg                config.ChatConfig
+	logger             *slog.Logger
 }
 
 // NewChatService 创建 ChatService
-func NewChatService(repos *db.Repositories, asr domain.ASRProvider, llm domain.LLMProvider, tts domain.TTSProvider, oss domain.OSSProvider, logger *slog.Logger) ChatService {
+func NewChatService(repos *db.Repositories, asr domain.ASRProvider, llm domain.LLMProvider, tts domain.TTSProvider, oss domain.OSSProvider, moderation domain.ModerationProvider, evaluation domain.EvaluationProvider, reviewService ReviewService, vocabularyService VocabularyService, memoryService LearnerMemoryService, taskCache *cache.ChatTaskCache, cfg config.ChatConfig, logger *slog.Logger) ChatService {
 	var conversationRepo db.VoiceConversationRepository
 	var messageRepo db.ConversationMessageRepository
+	var settingRepo db.SystemSettingRepository
+	var scenarioRepo db.ConversationScenarioRepository
+	var questionSetRepo db.QuestionSetRepository
+	var personaRepo db.TutorPersonaRepository
+	var profileRepo db.UserProfileRepository
+	var feedbackRepo db.ChatFeedbackRepository
 	if repos != nil {
 		conversationRepo = repos.VoiceConversation
 		messageRepo = repos.ConversationMessage
+		settingRepo = repos.SystemSetting
+		scenarioRepo = repos.ConversationScenario
+		questionSetRepo = repos.QuestionSet
+		personaRepo = repos.TutorPersona
+		profileRepo = repos.UserProfile
+		feedbackRepo = repos.ChatFeedback
 	}
 	return &chatServiceImpl{
-		conversationRepo: conversationRepo,
-		messageRepo:      messageRepo,
-		asrProvider:      asr,
-		llmProvider:      llm,
-		ttsProvider:      tts,
-		ossProvider:      oss,
-		logger:           logger,
+		conversationRepo:   conversationRepo,
+		messageRepo:        messageRepo,
+		settingRepo:        settingRepo,
+		scenarioRepo:       scenarioRepo,
+		questionSetRepo:    questionSetRepo,
+		personaRepo:        personaRepo,
+		profileRepo:        profileRepo,
+		feedbackRepo:       feedbackRepo,
+		asrProvider:        asr,
+		llmProvider:        llm,
+		ttsProvider:        tts,
+		ossProvider:        oss,
+		moderationProvider: moderation,
+		evaluationProvider: evaluation,
+		reviewService:      reviewService,
+		vocabularyService:  vocabularyService,
+		memoryService:      memoryService,
+		taskCache:          taskCache,
+		cfg:                cfg,
+		logger:             logger,
 	}
 }
 
-func (s *chatServiceImpl) ChatMVP(ctx context.Context, req *ChatMVPRequest) ([]byte, error) {
+func (s *chatServiceImpl) ChatMVP(ctx context.Context, req *ChatMVPRequest) (*ChatMVPResponse, error) {
 	// 步骤 1：基础校验
 	if req == nil {
 		err := errors.New("chat mvp request is nil")
@@ -197,8 +341,19 @@ func (s *chatServiceImpl) ChatMVP(ctx context.Context, req *ChatMVPRequest) ([]b
 		difficultyLevel = "beginner"
 	}
 
-	// 步骤 2：ASR 识别
+	// 步骤 2：解析会话（在调用付费服务前校验归属、状态与消息上限）
+	timer := newStageTimer()
+	maxMessages := s.maxConversationMessages(ctx)
+	conversation, err := s.resolveConversation(ctx, strings.TrimSpace(req.SessionID), req.UserID, conversationType, maxMessages)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat mvp resolve session failed", "session_id", req.SessionID, "error", err)
+		return nil, err
+	}
+
+	// 步骤 3：ASR 识别
+	asrDone := timer.track(model.LatencyStageASR)
 	asrResult, err := s.asrProvider.RecognizeAudio(ctx, req.AudioData, audioType, 16000)
+	asrDone()
 	if err != nil {
 		logger.ErrorContext(ctx, "chat mvp asr failed", "error", err)
 		return nil, err
@@ -210,177 +365,464 @@ func (s *chatServiceImpl) ChatMVP(ctx context.Context, req *ChatMVPRequest) ([]b
 		return nil, err
 	}
 
-	// 步骤 3：LLM 生成回复
-	systemPrompt := `
-You are a friendly English teacher for Chinese kids (6-12 years old) learning English.
-
-CORE RULES:
-1. Response length: Maximum 25 words (2 sentences)
-2. Vocabulary: Use only simple, common words (like: cat, happy, play, eat, go)
-3. Primary language: English
-4. When to use Chinese: Only for difficult grammar explanations (use 【】brackets)
-5. Always be encouraging and positive
-
-Response Pattern:
-- Child speaks English → Reply in simple English + praise
-- Child speaks Chinese → Gently prompt in English: "Let's try English! You can say..."
-- Child makes mistakes → Don't correct directly, just model the right form
-
-Examples:
-Child: "I go school yesterday" 
-You: "Great! I went to school yesterday too. What did you do there?"
-
-Child: "这个怎么说？"
-You: "Let's say it in English! You can ask: How do you say this?"
+	// 步骤 4：识别本轮语言并读取语言策略、人设与长期记忆，审核孩子输入（拦截时不调用 LLM，使用兜底回复）
+	lang := s.resolveReplyLanguage(ctx, req.UserID, conversation, difficultyLevel, userText, req.Translate)
+	persona := s.resolvePersona(ctx, req.UserID)
+	memory := s.resolveLearnerMemory(ctx, req.UserID, conversation)
+	userText, moderation := s.moderateInput(ctx, conversation, userText)
+	turn := &chatTurn{
+		conversation:     conversation,
+		userID:           req.UserID,
+		conversationType: conversationType,
+		difficultyLevel:  difficultyLevel,
+		audioType:        audioType,
+		userAudio:        req.AudioData,
+		sampleRate:       16000,
+		userText:         userText,
+		userLanguage:     lang.userLanguage,
+		durationSeconds:  asrResult.Duration,
+		maxMessages:      maxMessages,
+		moderation:       moderation,
+		learnerMemory:    memory,
+		latency:          timer,
+		debug:            req.Debug,
+	}
 
-Child: "I'm happy!"
-You: "Wonderful! I'm happy too! Why are you happy today?"
-`
-	replyText, err := s.llmProvider.Chat(ctx, systemPrompt, userText)
-	if err != nil {
-		logger.ErrorContext(ctx, "chat mvp llm failed", "error", err)
-		return nil, err
+	// 步骤 5：LLM 生成回复（问答会话判定回答后提问，其余结合会话历史回复），回复合成前审核
+	var replyText string
+	var question *questionTurn
+	llmDone := timer.track(model.LatencyStageLLM)
+	if moderation.inputBlocked {
+		replyText = moderation.fallbackReply()
+	} else if isQuestionSession(conversation) {
+		replyText, question, err = s.answerQuestion(ctx, conversation, userText)
+		if err != nil {
+			return nil, err
+		}
+		logger.InfoContext(ctx, "chat mvp answer checked", "session_id", conversation.ID, "score", question.check.Score, "reply_length", len(replyText))
+	} else {
+		chatMessages := s.buildChatContext(ctx, conversation, userText, lang, persona, memory, 0)
+		replyText, err = s.llmProvider.ChatWithHistory(ctx, chatMessages)
+		if err != nil {
+			llmDone()
+			logger.ErrorContext(ctx, "chat mvp llm failed", "error", err)
+			return s.failTurn(ctx, turn, model.TurnFailedStageLLM, err)
+		}
+		logger.InfoContext(ctx, "chat mvp llm reply", "replyText", replyText, "context_messages", len(chatMessages))
+	}
+	llmDone()
+	if !moderation.inputBlocked {
+		replyText, _ = s.moderateReply(ctx, moderation, replyText)
 	}
-	logger.InfoContext(ctx, "chat mvp llm reply", "replyText", replyText)
-	// 步骤 4：TTS 合成
-	ttsAudio, err := s.ttsProvider.Synthesize(ctx, replyText, nil)
+
+	// 步骤 6：按人设音色与语速合成语音（按语言策略同时生成中文释义，角色扮演同时判定场景目标）
+	turn.scenarioGoal = s.startScenarioGoalCheck(ctx, conversation, userText, replyText)
+	translationDone := make(chan string, 1)
+	go func() { translationDone <- s.translateReply(ctx, lang, moderation, replyText) }()
+	ttsDone := timer.track(model.LatencyStageTTS)
+	ttsAudio, err := s.ttsProvider.Synthesize(ctx, replyText, persona.synthesizeOptions())
+	ttsDone()
+	translation := <-translationDone
+	turn.replyText = replyText
+	turn.replyTranslation = translation
+	turn.question = question
 	if err != nil {
 		logger.ErrorContext(ctx, "chat mvp tts failed", "error", err)
-		return nil, err
+		if question != nil {
+			return nil, err // 问答会话的判定与进度随本轮保存，不保存未播报的轮次
+		}
+		return s.failTurn(ctx, turn, model.TurnFailedStageTTS, err)
 	}
 	logger.InfoContext(ctx, "chat mvp tts audio generated", "audioSize", len(ttsAudio))
-	logger.InfoContext(ctx, "开始上传用户音频与 AI 音频到 OSS")
-	// 步骤 5：上传用户音频与 AI 音频到 OSS
+
+	// 步骤 7：上传音频并追加本轮消息到会话（失败不影响主流程）
+	turn.replyAudio = ttsAudio
+	result := s.persistTurn(ctx, turn)
+
+	// 步骤 8：返回音频与会话信息
+	return &ChatMVPResponse{Audio: ttsAudio, ChatTurnResult: *result}, nil
+}
+
+// chatTurn 一轮对话的持久化参数
+type chatTurn struct {
+	conversation     *model.VoiceConversation // 为 nil 时新建会话
+	userID           string
+	conversationType string
+	difficultyLevel  string
+	audioType        string
+	userAudio        []byte
+	sampleRate       int // 用户音频采样率
+	userText         string
+	userLanguage     string // 用户消息语言（zh / en / mixed，无法判断时为空）
+	replyText        string
+	replyTranslation string // AI 回复的中文释义（未生成时为空）
+	replyAudio       []byte
+	durationSeconds  int
+	maxMessages      int
+	question         *questionTurn             // 问答会话本轮的判定与进度
+	moderation       *turnModeration           // 本轮审核结果，命中时标记会话待复核
+	learnerMemory    string                    // 本轮注入的长期记忆，新建会话时保存，后续轮次沿用
+	latency          *stageTimer               // 本轮各阶段耗时，随 AI 消息保存
+	failedStage      string                    // 回复处理失败的阶段（llm / tts），AI 消息标记失败、不判定场景与上限
+	scenarioGoal     <-chan *scenarioGoalCheck // 与 TTS 并行的场景目标判定（非场景会话为 nil）
+	debug            bool                      // 结果中附带各阶段耗时
+}
+
+// persistTurn 上传用户音频与 AI 音频（如有）到 OSS，并将本轮消息追加到会话
+// 上传或保存失败仅记录日志，不影响已生成的回复；达到消息上限时结束会话
+// 回复已生成（LLM / TTS 已产生费用），保存脱离请求生命周期，不因客户端断开或接口超时被取消
+func (s *chatServiceImpl) persistTurn(ctx context.Context, t *chatTurn) *ChatTurnResult {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), turnPersistTimeout)
+	defer cancel()
+
 	conversationID := uuid.New()
+	if t.conversation != nil {
+		conversationID = t.conversation.ID
+	}
+	result := &ChatTurnResult{SessionID: conversationID, UserLanguage: t.userLanguage, ReplyTranslation: t.replyTranslation}
+
+	// ─── 1. 上传用户音频与 AI 音频到 OSS ───
+	logger.InfoContext(ctx, "开始上传用户音频与 AI 音频到 OSS")
 	userMsgID := uuid.New()
 	aiMsgID := uuid.New()
-	userAudioKey := fmt.Sprintf("chat/%s/user_%s.%s", conversationID, userMsgID, audioType)
+	userAudioKey := fmt.Sprintf("chat/%s/user_%s.%s", conversationID, userMsgID, t.audioType)
 	aiAudioKey := fmt.Sprintf("chat/%s/ai_%s.mp3", conversationID, aiMsgID)
 
 	var userAudioURL string
 	var aiAudioURL string
+	ossDone := t.latency.track(model.LatencyStageOSS)
 	if s.ossProvider != nil {
-		if url, uploadErr := s.ossProvider.UploadAudio(ctx, userAudioKey, req.AudioData); uploadErr != nil {
-			logger.ErrorContext(ctx, "chat mvp upload user audio failed", "error", uploadErr)
+		if url, uploadErr := s.ossProvider.UploadAudio(ctx, userAudioKey, t.userAudio); uploadErr != nil {
+			logger.ErrorContext(ctx, "chat upload user audio failed", "error", uploadErr)
 		} else {
 			userAudioURL = url
 		}
-		if url, uploadErr := s.ossProvider.UploadAudio(ctx, aiAudioKey, ttsAudio); uploadErr != nil {
-			logger.ErrorContext(ctx, "chat mvp upload ai audio failed", "error", uploadErr)
-		} else {
-			aiAudioURL = url
+		if len(t.replyAudio) > 0 {
+			if url, uploadErr := s.ossProvider.UploadAudio(ctx, aiAudioKey, t.replyAudio); uploadErr != nil {
+				logger.ErrorContext(ctx, "chat upload ai audio failed", "error", uploadErr)
+			} else {
+				aiAudioURL = url
+			}
 		}
 	} else {
-		// 步骤 5：如果 OSS 未初始化，仅记录日志
-		logger.ErrorContext(ctx, "chat mvp oss provider not initialized", "error", errors.New("oss provider nil"))
+		// 如果 OSS 未初始化，仅记录日志
+		logger.ErrorContext(ctx, "chat oss provider not initialized", "error", errors.New("oss provider nil"))
+	}
+	ossDone()
+	latency := t.latency.breakdown()
+	if t.debug {
+		result.Latency = latency
+	}
+	logger.InfoContext(ctx, "chat oss audio urls", "userAudioURL", userAudioURL, "aiAudioURL", aiAudioURL)
+
+	// ─── 2. 追加本轮消息到会话 ───
+	if s.conversationRepo == nil || s.messageRepo == nil {
+		logger.ErrorContext(ctx, "chat repository not initialized", "error", errors.New("repository nil"))
+		return result
 	}
-	logger.InfoContext(ctx, "chat mvp oss audio urls", "userAudioURL", userAudioURL, "aiAudioURL", aiAudioURL)
 	logger.InfoContext(ctx, "保存对话记录到数据库")
-	// 步骤 6：保存对话记录到数据库（失败不影响主流程）
-	if s.conversationRepo != nil && s.messageRepo != nil {
+	if t.conversation == nil {
 		conversation := &model.VoiceConversation{
 			ID:               conversationID,
-			UserID:           req.UserID,
+			UserID:           t.userID,
 			Topic:            "General",
-			DifficultyLevel:  difficultyLevel,
-			ConversationType: conversationType,
-			MessageCount:     2,
-			DurationSeconds:  asrResult.Duration,
-			Status:           "completed",
+			DifficultyLevel:  t.difficultyLevel,
+			ConversationType: t.conversationType,
+			Status:           model.ConversationStatusActive,
+		}
+		if t.learnerMemory != "" {
+			conversation.LearnerMemory = &t.learnerMemory
 		}
+		// 新会话从孩子开始说话计时（录音时长 + 本轮处理耗时）
+		activeSince := time.Now().Add(-time.Duration(t.durationSeconds) * time.Second)
+		if latency != nil {
+			activeSince = activeSince.Add(-time.Duration(latency.TotalMS) * time.Millisecond)
+		}
+		conversation.LastActiveAt = &activeSince
 		if saveErr := s.conversationRepo.Create(ctx, conversation); saveErr != nil {
-			logger.ErrorContext(ctx, "chat mvp save conversation failed", "error", saveErr)
-		} else {
-			var userDuration *int
-			if asrResult.Duration > 0 {
-				userDuration = &asrResult.Duration
-			}
-			var userAudioPtr *string
-			if userAudioURL != "" {
-				userAudioPtr = &userAudioURL
-			}
-			var aiAudioPtr *string
-			if aiAudioURL != "" {
-				aiAudioPtr = &aiAudioURL
-			}
+			logger.ErrorContext(ctx, "chat save conversation failed", "error", saveErr)
+			return result
+		}
+	}
 
-			messages := []*model.ConversationMessage{
-				{
-					ID:             userMsgID,
-					ConversationID: conversationID,
-					SenderType:     "user",
-					MessageText:    userText,
-					AudioURL:       userAudioPtr,
-					AudioDuration:  userDuration,
-					SequenceNumber: 1,
-				},
-				{
-					ID:             aiMsgID,
-					ConversationID: conversationID,
-					SenderType:     "ai",
-					MessageText:    replyText,
-					AudioURL:       aiAudioPtr,
-					SequenceNumber: 2,
-				},
-			}
-			if saveErr := s.messageRepo.BatchCreate(ctx, messages); saveErr != nil {
-				logger.ErrorContext(ctx, "chat mvp save messages failed", "error", saveErr)
-			}
+	var userDuration *int
+	if t.durationSeconds > 0 {
+		userDuration = &t.durationSeconds
+	}
+	var userAudioPtr *string
+	if userAudioURL != "" {
+		userAudioPtr = &userAudioURL
+	}
+	var aiAudioPtr *string
+	if aiAudioURL != "" {
+		aiAudioPtr = &aiAudioURL
+	}
+	promptVersion := chatPromptVersion
+	var userLanguage *string
+	if t.userLanguage != "" {
+		userLanguage = &t.userLanguage
+	}
+	var replyTranslation *string
+	if t.replyTranslation != "" {
+		replyTranslation = &t.replyTranslation
+	}
+	var latencyMS *int
+	var stageLatencies model.StageLatencies
+	if latency != nil {
+		latencyMS = &latency.TotalMS
+		stageLatencies = latency.Stages
+	}
+	var failedStage *string
+	if t.failedStage != "" {
+		failedStage = &t.failedStage
+	}
+
+	messages := []*model.ConversationMessage{
+		{
+			ID:            userMsgID,
+			SenderType:    model.SenderTypeUser,
+			MessageText:   t.userText,
+			AudioURL:      userAudioPtr,
+			AudioDuration: userDuration,
+			Language:      userLanguage,
+		},
+		{
+			ID:             aiMsgID,
+			SenderType:     model.SenderTypeAI,
+			MessageText:    t.replyText,
+			AudioURL:       aiAudioPtr,
+			Translation:    replyTranslation,
+			PromptVersion:  &promptVersion,
+			LatencyMS:      latencyMS,
+			StageLatencies: stageLatencies,
+			FailedStage:    failedStage,
+		},
+	}
+	updated, saveErr := s.conversationRepo.AppendMessages(ctx, conversationID, messages, s.idleTimeout(ctx), t.maxMessages)
+	if saveErr != nil {
+		logger.ErrorContext(ctx, "chat save messages failed", "session_id", conversationID, "error", saveErr)
+		return result
+	}
+	result.Turn = (messages[0].SequenceNumber + 1) / 2
+	result.RemainingTurns = (t.maxMessages - updated.MessageCount) / 2
+
+	// ─── 3. 本轮命中内容安全审核时标记会话待复核 ───
+	s.flagConversation(ctx, conversationID, t.moderation)
+
+	// ─── 4. 后台分析用户消息的语法 / 用词错误（结果通过对话历史返回） ───
+	s.annotateMessageAsync(ctx, messages[0], t.difficultyLevel)
+
+	// ─── 5. 后台评测用户语音的发音（结果通过对话历史返回） ───
+	assessed := s.assessPronunciationAsync(ctx, messages[0], t)
+
+	// ─── 6. 后台累计孩子说出的单词到个人词汇表 ───
+	s.recordVocabularyAsync(ctx, t.userID, messages[0], t)
+
+	// ─── 7. 角色扮演：保存并行判定的场景成功标准，全部达成时结束会话（失败的轮次在重试成功后判定） ───
+	if t.conversation != nil && t.conversation.ScenarioID != nil && t.failedStage == "" {
+		if t.scenarioGoal != nil {
+			result.GoalAchieved = s.applyScenarioGoal(ctx, t.conversation, <-t.scenarioGoal)
 		}
-	} else {
-		logger.ErrorContext(ctx, "chat mvp repository not initialized", "error", errors.New("repository nil"))
+		result.MetCriteria = t.conversation.MetCriteria
+		result.SessionCompleted = result.GoalAchieved
 	}
-	logger.InfoContext(ctx, "chat mvp save conversation and messages success")
-	// 步骤 7：返回音频
-	return ttsAudio, nil
-}
 
-func (s *chatServiceImpl) SubmitChat(ctx context.Context, req *SubmitChatRequest) (string, error) {
-	// TODO: Step3 实现异步任务
-	// 1. 生成 task_id
-	// 2. 创建异步任务（ASR → LLM → TTS）
-	// 3. 将任务提交到队列
-	// 4. 返回 task_id
-	return "", nil
-}
+	// ─── 8. 问答：保存题目进度，全部答完时结束会话 ───
+	if t.conversation != nil && t.question != nil {
+		s.saveQuestionProgress(ctx, t.conversation, t.question)
+		result.AnswerCheck = &t.question.check
+		result.QuestionsAnswered = len(t.question.results)
+		result.QuestionTotal = t.question.total
+		result.SessionCompleted = t.question.completed
+	}
+
+	// ─── 9. 达到消息上限后结束会话，后续轮次需新建会话（失败的轮次保留会话以便重试） ───
+	if !result.SessionCompleted && t.failedStage == "" && updated.MessageCount+2 > t.maxMessages {
+		if statusErr := s.conversationRepo.UpdateStatus(ctx, conversationID, model.ConversationStatusCompleted); statusErr != nil {
+			logger.ErrorContext(ctx, "chat complete session failed", "session_id", conversationID, "error", statusErr)
+		}
+		result.SessionCompleted = true
+	}
 
-func (s *chatServiceImpl) GetChatResult(ctx context.Context, taskID string) (*ChatResultResponse, error) {
-	// TODO: Step3 实现
-	// 1. 从缓存/数据库查询任务状态
-	// 2. 如果完成，返回完整结果
-	// 3. 如果处理中，返回进度信息
-	// 4. 如果失败，返回错误信息
-	return nil, nil
+	// ─── 10. 会话结束：等待本轮发音评测写回后，后台生成会话总结、评分与反馈 ───
+	if result.SessionCompleted {
+		go func() {
+			<-assessed
+			s.reviewSessionAsync(ctx, conversationID)
+		}()
+	}
+	logger.InfoContext(ctx, "chat save conversation and messages success", "session_id", conversationID, "turn", result.Turn)
+	return result
 }
 
 func (s *chatServiceImpl) GetChatHistory(ctx context.Context, req *ChatHistoryRequest) ([]*ConversationTurn, int64, error) {
-	// TODO: Step2 实现
-	// 1. 验证用户对该会话的访问权限
-	// 2. 查询 conversation_messages 表
-	// 3. 按 order 排序，分页返回
-	return nil, 0, nil
+	// ─── 1. 参数标准化 ───
+	if req == nil || req.UserID == "" {
+		return nil, 0, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+	order := strings.ToLower(strings.TrimSpace(req.Order))
+	if order != "desc" {
+		order = "asc"
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	// ─── 2. 验证会话归属 ───
+	conversation, err := s.getOwnedConversation(ctx, req.SessionID, req.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// ─── 3. 按轮次分页（每轮 = 用户消息 + AI 回复） ───
+	nextSeq, err := s.messageRepo.GetNextSequenceNumber(ctx, conversation.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get conversation sequence failed: %w", err)
+	}
+	totalTurns := nextSeq / 2 // 最后序号为 nextSeq-1，轮次 = (序号 + 1) / 2
+	from, to := historyTurnRange(totalTurns, req.Page, req.PageSize, order == "desc")
+	if from > to {
+		return []*ConversationTurn{}, int64(totalTurns), nil
+	}
+
+	messages, err := s.messageRepo.GetBySequenceRange(ctx, conversation.ID, 2*from-1, 2*to)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get conversation messages failed: %w", err)
+	}
+	return toConversationTurns(messages, order == "desc"), int64(totalTurns), nil
 }
 
 func (s *chatServiceImpl) DeleteSession(ctx context.Context, sessionID, userID string) (int64, error) {
-	// TODO: Step2 实现
-	// 1. 验证用户对该会话的所有权
-	// 2. 删除会话下所有消息
-	// 3. 删除会话记录
-	// 4. 返回删除的消息数量
-	return 0, nil
+	// ─── 1. 验证会话归属 ───
+	conversation, err := s.getOwnedConversation(ctx, sessionID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	// ─── 2. 删除消息并软删除会话 ───
+	deleted, err := s.conversationRepo.DeleteWithMessages(ctx, conversation.ID)
+	if err != nil {
+		return 0, fmt.Errorf("delete conversation failed: %w", err)
+	}
+
+	logger.InfoContext(ctx, "chat session deleted", "session_id", sessionID, "user_id", userID, "deleted_messages", deleted)
+	return deleted, nil
 }
 
 func (s *chatServiceImpl) GetSessions(ctx context.Context, userID string, page, pageSize int) ([]*SessionSummary, int64, error) {
-	// TODO: Step2 实现
-	// 1. 查询 voice_conversations 表
-	// 2. 按最后交互时间降序排列
-	// 3. 分页返回会话摘要
-	return nil, 0, nil
+	if userID == "" {
+		return nil, 0, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+	if s.conversationRepo == nil || s.messageRepo == nil {
+		return nil, 0, apperr.ErrInternalError.WithMessage("conversation repository not initialized")
+	}
+
+	// ─── 1. 按最后交互时间降序分页 ───
+	conversations, total, err := s.conversationRepo.GetByUserIDOrderByLastMessage(ctx, userID, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list conversations failed: %w", err)
+	}
+
+	// ─── 2. 批量查询每个会话的最后一条消息 ───
+	ids := make([]string, 0, len(conversations))
+	for _, c := range conversations {
+		ids = append(ids, c.ID)
+	}
+	lastMessages, err := s.messageRepo.GetLastMessages(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get last messages failed: %w", err)
+	}
+
+	// ─── 3. 转换为会话摘要 ───
+	summaries := make([]*SessionSummary, 0, len(conversations))
+	for _, c := range conversations {
+		summaries = append(summaries, toSessionSummary(c, lastMessages[c.ID]))
+	}
+	return summaries, total, nil
 }
 
 func (s *chatServiceImpl) SubmitChatFeedback(ctx context.Context, req *SubmitFeedbackRequest) error {
-	// TODO: Step2 实现
-	// 1. 验证 task_id / session_id 存在
-	// 2. 保存反馈到数据库
+	// ─── 1. 参数校验 ───
+	if req == nil || req.UserID == "" {
+		return apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+	if req.Turn < 1 {
+		return apperr.ErrInvalidParam.WithMessage("turn must be >= 1")
+	}
+	if req.Rating < 1 || req.Rating > 5 {
+		return apperr.ErrInvalidParam.WithMessage("rating must be between 1 and 5")
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if len([]rune(comment)) > chatFeedbackCommentMaxRunes {
+		return apperr.ErrInvalidParam.WithMessage(fmt.Sprintf("comment must be at most %d characters", chatFeedbackCommentMaxRunes))
+	}
+	if s.feedbackRepo == nil || s.messageRepo == nil {
+		return apperr.ErrInternalError.WithMessage("feedback repository not initialized")
+	}
+
+	// ─── 2. 验证会话归属，定位本轮 AI 回复（第 N 轮回复的序号为 2N） ───
+	conversation, err := s.getOwnedConversation(ctx, strings.TrimSpace(req.SessionID), req.UserID)
+	if err != nil {
+		return err
+	}
+	messages, err := s.messageRepo.GetBySequenceRange(ctx, conversation.ID, 2*req.Turn, 2*req.Turn)
+	if err != nil {
+		return fmt.Errorf("get conversation message failed: %w", err)
+	}
+	if len(messages) == 0 || messages[0].SenderType != model.SenderTypeAI {
+		return apperr.ErrNotFound.WithMessage("conversation turn not found")
+	}
+	reply := messages[0]
+	taskID, err := s.ownedFeedbackTaskID(ctx, req.TaskID, req.UserID, conversation.ID)
+	if err != nil {
+		return err
+	}
+
+	// ─── 3. 保存反馈（同一版本重复提交时覆盖） ───
+	feedback := &model.ChatFeedback{
+		ID:             uuid.New(),
+		UserID:         req.UserID,
+		ConversationID: conversation.ID,
+		MessageID:      reply.ID,
+		Turn:           req.Turn,
+		Rating:         req.Rating,
+		Helpful:        req.Helpful,
+		PromptVersion:  reply.PromptVersion,
+		ReplyVersion:   len(reply.ReplyVersions) + 1,
+	}
+	if taskID != "" {
+		feedback.TaskID = &taskID
+	}
+	if comment != "" {
+		feedback.Comment = &comment
+	}
+	if err := s.feedbackRepo.Upsert(ctx, feedback); err != nil {
+		return fmt.Errorf("save chat feedback failed: %w", err)
+	}
+
+	logger.InfoContext(ctx, "chat feedback saved", "session_id", conversation.ID, "turn", req.Turn, "rating", req.Rating)
 	return nil
 }
+
+// ownedFeedbackTaskID 校验反馈关联的异步任务属于当前用户与会话，未传 task_id 时返回空字符串
+// 任务不存在、已过期或不属于当前用户与会话时按参数错误处理
+func (s *chatServiceImpl) ownedFeedbackTaskID(ctx context.Context, taskID, userID, conversationID string) (string, error) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return "", nil
+	}
+	if s.taskCache == nil {
+		return "", apperr.ErrInvalidParam.WithMessage("task_id is not supported")
+	}
+	record, err := s.taskCache.Get(ctx, taskID)
+	if err != nil {
+		return "", fmt.Errorf("get chat task failed: %w", err)
+	}
+	if record == nil || record.UserID != userID || record.SessionID != conversationID {
+		return "", apperr.ErrInvalidParam.WithMessage("task_id not found or does not belong to this session")
+	}
+	return taskID, nil
+}
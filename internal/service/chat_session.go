@@ -0,0 +1,386 @@
+// Package service 提供多轮语音对话的会话与上下文管理
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/domain"
+	llmPrompts "pronunciation-correction-system/internal/infrastructure/llm"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// defaultMaxConversationMessages 会话消息上限默认值（system_settings 读取失败时使用）
+const defaultMaxConversationMessages = 50
+
+// sessionLastMessageMaxRunes 会话列表中最后一条消息的最大展示长度
+const sessionLastMessageMaxRunes = 100
+
+// chatFeedbackCommentMaxRunes 对话反馈文字评价的最大长度
+const chatFeedbackCommentMaxRunes = 500
+
+// chatPromptVersion 对话回复提示词版本，随 AI 消息保存，用于按版本对比反馈评分
+// 修改 chatTeacherPrompt、语言策略提示词、角色扮演或问答提示词时递增
+const chatPromptVersion = "v2"
+
+// LLM 对话角色
+const (
+	chatRoleSystem    = "system"
+	chatRoleUser      = "user"
+	chatRoleAssistant = "assistant"
+)
+
+// chatTeacherPrompt 语音对话系统提示词（中文的使用方式由语言策略提示词追加）
+const chatTeacherPrompt = `
+You are a friendly English teacher for Chinese kids (6-12 years old) learning English.
+
+CORE RULES:
+1. Response length: Maximum 25 words (2 sentences)
+2. Vocabulary: Use only simple, common words (like: cat, happy, play, eat, go)
+3. Always be encouraging and positive
+
+Response Pattern:
+- Child speaks English → Reply in simple English + praise
+- Child makes mistakes → Don't correct directly, just model the right form
+
+Examples:
+Child: "I go school yesterday"
+You: "Great! I went to school yesterday too. What did you do there?"
+
+Child: "I'm happy!"
+You: "Wonderful! I'm happy too! Why are you happy today?"
+`
+
+// chatSummaryPrompt 较早轮次摘要提示词
+const chatSummaryPrompt = `You maintain a running memory of an English lesson between a teacher and a Chinese child.
+Merge the previous summary (if any) with the new turns into ONE short summary (at most 80 words).
+Keep: topics discussed, facts the child shared about themselves, words or sentences the child struggled with, questions still open.
+Write plain English sentences only, no lists, no greetings.`
+
+// resolveConversation 解析本轮对话所属会话
+// session_id 为空时返回 nil（由调用方在首轮成功后创建）；否则校验归属、状态（暂停与已结束的会话不能继续对话）与消息上限
+// role_play / question_answer 会话必须先通过场景 / 题集创建，不能由首轮对话隐式创建
+func (s *chatServiceImpl) resolveConversation(ctx context.Context, sessionID, userID, conversationType string, maxMessages int) (*model.VoiceConversation, error) {
+	if sessionID == "" {
+		if conversationType == model.ConversationTypeRolePlay {
+			return nil, apperr.ErrInvalidParam.WithMessage("role_play requires a session_id started from a scenario")
+		}
+		if conversationType == model.ConversationTypeQuestionAnswer {
+			return nil, apperr.ErrInvalidParam.WithMessage("question_answer requires a session_id started from a question set")
+		}
+		return nil, nil
+	}
+	conversation, err := s.getOwnedConversation(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.Status == model.ConversationStatusPaused {
+		return nil, apperr.ErrConversationPaused.WithMessage("resume the session before continuing")
+	}
+	if conversation.Status != model.ConversationStatusActive {
+		return nil, apperr.ErrConversationClosed.WithMessage("status " + conversation.Status)
+	}
+	if maxMessages > 0 && conversation.MessageCount+2 > maxMessages {
+		return nil, apperr.ErrConversationLimitReached.WithMessage(fmt.Sprintf("max %d messages", maxMessages))
+	}
+	return conversation, nil
+}
+
+// getOwnedConversation 获取会话并校验归属
+func (s *chatServiceImpl) getOwnedConversation(ctx context.Context, sessionID, userID string) (*model.VoiceConversation, error) {
+	if sessionID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("session_id is required")
+	}
+	if s.conversationRepo == nil {
+		return nil, apperr.ErrInternalError.WithMessage("conversation repository not initialized")
+	}
+	conversation, err := s.conversationRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, apperr.ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("get conversation failed: %w", err)
+	}
+	if conversation.UserID != userID {
+		return nil, apperr.ErrForbidden.WithMessage("conversation does not belong to user")
+	}
+	return conversation, nil
+}
+
+// maxConversationMessages 从 system_settings 读取单个会话的消息上限，失败时使用默认值
+func (s *chatServiceImpl) maxConversationMessages(ctx context.Context) int {
+	if s.settingRepo == nil {
+		return defaultMaxConversationMessages
+	}
+	value, err := s.settingRepo.GetIntValue(ctx, model.ConfigMaxConversationMessages)
+	if err != nil || value <= 0 {
+		if err != nil && !db.IsNotFound(err) {
+			logger.WarnContext(ctx, "chat read max conversation messages failed", "error", err)
+		}
+		return defaultMaxConversationMessages
+	}
+	return value
+}
+
+// buildChatContext 构建多轮对话的 LLM 消息列表：[系统提示词(+人设+语言策略+摘要), 场景开场白, 历史消息, 当前消息]
+// 角色扮演会话使用场景角色提示词（场景决定 AI 扮演的角色，不追加人设）
+// beforeSeq > 0 时只使用序号小于 beforeSeq 的历史（重新生成 / 重试某一轮时排除该轮）；处理失败的 AI 回复不计入历史
+// 历史查询失败时使用空历史继续，不阻塞本轮对话
+func (s *chatServiceImpl) buildChatContext(ctx context.Context, conversation *model.VoiceConversation, userText string, lang *replyLanguage, persona *turnPersona, memory string, beforeSeq int) []domain.ChatMessage {
+	var history []*model.ConversationMessage
+	summary := ""
+	if conversation != nil && s.messageRepo != nil {
+		messages, err := s.messageRepo.GetAfterSequence(ctx, conversation.ID, conversation.SummarizedUntil)
+		if err != nil {
+			logger.ErrorContext(ctx, "chat load history failed, continue without history", "session_id", conversation.ID, "error", err)
+		} else {
+			history = s.compactHistory(ctx, conversation, filterHistory(messages, beforeSeq))
+		}
+		if conversation.ContextSummary != nil {
+			summary = strings.TrimSpace(*conversation.ContextSummary)
+		}
+	}
+	history = trimHistoryByChars(history, s.cfg.MaxContextChars)
+
+	systemPrompt := chatTeacherPrompt + persona.prompt() + learnerMemoryPrompt(memory)
+	opening := ""
+	if scenario := s.loadScenario(ctx, conversation); scenario != nil {
+		systemPrompt = llmPrompts.BuildRolePlaySystemPrompt(scenario.Persona, scenario.Goal, conversation.DifficultyLevel,
+			scenario.TargetVocabulary, scenario.SuccessCriteria, conversation.MetCriteria)
+		// 上下文从会话开头开始时，补上 AI 开场白
+		if conversation.SummarizedUntil == 0 && (len(history) == 0 || history[0].SequenceNumber == 1) {
+			opening = scenario.OpeningLine
+		}
+	}
+	systemPrompt += languagePolicyPrompt(lang.policy, lang.difficulty)
+	if summary != "" {
+		systemPrompt += "\nEarlier in this conversation (summary):\n" + summary + "\n"
+	}
+
+	chatMessages := make([]domain.ChatMessage, 0, len(history)+3)
+	chatMessages = append(chatMessages, domain.ChatMessage{Role: chatRoleSystem, Content: systemPrompt})
+	if opening != "" {
+		chatMessages = append(chatMessages, domain.ChatMessage{Role: chatRoleAssistant, Content: opening})
+	}
+	for _, m := range history {
+		role := chatRoleUser
+		if m.SenderType == model.SenderTypeAI {
+			role = chatRoleAssistant
+		}
+		chatMessages = append(chatMessages, domain.ChatMessage{Role: role, Content: m.MessageText})
+	}
+	chatMessages = append(chatMessages, domain.ChatMessage{Role: chatRoleUser, Content: userText})
+	return chatMessages
+}
+
+// filterHistory 去掉序号不小于 beforeSeq（> 0 时）的消息与处理失败的 AI 回复
+func filterHistory(messages []*model.ConversationMessage, beforeSeq int) []*model.ConversationMessage {
+	filtered := make([]*model.ConversationMessage, 0, len(messages))
+	for _, m := range messages {
+		if beforeSeq > 0 && m.SequenceNumber >= beforeSeq {
+			break
+		}
+		if m.FailedStage != nil {
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+	return filtered
+}
+
+// compactHistory 历史消息超过 history_messages 时，将较早的一半合并进滚动摘要
+// 摘要成功后写回会话（后续轮次不再读取这些消息）；摘要失败或关闭时仅裁剪，不修改会话
+func (s *chatServiceImpl) compactHistory(ctx context.Context, conversation *model.VoiceConversation, messages []*model.ConversationMessage) []*model.ConversationMessage {
+	window := s.cfg.HistoryMessages
+	if window <= 0 || len(messages) <= window {
+		return messages
+	}
+	if !s.cfg.SummarizeHistory {
+		return messages[len(messages)-window:]
+	}
+
+	// 保留最近 window/2 条（按整轮保留），其余合并为摘要，避免每轮都触发摘要
+	keep := window / 2
+	keep -= keep % 2
+	if keep < 2 {
+		keep = 2
+	}
+	older := messages[:len(messages)-keep]
+	recent := messages[len(messages)-keep:]
+
+	previous := ""
+	if conversation.ContextSummary != nil {
+		previous = *conversation.ContextSummary
+	}
+	summary, err := s.summarizeTurns(ctx, previous, older)
+	if err != nil {
+		logger.WarnContext(ctx, "chat summarize history failed, trim only", "session_id", conversation.ID, "error", err)
+		return messages[len(messages)-window:]
+	}
+
+	summarizedUntil := older[len(older)-1].SequenceNumber
+	if err := s.conversationRepo.UpdateContextSummary(ctx, conversation.ID, summary, summarizedUntil); err != nil {
+		logger.ErrorContext(ctx, "chat save context summary failed", "session_id", conversation.ID, "error", err)
+	}
+	conversation.ContextSummary = &summary
+	conversation.SummarizedUntil = summarizedUntil
+	logger.InfoContext(ctx, "chat history summarized", "session_id", conversation.ID, "summarized_until", summarizedUntil)
+	return recent
+}
+
+// summarizeTurns 调用 LLM 将已有摘要与较早轮次合并为新的摘要
+func (s *chatServiceImpl) summarizeTurns(ctx context.Context, previous string, messages []*model.ConversationMessage) (string, error) {
+	var b strings.Builder
+	if previous = strings.TrimSpace(previous); previous != "" {
+		b.WriteString("Previous summary:\n")
+		b.WriteString(previous)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("New turns:\n")
+	for _, m := range messages {
+		speaker := "Child"
+		if m.SenderType == model.SenderTypeAI {
+			speaker = "Teacher"
+		}
+		b.WriteString(speaker + ": " + m.MessageText + "\n")
+	}
+
+	summary, err := s.llmProvider.Chat(ctx, chatSummaryPrompt, b.String())
+	if err != nil {
+		return "", err
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", fmt.Errorf("llm returned empty summary")
+	}
+	return summary, nil
+}
+
+// trimHistoryByChars 从最早的消息开始裁剪，使历史原文总字符数不超过 maxChars
+// 裁剪后以用户消息开头，保证上下文按 user / assistant 交替
+func trimHistoryByChars(messages []*model.ConversationMessage, maxChars int) []*model.ConversationMessage {
+	if maxChars <= 0 {
+		return messages
+	}
+	total := 0
+	start := len(messages)
+	for start > 0 {
+		size := len([]rune(messages[start-1].MessageText))
+		if total+size > maxChars {
+			break
+		}
+		total += size
+		start--
+	}
+	for start < len(messages) && messages[start].SenderType != model.SenderTypeUser {
+		start++
+	}
+	return messages[start:]
+}
+
+// historyTurnRange 计算历史分页对应的轮次区间 [from, to]（轮次从 1 开始）
+// 每轮由用户消息（奇数序号）与 AI 回复（偶数序号）组成；desc 时第 1 页为最近的轮次
+func historyTurnRange(totalTurns, page, pageSize int, desc bool) (from, to int) {
+	offset := (page - 1) * pageSize
+	if desc {
+		to = totalTurns - offset
+		from = to - pageSize + 1
+	} else {
+		from = offset + 1
+		to = offset + pageSize
+	}
+	if from < 1 {
+		from = 1
+	}
+	if to > totalTurns {
+		to = totalTurns
+	}
+	return from, to
+}
+
+// toConversationTurns 将消息按轮次聚合（轮次 = (序号 + 1) / 2）
+func toConversationTurns(messages []*model.ConversationMessage, desc bool) []*ConversationTurn {
+	turns := make([]*ConversationTurn, 0, len(messages)/2+1)
+	byTurn := make(map[int]*ConversationTurn, len(messages)/2+1)
+	for _, m := range messages {
+		number := (m.SequenceNumber + 1) / 2
+		turn, ok := byTurn[number]
+		if !ok {
+			turn = &ConversationTurn{Turn: number, CreatedAt: m.CreatedAt.Format(time.RFC3339)}
+			byTurn[number] = turn
+			turns = append(turns, turn)
+		}
+		audioURL := ""
+		if m.AudioURL != nil {
+			audioURL = *m.AudioURL
+		}
+		if m.SenderType == model.SenderTypeAI {
+			turn.AIText = m.MessageText
+			turn.AIAudioURL = audioURL
+			turn.ReplyVersion = len(m.ReplyVersions) + 1
+			if m.Translation != nil {
+				turn.AITranslation = *m.Translation
+			}
+			if m.FailedStage != nil {
+				turn.FailedStage = *m.FailedStage
+			}
+		} else {
+			turn.UserText = m.MessageText
+			turn.UserAudioURL = audioURL
+			turn.Annotations = m.Annotations
+			turn.PronunciationScore = m.PronunciationScore
+			turn.ProblemWords = m.ProblemWords
+			if m.Language != nil {
+				turn.UserLanguage = *m.Language
+			}
+			turn.CreatedAt = m.CreatedAt.Format(time.RFC3339)
+		}
+	}
+	if desc {
+		for i, j := 0, len(turns)-1; i < j; i, j = i+1, j-1 {
+			turns[i], turns[j] = turns[j], turns[i]
+		}
+	}
+	return turns
+}
+
+// toSessionSummary 转换为会话摘要
+func toSessionSummary(c *model.VoiceConversation, last *model.ConversationMessage) *SessionSummary {
+	lastInteraction := c.CreatedAt
+	if c.LastMessageAt != nil {
+		lastInteraction = *c.LastMessageAt
+	}
+	summary := &SessionSummary{
+		SessionID:         c.ID,
+		Topic:             c.Topic,
+		Status:            c.Status,
+		CreatedAt:         c.CreatedAt.Format(time.RFC3339),
+		MessageCount:      c.MessageCount,
+		DurationSeconds:   c.DurationSeconds,
+		LastInteractionAt: lastInteraction.Format(time.RFC3339),
+		Score:             c.Score,
+		ProblemWords:      c.ProblemWords,
+	}
+	if c.PausedAt != nil {
+		summary.PausedAt = c.PausedAt.Format(time.RFC3339)
+	}
+	if c.Summary != nil {
+		summary.Summary = *c.Summary
+	}
+	if c.Feedback != nil {
+		summary.Feedback = *c.Feedback
+	}
+	if last != nil {
+		text := []rune(last.MessageText)
+		if len(text) > sessionLastMessageMaxRunes {
+			text = append(text[:sessionLastMessageMaxRunes], '…')
+		}
+		summary.LastMessage = string(text)
+	}
+	return summary
+}
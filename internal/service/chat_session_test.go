@@ -0,0 +1,55 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+func TestToConversationTurns(t *testing.T) {
+	created := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	stage := model.TurnFailedStageTTS
+	messages := []*model.ConversationMessage{
+		{SequenceNumber: 1, SenderType: model.SenderTypeUser, MessageText: "Hello", CreatedAt: created},
+		{SequenceNumber: 2, SenderType: model.SenderTypeAI, MessageText: "Hi there!", CreatedAt: created,
+			ReplyVersions: model.ReplyVersionList{{Text: "Hi!"}, {Text: "Hey!"}}},
+		{SequenceNumber: 3, SenderType: model.SenderTypeUser, MessageText: "I have a dog", CreatedAt: created.Add(time.Minute)},
+		{SequenceNumber: 4, SenderType: model.SenderTypeAI, MessageText: "What is its name?", FailedStage: &stage, CreatedAt: created.Add(time.Minute)},
+	}
+	tests := []struct {
+		name             string
+		desc             bool
+		wantTurns        []int
+		wantVersions     []int
+		wantFailedStages []string
+	}{
+		{"ascending", false, []int{1, 2}, []int{3, 1}, []string{"", model.TurnFailedStageTTS}},
+		{"descending", true, []int{2, 1}, []int{1, 3}, []string{model.TurnFailedStageTTS, ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			turns := toConversationTurns(messages, tt.desc)
+			if len(turns) != len(tt.wantTurns) {
+				t.Fatalf("got %d turns, want %d", len(turns), len(tt.wantTurns))
+			}
+			for i, turn := range turns {
+				if turn.Turn != tt.wantTurns[i] || turn.ReplyVersion != tt.wantVersions[i] || turn.FailedStage != tt.wantFailedStages[i] {
+					t.Errorf("turns[%d] = turn %d, version %d, failed stage %q; want turn %d, version %d, failed stage %q",
+						i, turn.Turn, turn.ReplyVersion, turn.FailedStage, tt.wantTurns[i], tt.wantVersions[i], tt.wantFailedStages[i])
+				}
+				if turn.UserText == "" || turn.AIText == "" {
+					t.Errorf("turns[%d] missing user or AI text: %+v", i, turn)
+				}
+			}
+		})
+	}
+}
+
+func TestRegenerateReplyPrompt(t *testing.T) {
+	prompt := regenerateReplyPrompt("Do you like cats?")
+	if want := "\"Do you like cats?\""; !strings.Contains(prompt, want) {
+		t.Errorf("regenerateReplyPrompt() = %q, want it to quote the previous reply", prompt)
+	}
+}
@@ -0,0 +1,495 @@
+// Package service 提供实时语音对话（WebSocket）业务逻辑
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"pronunciation-correction-system/internal/domain"
+	"pronunciation-correction-system/internal/model"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/sentence"
+)
+
+// 实时语音对话事件类型
+const (
+	ChatStreamEventASRPartial = "asr_partial" // ASR 中间结果
+	ChatStreamEventASRFinal   = "asr_final"   // ASR 最终结果（整句）
+	ChatStreamEventReplyDelta = "reply_delta" // AI 回复的文本增量
+	ChatStreamEventReplyText  = "reply_text"  // AI 回复的一个完整句子（语音对话随后推送该句音频）
+	ChatStreamEventAudio      = "audio"       // 当前句子的 TTS 音频块（mp3）
+	ChatStreamEventTTSEnd     = "tts_end"     // 当前句子音频推送完毕
+)
+
+// minSentenceRunes 句子过短时与下一句合并，减少 TTS 调用次数
+const minSentenceRunes = 12
+
+// ===== 请求结构 =====
+
+// ChatStreamRequest 实时语音对话单轮请求
+type ChatStreamRequest struct {
+	Audio            <-chan []byte // 麦克风音频块，调用方在说话结束后关闭（写入时需同时监听 ctx）
+	AudioFormat      string        // pcm / wav
+	SampleRate       int           // 采样率，默认 16000
+	SessionID        string        // 为空时新建会话
+	ConversationType string
+	DifficultyLevel  string
+	Translate        bool // 本轮是否要求中文释义（translate_on_demand 策略使用）
+	UserID           string
+	Debug            bool // 结果中附带各阶段耗时
+
+	// OnReplyStart 开始生成回复（调用 LLM）前的回调，可为 nil；调用方据此判断被打断的轮次是否已产生费用
+	OnReplyStart func()
+}
+
+// ===== 响应结构 =====
+
+// ChatStreamEvent 实时语音对话事件
+type ChatStreamEvent struct {
+	Type  string // 见 ChatStreamEvent* 常量
+	Text  string // ASR 文本、回复文本增量或回复句子
+	Index int    // 回复句子序号（从 0 开始）
+	Audio []byte // TTS 音频块（仅 audio 事件）
+}
+
+// ChatStreamEmitter 事件推送函数，返回错误时中止本轮处理（如连接已断开）
+type ChatStreamEmitter func(event *ChatStreamEvent) error
+
+func (s *chatServiceImpl) ChatStream(ctx context.Context, req *ChatStreamRequest, emit ChatStreamEmitter) (*ChatTurnResult, error) {
+	// ─── 1. 基础校验 ───
+	if req == nil || req.UserID == "" {
+		return nil, errors.New("chat stream request invalid")
+	}
+	if req.Audio == nil {
+		return nil, errors.New("audio stream is nil")
+	}
+	if s.asrProvider == nil || s.llmProvider == nil || s.ttsProvider == nil {
+		return nil, errors.New("chat stream provider not initialized")
+	}
+	audioFormat := strings.ToLower(strings.TrimSpace(req.AudioFormat))
+	if audioFormat == "" {
+		audioFormat = "pcm"
+	}
+	sampleRate := req.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	conversationType := strings.TrimSpace(req.ConversationType)
+	if conversationType == "" {
+		conversationType = "free_talk"
+	}
+	difficultyLevel := strings.TrimSpace(req.DifficultyLevel)
+	if difficultyLevel == "" {
+		difficultyLevel = "beginner"
+	}
+
+	// ─── 2. 解析会话（在调用付费服务前校验） ───
+	timer := newStageTimer()
+	maxMessages := s.maxConversationMessages(ctx)
+	conversation, err := s.resolveConversation(ctx, strings.TrimSpace(req.SessionID), req.UserID, conversationType, maxMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	// ─── 3. 实时 ASR：音频到达即识别，推送中间结果（从孩子说完开始计时） ───
+	userText, userAudio, duration, err := s.recognizeStreaming(ctx, req.Audio, audioFormat, sampleRate, timer, emit)
+	if err != nil {
+		return nil, err
+	}
+
+	// ─── 4. 识别本轮语言并读取语言策略、人设与长期记忆，审核孩子输入（拦截时不调用 LLM，使用兜底回复） ───
+	lang := s.resolveReplyLanguage(ctx, req.UserID, conversation, difficultyLevel, userText, req.Translate)
+	persona := s.resolvePersona(ctx, req.UserID)
+	memory := s.resolveLearnerMemory(ctx, req.UserID, conversation)
+	userText, moderation := s.moderateInput(ctx, conversation, userText)
+
+	// ─── 5. LLM 流式生成回复，凑满一句即开始 TTS，音频块产生即推送 ───
+	var replyAudio []byte
+	emitAudio := func(event *ChatStreamEvent) error {
+		if event.Type == ChatStreamEventAudio {
+			timer.mark(model.LatencyStageFirstAudio)
+		}
+		return emit(event)
+	}
+	onSentence := func(index int, text string) error {
+		ttsDone := timer.track(model.LatencyStageTTS)
+		audio, err := s.synthesizeStreaming(ctx, text, index, persona.synthesizeOptions(), emitAudio)
+		ttsDone()
+		if err != nil {
+			logger.ErrorContext(ctx, "chat stream tts failed", "index", index, "error", err)
+			return err
+		}
+		replyAudio = append(replyAudio, audio...)
+		return emit(&ChatStreamEvent{Type: ChatStreamEventTTSEnd, Index: index})
+	}
+	if req.OnReplyStart != nil {
+		req.OnReplyStart()
+	}
+	llmDone := timer.trackExcluding(model.LatencyStageLLM, model.LatencyStageTTS)
+	replyText, question, err := s.generateStreamReply(ctx, conversation, userText, lang, persona, memory, moderation, emit, onSentence)
+	llmDone()
+	if err != nil {
+		return nil, err
+	}
+
+	// ─── 6. 完整播放后才保存本轮（被打断的轮次不进入会话历史），中文释义随本轮结果返回 ───
+	scenarioGoal := s.startScenarioGoalCheck(ctx, conversation, userText, replyText)
+	return s.persistTurn(ctx, &chatTurn{
+		conversation:     conversation,
+		userID:           req.UserID,
+		conversationType: conversationType,
+		difficultyLevel:  difficultyLevel,
+		audioType:        audioFormat,
+		userAudio:        userAudio,
+		sampleRate:       sampleRate,
+		userText:         userText,
+		userLanguage:     lang.userLanguage,
+		replyText:        replyText,
+		replyTranslation: s.translateReply(ctx, lang, moderation, replyText),
+		replyAudio:       replyAudio,
+		durationSeconds:  duration,
+		maxMessages:      maxMessages,
+		question:         question,
+		moderation:       moderation,
+		learnerMemory:    memory,
+		latency:          timer,
+		scenarioGoal:     scenarioGoal,
+		debug:            req.Debug,
+	}), nil
+}
+
+func (s *chatServiceImpl) ChatTextStream(ctx context.Context, req *ChatMVPRequest, emit ChatStreamEmitter) (*ChatTurnResult, error) {
+	// ─── 1. 基础校验 ───
+	if req == nil || req.UserID == "" {
+		return nil, errors.New("chat text stream request invalid")
+	}
+	if len(req.AudioData) == 0 {
+		return nil, errors.New("audio data is empty")
+	}
+	if s.asrProvider == nil || s.llmProvider == nil {
+		return nil, errors.New("chat text stream provider not initialized")
+	}
+	audioType := strings.ToLower(strings.TrimSpace(req.AudioType))
+	if audioType == "" {
+		audioType = "wav"
+	}
+	conversationType := strings.TrimSpace(req.ConversationType)
+	if conversationType == "" {
+		conversationType = "free_talk"
+	}
+	difficultyLevel := strings.TrimSpace(req.DifficultyLevel)
+	if difficultyLevel == "" {
+		difficultyLevel = "beginner"
+	}
+
+	// ─── 2. 解析会话（在调用付费服务前校验） ───
+	timer := newStageTimer()
+	maxMessages := s.maxConversationMessages(ctx)
+	conversation, err := s.resolveConversation(ctx, strings.TrimSpace(req.SessionID), req.UserID, conversationType, maxMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	// ─── 3. ASR 识别 ───
+	asrDone := timer.track(model.LatencyStageASR)
+	asrResult, err := s.asrProvider.RecognizeAudio(ctx, req.AudioData, audioType, 16000)
+	asrDone()
+	if err != nil {
+		logger.ErrorContext(ctx, "chat text stream asr failed", "error", err)
+		return nil, err
+	}
+	userText := strings.TrimSpace(asrResult.Text)
+	if userText == "" {
+		return nil, errors.New("asr result is empty")
+	}
+	if err := emit(&ChatStreamEvent{Type: ChatStreamEventASRFinal, Text: userText}); err != nil {
+		return nil, err
+	}
+
+	// ─── 4. 识别本轮语言并读取语言策略、人设与长期记忆，审核孩子输入（拦截时不调用 LLM，使用兜底回复） ───
+	lang := s.resolveReplyLanguage(ctx, req.UserID, conversation, difficultyLevel, userText, req.Translate)
+	persona := s.resolvePersona(ctx, req.UserID)
+	memory := s.resolveLearnerMemory(ctx, req.UserID, conversation)
+	userText, moderation := s.moderateInput(ctx, conversation, userText)
+
+	// ─── 5. LLM 流式生成回复，推送文本增量与整句 ───
+	llmDone := timer.track(model.LatencyStageLLM)
+	replyText, question, err := s.generateStreamReply(ctx, conversation, userText, lang, persona, memory, moderation, emit, nil)
+	llmDone()
+	if err != nil {
+		return nil, err
+	}
+
+	// ─── 6. 保存本轮（不合成 AI 语音），中文释义随本轮结果返回 ───
+	scenarioGoal := s.startScenarioGoalCheck(ctx, conversation, userText, replyText)
+	return s.persistTurn(ctx, &chatTurn{
+		conversation:     conversation,
+		userID:           req.UserID,
+		conversationType: conversationType,
+		difficultyLevel:  difficultyLevel,
+		audioType:        audioType,
+		userAudio:        req.AudioData,
+		sampleRate:       16000,
+		userText:         userText,
+		userLanguage:     lang.userLanguage,
+		replyText:        replyText,
+		replyTranslation: s.translateReply(ctx, lang, moderation, replyText),
+		durationSeconds:  asrResult.Duration,
+		maxMessages:      maxMessages,
+		question:         question,
+		moderation:       moderation,
+		learnerMemory:    memory,
+		latency:          timer,
+		scenarioGoal:     scenarioGoal,
+		debug:            req.Debug,
+	}), nil
+}
+
+// recognizeStreaming 实时识别并推送中间结果，返回完整识别文本、本轮录音与音频时长（秒）
+// 音频块在转发给 ASR 的同时保留一份，用于识别完成后上传
+// 说话期间的识别与录音同时进行，timer 从音频流结束开始计时，asr 阶段为音频流结束到得到最终结果
+func (s *chatServiceImpl) recognizeStreaming(ctx context.Context, audio <-chan []byte, format string, sampleRate int, timer *stageTimer, emit ChatStreamEmitter) (string, []byte, int, error) {
+	// 提前返回时停止转发与识别
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var recorded []byte
+	var audioEnd time.Time
+	forward := make(chan []byte, 32)
+	forwardDone := make(chan struct{})
+	go func() {
+		defer close(forwardDone)
+		defer close(forward)
+		for {
+			select {
+			case chunk, ok := <-audio:
+				if !ok {
+					audioEnd = time.Now()
+					return
+				}
+				recorded = append(recorded, chunk...)
+				select {
+				case forward <- chunk:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	events, err := s.asrProvider.RecognizeLiveAudio(ctx, forward, format, sampleRate)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat stream asr failed", "error", err)
+		return "", nil, 0, err
+	}
+
+	var text strings.Builder
+	duration := 0
+	for event := range events {
+		if event.Error != nil {
+			logger.ErrorContext(ctx, "chat stream asr event failed", "error", event.Error)
+			return "", nil, 0, event.Error
+		}
+		switch event.Type {
+		case "partial":
+			// 中间结果 = 已确定的句子 + 当前句子的中间文本
+			if err := emit(&ChatStreamEvent{Type: ChatStreamEventASRPartial, Text: text.String() + event.Text}); err != nil {
+				return "", nil, 0, err
+			}
+		case "final":
+			text.WriteString(event.Text)
+			if event.Duration > 0 {
+				duration = event.Duration
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return "", nil, 0, err
+	}
+	// 识别在音频通道关闭后才会结束，此处等待转发协程退出后再读取录音
+	<-forwardDone
+	if !audioEnd.IsZero() {
+		timer.restartAt(audioEnd)
+		timer.add(model.LatencyStageASR, time.Since(audioEnd))
+	}
+
+	userText := strings.TrimSpace(text.String())
+	if userText == "" {
+		return "", nil, 0, errors.New("asr result is empty")
+	}
+	if duration == 0 && format == "pcm" && sampleRate > 0 {
+		duration = len(recorded) / (sampleRate * 2) // 16bit 单声道
+	}
+	if err := emit(&ChatStreamEvent{Type: ChatStreamEventASRFinal, Text: userText}); err != nil {
+		return "", nil, 0, err
+	}
+	return userText, recorded, duration, nil
+}
+
+// synthesizeStreaming 按合成参数（人设音色与语速，可为 nil）合成一个句子，音频块产生即推送，返回该句完整音频
+func (s *chatServiceImpl) synthesizeStreaming(ctx context.Context, sentence string, index int, options *domain.SynthesizeOptions, emit ChatStreamEmitter) ([]byte, error) {
+	audioChan := make(chan []byte, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.ttsProvider.SynthesizeStream(ctx, sentence, options, audioChan)
+	}()
+
+	var audio []byte
+	var emitErr error
+	for chunk := range audioChan {
+		if emitErr != nil {
+			continue // 推送失败后继续排空，等待合成方关闭 channel
+		}
+		audio = append(audio, chunk...)
+		emitErr = emit(&ChatStreamEvent{Type: ChatStreamEventAudio, Index: index, Audio: chunk})
+	}
+	if err := <-done; err != nil {
+		return nil, err
+	}
+	if emitErr != nil {
+		return nil, emitErr
+	}
+	return audio, nil
+}
+
+// generateStreamReply 生成本轮回复并推送：问答会话先判定回答再整段推送，其余会话结合历史流式生成
+// 孩子输入被拦截时不调用 LLM，整段推送兜底回复
+func (s *chatServiceImpl) generateStreamReply(ctx context.Context, conversation *model.VoiceConversation, userText string, lang *replyLanguage, persona *turnPersona, memory string, moderation *turnModeration, emit ChatStreamEmitter, onSentence func(index int, text string) error) (string, *questionTurn, error) {
+	if moderation.inputBlocked {
+		replyText := moderation.fallbackReply()
+		return replyText, nil, emitWholeReply(replyText, emit, onSentence)
+	}
+	if !isQuestionSession(conversation) {
+		chatMessages := s.buildChatContext(ctx, conversation, userText, lang, persona, memory, 0)
+		replyText, err := s.streamReply(ctx, chatMessages, moderation, emit, onSentence)
+		return replyText, nil, err
+	}
+
+	replyText, question, err := s.answerQuestion(ctx, conversation, userText)
+	if err != nil {
+		return "", nil, err
+	}
+	replyText, _ = s.moderateReply(ctx, moderation, replyText)
+	if err := emitWholeReply(replyText, emit, onSentence); err != nil {
+		return "", nil, err
+	}
+	return replyText, question, nil
+}
+
+// emitWholeReply 推送一段已生成的完整回复：一次文本增量，再按句推送 reply_text 并回调 onSentence（可为 nil）
+func emitWholeReply(replyText string, emit ChatStreamEmitter, onSentence func(index int, text string) error) error {
+	if err := emit(&ChatStreamEvent{Type: ChatStreamEventReplyDelta, Text: replyText}); err != nil {
+		return err
+	}
+	for index, text := range sentence.Split(replyText, minSentenceRunes) {
+		if err := emit(&ChatStreamEvent{Type: ChatStreamEventReplyText, Text: text, Index: index}); err != nil {
+			return err
+		}
+		if onSentence != nil {
+			if err := onSentence(index, text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// streamReply 流式生成回复：推送文本增量，每凑满一句推送 reply_text 并回调 onSentence（可为 nil）
+// 启用内容安全审核时按句审核后再推送（不推送未审核的文本增量），某句被拦截时推送兜底回复并停止生成
+// 返回完整回复文本；任一推送或回调失败时取消生成
+func (s *chatServiceImpl) streamReply(ctx context.Context, messages []domain.ChatMessage, moderation *turnModeration, emit ChatStreamEmitter, onSentence func(index int, text string) error) (string, error) {
+	// 提前返回时取消生成，释放 LLM 连接
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	deltas, err := s.llmProvider.ChatStream(ctx, messages)
+	if err != nil {
+		logger.ErrorContext(ctx, "chat stream llm failed", "error", err)
+		return "", err
+	}
+
+	var reply strings.Builder
+	var sentences []string
+	gated := s.moderationProvider != nil
+	blocked := false
+	chunker := sentence.NewChunker(minSentenceRunes)
+	index := 0
+	handleSentence := func(text string) error {
+		if gated {
+			text, blocked = s.moderateReply(ctx, moderation, text)
+			delta := text
+			if index > 0 {
+				delta = " " + text
+			}
+			if err := emit(&ChatStreamEvent{Type: ChatStreamEventReplyDelta, Text: delta}); err != nil {
+				return err
+			}
+		}
+		sentences = append(sentences, text)
+		if err := emit(&ChatStreamEvent{Type: ChatStreamEventReplyText, Text: text, Index: index}); err != nil {
+			return err
+		}
+		if onSentence != nil {
+			if err := onSentence(index, text); err != nil {
+				return err
+			}
+		}
+		index++
+		return nil
+	}
+
+generate:
+	for delta := range deltas {
+		if delta.Error != nil {
+			logger.ErrorContext(ctx, "chat stream llm failed", "error", delta.Error)
+			return "", delta.Error
+		}
+		if delta.Done {
+			if delta.FinishReason != "" && delta.FinishReason != "stop" {
+				logger.WarnContext(ctx, "chat stream llm finished abnormally", "finish_reason", delta.FinishReason)
+			}
+			if delta.Usage != nil {
+				logger.InfoContext(ctx, "chat stream llm usage", "total_tokens", delta.Usage.TotalTokens)
+			}
+			break
+		}
+		reply.WriteString(delta.Content)
+		if !gated {
+			if err := emit(&ChatStreamEvent{Type: ChatStreamEventReplyDelta, Text: delta.Content}); err != nil {
+				return "", err
+			}
+		}
+		for _, text := range chunker.Push(delta.Content) {
+			if err := handleSentence(text); err != nil {
+				return "", err
+			}
+			if blocked {
+				logger.WarnContext(ctx, "chat stream reply blocked by moderation, stop generating", "sentences", len(sentences))
+				break generate
+			}
+		}
+	}
+	// 通道因 ctx 取消而提前关闭
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if rest := chunker.Flush(); rest != "" && !blocked {
+		if err := handleSentence(rest); err != nil {
+			return "", err
+		}
+	}
+
+	// 启用审核时保存实际推送的句子，而非 LLM 原文
+	replyText := strings.TrimSpace(reply.String())
+	if gated {
+		replyText = strings.TrimSpace(strings.Join(sentences, " "))
+	}
+	if replyText == "" {
+		return "", errors.New("llm reply is empty")
+	}
+	return replyText, nil
+}
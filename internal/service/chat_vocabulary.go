@@ -0,0 +1,35 @@
+// Package service 提供对话词汇统计（孩子说出的单词累计到个人词汇表）
+package service
+
+import (
+	"context"
+	"time"
+
+	"pronunciation-correction-system/internal/model"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// vocabularyRecordTimeout 单条消息词汇统计超时（与请求生命周期无关）
+const vocabularyRecordTimeout = 10 * time.Second
+
+// recordVocabularyAsync 在后台将已保存的用户消息累计到个人词汇表，不阻塞本轮回复
+// 输入被审核拦截或打码改写时不统计（与发音评测一致：个人信息占位符如 [phone] 会被分词为普通单词）
+func (s *chatServiceImpl) recordVocabularyAsync(ctx context.Context, userID string, message *model.ConversationMessage, t *chatTurn) {
+	if s.vocabularyService == nil || !containsLatinLetter(message.MessageText) {
+		return
+	}
+	if t.moderation != nil && (t.moderation.inputBlocked || t.moderation.inputRewritten) {
+		return
+	}
+
+	// 脱离请求生命周期在后台执行（HTTP 响应返回或 WebSocket 轮次结束后继续）
+	runBackground(ctx, "vocabulary record", vocabularyRecordTimeout, func(ctx context.Context) {
+		if err := s.vocabularyService.RecordText(ctx, &RecordVocabularyRequest{
+			UserID: userID,
+			Text:   message.MessageText,
+			Source: model.VocabularySourceConversation,
+		}); err != nil {
+			logger.WarnContext(ctx, "chat record vocabulary failed", "message_id", message.ID, "error", err)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+// Package service 提供单词示范音频、场景与题集开场白音频生成辅助
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pronunciation-correction-system/internal/domain"
+	"pronunciation-correction-system/internal/model"
+)
+
+// wordDemoAudioKey 单词示范音频的 OSS 路径（按单词共享，避免重复合成）
+func wordDemoAudioKey(word string) string {
+	return fmt.Sprintf("demo/words/%s.mp3", word)
+}
+
+// ensureWordDemoAudio 获取单词示范音频 URL
+// OSS 已存在时直接返回公开 URL，否则调用 TTS 合成后上传
+func ensureWordDemoAudio(ctx context.Context, tts domain.TTSProvider, oss domain.OSSProvider, word string) (string, error) {
+	if tts == nil || oss == nil {
+		return "", errors.New("tts or oss provider not initialized")
+	}
+
+	key := wordDemoAudioKey(word)
+	if exists, err := oss.FileExists(ctx, key); err == nil && exists {
+		return oss.GetPublicURL(key), nil
+	}
+
+	audio, err := tts.Synthesize(ctx, word, nil)
+	if err != nil {
+		return "", fmt.Errorf("tts synthesize word failed: %w", err)
+	}
+
+	url, err := oss.UploadAudio(ctx, key, audio)
+	if err != nil {
+		return "", fmt.Errorf("upload word demo audio failed: %w", err)
+	}
+	return url, nil
+}
+
+// scenarioOpeningAudioKey 场景开场白音频的 OSS 路径（包含更新时间，修改开场白后重新合成）
+func scenarioOpeningAudioKey(scenario *model.ConversationScenario) string {
+	return fmt.Sprintf("demo/scenarios/%s_%d.mp3", scenario.Code, scenario.UpdatedAt.Unix())
+}
+
+// ensureScenarioOpeningAudio 获取场景开场白音频 URL
+// OSS 已存在时直接返回公开 URL，否则调用 TTS 合成后上传
+func ensureScenarioOpeningAudio(ctx context.Context, tts domain.TTSProvider, oss domain.OSSProvider, scenario *model.ConversationScenario) (string, error) {
+	if tts == nil || oss == nil {
+		return "", errors.New("tts or oss provider not initialized")
+	}
+
+	key := scenarioOpeningAudioKey(scenario)
+	if exists, err := oss.FileExists(ctx, key); err == nil && exists {
+		return oss.GetPublicURL(key), nil
+	}
+
+	audio, err := tts.Synthesize(ctx, scenario.OpeningLine, nil)
+	if err != nil {
+		return "", fmt.Errorf("tts synthesize scenario opening failed: %w", err)
+	}
+
+	url, err := oss.UploadAudio(ctx, key, audio)
+	if err != nil {
+		return "", fmt.Errorf("upload scenario opening audio failed: %w", err)
+	}
+	return url, nil
+}
+
+// questionSetOpeningAudioKey 题集开场白音频的 OSS 路径（包含更新时间，修改题目后重新合成）
+func questionSetOpeningAudioKey(set *model.QuestionSet) string {
+	return fmt.Sprintf("demo/question_sets/%s_%d.mp3", set.Code, set.UpdatedAt.Unix())
+}
+
+// ensureQuestionSetOpeningAudio 获取题集开场白（引导语 + 第一个问题）音频 URL
+// OSS 已存在时直接返回公开 URL，否则调用 TTS 合成后上传
+func ensureQuestionSetOpeningAudio(ctx context.Context, tts domain.TTSProvider, oss domain.OSSProvider, set *model.QuestionSet, openingLine string) (string, error) {
+	if tts == nil || oss == nil {
+		return "", errors.New("tts or oss provider not initialized")
+	}
+
+	key := questionSetOpeningAudioKey(set)
+	if exists, err := oss.FileExists(ctx, key); err == nil && exists {
+		return oss.GetPublicURL(key), nil
+	}
+
+	audio, err := tts.Synthesize(ctx, openingLine, nil)
+	if err != nil {
+		return "", fmt.Errorf("tts synthesize question set opening failed: %w", err)
+	}
+
+	url, err := oss.UploadAudio(ctx, key, audio)
+	if err != nil {
+		return "", fmt.Errorf("upload question set opening audio failed: %w", err)
+	}
+	return url, nil
+}
@@ -0,0 +1,341 @@
+// Package service 提供同一文本多次尝试的对比逻辑
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+)
+
+// 对比模式
+const (
+	CompareModeExplicit     = "explicit"       // 指定两条评测记录
+	CompareModeLatestVsBest = "latest_vs_best" // 同一文本此前的最佳记录 vs 最近一次记录
+)
+
+// ===== 请求结构 =====
+
+// CompareEvaluationsRequest 评测对比请求
+// 指定 FromEvalID + ToEvalID 时对比两条记录；只指定 TextID 时对比该文本的最佳记录与最近记录
+type CompareEvaluationsRequest struct {
+	UserID     string
+	FromEvalID string // 基准记录（较早/较好的一次）
+	ToEvalID   string // 对比记录（较新的一次）
+	TextID     string // latest_vs_best 模式使用
+}
+
+// ===== 响应结构 =====
+
+// EvaluationComparisonResponse 评测对比结果
+type EvaluationComparisonResponse struct {
+	Mode          string            `json:"mode"`
+	TextID        string            `json:"text_id"`
+	ReferenceText string            `json:"reference_text"`
+	From          *ComparedAttempt  `json:"from"`
+	To            *ComparedAttempt  `json:"to"`
+	SameAttempt   bool              `json:"same_attempt"` // 只有一次尝试（没有更早的记录可对比）
+	ScoreDeltas   *ScoreDeltas      `json:"score_deltas"`
+	Improved      bool              `json:"improved"` // 总分是否提升
+	WordDeltas    []*WordScoreDelta `json:"word_deltas"`
+	// WordLevelAvailable 两条记录均有单词级结果时为 true；
+	// 历史记录缺少单词级结果时仅能基于 problem_words 对比问题单词
+	WordLevelAvailable bool     `json:"word_level_available"`
+	FixedWords         []string `json:"fixed_words"`       // 已改善：基准中有问题，对比记录中无问题
+	NewProblemWords    []string `json:"new_problem_words"` // 新问题：基准中无问题，对比记录中有问题
+	StillProblemWords  []string `json:"still_problem_words"`
+}
+
+// ComparedAttempt 参与对比的单次尝试
+type ComparedAttempt struct {
+	EvalID       string      `json:"eval_id"`
+	OverallScore float64     `json:"overall_score"`
+	Scores       *EvalScores `json:"scores"`
+	ProblemWords []string    `json:"problem_words"`
+	CreatedAt    string      `json:"created_at"`
+}
+
+// ScoreDeltas 各维度得分变化（To - From）
+type ScoreDeltas struct {
+	Overall       float64 `json:"overall"`
+	Pronunciation float64 `json:"pronunciation"`
+	Fluency       float64 `json:"fluency"`
+	Integrity     float64 `json:"integrity"`
+}
+
+// WordScoreDelta 单词得分变化
+// 某次尝试缺少该单词（漏读或文本不一致）时对应得分为 nil
+type WordScoreDelta struct {
+	Index     int      `json:"index"`
+	Word      string   `json:"word"`
+	FromScore *float64 `json:"from_score"`
+	ToScore   *float64 `json:"to_score"`
+	Delta     *float64 `json:"delta"`
+	Status    string   `json:"status"` // fixed / new_problem / still_problem / ok / missing
+}
+
+// 单词对比状态
+const (
+	wordStatusFixed        = "fixed"
+	wordStatusNewProblem   = "new_problem"
+	wordStatusStillProblem = "still_problem"
+	wordStatusOK           = "ok"
+	wordStatusMissing      = "missing" // 仅出现在基准记录中
+)
+
+// CompareEvaluations 对比同一文本的两次尝试
+func (s *evaluateServiceImpl) CompareEvaluations(ctx context.Context, req *CompareEvaluationsRequest) (*EvaluationComparisonResponse, error) {
+	if req == nil || req.UserID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+
+	// ─── 1. 加载参与对比的两条记录 ───
+	var (
+		from, to *model.PronunciationEvaluation
+		mode     string
+		err      error
+	)
+	switch {
+	case req.FromEvalID != "" || req.ToEvalID != "":
+		if req.FromEvalID == "" || req.ToEvalID == "" {
+			return nil, apperr.ErrInvalidParam.WithMessage("from_id and to_id must be provided together")
+		}
+		mode = CompareModeExplicit
+		if from, err = s.getOwnedEvaluation(ctx, req.FromEvalID, req.UserID); err != nil {
+			return nil, err
+		}
+		if to, err = s.getOwnedEvaluation(ctx, req.ToEvalID, req.UserID); err != nil {
+			return nil, err
+		}
+		if from.TargetText != to.TargetText {
+			return nil, apperr.ErrInvalidParam.WithMessage("evaluations must be on the same text")
+		}
+	case req.TextID != "":
+		mode = CompareModeLatestVsBest
+		if from, to, err = s.getBestAndLatestEvaluation(ctx, req.UserID, req.TextID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, apperr.ErrInvalidParam.WithMessage("from_id and to_id, or text_id is required")
+	}
+
+	// ─── 2. 计算各维度得分变化 ───
+	resp := &EvaluationComparisonResponse{
+		Mode:          mode,
+		TextID:        textIDForTarget(to.TargetText),
+		ReferenceText: to.TargetText,
+		From:          toComparedAttempt(from),
+		To:            toComparedAttempt(to),
+		SameAttempt:   from.ID == to.ID,
+		ScoreDeltas: &ScoreDeltas{
+			Overall:       float64(to.OverallScore - from.OverallScore),
+			Pronunciation: float64(to.AccuracyScore - from.AccuracyScore),
+			Fluency:       float64(to.FluencyScore - from.FluencyScore),
+			Integrity:     float64(to.IntegrityScore - from.IntegrityScore),
+		},
+		Improved: to.OverallScore > from.OverallScore,
+	}
+
+	// ─── 3. 计算单词得分变化与问题单词变化 ───
+	resp.WordLevelAvailable = len(from.WordScores) > 0 && len(to.WordScores) > 0
+	if resp.WordLevelAvailable {
+		resp.WordDeltas = compareWordScores(from.WordScores, to.WordScores)
+	} else {
+		resp.WordDeltas = []*WordScoreDelta{}
+	}
+	resp.FixedWords, resp.NewProblemWords, resp.StillProblemWords = compareProblemWords(
+		problemWordsOf(from), problemWordsOf(to),
+	)
+
+	return resp, nil
+}
+
+// getBestAndLatestEvaluation 查询用户在某文本上的最近记录与此前的最佳记录
+// 最佳记录从最近一次之前的尝试中选取，否则最佳总分不低于最近一次，无法体现进步；
+// 只有一次尝试时两者为同一条记录（SameAttempt）
+func (s *evaluateServiceImpl) getBestAndLatestEvaluation(ctx context.Context, userID, textID string) (best, latest *model.PronunciationEvaluation, err error) {
+	targetText, ok := textIDMap[textID]
+	if !ok {
+		return nil, nil, apperr.ErrInvalidParam.WithMessage("unknown text_id: " + textID)
+	}
+
+	latests, _, err := s.repos.PronunciationEvaluation.Query(ctx, &db.EvaluationQuery{
+		UserID: userID, TargetText: targetText, OrderBy: "created_at", Order: "desc",
+	}, 1, 1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query latest evaluation failed: %w", err)
+	}
+	if len(latests) == 0 {
+		return nil, nil, apperr.ErrEvaluationNotFound.WithMessage("no evaluation found for text_id: " + textID)
+	}
+
+	bests, _, err := s.repos.PronunciationEvaluation.Query(ctx, &db.EvaluationQuery{
+		UserID: userID, TargetText: targetText, ExcludeID: latests[0].ID, OrderBy: "score", Order: "desc",
+	}, 1, 1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query best evaluation failed: %w", err)
+	}
+	if len(bests) == 0 {
+		return latests[0], latests[0], nil
+	}
+
+	return bests[0], latests[0], nil
+}
+
+// compareWordScores 逐词对比
+// 按"单词 + 第 N 次出现"对齐，文本中重复出现的单词也能一一对应
+func compareWordScores(from, to model.WordScoreList) []*WordScoreDelta {
+	fromByKey := keyWordScores(from)
+
+	deltas := make([]*WordScoreDelta, 0, len(to))
+	matched := make(map[string]bool, len(to))
+	for _, w := range keyWordScoresOrdered(to) {
+		toScore := w.word.Score
+		delta := &WordScoreDelta{Index: w.word.Index, Word: w.word.Word, ToScore: &toScore}
+		if f, ok := fromByKey[w.key]; ok {
+			fromScore := f.Score
+			diff := roundScore(toScore - fromScore)
+			delta.FromScore = &fromScore
+			delta.Delta = &diff
+			delta.Status = wordDeltaStatus(f.IsProblem, w.word.IsProblem)
+			matched[w.key] = true
+		} else {
+			delta.Status = wordDeltaStatus(false, w.word.IsProblem)
+		}
+		deltas = append(deltas, delta)
+	}
+
+	// 基准中存在、对比记录中缺失的单词（文本不一致时）追加在末尾
+	for _, w := range keyWordScoresOrdered(from) {
+		if matched[w.key] {
+			continue
+		}
+		fromScore := w.word.Score
+		deltas = append(deltas, &WordScoreDelta{
+			Index:     w.word.Index,
+			Word:      w.word.Word,
+			FromScore: &fromScore,
+			Status:    wordStatusMissing,
+		})
+	}
+	return deltas
+}
+
+// keyedWordScore 带对齐键的单词结果
+type keyedWordScore struct {
+	key  string
+	word model.WordScore
+}
+
+// keyWordScores 生成对齐键到单词结果的映射
+func keyWordScores(list model.WordScoreList) map[string]model.WordScore {
+	keyed := make(map[string]model.WordScore, len(list))
+	for _, w := range keyWordScoresOrdered(list) {
+		keyed[w.key] = w.word
+	}
+	return keyed
+}
+
+// keyWordScoresOrdered 按原顺序生成对齐键（归一化单词 + "#" + 出现序号）
+func keyWordScoresOrdered(list model.WordScoreList) []keyedWordScore {
+	seen := make(map[string]int, len(list))
+	keyed := make([]keyedWordScore, 0, len(list))
+	for _, w := range list {
+		norm := normalizeReviewWord(w.Word)
+		if norm == "" {
+			continue
+		}
+		keyed = append(keyed, keyedWordScore{key: norm + "#" + strconv.Itoa(seen[norm]), word: w})
+		seen[norm]++
+	}
+	return keyed
+}
+
+// wordDeltaStatus 根据前后两次是否为问题单词判断状态
+func wordDeltaStatus(fromProblem, toProblem bool) string {
+	switch {
+	case fromProblem && !toProblem:
+		return wordStatusFixed
+	case !fromProblem && toProblem:
+		return wordStatusNewProblem
+	case fromProblem && toProblem:
+		return wordStatusStillProblem
+	default:
+		return wordStatusOK
+	}
+}
+
+// compareProblemWords 对比前后两次的问题单词集合（归一化后去重，保持原顺序）
+func compareProblemWords(from, to []string) (fixed, added, still []string) {
+	fromSet := make(map[string]bool, len(from))
+	for _, w := range from {
+		fromSet[w] = true
+	}
+	toSet := make(map[string]bool, len(to))
+	for _, w := range to {
+		toSet[w] = true
+	}
+
+	fixed, added, still = []string{}, []string{}, []string{}
+	for _, w := range from {
+		if !toSet[w] {
+			fixed = append(fixed, w)
+		}
+	}
+	for _, w := range to {
+		if fromSet[w] {
+			still = append(still, w)
+		} else {
+			added = append(added, w)
+		}
+	}
+	return fixed, added, still
+}
+
+// problemWordsOf 提取问题单词（优先使用单词级结果，历史记录回退到 problem_words）
+func problemWordsOf(e *model.PronunciationEvaluation) []string {
+	seen := make(map[string]bool)
+	words := make([]string, 0)
+	add := func(word string) {
+		norm := normalizeReviewWord(word)
+		if norm == "" || seen[norm] {
+			return
+		}
+		seen[norm] = true
+		words = append(words, norm)
+	}
+
+	if len(e.WordScores) > 0 {
+		for _, w := range e.WordScores {
+			if w.IsProblem {
+				add(w.Word)
+			}
+		}
+		return words
+	}
+	for _, w := range e.ProblemWords {
+		add(w)
+	}
+	return words
+}
+
+// toComparedAttempt 转换为对比中的单次尝试
+func toComparedAttempt(e *model.PronunciationEvaluation) *ComparedAttempt {
+	return &ComparedAttempt{
+		EvalID:       e.ID,
+		OverallScore: float64(e.OverallScore),
+		Scores:       toEvalScores(e),
+		ProblemWords: problemWordsOf(e),
+		CreatedAt:    e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// roundScore 得分保留一位小数
+func roundScore(v float64) float64 {
+	return math.Round(v*10) / 10
+}
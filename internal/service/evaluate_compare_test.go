@@ -0,0 +1,75 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+func TestCompareWordScores(t *testing.T) {
+	from := model.WordScoreList{
+		{Index: 0, Word: "The", Score: 90},
+		{Index: 1, Word: "cat", Score: 40, IsProblem: true},
+		{Index: 2, Word: "sat", Score: 85},
+		{Index: 3, Word: "the", Score: 50, IsProblem: true},
+		{Index: 4, Word: "mat", Score: 70},
+	}
+	to := model.WordScoreList{
+		{Index: 0, Word: "the", Score: 92},
+		{Index: 1, Word: "cat", Score: 80},
+		{Index: 2, Word: "sat", Score: 45, IsProblem: true},
+		{Index: 3, Word: "the", Score: 48, IsProblem: true},
+	}
+
+	deltas := compareWordScores(from, to)
+
+	want := []struct {
+		word   string
+		status string
+		delta  *float64
+	}{
+		{"the", wordStatusOK, floatPtr(2)},
+		{"cat", wordStatusFixed, floatPtr(40)},
+		{"sat", wordStatusNewProblem, floatPtr(-40)},
+		{"the", wordStatusStillProblem, floatPtr(-2)},
+		{"mat", wordStatusMissing, nil},
+	}
+	if len(deltas) != len(want) {
+		t.Fatalf("got %d deltas, want %d", len(deltas), len(want))
+	}
+	for i, w := range want {
+		d := deltas[i]
+		if normalizeReviewWord(d.Word) != w.word || d.Status != w.status {
+			t.Errorf("delta %d = %s/%s, want %s/%s", i, d.Word, d.Status, w.word, w.status)
+		}
+		if (d.Delta == nil) != (w.delta == nil) || (d.Delta != nil && *d.Delta != *w.delta) {
+			t.Errorf("delta %d (%s) score delta = %v, want %v", i, w.word, d.Delta, w.delta)
+		}
+	}
+}
+
+func TestCompareProblemWords(t *testing.T) {
+	tests := []struct {
+		name                string
+		from, to            []string
+		fixed, added, still []string
+	}{
+		{"empty", nil, nil, []string{}, []string{}, []string{}},
+		{"all fixed", []string{"cat", "mat"}, nil, []string{"cat", "mat"}, []string{}, []string{}},
+		{"all new", nil, []string{"sat"}, []string{}, []string{"sat"}, []string{}},
+		{"mixed", []string{"cat", "the"}, []string{"the", "sat"}, []string{"cat"}, []string{"sat"}, []string{"the"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixed, added, still := compareProblemWords(tt.from, tt.to)
+			if !reflect.DeepEqual(fixed, tt.fixed) || !reflect.DeepEqual(added, tt.added) || !reflect.DeepEqual(still, tt.still) {
+				t.Errorf("compareProblemWords = %v, %v, %v; want %v, %v, %v", fixed, added, still, tt.fixed, tt.added, tt.still)
+			}
+		})
+	}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
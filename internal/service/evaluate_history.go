@@ -0,0 +1,225 @@
+// Package service 提供评测历史查询与导出辅助逻辑
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+)
+
+// 导出格式
+const (
+	ExportFormatCSV   = "csv"   // 逗号分隔（含表头）
+	ExportFormatJSONL = "jsonl" // 每行一个 JSON 对象
+)
+
+// exportBatchSize 导出时每批查询条数
+const exportBatchSize = 200
+
+// historyDateLayout 历史查询日期格式
+const historyDateLayout = "2006-01-02"
+
+// evaluationExportHeader CSV 表头（与 evaluationExportRow.csvRecord 顺序一致）
+var evaluationExportHeader = []string{
+	"eval_id", "created_at", "text_id", "target_text", "overall_score",
+	"accuracy_score", "fluency_score", "integrity_score", "feedback_level",
+	"problem_words", "status",
+}
+
+// evaluationExportRow 导出行（JSON lines 直接序列化）
+type evaluationExportRow struct {
+	EvalID         string   `json:"eval_id"`
+	CreatedAt      string   `json:"created_at"`
+	TextID         string   `json:"text_id"`
+	TargetText     string   `json:"target_text"`
+	OverallScore   int      `json:"overall_score"`
+	AccuracyScore  int      `json:"accuracy_score"`
+	FluencyScore   int      `json:"fluency_score"`
+	IntegrityScore int      `json:"integrity_score"`
+	FeedbackLevel  string   `json:"feedback_level"`
+	ProblemWords   []string `json:"problem_words"`
+	Status         string   `json:"status"`
+}
+
+// csvRecord 转换为 CSV 记录（问题单词以分号连接，文本单元格做公式转义）
+func (r *evaluationExportRow) csvRecord() []string {
+	return []string{
+		csvSafeCell(r.EvalID), csvSafeCell(r.CreatedAt), csvSafeCell(r.TextID), csvSafeCell(r.TargetText), strconv.Itoa(r.OverallScore),
+		strconv.Itoa(r.AccuracyScore), strconv.Itoa(r.FluencyScore), strconv.Itoa(r.IntegrityScore), csvSafeCell(r.FeedbackLevel),
+		csvSafeCell(strings.Join(r.ProblemWords, ";")), csvSafeCell(r.Status),
+	}
+}
+
+// csvSafeCell 防止 CSV 公式注入：以 = + - @ 制表符或回车开头的单元格加单引号前缀，表格软件按文本显示
+func csvSafeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// buildEvaluationQuery 将历史查询请求转换为 Repository 查询条件
+// text_id 通过文本映射转换为目标文本；date_to 为包含当天的闭区间
+func buildEvaluationQuery(req *EvalHistoryRequest) (*db.EvaluationQuery, error) {
+	if req == nil || req.UserID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+
+	query := &db.EvaluationQuery{
+		UserID:  req.UserID,
+		OrderBy: req.OrderBy,
+		Order:   req.Order,
+	}
+	if req.OrderBy != "" && req.OrderBy != "created_at" && req.OrderBy != "score" {
+		return nil, apperr.ErrInvalidParam.WithMessage("order_by must be created_at or score")
+	}
+	if req.Order != "" && req.Order != "asc" && req.Order != "desc" {
+		return nil, apperr.ErrInvalidParam.WithMessage("order must be asc or desc")
+	}
+
+	if req.TextID != "" {
+		targetText, ok := textIDMap[req.TextID]
+		if !ok {
+			return nil, apperr.ErrInvalidParam.WithMessage("unknown text_id: " + req.TextID)
+		}
+		query.TargetText = targetText
+	}
+
+	if req.DateFrom != "" {
+		start, err := time.ParseInLocation(historyDateLayout, req.DateFrom, time.Local)
+		if err != nil {
+			return nil, apperr.ErrInvalidParam.WithMessage("date_from must be YYYY-MM-DD")
+		}
+		query.Start = &start
+	}
+	if req.DateTo != "" {
+		end, err := time.ParseInLocation(historyDateLayout, req.DateTo, time.Local)
+		if err != nil {
+			return nil, apperr.ErrInvalidParam.WithMessage("date_to must be YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		query.End = &end
+	}
+	if query.Start != nil && query.End != nil && !query.Start.Before(*query.End) {
+		return nil, apperr.ErrInvalidParam.WithMessage("date_from must not be after date_to")
+	}
+
+	return query, nil
+}
+
+// textIDForTarget 根据目标文本反查文本 ID（未找到时返回空字符串）
+func textIDForTarget(targetText string) string {
+	for id, text := range textIDMap {
+		if text == targetText {
+			return id
+		}
+	}
+	return ""
+}
+
+// toEvalSummary 转换为评测摘要
+func toEvalSummary(e *model.PronunciationEvaluation) *EvalSummary {
+	return &EvalSummary{
+		EvalID:        e.ID,
+		TextID:        textIDForTarget(e.TargetText),
+		ReferenceText: e.TargetText,
+		OverallScore:  float64(e.OverallScore),
+		Scores:        toEvalScores(e),
+		CreatedAt:     e.CreatedAt.Format(time.RFC3339),
+		Status:        e.Status,
+	}
+}
+
+// toEvaluationResult 转换为评测完整结果
+func toEvaluationResult(e *model.PronunciationEvaluation) *EvaluationResultResponse {
+	resp := &EvaluationResultResponse{
+		EvalID:        e.ID,
+		Status:        e.Status,
+		TextID:        textIDForTarget(e.TargetText),
+		ReferenceText: e.TargetText,
+		OverallScore:  float64(e.OverallScore),
+		Scores:        toEvalScores(e),
+		ProblemWords:  []string(e.ProblemWords),
+		WordDetails:   toWordDetails(e.WordScores),
+		DetailedFeedback: &DetailedFeedback{
+			Strengths:    []string{},
+			Improvements: []string{},
+			Suggestions:  []string{},
+		},
+		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+	}
+	if e.AudioDuration != nil {
+		resp.DurationMs = *e.AudioDuration * 1000
+	}
+	resp.DetailedFeedback.Improvements = append(resp.DetailedFeedback.Improvements, e.ProblemWords...)
+	if e.FeedbackText != nil && *e.FeedbackText != "" {
+		resp.DetailedFeedback.Suggestions = append(resp.DetailedFeedback.Suggestions, *e.FeedbackText)
+	}
+	if e.DemoSentenceAudioURL != nil {
+		resp.ReferenceAudio = *e.DemoSentenceAudioURL
+	}
+	return resp
+}
+
+// toWordScoreList 将单词详情转换为持久化的单词级结果
+func toWordScoreList(details []WordDetail) model.WordScoreList {
+	if len(details) == 0 {
+		return nil
+	}
+	list := make(model.WordScoreList, 0, len(details))
+	for i, d := range details {
+		list = append(list, model.WordScore{
+			Index:     i,
+			Word:      d.Word,
+			Score:     d.Score,
+			IsProblem: d.IsProblem,
+		})
+	}
+	return list
+}
+
+// toWordDetails 将持久化的单词级结果转换为单词详情（历史记录无数据时返回 nil）
+func toWordDetails(list model.WordScoreList) []WordDetail {
+	if len(list) == 0 {
+		return nil
+	}
+	details := make([]WordDetail, 0, len(list))
+	for _, w := range list {
+		details = append(details, WordDetail{Word: w.Word, Score: w.Score, IsProblem: w.IsProblem})
+	}
+	return details
+}
+
+// toEvalScores 转换为分项得分
+func toEvalScores(e *model.PronunciationEvaluation) *EvalScores {
+	return &EvalScores{
+		Pronunciation: float64(e.AccuracyScore),
+		Fluency:       float64(e.FluencyScore),
+		Integrity:     float64(e.IntegrityScore),
+	}
+}
+
+// toEvaluationExportRow 转换为导出行
+func toEvaluationExportRow(e *model.PronunciationEvaluation) *evaluationExportRow {
+	problemWords := []string(e.ProblemWords)
+	if problemWords == nil {
+		problemWords = []string{}
+	}
+	return &evaluationExportRow{
+		EvalID:         e.ID,
+		CreatedAt:      e.CreatedAt.Format(time.RFC3339),
+		TextID:         textIDForTarget(e.TargetText),
+		TargetText:     e.TargetText,
+		OverallScore:   e.OverallScore,
+		AccuracyScore:  e.AccuracyScore,
+		FluencyScore:   e.FluencyScore,
+		IntegrityScore: e.IntegrityScore,
+		FeedbackLevel:  e.FeedbackLevel,
+		ProblemWords:   problemWords,
+		Status:         e.Status,
+	}
+}
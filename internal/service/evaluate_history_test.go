@@ -0,0 +1,47 @@
+package service
+
+import "testing"
+
+func TestCSVSafeCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"I like apples.", "I like apples."},
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+1", "'+1"},
+		{"-1+2", "'-1+2"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"\rcmd", "'\rcmd"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := csvSafeCell(tt.value); got != tt.want {
+			t.Errorf("csvSafeCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluationExportRowCSVRecordEscapesText(t *testing.T) {
+	row := &evaluationExportRow{
+		EvalID:       "e1",
+		TargetText:   "=cmd|' /C calc'!A0",
+		OverallScore: -1,
+		ProblemWords: []string{"@word", "apple"},
+	}
+	record := row.csvRecord()
+	if len(record) != len(evaluationExportHeader) {
+		t.Fatalf("record has %d cells, header has %d", len(record), len(evaluationExportHeader))
+	}
+	if record[3] != "'=cmd|' /C calc'!A0" {
+		t.Errorf("target_text = %q, want escaped", record[3])
+	}
+	if record[4] != "-1" {
+		t.Errorf("overall_score = %q, numeric cells must not be escaped", record[4])
+	}
+	if record[9] != "'@word;apple" {
+		t.Errorf("problem_words = %q, want escaped", record[9])
+	}
+}
@@ -0,0 +1,462 @@
+// Package service 提供最小对立词（minimal pair）练习业务逻辑
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+
+	"pronunciation-correction-system/internal/domain"
+	llmPrompts "pronunciation-correction-system/internal/infrastructure/llm"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// ExerciseTypeMinimalPair 最小对立词练习类型
+const ExerciseTypeMinimalPair = "minimal_pair"
+
+// ===== 请求结构 =====
+
+// MinimalPairExercisesRequest 获取最小对立词练习请求
+type MinimalPairExercisesRequest struct {
+	UserID     string
+	ContrastID string // 指定对立组（可选，为空时根据学习者混淆自动选择）
+	Limit      int
+	Expand     bool // 是否使用 LLM 扩展词对
+}
+
+// SubmitMinimalPairRequest 提交最小对立词练习请求
+type SubmitMinimalPairRequest struct {
+	ContrastID string
+	WordA      string
+	WordB      string
+	Target     string // 要求学习者读出的单词（WordA 或 WordB）
+	AudioData  []byte
+	AudioType  string
+	UserID     string
+}
+
+// ===== 响应结构 =====
+
+// MinimalPairContrastInfo 对立组信息
+type MinimalPairContrastInfo struct {
+	ContrastID  string    `json:"contrast_id"`
+	Phonemes    [2]string `json:"phonemes"`
+	Description string    `json:"description"`
+	Hits        int       `json:"hits"` // 学习者问题单词命中次数（0 表示默认推荐）
+}
+
+// MinimalPairExercise 最小对立词练习项
+type MinimalPairExercise struct {
+	ContrastID    string `json:"contrast_id"`
+	WordA         string `json:"word_a"`
+	WordB         string `json:"word_b"`
+	WordAAudioURL string `json:"word_a_audio_url"`
+	WordBAudioURL string `json:"word_b_audio_url"`
+	Target        string `json:"target"` // 要求读出的单词
+	Source        string `json:"source"` // curated / llm
+}
+
+// MinimalPairExercisesResponse 最小对立词练习列表响应
+type MinimalPairExercisesResponse struct {
+	ExerciseType string                     `json:"exercise_type"`
+	Contrasts    []*MinimalPairContrastInfo `json:"contrasts"`
+	Items        []*MinimalPairExercise     `json:"items"`
+}
+
+// MinimalPairResultResponse 最小对立词练习结果
+type MinimalPairResultResponse struct {
+	ExerciseType   string  `json:"exercise_type"`
+	ContrastID     string  `json:"contrast_id"`
+	Target         string  `json:"target"`
+	Produced       string  `json:"produced"` // 判定学习者实际读出的单词
+	Correct        bool    `json:"correct"`
+	TargetScore    float64 `json:"target_score"`
+	OtherScore     float64 `json:"other_score"`
+	FeedbackText   string  `json:"feedback_text"`
+	TargetAudioURL string  `json:"target_audio_url"`
+}
+
+// ===================== 练习生成 =====================
+
+const (
+	// defaultMinimalPairLimit 默认练习条数
+	defaultMinimalPairLimit = 6
+	// maxMinimalPairLimit 最大练习条数
+	maxMinimalPairLimit = 20
+	// maxDetectedContrasts 自动选择的对立组上限
+	maxDetectedContrasts = 3
+	// minimalPairExpansionCount 每次 LLM 扩展的词对数量
+	minimalPairExpansionCount = 6
+)
+
+// minimalPairWordPattern LLM 扩展词校验（仅允许小写单词）
+var minimalPairWordPattern = regexp.MustCompile(`^[a-z']{2,20}$`)
+
+func (s *evaluateServiceImpl) GetMinimalPairExercises(ctx context.Context, req *MinimalPairExercisesRequest) (*MinimalPairExercisesResponse, error) {
+	if req == nil || req.UserID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxMinimalPairLimit {
+		limit = defaultMinimalPairLimit
+	}
+
+	// ─── 1. 确定练习的对立组 ───
+	var contrasts []*MinimalPairContrastInfo
+	if req.ContrastID != "" {
+		c := findMinimalPairContrast(req.ContrastID)
+		if c == nil {
+			return nil, apperr.ErrInvalidParam.WithMessage("unknown contrast_id: " + req.ContrastID)
+		}
+		contrasts = []*MinimalPairContrastInfo{contrastInfo(c, 0)}
+	} else {
+		contrasts = s.detectConfusions(ctx, req.UserID)
+	}
+
+	// ─── 2. 每组构建词对池（精选 + 可选 LLM 扩展）并打乱 ───
+	type pairSource struct {
+		pair   [2]string
+		source string
+	}
+	pools := make([][]pairSource, len(contrasts))
+	for i, info := range contrasts {
+		c := findMinimalPairContrast(info.ContrastID)
+		for _, p := range c.Pairs {
+			pools[i] = append(pools[i], pairSource{pair: p, source: "curated"})
+		}
+		if req.Expand {
+			for _, p := range s.expandMinimalPairs(ctx, c) {
+				pools[i] = append(pools[i], pairSource{pair: p, source: "llm"})
+			}
+		}
+		rand.Shuffle(len(pools[i]), func(a, b int) { pools[i][a], pools[i][b] = pools[i][b], pools[i][a] })
+	}
+
+	// ─── 3. 轮流从各组取词对，随机指定目标单词 ───
+	items := make([]*MinimalPairExercise, 0, limit)
+	for round := 0; len(items) < limit; round++ {
+		picked := false
+		for i, pool := range pools {
+			if round >= len(pool) || len(items) >= limit {
+				continue
+			}
+			picked = true
+			ps := pool[round]
+			items = append(items, &MinimalPairExercise{
+				ContrastID: contrasts[i].ContrastID,
+				WordA:      ps.pair[0],
+				WordB:      ps.pair[1],
+				Target:     ps.pair[rand.Intn(2)],
+				Source:     ps.source,
+			})
+		}
+		if !picked {
+			break
+		}
+	}
+
+	// ─── 4. 生成示范音频（失败时返回空 URL，不影响练习） ───
+	for _, item := range items {
+		item.WordAAudioURL = s.wordDemoAudioURL(ctx, item.WordA)
+		item.WordBAudioURL = s.wordDemoAudioURL(ctx, item.WordB)
+	}
+
+	return &MinimalPairExercisesResponse{
+		ExerciseType: ExerciseTypeMinimalPair,
+		Contrasts:    contrasts,
+		Items:        items,
+	}, nil
+}
+
+// ===================== 练习评测 =====================
+
+func (s *evaluateServiceImpl) SubmitMinimalPair(ctx context.Context, req *SubmitMinimalPairRequest) (*MinimalPairResultResponse, error) {
+	// ─── 基础校验 ───
+	if req == nil {
+		return nil, apperr.ErrInvalidParam.WithMessage("request is nil")
+	}
+	if len(req.AudioData) == 0 {
+		return nil, apperr.ErrInvalidParam.WithMessage("audio data is empty")
+	}
+	if s.evaluationProvider == nil {
+		return nil, errors.New("evaluation provider not initialized")
+	}
+
+	contrast := findMinimalPairContrast(req.ContrastID)
+	if contrast == nil {
+		return nil, apperr.ErrInvalidParam.WithMessage("unknown contrast_id: " + req.ContrastID)
+	}
+	wordA := normalizeReviewWord(req.WordA)
+	wordB := normalizeReviewWord(req.WordB)
+	target := normalizeReviewWord(req.Target)
+	if !isKnownMinimalPair(contrast, s.expandedMinimalPairs(ctx, contrast), wordA, wordB) {
+		return nil, apperr.ErrInvalidParam.WithMessage("unknown minimal pair")
+	}
+	if target != wordA && target != wordB {
+		return nil, apperr.ErrInvalidParam.WithMessage("target must be word_a or word_b")
+	}
+	other := wordA
+	if target == wordA {
+		other = wordB
+	}
+
+	// ─── 1. 分别以两个单词为参考文本进行单词模式评测 ───
+	targetResult, err := s.evaluationProvider.Assess(ctx, target, req.AudioData, domain.AssessCategoryWord)
+	if err != nil {
+		logger.ErrorContext(ctx, "minimal pair assess target failed", "error", err)
+		return nil, fmt.Errorf("speech assessment failed: %w", err)
+	}
+	otherResult, err := s.evaluationProvider.Assess(ctx, other, req.AudioData, domain.AssessCategoryWord)
+	if err != nil {
+		logger.ErrorContext(ctx, "minimal pair assess other failed", "error", err)
+		return nil, fmt.Errorf("speech assessment failed: %w", err)
+	}
+	targetScore := wordScoreOf(targetResult.TotalScore, targetResult.Words, target)
+	otherScore := wordScoreOf(otherResult.TotalScore, otherResult.Words, other)
+
+	// ─── 2. 判定读出的是哪一个单词 ───
+	produced := target
+	if otherScore > targetScore {
+		produced = other
+	}
+	correct := produced == target && targetScore >= reviewEnqueueThreshold
+
+	var feedbackText string
+	switch {
+	case correct:
+		feedbackText = fmt.Sprintf("Great! I heard \"%s\" clearly.", target)
+	case produced == target:
+		feedbackText = fmt.Sprintf("Almost! Listen to \"%s\" once more and say it slowly.", target)
+	default:
+		feedbackText = fmt.Sprintf("That sounded like \"%s\". Listen and try \"%s\" again!", other, target)
+	}
+
+	logger.InfoContext(ctx, "minimal pair evaluated",
+		"contrast_id", contrast.ID,
+		"target", target,
+		"produced", produced,
+		"target_score", targetScore,
+		"other_score", otherScore,
+	)
+
+	// ─── 3. 目标单词得分计入复习队列 ───
+	if s.reviewService != nil {
+		if reviewErr := s.reviewService.RecordWordScores(ctx, &RecordWordScoresRequest{
+			UserID: req.UserID,
+			Words:  []WordDetail{{Word: target, Score: targetScore, IsProblem: !correct}},
+		}); reviewErr != nil {
+			logger.ErrorContext(ctx, "minimal pair record review word failed", "error", reviewErr)
+		}
+	}
+
+	return &MinimalPairResultResponse{
+		ExerciseType:   ExerciseTypeMinimalPair,
+		ContrastID:     contrast.ID,
+		Target:         target,
+		Produced:       produced,
+		Correct:        correct,
+		TargetScore:    targetScore,
+		OtherScore:     otherScore,
+		FeedbackText:   feedbackText,
+		TargetAudioURL: s.wordDemoAudioURL(ctx, target),
+	}, nil
+}
+
+// ===================== 辅助函数 =====================
+
+// detectConfusions 根据学习者近期问题单词推断易混淆的对立组
+// 单词属于某组词对记 3 分，命中拼写线索记 1 分；无命中时返回默认组
+func (s *evaluateServiceImpl) detectConfusions(ctx context.Context, userID string) []*MinimalPairContrastInfo {
+	words := s.collectProblemWords(ctx, userID)
+
+	hits := make(map[string]int)
+	for _, c := range minimalPairDictionary {
+		for _, word := range words {
+			switch {
+			case containsPairWord(c.Pairs, word):
+				hits[c.ID] += 3
+			case c.Cues[0].MatchString(word) || c.Cues[1].MatchString(word):
+				hits[c.ID]++
+			}
+		}
+	}
+
+	var detected []*MinimalPairContrastInfo
+	for _, c := range minimalPairDictionary {
+		if hits[c.ID] > 0 {
+			detected = append(detected, contrastInfo(c, hits[c.ID]))
+		}
+	}
+	// 稳定排序：命中相同时保持词典顺序（按常见程度排列）
+	sort.SliceStable(detected, func(i, j int) bool { return detected[i].Hits > detected[j].Hits })
+	if len(detected) > maxDetectedContrasts {
+		detected = detected[:maxDetectedContrasts]
+	}
+
+	if len(detected) == 0 {
+		for _, id := range defaultMinimalPairContrasts {
+			detected = append(detected, contrastInfo(findMinimalPairContrast(id), 0))
+		}
+	}
+	return detected
+}
+
+// collectProblemWords 汇总学习者近期评测问题单词和未掌握的复习单词
+func (s *evaluateServiceImpl) collectProblemWords(ctx context.Context, userID string) []string {
+	var words []string
+	if s.repos == nil {
+		return words
+	}
+
+	evaluations, _, err := s.repos.PronunciationEvaluation.GetByUserID(ctx, userID, 1, 20)
+	if err != nil {
+		logger.ErrorContext(ctx, "minimal pair load evaluations failed", "error", err)
+	}
+	for _, e := range evaluations {
+		for _, w := range e.ProblemWords {
+			if word := normalizeReviewWord(w); word != "" {
+				words = append(words, word)
+			}
+		}
+	}
+
+	items, _, err := s.repos.ReviewItem.GetByUserID(ctx, userID, 1, 100)
+	if err != nil {
+		logger.ErrorContext(ctx, "minimal pair load review items failed", "error", err)
+	}
+	for _, item := range items {
+		if item.Status != model.ReviewStatusMastered {
+			words = append(words, item.Word)
+		}
+	}
+	return words
+}
+
+// expandMinimalPairs 使用 LLM 扩展对立组词对（失败时返回空）
+// 扩展结果存入 Redis，提交时只接受精选词对与已下发的扩展词对；Redis 不可用时不扩展（无法校验提交）
+// 只保留两个单词分别符合对立组拼写线索的词对
+func (s *evaluateServiceImpl) expandMinimalPairs(ctx context.Context, c *minimalPairContrast) [][2]string {
+	if s.minimalPairCache == nil || s.llmProvider == nil {
+		return nil
+	}
+	pairs, found, err := s.minimalPairCache.GetExpansion(ctx, c.ID)
+	if err != nil {
+		logger.ErrorContext(ctx, "minimal pair load expansion failed", "contrast_id", c.ID, "error", err)
+		return nil
+	}
+	if found {
+		return pairs
+	}
+
+	systemPrompt, userMessage := llmPrompts.BuildMinimalPairExpansionPrompt(c.Phonemes[0], c.Phonemes[1], c.Pairs, minimalPairExpansionCount)
+	reply, err := s.llmProvider.Chat(ctx, systemPrompt, userMessage)
+	if err != nil {
+		logger.ErrorContext(ctx, "minimal pair llm expansion failed", "contrast_id", c.ID, "error", err)
+		return nil
+	}
+
+	// 截取 JSON 数组部分，容忍 LLM 输出多余文字
+	start, end := strings.Index(reply, "["), strings.LastIndex(reply, "]")
+	if start < 0 || end <= start {
+		logger.ErrorContext(ctx, "minimal pair llm expansion invalid reply", "contrast_id", c.ID, "reply", reply)
+		return nil
+	}
+	var raw [][]string
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		logger.ErrorContext(ctx, "minimal pair llm expansion parse failed", "contrast_id", c.ID, "error", err)
+		return nil
+	}
+
+	pairs = make([][2]string, 0, len(raw))
+	for _, p := range raw {
+		if len(p) != 2 {
+			continue
+		}
+		a, b := strings.ToLower(strings.TrimSpace(p[0])), strings.ToLower(strings.TrimSpace(p[1]))
+		if a == b || !minimalPairWordPattern.MatchString(a) || !minimalPairWordPattern.MatchString(b) {
+			continue
+		}
+		if containsPairWord(c.Pairs, a) && containsPairWord(c.Pairs, b) {
+			continue
+		}
+		if !matchesContrastCues(c, a, b) {
+			continue
+		}
+		pairs = append(pairs, [2]string{a, b})
+	}
+
+	if err := s.minimalPairCache.SetExpansion(ctx, c.ID, pairs); err != nil {
+		// 未保存的词对提交时无法通过校验，不下发
+		logger.ErrorContext(ctx, "minimal pair save expansion failed", "contrast_id", c.ID, "error", err)
+		return nil
+	}
+	return pairs
+}
+
+// expandedMinimalPairs 读取对立组已下发的 LLM 扩展词对（未扩展、已过期或读取失败时返回空）
+func (s *evaluateServiceImpl) expandedMinimalPairs(ctx context.Context, c *minimalPairContrast) [][2]string {
+	if s.minimalPairCache == nil {
+		return nil
+	}
+	pairs, _, err := s.minimalPairCache.GetExpansion(ctx, c.ID)
+	if err != nil {
+		logger.ErrorContext(ctx, "minimal pair load expansion failed", "contrast_id", c.ID, "error", err)
+		return nil
+	}
+	return pairs
+}
+
+// isKnownMinimalPair 校验词对属于该对立组：精选词对，或服务端下发过的 LLM 扩展词对（顺序一致）
+// 不按拼写线索放行，防止客户端提交任意单词写入复习队列或生成示范音频
+func isKnownMinimalPair(c *minimalPairContrast, expanded [][2]string, wordA, wordB string) bool {
+	for _, pairs := range [][][2]string{c.Pairs, expanded} {
+		for _, p := range pairs {
+			if p[0] == wordA && p[1] == wordB {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesContrastCues 判断两个单词分别符合对立组两侧的拼写线索
+func matchesContrastCues(c *minimalPairContrast, wordA, wordB string) bool {
+	return c.Cues[0].MatchString(wordA) && c.Cues[1].MatchString(wordB)
+}
+
+// wordDemoAudioURL 获取单词示范音频 URL（失败时返回空字符串）
+func (s *evaluateServiceImpl) wordDemoAudioURL(ctx context.Context, word string) string {
+	url, err := ensureWordDemoAudio(ctx, s.ttsProvider, s.ossProvider, word)
+	if err != nil {
+		logger.ErrorContext(ctx, "minimal pair demo audio failed", "word", word, "error", err)
+		return ""
+	}
+	return url
+}
+
+// contrastInfo 构建对立组信息
+func contrastInfo(c *minimalPairContrast, hits int) *MinimalPairContrastInfo {
+	return &MinimalPairContrastInfo{
+		ContrastID:  c.ID,
+		Phonemes:    c.Phonemes,
+		Description: c.Description,
+		Hits:        hits,
+	}
+}
+
+// containsPairWord 判断单词是否出现在词对列表中
+func containsPairWord(pairs [][2]string, word string) bool {
+	for _, p := range pairs {
+		if p[0] == word || p[1] == word {
+			return true
+		}
+	}
+	return false
+}
@@ -3,14 +3,20 @@ package service
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"strings"
 
+	"pronunciation-correction-system/internal/cache"
 	"pronunciation-correction-system/internal/db"
 	"pronunciation-correction-system/internal/domain"
 	llmPrompts "pronunciation-correction-system/internal/infrastructure/llm"
 	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
 	"pronunciation-correction-system/internal/pkg/logger"
 	"pronunciation-correction-system/internal/pkg/uuid"
 )
@@ -25,6 +31,7 @@ type EvaluateMVPRequest struct {
 	Category        string // read_sentence / read_word
 	DifficultyLevel string // beginner / intermediate / advanced
 	UserID          string
+	Debug           bool // 结果中附带各阶段耗时
 }
 
 // SubmitEvaluationRequest 异步发音评测提交请求
@@ -77,6 +84,9 @@ type EvaluateMVPResponse struct {
 	// === 其他 ===
 	TargetText string `json:"target_text"` // 目标文本
 	EvalID     string `json:"eval_id"`     // 评测记录 ID
+
+	// === 调试 ===
+	Latency *LatencyBreakdown `json:"latency,omitempty"` // 各阶段耗时（仅 debug 请求返回）
 }
 
 // DemoAudio 示范音频（A/B/C 级提供）
@@ -103,6 +113,7 @@ type EvaluationResultResponse struct {
 	Scores           *EvalScores       `json:"scores"`
 	DurationMs       int               `json:"duration_ms"`
 	ProblemWords     []string          `json:"problem_words,omitempty"`
+	WordDetails      []WordDetail      `json:"word_details,omitempty"`
 	DetailedFeedback *DetailedFeedback `json:"detailed_feedback"`
 	ReferenceAudio   string            `json:"reference_audio"`
 	CreatedAt        string            `json:"created_at"`
@@ -157,14 +168,26 @@ type EvaluateService interface {
 	// GetEvaluationHistory 获取用户评测历史列表
 	GetEvaluationHistory(ctx context.Context, req *EvalHistoryRequest) ([]*EvalSummary, int64, error)
 
-	// GetEvaluationDetail 获取单次评测完整详情
-	GetEvaluationDetail(ctx context.Context, evalID string) (*EvaluationResultResponse, error)
+	// GetEvaluationDetail 获取单次评测完整详情（校验归属）
+	GetEvaluationDetail(ctx context.Context, evalID, userID string) (*EvaluationResultResponse, error)
 
-	// DeleteEvaluation 删除评测记录
+	// DeleteEvaluation 删除评测记录（校验归属）
 	DeleteEvaluation(ctx context.Context, evalID, userID string) error
 
+	// ExportEvaluationHistory 按过滤条件流式导出用户全部评测历史（csv / jsonl）
+	ExportEvaluationHistory(ctx context.Context, req *EvalHistoryRequest, format string, w io.Writer) error
+
 	// GetReferenceAudio 获取指定文本的标准发音音频
 	GetReferenceAudio(ctx context.Context, textID string) (*ReferenceAudioResponse, error)
+
+	// GetMinimalPairExercises 根据学习者混淆的音素生成最小对立词练习
+	GetMinimalPairExercises(ctx context.Context, req *MinimalPairExercisesRequest) (*MinimalPairExercisesResponse, error)
+
+	// SubmitMinimalPair 提交最小对立词练习录音，判断读出的是词对中的哪一个
+	SubmitMinimalPair(ctx context.Context, req *SubmitMinimalPairRequest) (*MinimalPairResultResponse, error)
+
+	// CompareEvaluations 对比同一文本的两次尝试（指定两条记录，或最佳 vs 最近）
+	CompareEvaluations(ctx context.Context, req *CompareEvaluationsRequest) (*EvaluationComparisonResponse, error)
 }
 
 // ===== 空实现 =====
@@ -176,6 +199,9 @@ type evaluateServiceImpl struct {
 	llmProvider        domain.LLMProvider
 	ttsProvider        domain.TTSProvider
 	ossProvider        domain.OSSProvider
+	reviewService      ReviewService
+	vocabularyService  VocabularyService
+	minimalPairCache   *cache.MinimalPairCache // LLM 扩展词对（为 nil 时不扩展）
 	logger             *slog.Logger
 }
 
@@ -186,6 +212,9 @@ func NewEvaluateService(
 	llmProvider domain.LLMProvider,
 	ttsProvider domain.TTSProvider,
 	ossProvider domain.OSSProvider,
+	reviewService ReviewService,
+	vocabularyService VocabularyService,
+	minimalPairCache *cache.MinimalPairCache,
 	logger *slog.Logger,
 ) EvaluateService {
 	return &evaluateServiceImpl{
@@ -194,6 +223,9 @@ func NewEvaluateService(
 		llmProvider:        llmProvider,
 		ttsProvider:        ttsProvider,
 		ossProvider:        ossProvider,
+		reviewService:      reviewService,
+		vocabularyService:  vocabularyService,
+		minimalPairCache:   minimalPairCache,
 		logger:             logger,
 	}
 }
@@ -212,6 +244,8 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 	if s.evaluationProvider == nil || s.llmProvider == nil || s.ttsProvider == nil {
 		return nil, errors.New("required providers not initialized")
 	}
+	timer := newStageTimer()
+
 	// ─── 1. 获取目标文本（硬编码映射） ───
 	targetText, ok := textIDMap[req.TextID]
 	if !ok {
@@ -220,7 +254,9 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 	logger.InfoContext(ctx, "evaluate mvp start", "text_id", req.TextID, "target_text", targetText)
 
 	// ─── 2. 讯飞语音评测 ───
-	evalResult, err := s.evaluationProvider.Assess(ctx, targetText, req.AudioData)
+	assessDone := timer.track(model.LatencyStageAssessment)
+	evalResult, err := s.evaluationProvider.Assess(ctx, targetText, req.AudioData, req.Category)
+	assessDone()
 	if err != nil {
 		logger.ErrorContext(ctx, "evaluate mvp assess failed", "error", err)
 		return nil, fmt.Errorf("speech assessment failed: %w", err)
@@ -262,7 +298,9 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 
 	// ─── 5. LLM 生成反馈文本 ───
 	systemPrompt, userMessage := buildPromptByLevel(feedbackLevel, targetText, score, worstWord, worstWordScore)
+	llmDone := timer.track(model.LatencyStageLLM)
 	feedbackText, err := s.llmProvider.Chat(ctx, systemPrompt, userMessage)
+	llmDone()
 	if err != nil {
 		logger.ErrorContext(ctx, "evaluate mvp llm failed", "error", err)
 		feedbackText = levelText // fallback
@@ -270,7 +308,9 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 	logger.InfoContext(ctx, "evaluate mvp llm feedback", "feedback", feedbackText)
 
 	// ─── 6. TTS 合成反馈音频 ───
+	ttsDone := timer.track(model.LatencyStageTTS)
 	feedbackAudio, err := s.ttsProvider.Synthesize(ctx, feedbackText, nil)
+	ttsDone()
 	if err != nil {
 		logger.ErrorContext(ctx, "evaluate mvp tts feedback failed", "error", err)
 		return nil, fmt.Errorf("tts synthesize feedback failed: %w", err)
@@ -288,7 +328,9 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 		if worstWord != "" {
 			demoText = worstWord
 			demoType = "word"
+			ttsDone = timer.track(model.LatencyStageTTS)
 			demoAudioData, err = s.ttsProvider.Synthesize(ctx, worstWord, nil)
+			ttsDone()
 			if err != nil {
 				logger.ErrorContext(ctx, "evaluate mvp tts demo word failed", "error", err)
 			}
@@ -297,7 +339,9 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 		// 整句示范
 		demoText = targetText
 		demoType = "sentence"
+		ttsDone = timer.track(model.LatencyStageTTS)
 		demoAudioData, err = s.ttsProvider.Synthesize(ctx, targetText, nil)
+		ttsDone()
 		if err != nil {
 			logger.ErrorContext(ctx, "evaluate mvp tts demo sentence failed", "error", err)
 		}
@@ -308,6 +352,7 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 	var feedbackAudioURL string
 	var demoAudioURL string
 
+	ossDone := timer.track(model.LatencyStageOSS)
 	if s.ossProvider != nil {
 		// 上传反馈音频
 		feedbackKey := fmt.Sprintf("evaluate/%s/feedback_%s.mp3", evalID, uuid.New())
@@ -328,6 +373,9 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 		}
 	}
 
+	ossDone()
+	latency := timer.breakdown()
+
 	if len(demoAudioData) > 0 && demoAudioURL != "" {
 		demoAudio = &DemoAudio{
 			Type:     demoType,
@@ -350,8 +398,11 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 			FeedbackText:     strPtr(feedbackText),
 			FeedbackAudioURL: strPtr(feedbackAudioURL),
 			ProblemWords:     model.StringArray(problemWords),
+			WordScores:       toWordScoreList(wordDetails),
 			DifficultyLevel:  req.DifficultyLevel,
 			Status:           "completed",
+			LatencyMS:        &latency.TotalMS,
+			StageLatencies:   latency.Stages,
 		}
 		if demoAudio != nil && demoType == "sentence" {
 			evaluation.DemoSentenceAudioURL = strPtr(demoAudioURL)
@@ -367,7 +418,40 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 		}
 	}
 
-	// ─── 10. 构建响应 ───
+	// ─── 10. 更新单词复习队列（失败不影响评测结果） ───
+	if s.reviewService != nil {
+		var demoURLs map[string]string
+		if demoAudio != nil && demoType == "word" {
+			demoURLs = map[string]string{worstWord: demoAudioURL}
+		}
+		if reviewErr := s.reviewService.RecordWordScores(ctx, &RecordWordScoresRequest{
+			UserID:        req.UserID,
+			EvaluationID:  evalID,
+			Words:         wordDetails,
+			DemoAudioURLs: demoURLs,
+		}); reviewErr != nil {
+			logger.ErrorContext(ctx, "evaluate mvp record review words failed", "error", reviewErr)
+		}
+	}
+
+	// ─── 11. 读准的单词累计到个人词汇表（问题单词不计入，失败不影响评测结果） ───
+	passedWords := make([]string, 0, len(wordDetails))
+	for _, w := range wordDetails {
+		if !w.IsProblem {
+			passedWords = append(passedWords, w.Word)
+		}
+	}
+	if s.vocabularyService != nil && len(passedWords) > 0 {
+		if vocabErr := s.vocabularyService.RecordText(ctx, &RecordVocabularyRequest{
+			UserID: req.UserID,
+			Text:   strings.Join(passedWords, " "),
+			Source: model.VocabularySourceEvaluation,
+		}); vocabErr != nil {
+			logger.ErrorContext(ctx, "evaluate mvp record vocabulary failed", "error", vocabErr)
+		}
+	}
+
+	// ─── 12. 构建响应 ───
 	resp := &EvaluateMVPResponse{
 		OverallScore:     score,
 		FeedbackLevel:    feedbackLevel,
@@ -382,6 +466,9 @@ func (s *evaluateServiceImpl) EvaluateMVP(ctx context.Context, req *EvaluateMVPR
 		TargetText:       targetText,
 		EvalID:           evalID,
 	}
+	if req.Debug {
+		resp.Latency = latency
+	}
 
 	logger.InfoContext(ctx, "evaluate mvp completed", "eval_id", evalID, "level", feedbackLevel, "score", score)
 	return resp, nil
@@ -493,29 +580,119 @@ func (s *evaluateServiceImpl) GetEvaluationResult(ctx context.Context, evalID st
 }
 
 func (s *evaluateServiceImpl) GetEvaluationHistory(ctx context.Context, req *EvalHistoryRequest) ([]*EvalSummary, int64, error) {
-	// TODO: Step2 实现
-	// 1. 查询 pronunciation_evaluations 表
-	// 2. 按 order_by + order 排序
-	// 3. 支持 text_id / date_from / date_to 过滤
-	// 4. 分页返回评测摘要
-	return nil, 0, nil
+	// ─── 1. 解析过滤条件 ───
+	query, err := buildEvaluationQuery(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// ─── 2. 分页查询 ───
+	evaluations, total, err := s.repos.PronunciationEvaluation.Query(ctx, query, req.Page, req.PageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query evaluation history failed: %w", err)
+	}
+
+	// ─── 3. 转换为摘要 ───
+	summaries := make([]*EvalSummary, 0, len(evaluations))
+	for _, e := range evaluations {
+		summaries = append(summaries, toEvalSummary(e))
+	}
+	return summaries, total, nil
 }
 
-func (s *evaluateServiceImpl) GetEvaluationDetail(ctx context.Context, evalID string) (*EvaluationResultResponse, error) {
-	// TODO: Step2 实现
-	// 1. 查询 pronunciation_evaluations 表
-	// 2. 解析 JSON 字段（phonemes, detailed_feedback）
-	// 3. 返回完整评测详情
-	return nil, nil
+func (s *evaluateServiceImpl) GetEvaluationDetail(ctx context.Context, evalID, userID string) (*EvaluationResultResponse, error) {
+	evaluation, err := s.getOwnedEvaluation(ctx, evalID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toEvaluationResult(evaluation), nil
 }
 
 func (s *evaluateServiceImpl) DeleteEvaluation(ctx context.Context, evalID, userID string) error {
-	// TODO: Step2 实现
-	// 1. 验证用户对该评测记录的所有权
-	// 2. 软删除评测记录
+	// ─── 1. 验证所有权 ───
+	if _, err := s.getOwnedEvaluation(ctx, evalID, userID); err != nil {
+		return err
+	}
+
+	// ─── 2. 删除评测记录（表无 deleted_at，物理删除） ───
+	if err := s.repos.PronunciationEvaluation.Delete(ctx, evalID); err != nil {
+		return fmt.Errorf("delete evaluation failed: %w", err)
+	}
+
+	logger.InfoContext(ctx, "evaluation deleted", "eval_id", evalID, "user_id", userID)
 	return nil
 }
 
+func (s *evaluateServiceImpl) ExportEvaluationHistory(ctx context.Context, req *EvalHistoryRequest, format string, w io.Writer) error {
+	// ─── 1. 解析过滤条件（写出任何数据前完成校验） ───
+	if format != ExportFormatCSV && format != ExportFormatJSONL {
+		return apperr.ErrInvalidParam.WithMessage("format must be csv or jsonl")
+	}
+	query, err := buildEvaluationQuery(req)
+	if err != nil {
+		return err
+	}
+
+	// ─── 2. 按批次流式写出 ───
+	var csvWriter *csv.Writer
+	var jsonEncoder *json.Encoder
+	if format == ExportFormatCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(evaluationExportHeader); err != nil {
+			return fmt.Errorf("write csv header failed: %w", err)
+		}
+	} else {
+		jsonEncoder = json.NewEncoder(w)
+	}
+
+	err = s.repos.PronunciationEvaluation.QueryInBatches(ctx, query, exportBatchSize, func(batch []*model.PronunciationEvaluation) error {
+		for _, e := range batch {
+			row := toEvaluationExportRow(e)
+			if csvWriter != nil {
+				if err := csvWriter.Write(row.csvRecord()); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := jsonEncoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("export evaluation history failed: %w", err)
+	}
+	if csvWriter != nil {
+		// 无数据时也需输出表头
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+	return nil
+}
+
+// getOwnedEvaluation 查询评测记录并校验归属
+func (s *evaluateServiceImpl) getOwnedEvaluation(ctx context.Context, evalID, userID string) (*model.PronunciationEvaluation, error) {
+	if evalID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("eval_id is required")
+	}
+	evaluation, err := s.repos.PronunciationEvaluation.GetByID(ctx, evalID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, apperr.ErrEvaluationNotFound
+		}
+		return nil, fmt.Errorf("get evaluation failed: %w", err)
+	}
+	if evaluation.UserID != userID {
+		return nil, apperr.ErrForbidden.WithMessage("evaluation does not belong to user")
+	}
+	return evaluation, nil
+}
+
 func (s *evaluateServiceImpl) GetReferenceAudio(ctx context.Context, textID string) (*ReferenceAudioResponse, error) {
 	// TODO: Step2 实现
 	// 1. 查询文本资源获取标准文本
@@ -0,0 +1,400 @@
+// Package service 提供学习者长期记忆业务逻辑（会话结束后提取、新会话开始时注入、家长查看与删除）
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/domain"
+	llmPrompts "pronunciation-correction-system/internal/infrastructure/llm"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/uuid"
+)
+
+// 长期记忆规则
+const (
+	defaultMemoryRetentionDays = 180 // 未配置 learner_memory_retention_days 时的保留天数
+	memoryMinConfidence        = 0.5 // 置信度低于该值的提取结果不保存
+	memoryRecallMinConfidence  = 0.7 // 置信度低于该值的记忆不注入对话
+	memoryRecallMaxFacts       = 6   // 每个会话最多注入的记忆条数
+	memoryRecallHalfLifeDays   = 60  // 相关度按最后提取时间衰减的半衰期（天）
+	memoryMaxFactsPerSession   = 5   // 每个会话最多提取的事实数
+	memoryMaxContentRunes      = 255 // 事实内容最大长度（与表字段一致）
+)
+
+// memoryCategories 合法的记忆类别（LLM 返回其他值时归为 other）
+var memoryCategories = map[string]bool{
+	model.MemoryCategoryName:     true,
+	model.MemoryCategoryPet:      true,
+	model.MemoryCategoryHobby:    true,
+	model.MemoryCategorySchool:   true,
+	model.MemoryCategoryFamily:   true,
+	model.MemoryCategoryFavorite: true,
+	model.MemoryCategoryOther:    true,
+}
+
+// memoryCategoryWeights 类别相关度权重（名字最常用于称呼孩子，优先注入）
+var memoryCategoryWeights = map[string]float64{
+	model.MemoryCategoryName: 1.5,
+	model.MemoryCategoryPet:  1.2,
+}
+
+// memoryFactKeyPattern 规范化后的事实键
+var memoryFactKeyPattern = regexp.MustCompile(`^[a-z0-9_]{1,100}$`)
+
+// ===== 响应结构 =====
+
+// LearnerMemoryItem 家长可见的长期记忆条目
+type LearnerMemoryItem struct {
+	ID           string  `json:"id"`
+	Category     string  `json:"category"`
+	Content      string  `json:"content"`
+	Confidence   float64 `json:"confidence"`
+	MentionCount int     `json:"mention_count"`
+	FirstSeenAt  string  `json:"first_seen_at"`
+	LastSeenAt   string  `json:"last_seen_at"`
+}
+
+// extractedMemory LLM 提取的一条事实
+type extractedMemory struct {
+	Key        string  `json:"key"`
+	Category   string  `json:"category"`
+	Content    string  `json:"content"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ===== Service 接口 =====
+
+// LearnerMemoryService 学习者长期记忆业务接口
+type LearnerMemoryService interface {
+	// ExtractFromConversation 从会话消息中提取孩子的个人信息，审核通过后保存（长期记忆关闭时不提取）
+	ExtractFromConversation(ctx context.Context, conversation *model.VoiceConversation, messages []*model.ConversationMessage) error
+
+	// Recall 按相关度选取用于新会话的记忆（长期记忆关闭或没有可用记忆时返回空列表）
+	Recall(ctx context.Context, userID string) ([]*model.LearnerMemory, error)
+
+	// ListMemories 分页获取保留期限内的记忆（按最后提取时间降序），先清除超过保留期限的条目
+	ListMemories(ctx context.Context, userID string, page, pageSize int) ([]*LearnerMemoryItem, int64, error)
+
+	// DeleteMemory 删除一条记忆，并清除会话中已注入的记忆
+	DeleteMemory(ctx context.Context, userID, memoryID string) error
+
+	// ClearMemories 删除全部记忆，并清除会话中已注入的记忆，返回删除条数
+	ClearMemories(ctx context.Context, userID string) (int64, error)
+}
+
+// ===== 实现 =====
+
+// learnerMemoryServiceImpl LearnerMemory Service 实现
+type learnerMemoryServiceImpl struct {
+	memoryRepo         db.LearnerMemoryRepository
+	conversationRepo   db.VoiceConversationRepository
+	settingRepo        db.SystemSettingRepository
+	llmProvider        domain.LLMProvider
+	moderationProvider domain.ModerationProvider // 为 nil 时不审核
+	logger             *slog.Logger
+}
+
+// NewLearnerMemoryService 创建 LearnerMemoryService
+func NewLearnerMemoryService(repos *db.Repositories, llm domain.LLMProvider, moderation domain.ModerationProvider, logger *slog.Logger) LearnerMemoryService {
+	var memoryRepo db.LearnerMemoryRepository
+	var conversationRepo db.VoiceConversationRepository
+	var settingRepo db.SystemSettingRepository
+	if repos != nil {
+		memoryRepo = repos.LearnerMemory
+		conversationRepo = repos.VoiceConversation
+		settingRepo = repos.SystemSetting
+	}
+	return &learnerMemoryServiceImpl{
+		memoryRepo:         memoryRepo,
+		conversationRepo:   conversationRepo,
+		settingRepo:        settingRepo,
+		llmProvider:        llm,
+		moderationProvider: moderation,
+		logger:             logger,
+	}
+}
+
+func (s *learnerMemoryServiceImpl) ExtractFromConversation(ctx context.Context, conversation *model.VoiceConversation, messages []*model.ConversationMessage) error {
+	if conversation == nil || s.memoryRepo == nil || s.llmProvider == nil {
+		return nil
+	}
+
+	// ─── 1. 长期记忆关闭时不提取 ───
+	cutoff, enabled := s.retentionCutoff(ctx)
+	if !enabled {
+		return nil
+	}
+
+	// ─── 2. 整理对话原文（被拦截的孩子输入不参与提取） ───
+	var transcript strings.Builder
+	childLines := 0
+	for _, m := range messages {
+		speaker := "Teacher"
+		if m.SenderType == model.SenderTypeUser {
+			if m.MessageText == blockedInputPlaceholder {
+				continue
+			}
+			speaker = "Child"
+			childLines++
+		}
+		transcript.WriteString(speaker + ": " + m.MessageText + "\n")
+	}
+	if childLines == 0 {
+		return nil
+	}
+
+	// ─── 3. LLM 提取事实（附上已记住的事实，沿用已有的键） ───
+	existing, err := s.memoryRepo.ListRecallable(ctx, conversation.UserID, cutoff, 0)
+	if err != nil {
+		return fmt.Errorf("list existing memories failed: %w", err)
+	}
+	known := make([]string, 0, len(existing))
+	for _, m := range existing {
+		known = append(known, m.FactKey+": "+m.Content)
+	}
+	systemPrompt, userMessage := llmPrompts.BuildLearnerMemoryPrompt(known, transcript.String())
+	reply, err := s.llmProvider.Chat(ctx, systemPrompt, userMessage)
+	if err != nil {
+		return fmt.Errorf("llm extract memories failed: %w", err)
+	}
+	facts, err := parseExtractedMemories(reply)
+	if err != nil {
+		// 回复含孩子的个人信息，原文仅在 debug 级别记录
+		logger.DebugContext(ctx, "learner memory extraction raw reply", "session_id", conversation.ID, "reply", reply)
+		return fmt.Errorf("parse extracted memories failed (reply_length %d): %w", len(reply), err)
+	}
+
+	// ─── 4. 逐条审核，命中任何类别或审核出错时丢弃 ───
+	now := time.Now()
+	memories := make([]*model.LearnerMemory, 0, len(facts))
+	for _, fact := range facts {
+		if !s.moderateFact(ctx, conversation.ID, fact) {
+			continue
+		}
+		memories = append(memories, &model.LearnerMemory{
+			ID:                   uuid.New(),
+			UserID:               conversation.UserID,
+			FactKey:              fact.Key,
+			Category:             fact.Category,
+			Content:              fact.Content,
+			Confidence:           fact.Confidence,
+			MentionCount:         1,
+			SourceConversationID: &conversation.ID,
+			FirstSeenAt:          now,
+			LastSeenAt:           now,
+		})
+	}
+
+	// ─── 5. 保存，并清除超过保留期限的条目 ───
+	if err := s.memoryRepo.Upsert(ctx, memories); err != nil {
+		return err
+	}
+	if _, err := s.memoryRepo.DeleteLastSeenBefore(ctx, conversation.UserID, cutoff); err != nil {
+		logger.ErrorContext(ctx, "learner memory purge expired failed", "user_id", conversation.UserID, "error", err)
+	}
+	logger.InfoContext(ctx, "learner memories extracted", "session_id", conversation.ID,
+		"extracted", len(facts), "saved", len(memories))
+	return nil
+}
+
+func (s *learnerMemoryServiceImpl) Recall(ctx context.Context, userID string) ([]*model.LearnerMemory, error) {
+	if s.memoryRepo == nil || userID == "" {
+		return nil, nil
+	}
+	cutoff, enabled := s.retentionCutoff(ctx)
+	if !enabled {
+		return nil, nil
+	}
+	memories, err := s.memoryRepo.ListRecallable(ctx, userID, cutoff, memoryRecallMinConfidence)
+	if err != nil {
+		return nil, err
+	}
+
+	// 相关度 = 置信度 × 类别权重 × 时间衰减 × 提及次数加成，取前 memoryRecallMaxFacts 条
+	now := time.Now()
+	relevance := make(map[string]float64, len(memories))
+	for _, m := range memories {
+		weight := memoryCategoryWeights[m.Category]
+		if weight == 0 {
+			weight = 1
+		}
+		ageDays := now.Sub(m.LastSeenAt).Hours() / 24
+		decay := math.Pow(0.5, ageDays/memoryRecallHalfLifeDays)
+		mentions := 1 + 0.1*float64(min(m.MentionCount-1, 5))
+		relevance[m.ID] = m.Confidence * weight * decay * mentions
+	}
+	sort.SliceStable(memories, func(i, j int) bool {
+		return relevance[memories[i].ID] > relevance[memories[j].ID]
+	})
+	if len(memories) > memoryRecallMaxFacts {
+		memories = memories[:memoryRecallMaxFacts]
+	}
+	return memories, nil
+}
+
+func (s *learnerMemoryServiceImpl) ListMemories(ctx context.Context, userID string, page, pageSize int) ([]*LearnerMemoryItem, int64, error) {
+	if s.memoryRepo == nil {
+		return nil, 0, apperr.ErrInternalError.WithMessage("memory repository not initialized")
+	}
+
+	// ─── 1. 清除超过保留期限的条目（长期记忆关闭时全部清除） ───
+	cutoff, _ := s.retentionCutoff(ctx)
+	if _, err := s.memoryRepo.DeleteLastSeenBefore(ctx, userID, cutoff); err != nil {
+		return nil, 0, fmt.Errorf("purge expired memories failed: %w", err)
+	}
+
+	// ─── 2. 分页查询 ───
+	memories, total, err := s.memoryRepo.ListByUserID(ctx, userID, cutoff, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list memories failed: %w", err)
+	}
+	items := make([]*LearnerMemoryItem, 0, len(memories))
+	for _, m := range memories {
+		items = append(items, toLearnerMemoryItem(m))
+	}
+	return items, total, nil
+}
+
+func (s *learnerMemoryServiceImpl) DeleteMemory(ctx context.Context, userID, memoryID string) error {
+	if s.memoryRepo == nil {
+		return apperr.ErrInternalError.WithMessage("memory repository not initialized")
+	}
+	if strings.TrimSpace(memoryID) == "" {
+		return apperr.ErrInvalidParam.WithMessage("memory_id is required")
+	}
+	deleted, err := s.memoryRepo.DeleteByID(ctx, memoryID, userID)
+	if err != nil {
+		return fmt.Errorf("delete memory failed: %w", err)
+	}
+	if !deleted {
+		return apperr.ErrMemoryNotFound
+	}
+	s.clearConversationMemory(ctx, userID)
+	logger.InfoContext(ctx, "learner memory deleted", "user_id", userID, "memory_id", memoryID)
+	return nil
+}
+
+func (s *learnerMemoryServiceImpl) ClearMemories(ctx context.Context, userID string) (int64, error) {
+	if s.memoryRepo == nil {
+		return 0, apperr.ErrInternalError.WithMessage("memory repository not initialized")
+	}
+	deleted, err := s.memoryRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("clear memories failed: %w", err)
+	}
+	s.clearConversationMemory(ctx, userID)
+	logger.InfoContext(ctx, "learner memories cleared", "user_id", userID, "deleted", deleted)
+	return deleted, nil
+}
+
+// retentionCutoff 读取保留天数，返回保留期限的起点；保留天数为 0 时 enabled 为 false（起点为当前时间，全部视为过期）
+func (s *learnerMemoryServiceImpl) retentionCutoff(ctx context.Context) (time.Time, bool) {
+	days := defaultMemoryRetentionDays
+	if s.settingRepo != nil {
+		value, err := s.settingRepo.GetIntValue(ctx, model.ConfigLearnerMemoryRetentionDays)
+		switch {
+		case err == nil && value >= 0:
+			days = value
+		case err != nil && !db.IsNotFound(err):
+			logger.WarnContext(ctx, "learner memory read retention days failed", "error", err)
+		}
+	}
+	now := time.Now()
+	return now.AddDate(0, 0, -days), days > 0
+}
+
+// moderateFact 审核一条提取结果，仅 allow 的条目可保存
+// 与对话审核不同，审核服务出错时丢弃（记忆可缺失，不可保存未经审核的个人信息）
+func (s *learnerMemoryServiceImpl) moderateFact(ctx context.Context, sessionID string, fact *extractedMemory) bool {
+	if s.moderationProvider == nil {
+		return true
+	}
+	result, err := s.moderationProvider.ModerateOutput(ctx, fact.Content)
+	if err != nil {
+		logger.ErrorContext(ctx, "learner memory moderation failed, drop fact", "session_id", sessionID, "fact_key", fact.Key, "error", err)
+		return false
+	}
+	if result.Action != domain.ModerationActionAllow {
+		logger.WarnContext(ctx, "learner memory fact rejected by moderation", "session_id", sessionID,
+			"fact_key", fact.Key, "action", result.Action, "categories", result.Categories)
+		return false
+	}
+	return true
+}
+
+// clearConversationMemory 清除会话中已注入的记忆（失败仅记录日志）
+func (s *learnerMemoryServiceImpl) clearConversationMemory(ctx context.Context, userID string) {
+	if s.conversationRepo == nil {
+		return
+	}
+	if err := s.conversationRepo.ClearLearnerMemory(ctx, userID); err != nil {
+		logger.ErrorContext(ctx, "learner memory clear conversation snapshot failed", "user_id", userID, "error", err)
+	}
+}
+
+// parseExtractedMemories 解析 LLM 返回的 {"facts": [...]}
+// 截取 JSON 对象部分，容忍多余文字；键规范化为小写蛇形，丢弃键或内容为空、置信度过低的条目，未知类别归为 other
+func parseExtractedMemories(reply string) ([]*extractedMemory, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("no json object in reply")
+	}
+	var raw struct {
+		Facts []*extractedMemory `json:"facts"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(raw.Facts))
+	facts := make([]*extractedMemory, 0, len(raw.Facts))
+	for _, f := range raw.Facts {
+		if f == nil {
+			continue
+		}
+		f.Key = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(f.Key)), " ", "_")
+		f.Category = strings.ToLower(strings.TrimSpace(f.Category))
+		f.Content = strings.TrimSpace(f.Content)
+		f.Confidence = math.Max(0, math.Min(1, f.Confidence))
+		if !memoryFactKeyPattern.MatchString(f.Key) || seen[f.Key] || f.Content == "" || f.Confidence < memoryMinConfidence {
+			continue
+		}
+		if utf8.RuneCountInString(f.Content) > memoryMaxContentRunes {
+			f.Content = string([]rune(f.Content)[:memoryMaxContentRunes])
+		}
+		if !memoryCategories[f.Category] {
+			f.Category = model.MemoryCategoryOther
+		}
+		seen[f.Key] = true
+		facts = append(facts, f)
+		if len(facts) == memoryMaxFactsPerSession {
+			break
+		}
+	}
+	return facts, nil
+}
+
+// toLearnerMemoryItem 转换为家长可见的记忆条目
+func toLearnerMemoryItem(m *model.LearnerMemory) *LearnerMemoryItem {
+	return &LearnerMemoryItem{
+		ID:           m.ID,
+		Category:     m.Category,
+		Content:      m.Content,
+		Confidence:   m.Confidence,
+		MentionCount: m.MentionCount,
+		FirstSeenAt:  m.FirstSeenAt.Format(time.RFC3339),
+		LastSeenAt:   m.LastSeenAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+func TestParseExtractedMemories(t *testing.T) {
+	tests := []struct {
+		name           string
+		reply          string
+		wantKeys       []string
+		wantCategories []string
+		wantErr        bool
+	}{
+		{
+			name:           "plain json",
+			reply:          `{"facts":[{"key":"pet_name","category":"pet","content":"Has a dog called Lucky","confidence":0.9}]}`,
+			wantKeys:       []string{"pet_name"},
+			wantCategories: []string{model.MemoryCategoryPet},
+		},
+		{
+			name:           "surrounding text and key normalization",
+			reply:          "Here you go:\n```json\n{\"facts\":[{\"key\":\" Favorite Color \",\"category\":\"FAVORITE\",\"content\":\"blue\",\"confidence\":0.8}]}\n```",
+			wantKeys:       []string{"favorite_color"},
+			wantCategories: []string{model.MemoryCategoryFavorite},
+		},
+		{
+			name:           "unknown category becomes other",
+			reply:          `{"facts":[{"key":"birthday","category":"date","content":"May 1st","confidence":0.7}]}`,
+			wantKeys:       []string{"birthday"},
+			wantCategories: []string{model.MemoryCategoryOther},
+		},
+		{
+			name: "drops invalid, empty, duplicate and low confidence facts",
+			reply: `{"facts":[
+				null,
+				{"key":"bad-key!","category":"hobby","content":"football","confidence":0.9},
+				{"key":"hobby","category":"hobby","content":"  ","confidence":0.9},
+				{"key":"hobby","category":"hobby","content":"football","confidence":0.9},
+				{"key":"hobby","category":"hobby","content":"swimming","confidence":0.9},
+				{"key":"school","category":"school","content":"Grade 3","confidence":0.3}
+			]}`,
+			wantKeys:       []string{"hobby"},
+			wantCategories: []string{model.MemoryCategoryHobby},
+		},
+		{
+			name: "caps facts per session",
+			reply: `{"facts":[
+				{"key":"a","category":"other","content":"1","confidence":1},
+				{"key":"b","category":"other","content":"2","confidence":1},
+				{"key":"c","category":"other","content":"3","confidence":1},
+				{"key":"d","category":"other","content":"4","confidence":1},
+				{"key":"e","category":"other","content":"5","confidence":1},
+				{"key":"f","category":"other","content":"6","confidence":1}
+			]}`,
+			wantKeys: []string{"a", "b", "c", "d", "e"},
+		},
+		{name: "no facts", reply: `{"facts":[]}`, wantKeys: []string{}},
+		{name: "no json", reply: "I could not find any facts.", wantErr: true},
+		{name: "broken json", reply: `{"facts":[{"key":}]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facts, err := parseExtractedMemories(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExtractedMemories() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(facts) != len(tt.wantKeys) {
+				t.Fatalf("got %d facts, want %d", len(facts), len(tt.wantKeys))
+			}
+			for i, f := range facts {
+				if f.Key != tt.wantKeys[i] {
+					t.Errorf("facts[%d].Key = %q, want %q", i, f.Key, tt.wantKeys[i])
+				}
+				if i < len(tt.wantCategories) && f.Category != tt.wantCategories[i] {
+					t.Errorf("facts[%d].Category = %q, want %q", i, f.Category, tt.wantCategories[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseExtractedMemoriesClampsValues(t *testing.T) {
+	long := strings.Repeat("很", memoryMaxContentRunes+10)
+	facts, err := parseExtractedMemories(`{"facts":[{"key":"long","category":"other","content":"` + long + `","confidence":1.5}]}`)
+	if err != nil || len(facts) != 1 {
+		t.Fatalf("parseExtractedMemories() = %v, %v", facts, err)
+	}
+	if n := utf8.RuneCountInString(facts[0].Content); n != memoryMaxContentRunes {
+		t.Errorf("content length = %d, want %d", n, memoryMaxContentRunes)
+	}
+	if facts[0].Confidence != 1 {
+		t.Errorf("confidence = %v, want 1", facts[0].Confidence)
+	}
+}
@@ -0,0 +1,125 @@
+// Package service 提供最小对立词（minimal pair）词典
+package service
+
+import (
+	"regexp"
+)
+
+// minimalPairContrast 音素对立组
+// 每组包含两个易混淆音素、识别线索和精选最小对立词
+type minimalPairContrast struct {
+	ID          string            // 对立组 ID，如 "l_r"
+	Phonemes    [2]string         // 对立音素（IPA），与 Pairs 中单词顺序对应
+	Description string            // 中文说明
+	Cues        [2]*regexp.Regexp // 拼写线索（按词首/词尾等位置锚定，用于从问题单词推断混淆和校验扩展词对）
+	Pairs       [][2]string       // 精选最小对立词
+}
+
+// minimalPairDictionary 精选最小对立词词典（随服务发布）
+// 覆盖中国儿童学习英语时最常见的音素混淆
+var minimalPairDictionary = []*minimalPairContrast{
+	{
+		ID:          "l_r",
+		Phonemes:    [2]string{"/l/", "/r/"},
+		Description: "舌尖抵上齿龈的 /l/ 与卷舌不接触的 /r/",
+		Cues:        [2]*regexp.Regexp{regexp.MustCompile(`\b[bcfgkps]?l|[aeiou]ll?[aeiouy]`), regexp.MustCompile(`\b[bcdfgkptw]?r|[aeiou]rr?[aeiouy]`)},
+		Pairs: [][2]string{
+			{"light", "right"}, {"lead", "read"}, {"long", "wrong"}, {"lock", "rock"},
+			{"fly", "fry"}, {"play", "pray"}, {"glass", "grass"}, {"collect", "correct"},
+			{"lane", "rain"}, {"lice", "rice"}, {"climb", "crime"}, {"belly", "berry"},
+		},
+	},
+	{
+		ID:          "ee_ih",
+		Phonemes:    [2]string{"/iː/", "/ɪ/"},
+		Description: "长元音 /iː/ 与短元音 /ɪ/",
+		Cues:        [2]*regexp.Regexp{regexp.MustCompile(`\b[^aeiou\s]*(ee|ea)[^aeiou\s]*e?\b`), regexp.MustCompile(`\b[^aeiou\s]*i([^aeiouy\s]+|ve)\b`)},
+		Pairs: [][2]string{
+			{"sheep", "ship"}, {"seat", "sit"}, {"leave", "live"}, {"feel", "fill"},
+			{"heel", "hill"}, {"beat", "bit"}, {"eat", "it"}, {"cheap", "chip"},
+			{"sleep", "slip"}, {"peak", "pick"}, {"feet", "fit"}, {"deep", "dip"},
+		},
+	},
+	{
+		ID:          "th_s",
+		Phonemes:    [2]string{"/θ/", "/s/"},
+		Description: "咬舌清辅音 /θ/ 与 /s/",
+		Cues:        [2]*regexp.Regexp{regexp.MustCompile(`\bth|th\b`), regexp.MustCompile(`\bs[^h]|ss\b|[^s]se\b|ce\b`)},
+		Pairs: [][2]string{
+			{"think", "sink"}, {"thick", "sick"}, {"thumb", "sum"}, {"mouth", "mouse"},
+			{"path", "pass"}, {"thing", "sing"}, {"faith", "face"}, {"worth", "worse"},
+		},
+	},
+	{
+		ID:          "dh_d",
+		Phonemes:    [2]string{"/ð/", "/d/"},
+		Description: "咬舌浊辅音 /ð/ 与 /d/",
+		Cues:        [2]*regexp.Regexp{regexp.MustCompile(`\bth|the\b`), regexp.MustCompile(`\bd|d\b`)},
+		Pairs: [][2]string{
+			{"they", "day"}, {"then", "den"}, {"those", "doze"}, {"though", "dough"},
+			{"there", "dare"}, {"breathe", "breed"},
+		},
+	},
+	{
+		ID:          "v_w",
+		Phonemes:    [2]string{"/v/", "/w/"},
+		Description: "上齿咬下唇的 /v/ 与圆唇的 /w/",
+		Cues:        [2]*regexp.Regexp{regexp.MustCompile(`\bv|ve\b`), regexp.MustCompile(`\bw`)},
+		Pairs: [][2]string{
+			{"vest", "west"}, {"vine", "wine"}, {"vet", "wet"}, {"verse", "worse"},
+			{"vow", "wow"}, {"veil", "whale"},
+		},
+	},
+	{
+		ID:          "ae_e",
+		Phonemes:    [2]string{"/æ/", "/e/"},
+		Description: "开口较大的 /æ/ 与 /e/",
+		Cues:        [2]*regexp.Regexp{regexp.MustCompile(`\b[^aeiou\s]*a[^aeiouy\s]+\b`), regexp.MustCompile(`\b[^aeiou\s]*(e|ea|ai)[^aeiouy\s]+\b`)},
+		Pairs: [][2]string{
+			{"bad", "bed"}, {"man", "men"}, {"sad", "said"}, {"pan", "pen"},
+			{"bat", "bet"}, {"had", "head"}, {"sat", "set"}, {"land", "lend"},
+		},
+	},
+	{
+		ID:          "n_l",
+		Phonemes:    [2]string{"/n/", "/l/"},
+		Description: "鼻音 /n/ 与边音 /l/",
+		Cues:        [2]*regexp.Regexp{regexp.MustCompile(`\b[ks]?n`), regexp.MustCompile(`\b[bcfgps]?l`)},
+		Pairs: [][2]string{
+			{"night", "light"}, {"no", "low"}, {"need", "lead"}, {"knock", "lock"},
+			{"nine", "line"}, {"snow", "slow"}, {"net", "let"},
+		},
+	},
+	{
+		ID:          "sh_s",
+		Phonemes:    [2]string{"/ʃ/", "/s/"},
+		Description: "圆唇的 /ʃ/ 与 /s/",
+		Cues:        [2]*regexp.Regexp{regexp.MustCompile(`\bsh|sh\b|tion\b`), regexp.MustCompile(`\bs[^h]|ss\b|ce\b`)},
+		Pairs: [][2]string{
+			{"she", "see"}, {"ship", "sip"}, {"shell", "sell"}, {"sheet", "seat"},
+			{"shore", "sore"}, {"shock", "sock"},
+		},
+	},
+	{
+		ID:          "uh_oo",
+		Phonemes:    [2]string{"/ʊ/", "/uː/"},
+		Description: "短元音 /ʊ/ 与长元音 /uː/",
+		Cues:        [2]*regexp.Regexp{regexp.MustCompile(`\b[^aeiou\s]*(u|oo)(ll|sh|t|d|k)\b|ould\b`), regexp.MustCompile(`\b[^aeiou\s]*(oo|ew|ue|ui)[^aeiouy\s]*(e|ed)?\b`)},
+		Pairs: [][2]string{
+			{"full", "fool"}, {"pull", "pool"}, {"soot", "suit"}, {"should", "shooed"},
+		},
+	},
+}
+
+// defaultMinimalPairContrasts 没有检测到混淆时的默认练习组
+var defaultMinimalPairContrasts = []string{"l_r", "ee_ih", "th_s"}
+
+// findMinimalPairContrast 根据 ID 查找对立组
+func findMinimalPairContrast(id string) *minimalPairContrast {
+	for _, c := range minimalPairDictionary {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
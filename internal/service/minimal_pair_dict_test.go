@@ -0,0 +1,80 @@
+package service
+
+import "testing"
+
+func TestMinimalPairDictionaryPairsMatchCues(t *testing.T) {
+	for _, c := range minimalPairDictionary {
+		for _, p := range c.Pairs {
+			if !matchesContrastCues(c, p[0], p[1]) {
+				t.Errorf("contrast %s: pair %q/%q does not match its cues", c.ID, p[0], p[1])
+			}
+		}
+	}
+}
+
+func TestMinimalPairCuesIgnoreUnrelatedSubstrings(t *testing.T) {
+	tests := []struct {
+		contrastID string
+		side       int
+		word       string
+		want       bool
+	}{
+		{"l_r", 0, "world", false},
+		{"l_r", 1, "world", false},
+		{"l_r", 0, "table", false},
+		{"l_r", 0, "clock", true},
+		{"l_r", 1, "bring", true},
+		{"ee_ih", 0, "idea", false},
+		{"ee_ih", 1, "time", false},
+		{"ee_ih", 1, "fish", true},
+		{"th_s", 0, "mother", false},
+		{"th_s", 1, "fish", false},
+		{"th_s", 1, "house", true},
+		{"dh_d", 1, "window", false},
+		{"dh_d", 1, "dog", true},
+		{"v_w", 0, "seven", false},
+		{"v_w", 1, "flower", false},
+		{"v_w", 0, "have", true},
+		{"ae_e", 0, "cake", false},
+		{"ae_e", 0, "cat", true},
+		{"n_l", 0, "banana", false},
+		{"n_l", 1, "apple", false},
+		{"sh_s", 0, "action", true},
+		{"sh_s", 1, "bus", false},
+		{"uh_oo", 0, "bus", false},
+		{"uh_oo", 1, "moon", true},
+	}
+	for _, tt := range tests {
+		c := findMinimalPairContrast(tt.contrastID)
+		if c == nil {
+			t.Fatalf("contrast %s not found", tt.contrastID)
+		}
+		if got := c.Cues[tt.side].MatchString(tt.word); got != tt.want {
+			t.Errorf("contrast %s cue %d match %q = %v, want %v", tt.contrastID, tt.side, tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestIsKnownMinimalPair(t *testing.T) {
+	c := findMinimalPairContrast("l_r")
+	expanded := [][2]string{{"load", "road"}}
+	tests := []struct {
+		name         string
+		wordA, wordB string
+		want         bool
+	}{
+		{"curated", "light", "right", true},
+		{"served expansion", "load", "road", true},
+		{"matches cues but never served", "lake", "rake", false},
+		{"reversed sides", "right", "light", false},
+		{"same word", "lake", "lake", false},
+		{"unrelated words", "cat", "dog", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isKnownMinimalPair(c, expanded, tt.wordA, tt.wordB); got != tt.want {
+				t.Errorf("isKnownMinimalPair(%q, %q) = %v, want %v", tt.wordA, tt.wordB, got, tt.want)
+			}
+		})
+	}
+}
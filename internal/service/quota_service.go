@@ -0,0 +1,220 @@
+// Package service 提供用户每日配额业务逻辑
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pronunciation-correction-system/internal/cache"
+	"pronunciation-correction-system/internal/config"
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/model"
+)
+
+// quotaFeatures 参与配额统计的功能（GET /user/quota 按此顺序返回）
+var quotaFeatures = []string{model.QuotaFeatureEvaluate, model.QuotaFeatureChat}
+
+// ===== 响应结构 =====
+
+// QuotaDecision 单次配额消耗结果
+type QuotaDecision struct {
+	Allowed   bool      // 是否允许本次请求
+	Unlimited bool      // 套餐不限次数（不计数）
+	Plan      string    // 用户套餐
+	Limit     int       // 每日上限
+	Used      int       // 已使用次数（含本次）
+	Remaining int       // 剩余次数
+	ResetAt   time.Time // 配额重置时间（次日零点）
+}
+
+// QuotaUsage 单个功能的配额使用情况
+type QuotaUsage struct {
+	Feature   string `json:"feature"`
+	Unlimited bool   `json:"unlimited"`
+	Limit     int    `json:"limit"`
+	Used      int    `json:"used"`
+	Remaining int    `json:"remaining"`
+}
+
+// UserQuotaResponse 用户今日配额
+type UserQuotaResponse struct {
+	Plan    string        `json:"plan"`
+	Date    string        `json:"date"`
+	ResetAt string        `json:"reset_at"`
+	Quotas  []*QuotaUsage `json:"quotas"`
+}
+
+// ===== Service 接口 =====
+
+// QuotaService 用户每日配额业务接口
+type QuotaService interface {
+	// Consume 在调用外部服务前消耗一次配额
+	// Redis 不可用时放行（Allowed=true, Unlimited=true），配额不应成为单点故障
+	Consume(ctx context.Context, userID, feature string) (*QuotaDecision, error)
+
+	// Refund 回退一次配额（请求因服务端原因失败时调用）
+	Refund(ctx context.Context, userID, feature string) error
+
+	// GetUserQuota 获取用户今日各功能配额使用情况
+	GetUserQuota(ctx context.Context, userID string) (*UserQuotaResponse, error)
+}
+
+// quotaServiceImpl Quota Service 实现
+type quotaServiceImpl struct {
+	repos      *db.Repositories
+	quotaCache *cache.QuotaCache // 可为 nil（Redis 不可用）
+	userCache  *cache.UserCache  // 可为 nil（Redis 不可用）
+	cfg        config.QuotaConfig
+	logger     *slog.Logger
+}
+
+// NewQuotaService 创建 QuotaService
+func NewQuotaService(
+	repos *db.Repositories,
+	quotaCache *cache.QuotaCache,
+	userCache *cache.UserCache,
+	cfg config.QuotaConfig,
+	logger *slog.Logger,
+) QuotaService {
+	if cfg.DefaultPlan == "" {
+		cfg.DefaultPlan = model.UserPlanFree
+	}
+	return &quotaServiceImpl{
+		repos:      repos,
+		quotaCache: quotaCache,
+		userCache:  userCache,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+func (s *quotaServiceImpl) Consume(ctx context.Context, userID, feature string) (*QuotaDecision, error) {
+	// ─── 1. 根据套餐确定上限 ───
+	plan := s.resolvePlan(ctx, userID)
+	limit := s.limitFor(plan, feature)
+	decision := &QuotaDecision{
+		Allowed: true,
+		Plan:    plan,
+		Limit:   limit,
+		ResetAt: quotaResetAt(time.Now()),
+	}
+	if limit < 0 || s.quotaCache == nil {
+		decision.Unlimited = true
+		return decision, nil
+	}
+
+	// ─── 2. 原子消耗配额 ───
+	allowed, used, err := s.quotaCache.ConsumeFeature(ctx, userID, feature, limit)
+	if err != nil {
+		return nil, fmt.Errorf("consume quota failed: %w", err)
+	}
+	decision.Allowed = allowed
+	decision.Used = used
+	decision.Remaining = limit - used
+	if decision.Remaining < 0 {
+		decision.Remaining = 0
+	}
+	return decision, nil
+}
+
+func (s *quotaServiceImpl) Refund(ctx context.Context, userID, feature string) error {
+	if s.quotaCache == nil {
+		return nil
+	}
+	if _, err := s.quotaCache.RefundFeature(ctx, userID, feature); err != nil {
+		return fmt.Errorf("refund quota failed: %w", err)
+	}
+	return nil
+}
+
+func (s *quotaServiceImpl) GetUserQuota(ctx context.Context, userID string) (*UserQuotaResponse, error) {
+	now := time.Now()
+	plan := s.resolvePlan(ctx, userID)
+	resp := &UserQuotaResponse{
+		Plan:    plan,
+		Date:    now.Format("2006-01-02"),
+		ResetAt: quotaResetAt(now).Format(time.RFC3339),
+		Quotas:  make([]*QuotaUsage, 0, len(quotaFeatures)),
+	}
+
+	for _, feature := range quotaFeatures {
+		usage := &QuotaUsage{Feature: feature, Limit: s.limitFor(plan, feature)}
+		if usage.Limit < 0 {
+			usage.Unlimited = true
+			usage.Limit = 0
+		}
+		if s.quotaCache != nil {
+			used, err := s.quotaCache.GetFeatureUsed(ctx, userID, feature)
+			if err != nil {
+				return nil, fmt.Errorf("get quota used failed: %w", err)
+			}
+			usage.Used = used
+		}
+		if !usage.Unlimited {
+			usage.Remaining = usage.Limit - usage.Used
+			if usage.Remaining < 0 {
+				usage.Remaining = 0
+			}
+		}
+		resp.Quotas = append(resp.Quotas, usage)
+	}
+	return resp, nil
+}
+
+// resolvePlan 查询用户套餐（缓存 → 数据库），失败或未配置时使用默认套餐
+func (s *quotaServiceImpl) resolvePlan(ctx context.Context, userID string) string {
+	if s.userCache != nil {
+		if user, err := s.userCache.GetProfile(ctx, userID); err == nil && user != nil && user.Plan != "" {
+			return s.knownPlan(user.Plan)
+		}
+	}
+	if s.repos == nil {
+		return s.cfg.DefaultPlan
+	}
+
+	user, err := s.repos.User.GetByID(ctx, userID)
+	if err != nil {
+		if !db.IsNotFound(err) {
+			s.logger.ErrorContext(ctx, "quota resolve plan failed", "user_id", userID, "error", err)
+		}
+		return s.cfg.DefaultPlan
+	}
+	if s.userCache != nil {
+		if cacheErr := s.userCache.SetProfile(ctx, user); cacheErr != nil {
+			s.logger.WarnContext(ctx, "quota cache user profile failed", "user_id", userID, "error", cacheErr)
+		}
+	}
+	if user.Plan == "" {
+		return s.cfg.DefaultPlan
+	}
+	return s.knownPlan(user.Plan)
+}
+
+// knownPlan 未在配置中定义的套餐按默认套餐处理
+func (s *quotaServiceImpl) knownPlan(plan string) string {
+	if _, ok := s.cfg.Plans[plan]; ok {
+		return plan
+	}
+	return s.cfg.DefaultPlan
+}
+
+// limitFor 获取套餐某功能的每日上限（负数表示不限）
+func (s *quotaServiceImpl) limitFor(plan, feature string) int {
+	quota := s.cfg.Plans[plan]
+	switch feature {
+	case model.QuotaFeatureEvaluate:
+		return quota.Evaluate
+	case model.QuotaFeatureChat:
+		return quota.Chat
+	default:
+		return -1
+	}
+}
+
+// quotaResetAt 配额重置时间（次日零点，与 Redis 计数器 TTL 一致）
+func quotaResetAt(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+}
@@ -3,7 +3,16 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/uuid"
 )
 
 // ===== 请求结构 =====
@@ -31,18 +40,19 @@ type GenerateReportRequest struct {
 
 // ReportMVPResponse MVP 报告响应
 type ReportMVPResponse struct {
-	ReportID           string   `json:"report_id"`
-	ReportType         string   `json:"report_type"`
-	PeriodStartDate    string   `json:"period_start_date"`
-	PeriodEndDate      string   `json:"period_end_date"`
-	TotalConversations int      `json:"total_conversations"`
-	TotalEvaluations   int      `json:"total_evaluations"`
-	AverageScore       float64  `json:"average_evaluation_score"`
-	ImprovementRate    float64  `json:"improvement_rate"`
-	Strengths          []string `json:"strengths"`
-	Weaknesses         []string `json:"weaknesses"`
-	Recommendations    string   `json:"recommendations"`
-	ReportContent      string   `json:"report_content"`
+	ReportID           string            `json:"report_id"`
+	ReportType         string            `json:"report_type"`
+	PeriodStartDate    string            `json:"period_start_date"`
+	PeriodEndDate      string            `json:"period_end_date"`
+	TotalConversations int               `json:"total_conversations"`
+	TotalEvaluations   int               `json:"total_evaluations"`
+	AverageScore       float64           `json:"average_evaluation_score"`
+	ImprovementRate    float64           `json:"improvement_rate"`
+	Strengths          []string          `json:"strengths"`
+	Weaknesses         []string          `json:"weaknesses"`
+	Recommendations    string            `json:"recommendations"`
+	ReportContent      string            `json:"report_content"`
+	VocabularyGrowth   *VocabularyGrowth `json:"vocabulary_growth"`
 }
 
 // ReportStatusResponse 报告生成状态
@@ -66,6 +76,7 @@ type ReportDetailResponse struct {
 	ChatStatistics        *ChatStatistics        `json:"chat_statistics"`
 	LearningInsights      *LearningInsights      `json:"learning_insights"`
 	AIGeneratedReport     *AIGeneratedReport     `json:"ai_generated_report"`
+	VocabularyGrowth      *VocabularyGrowth      `json:"vocabulary_growth"`
 	NextGoals             []string               `json:"next_goals"`
 }
 
@@ -159,31 +170,141 @@ type ReportService interface {
 	GetDashboard(ctx context.Context, userID string) (*DashboardResponse, error)
 }
 
-// ===== 空实现 =====
+// ===== 实现 =====
 
-// reportServiceImpl Report Service 实现（暂为空实现）
+// reportServiceImpl Report Service 实现
 type reportServiceImpl struct {
 	// TODO: Step2 注入依赖
-	// reportRepo     db.LearningReportRepository
-	// evaluationRepo db.PronunciationEvaluationRepository
-	// conversationRepo db.VoiceConversationRepository
 	// llmProvider    domain.LLMProvider
-	logger *slog.Logger
+	reportRepo        db.LearningReportRepository
+	evaluationRepo    db.PronunciationEvaluationRepository
+	conversationRepo  db.VoiceConversationRepository
+	messageRepo       db.ConversationMessageRepository // 对话语法批注 → 不足分析
+	vocabularyService VocabularyService                // 个人词汇表 → 词汇增长
+	logger            *slog.Logger
 }
 
 // NewReportService 创建 ReportService
-func NewReportService(logger *slog.Logger) ReportService {
-	return &reportServiceImpl{logger: logger}
+func NewReportService(repos *db.Repositories, vocabularyService VocabularyService, logger *slog.Logger) ReportService {
+	s := &reportServiceImpl{vocabularyService: vocabularyService, logger: logger}
+	if repos != nil {
+		s.reportRepo = repos.LearningReport
+		s.evaluationRepo = repos.PronunciationEvaluation
+		s.conversationRepo = repos.VoiceConversation
+		s.messageRepo = repos.ConversationMessage
+	}
+	return s
 }
 
 func (s *reportServiceImpl) ReportMVP(ctx context.Context, req *ReportMVPRequest) (*ReportMVPResponse, error) {
-	// TODO: Step2 实现
-	// 1. 查询用户在时间范围内的对话和评测数据
-	// 2. 统计汇总（总对话数、总评测数、平均分、进步率）
-	// 3. LLM 生成报告内容（strengths, weaknesses, recommendations）
-	// 4. 保存报告到数据库
-	// 5. 返回报告摘要和完整内容
-	return nil, nil
+	// 步骤 1：参数校验，确定统计周期 [start, end)
+	if req == nil || req.UserID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+	reportType := strings.TrimSpace(req.ReportType)
+	start, end, err := reportPeriod(reportType, strings.TrimSpace(req.StartDate), strings.TrimSpace(req.EndDate), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	resp := &ReportMVPResponse{
+		ReportID:        uuid.New(),
+		ReportType:      reportType,
+		PeriodStartDate: start.Format(historyDateLayout),
+		PeriodEndDate:   end.AddDate(0, 0, -1).Format(historyDateLayout),
+		Strengths:       []string{},
+		Weaknesses:      []string{},
+	}
+
+	// 步骤 2：统计周期内的对话数、评测数与平均分
+	if s.conversationRepo != nil {
+		count, err := s.conversationRepo.CountByUserIDAndDateRange(ctx, req.UserID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("count conversations failed: %w", err)
+		}
+		resp.TotalConversations = int(count)
+	}
+	if s.evaluationRepo != nil {
+		count, err := s.evaluationRepo.CountByUserIDAndDateRange(ctx, req.UserID, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("count evaluations failed: %w", err)
+		}
+		resp.TotalEvaluations = int(count)
+		if count > 0 {
+			if resp.AverageScore, err = s.evaluationRepo.GetAverageScoreByUserIDAndDateRange(ctx, req.UserID, start, end); err != nil {
+				return nil, fmt.Errorf("average evaluation score failed: %w", err)
+			}
+		}
+	}
+
+	// 步骤 3：不足分析（对话语法批注汇总，失败时报告其余内容照常生成）
+	weaknesses, err := s.grammarWeaknesses(ctx, req.UserID, start, end)
+	if err != nil {
+		logger.WarnContext(ctx, "report grammar weaknesses failed", "user_id", req.UserID, "error", err)
+	}
+	resp.Weaknesses = append(resp.Weaknesses, weaknesses...)
+
+	// 步骤 4：词汇增长（来自个人词汇表）
+	resp.VocabularyGrowth = s.vocabularyGrowth(ctx, req.UserID, start, end)
+
+	// TODO: Step2 LLM 生成 strengths、recommendations、report_content 与进步率
+
+	// 步骤 5：保存报告到数据库
+	if s.reportRepo != nil {
+		report := &model.LearningReport{
+			ID:                     resp.ReportID,
+			UserID:                 req.UserID,
+			ReportType:             reportType,
+			PeriodStartDate:        start,
+			PeriodEndDate:          end.AddDate(0, 0, -1),
+			TotalConversations:     resp.TotalConversations,
+			TotalEvaluations:       resp.TotalEvaluations,
+			AverageEvaluationScore: resp.AverageScore,
+			Strengths:              resp.Strengths,
+			Weaknesses:             resp.Weaknesses,
+		}
+		if err := s.reportRepo.Create(ctx, report); err != nil {
+			return nil, fmt.Errorf("save report failed: %w", err)
+		}
+	}
+	logger.InfoContext(ctx, "report mvp generated", "report_id", resp.ReportID, "user_id", req.UserID, "report_type", reportType)
+	return resp, nil
+}
+
+// reportPeriod 计算报告统计周期 [start, end)，日期按本地时区解析，end_date 当天包含在内
+// 未指定日期时 weekly 为截至今天的 7 天、monthly 为截至今天的 1 个月；custom 必须指定起止日期
+func reportPeriod(reportType, startDate, endDate string, now time.Time) (time.Time, time.Time, error) {
+	switch reportType {
+	case model.ReportTypeWeekly, model.ReportTypeMonthly, model.ReportTypeCustom:
+	default:
+		return time.Time{}, time.Time{}, apperr.ErrInvalidParam.WithMessage("report_type must be weekly, monthly or custom")
+	}
+	if reportType == model.ReportTypeCustom && (startDate == "" || endDate == "") {
+		return time.Time{}, time.Time{}, apperr.ErrInvalidParam.WithMessage("start_date and end_date are required for custom report")
+	}
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	if endDate != "" {
+		t, err := time.ParseInLocation(historyDateLayout, endDate, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, apperr.ErrInvalidParam.WithMessage("end_date must be YYYY-MM-DD")
+		}
+		end = t.AddDate(0, 0, 1)
+	}
+	start := end.AddDate(0, 0, -7)
+	if reportType == model.ReportTypeMonthly {
+		start = end.AddDate(0, -1, 0)
+	}
+	if startDate != "" {
+		t, err := time.ParseInLocation(historyDateLayout, startDate, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, apperr.ErrInvalidParam.WithMessage("start_date must be YYYY-MM-DD")
+		}
+		start = t
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, apperr.ErrInvalidParam.WithMessage("start_date must not be after end_date")
+	}
+	return start, end, nil
 }
 
 func (s *reportServiceImpl) GenerateReport(ctx context.Context, req *GenerateReportRequest) (string, error) {
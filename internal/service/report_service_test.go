@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportPeriod(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.Local)
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.Local) }
+	tests := []struct {
+		name               string
+		reportType         string
+		startDate, endDate string
+		wantStart, wantEnd time.Time
+		wantErr            bool
+	}{
+		{"weekly default", "weekly", "", "", day(1, 9), day(1, 16), false},
+		{"monthly default", "monthly", "", "", day(12, 16).AddDate(-1, 0, 0), day(1, 16), false},
+		{"weekly with end date", "weekly", "", "2024-01-10", day(1, 4), day(1, 11), false},
+		{"custom", "custom", "2024-01-01", "2024-01-01", day(1, 1), day(1, 2), false},
+		{"custom without dates", "custom", "2024-01-01", "", time.Time{}, time.Time{}, true},
+		{"start after end", "weekly", "2024-01-12", "2024-01-10", time.Time{}, time.Time{}, true},
+		{"invalid date", "weekly", "2024/01/01", "", time.Time{}, time.Time{}, true},
+		{"unknown type", "daily", "", "", time.Time{}, time.Time{}, true},
+		{"empty type", "", "", "", time.Time{}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := reportPeriod(tt.reportType, tt.startDate, tt.endDate, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reportPeriod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("reportPeriod() = [%v, %v), want [%v, %v)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
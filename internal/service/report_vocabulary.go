@@ -0,0 +1,23 @@
+// Package service 提供学习报告词汇增长（来自个人词汇表）
+package service
+
+import (
+	"context"
+	"time"
+
+	"pronunciation-correction-system/internal/pkg/logger"
+)
+
+// vocabularyGrowth 统计用户在报告周期 [start, end) 内的词汇增长
+// 词汇表不可用或查询失败时返回 nil（报告其余内容照常生成）
+func (s *reportServiceImpl) vocabularyGrowth(ctx context.Context, userID string, start, end time.Time) *VocabularyGrowth {
+	if s.vocabularyService == nil {
+		return nil
+	}
+	growth, err := s.vocabularyService.GetGrowth(ctx, userID, start, end)
+	if err != nil {
+		logger.WarnContext(ctx, "report vocabulary growth failed", "user_id", userID, "error", err)
+		return nil
+	}
+	return growth
+}
@@ -0,0 +1,87 @@
+// Package service 提供学习报告不足分析（来自对话语法批注）
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+// 语法不足汇总规则
+const (
+	grammarWeaknessMinCount = 2 // 同类错误出现次数达到该值才写入报告
+	grammarWeaknessMaxItems = 3 // 最多写入的错误类型数
+)
+
+// grammarErrorLabels 错误类型的中文名称（用于报告展示）
+var grammarErrorLabels = map[string]string{
+	model.GrammarErrorTense:       "时态",
+	model.GrammarErrorAgreement:   "主谓一致",
+	model.GrammarErrorArticle:     "冠词",
+	model.GrammarErrorPreposition: "介词",
+	model.GrammarErrorPlural:      "单复数",
+	model.GrammarErrorWordOrder:   "语序",
+	model.GrammarErrorMissingWord: "句子完整性（缺词）",
+	model.GrammarErrorWordChoice:  "用词",
+	model.GrammarErrorOther:       "语法",
+}
+
+// grammarWeaknesses 汇总用户在时间范围内对话中的语法 / 用词批注，生成报告不足分析条目
+// 按错误次数降序，每条附一个最近的例子，如：时态使用需加强（出现 5 次，如 "I go school" → "I went to school"）
+func (s *reportServiceImpl) grammarWeaknesses(ctx context.Context, userID string, start, end time.Time) ([]string, error) {
+	if s.messageRepo == nil {
+		return nil, nil
+	}
+	messages, err := s.messageRepo.GetAnnotatedByUserID(ctx, userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("get annotated messages failed: %w", err)
+	}
+
+	type errorStat struct {
+		errorType string
+		count     int
+		example   model.GrammarAnnotation // 最近一次出现的例子
+	}
+	stats := make(map[string]*errorStat)
+	for _, m := range messages {
+		for _, a := range m.Annotations {
+			stat, ok := stats[a.ErrorType]
+			if !ok {
+				stat = &errorStat{errorType: a.ErrorType}
+				stats[a.ErrorType] = stat
+			}
+			stat.count++
+			stat.example = a
+		}
+	}
+
+	sorted := make([]*errorStat, 0, len(stats))
+	for _, stat := range stats {
+		if stat.count >= grammarWeaknessMinCount {
+			sorted = append(sorted, stat)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].errorType < sorted[j].errorType
+	})
+	if len(sorted) > grammarWeaknessMaxItems {
+		sorted = sorted[:grammarWeaknessMaxItems]
+	}
+
+	weaknesses := make([]string, 0, len(sorted))
+	for _, stat := range sorted {
+		label, ok := grammarErrorLabels[stat.errorType]
+		if !ok {
+			label = grammarErrorLabels[model.GrammarErrorOther]
+		}
+		weaknesses = append(weaknesses, fmt.Sprintf("%s使用需加强（出现 %d 次，如 \"%s\" → \"%s\"）",
+			label, stat.count, stat.example.Original, stat.example.Corrected))
+	}
+	return weaknesses, nil
+}
@@ -0,0 +1,449 @@
+// Package service 提供单词间隔复习（SM-2）业务逻辑
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/domain"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/logger"
+	"pronunciation-correction-system/internal/pkg/uuid"
+)
+
+// ===== 请求结构 =====
+
+// RecordWordScoresRequest 记录单词级得分请求（评测完成后调用）
+type RecordWordScoresRequest struct {
+	UserID        string
+	EvaluationID  string
+	Words         []WordDetail
+	DemoAudioURLs map[string]string // 评测阶段已生成的单词示范音频（可选）
+}
+
+// SubmitReviewRequest 提交单词复习结果请求
+type SubmitReviewRequest struct {
+	ReviewID  string
+	AudioData []byte
+	AudioType string
+	UserID    string
+}
+
+// ===== 响应结构 =====
+
+// ReviewDrill 单词复习练习项
+type ReviewDrill struct {
+	ReviewID     string `json:"review_id"`
+	Word         string `json:"word"`
+	DemoAudioURL string `json:"demo_audio_url"`
+	LastScore    int    `json:"last_score"`
+	Repetitions  int    `json:"repetitions"`
+	IntervalDays int    `json:"interval_days"`
+	Status       string `json:"status"`
+	DueAt        string `json:"due_at"`
+}
+
+// TodayReviewResponse 今日复习列表响应
+type TodayReviewResponse struct {
+	Date     string         `json:"date"`      // 2026-01-02
+	DueTotal int64          `json:"due_total"` // 今日到期总数
+	Items    []*ReviewDrill `json:"items"`
+}
+
+// SubmitReviewResponse 单词复习结果响应
+type SubmitReviewResponse struct {
+	ReviewID     string  `json:"review_id"`
+	Word         string  `json:"word"`
+	Score        float64 `json:"score"`
+	Quality      int     `json:"quality"` // SM-2 回答质量 0-5
+	Passed       bool    `json:"passed"`
+	EaseFactor   float64 `json:"ease_factor"`
+	IntervalDays int     `json:"interval_days"`
+	Status       string  `json:"status"`
+	NextDueAt    string  `json:"next_due_at"`
+}
+
+// ===== Service 接口 =====
+
+// ReviewService 单词间隔复习业务接口
+type ReviewService interface {
+	// RecordWordScores 根据评测的单词级得分更新复习队列
+	// 低分单词入队（已在队列则降级），到期单词得高分则升级
+	RecordWordScores(ctx context.Context, req *RecordWordScoresRequest) error
+
+	// GetTodayReviews 获取今日到期的单词复习列表（含示范音频）
+	GetTodayReviews(ctx context.Context, userID string, limit int) (*TodayReviewResponse, error)
+
+	// SubmitReview 提交单词复习录音，评测后按 SM-2 重新调度
+	SubmitReview(ctx context.Context, req *SubmitReviewRequest) (*SubmitReviewResponse, error)
+}
+
+// ===== 实现 =====
+
+// reviewServiceImpl Review Service 实现
+type reviewServiceImpl struct {
+	repos              *db.Repositories
+	evaluationProvider domain.EvaluationProvider
+	ttsProvider        domain.TTSProvider
+	ossProvider        domain.OSSProvider
+	logger             *slog.Logger
+}
+
+// NewReviewService 创建 ReviewService
+func NewReviewService(
+	repos *db.Repositories,
+	evaluationProvider domain.EvaluationProvider,
+	ttsProvider domain.TTSProvider,
+	ossProvider domain.OSSProvider,
+	logger *slog.Logger,
+) ReviewService {
+	return &reviewServiceImpl{
+		repos:              repos,
+		evaluationProvider: evaluationProvider,
+		ttsProvider:        ttsProvider,
+		ossProvider:        ossProvider,
+		logger:             logger,
+	}
+}
+
+func (s *reviewServiceImpl) RecordWordScores(ctx context.Context, req *RecordWordScoresRequest) error {
+	if req == nil || req.UserID == "" {
+		return errors.New("record word scores request is invalid")
+	}
+
+	// ─── 1. 规范化单词，同一单词取最低分 ───
+	scores := make(map[string]float64, len(req.Words))
+	for _, w := range req.Words {
+		word := normalizeReviewWord(w.Word)
+		if word == "" {
+			continue
+		}
+		if prev, ok := scores[word]; !ok || w.Score < prev {
+			scores[word] = w.Score
+		}
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+
+	words := make([]string, 0, len(scores))
+	for word := range scores {
+		words = append(words, word)
+	}
+
+	// ─── 2. 查询已在队列中的单词 ───
+	existing, err := s.repos.ReviewItem.GetByUserIDAndWords(ctx, req.UserID, words)
+	if err != nil {
+		return fmt.Errorf("load review items failed: %w", err)
+	}
+	itemByWord := make(map[string]*model.ReviewItem, len(existing))
+	for _, item := range existing {
+		itemByWord[item.Word] = item
+	}
+
+	// ─── 3. 入队 / 升级 / 降级 ───
+	now := time.Now()
+	for _, word := range words {
+		score := scores[word]
+		item, inQueue := itemByWord[word]
+
+		switch {
+		case !inQueue && score < reviewEnqueueThreshold:
+			item = newReviewItem(req.UserID, word, score, now)
+			if req.EvaluationID != "" {
+				item.SourceEvaluationID = strPtr(req.EvaluationID)
+			}
+			if url := demoURLForWord(req.DemoAudioURLs, word); url != "" {
+				item.DemoAudioURL = strPtr(url)
+			}
+			if err := s.repos.ReviewItem.Create(ctx, item); err != nil && !db.IsDuplicate(err) {
+				logger.ErrorContext(ctx, "review enqueue word failed", "word", word, "error", err)
+			}
+
+		case !inQueue:
+			// 不在队列且发音良好，无需处理
+
+		case score < reviewEnqueueThreshold || !item.DueAt.After(now):
+			// 低分随时降级；高分仅在到期后升级，避免一天内反复朗读导致间隔虚增
+			scheduleReview(item, score, now)
+			if err := s.repos.ReviewItem.Update(ctx, item); err != nil {
+				logger.ErrorContext(ctx, "review reschedule word failed", "word", word, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *reviewServiceImpl) GetTodayReviews(ctx context.Context, userID string, limit int) (*TodayReviewResponse, error) {
+	if userID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+	if limit <= 0 || limit > maxDailyReviewLimit {
+		limit = defaultDailyReviewLimit
+	}
+
+	// ─── 1. 查询截至今日结束到期的单词 ───
+	now := time.Now()
+	endOfToday := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+
+	items, err := s.repos.ReviewItem.GetDueByUserID(ctx, userID, endOfToday, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list due review items failed: %w", err)
+	}
+	total, err := s.repos.ReviewItem.CountDueByUserID(ctx, userID, endOfToday)
+	if err != nil {
+		return nil, fmt.Errorf("count due review items failed: %w", err)
+	}
+
+	// ─── 2. 组装练习项，缺失示范音频时懒加载生成 ───
+	drills := make([]*ReviewDrill, 0, len(items))
+	for _, item := range items {
+		demoURL := ""
+		if item.DemoAudioURL != nil {
+			demoURL = *item.DemoAudioURL
+		}
+		if demoURL == "" {
+			demoURL = s.ensureDemoAudio(ctx, item)
+		}
+		drills = append(drills, &ReviewDrill{
+			ReviewID:     item.ID,
+			Word:         item.Word,
+			DemoAudioURL: demoURL,
+			LastScore:    item.LastScore,
+			Repetitions:  item.Repetitions,
+			IntervalDays: item.IntervalDays,
+			Status:       item.Status,
+			DueAt:        item.DueAt.Format(time.RFC3339),
+		})
+	}
+
+	return &TodayReviewResponse{
+		Date:     now.Format("2006-01-02"),
+		DueTotal: total,
+		Items:    drills,
+	}, nil
+}
+
+func (s *reviewServiceImpl) SubmitReview(ctx context.Context, req *SubmitReviewRequest) (*SubmitReviewResponse, error) {
+	// ─── 基础校验 ───
+	if req == nil || req.ReviewID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("review_id is required")
+	}
+	if len(req.AudioData) == 0 {
+		return nil, apperr.ErrInvalidParam.WithMessage("audio data is empty")
+	}
+	if s.evaluationProvider == nil {
+		return nil, errors.New("evaluation provider not initialized")
+	}
+
+	// ─── 1. 加载复习项并校验归属 ───
+	item, err := s.repos.ReviewItem.GetByID(ctx, req.ReviewID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, apperr.ErrNotFound.WithMessage("review item not found")
+		}
+		return nil, fmt.Errorf("get review item failed: %w", err)
+	}
+	if item.UserID != req.UserID {
+		return nil, apperr.ErrForbidden.WithMessage("review item does not belong to user")
+	}
+
+	// ─── 2. 单词模式评测 ───
+	evalResult, err := s.evaluationProvider.Assess(ctx, item.Word, req.AudioData, reviewItemCategory(item))
+	if err != nil {
+		logger.ErrorContext(ctx, "review assess failed", "review_id", item.ID, "error", err)
+		return nil, fmt.Errorf("speech assessment failed: %w", err)
+	}
+	score := wordScoreOf(evalResult.TotalScore, evalResult.Words, item.Word)
+
+	// ─── 3. SM-2 重新调度并保存 ───
+	now := time.Now()
+	quality := scheduleReview(item, score, now)
+	if err := s.repos.ReviewItem.Update(ctx, item); err != nil {
+		return nil, fmt.Errorf("update review item failed: %w", err)
+	}
+
+	logger.InfoContext(ctx, "review submitted",
+		"review_id", item.ID,
+		"word", item.Word,
+		"score", score,
+		"quality", quality,
+		"interval_days", item.IntervalDays,
+	)
+
+	return &SubmitReviewResponse{
+		ReviewID:     item.ID,
+		Word:         item.Word,
+		Score:        score,
+		Quality:      quality,
+		Passed:       quality >= sm2PassQuality,
+		EaseFactor:   item.EaseFactor,
+		IntervalDays: item.IntervalDays,
+		Status:       item.Status,
+		NextDueAt:    item.DueAt.Format(time.RFC3339),
+	}, nil
+}
+
+// ensureDemoAudio 为复习项生成单词示范音频并回写数据库
+// 生成失败时返回空字符串，不影响复习列表返回
+func (s *reviewServiceImpl) ensureDemoAudio(ctx context.Context, item *model.ReviewItem) string {
+	url, err := ensureWordDemoAudio(ctx, s.ttsProvider, s.ossProvider, item.Word)
+	if err != nil {
+		logger.ErrorContext(ctx, "review demo audio failed", "word", item.Word, "error", err)
+		return ""
+	}
+
+	if err := s.repos.ReviewItem.UpdateDemoAudioURL(ctx, item.ID, url); err != nil {
+		logger.ErrorContext(ctx, "review save demo audio url failed", "review_id", item.ID, "error", err)
+	}
+	return url
+}
+
+// ===================== SM-2 调度 =====================
+
+const (
+	// reviewEnqueueThreshold 单词得分低于该值时入队（与评测问题单词阈值一致）
+	reviewEnqueueThreshold = 60
+	// defaultDailyReviewLimit 每日复习列表默认条数
+	defaultDailyReviewLimit = 20
+	// maxDailyReviewLimit 每日复习列表最大条数
+	maxDailyReviewLimit = 100
+
+	// sm2InitialEaseFactor 初始难易度因子
+	sm2InitialEaseFactor = 2.5
+	// sm2MinEaseFactor 难易度因子下限
+	sm2MinEaseFactor = 1.3
+	// sm2PassQuality 回答质量及格线（< 3 视为遗忘）
+	sm2PassQuality = 3
+	// sm2MasteredIntervalDays 间隔达到该天数视为已掌握
+	sm2MasteredIntervalDays = 21
+)
+
+// newReviewItem 创建新入队的复习项（立即到期）
+func newReviewItem(userID, word string, score float64, now time.Time) *model.ReviewItem {
+	return &model.ReviewItem{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Word:         word,
+		EaseFactor:   sm2InitialEaseFactor,
+		IntervalDays: 0,
+		Repetitions:  0,
+		DueAt:        now,
+		LastScore:    clampScore(score),
+		Status:       model.ReviewStatusLearning,
+	}
+}
+
+// scheduleReview 按 SM-2 算法更新复习项，返回本次回答质量
+//
+//	EF' = EF + (0.1 - (5-q) * (0.08 + (5-q) * 0.02))，下限 1.3
+//	q < 3：连续次数清零，间隔重置为 1 天
+//	q >= 3：第 1 次 1 天，第 2 次 6 天，之后 interval * EF
+func scheduleReview(item *model.ReviewItem, score float64, now time.Time) int {
+	q := scoreToQuality(score)
+
+	diff := float64(5 - q)
+	item.EaseFactor += 0.1 - diff*(0.08+diff*0.02)
+	if item.EaseFactor < sm2MinEaseFactor {
+		item.EaseFactor = sm2MinEaseFactor
+	}
+
+	if q < sm2PassQuality {
+		item.Repetitions = 0
+		item.IntervalDays = 1
+		item.LapseCount++
+	} else {
+		item.Repetitions++
+		switch item.Repetitions {
+		case 1:
+			item.IntervalDays = 1
+		case 2:
+			item.IntervalDays = 6
+		default:
+			item.IntervalDays = int(math.Round(float64(item.IntervalDays) * item.EaseFactor))
+		}
+	}
+
+	switch {
+	case q < sm2PassQuality || item.Repetitions == 0:
+		item.Status = model.ReviewStatusLearning
+	case item.IntervalDays >= sm2MasteredIntervalDays:
+		item.Status = model.ReviewStatusMastered
+	default:
+		item.Status = model.ReviewStatusReviewing
+	}
+
+	item.DueAt = now.AddDate(0, 0, item.IntervalDays)
+	item.LastScore = clampScore(score)
+	item.ReviewCount++
+	item.LastReviewedAt = &now
+	return q
+}
+
+// scoreToQuality 将 0-100 单词得分映射为 SM-2 回答质量 0-5
+func scoreToQuality(score float64) int {
+	switch {
+	case score >= 90:
+		return 5
+	case score >= 75:
+		return 4
+	case score >= reviewEnqueueThreshold:
+		return 3
+	case score >= 40:
+		return 2
+	case score >= 20:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// clampScore 将得分限制在 0-100 并取整
+func clampScore(score float64) int {
+	return int(math.Round(math.Max(0, math.Min(100, score))))
+}
+
+// normalizeReviewWord 单词规范化：去除首尾标点并转小写
+func normalizeReviewWord(word string) string {
+	word = strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	return strings.ToLower(word)
+}
+
+// reviewItemCategory 复习项的评测题型：单词按单词模式评测，含空格的短语按句子评测
+func reviewItemCategory(item *model.ReviewItem) string {
+	if strings.ContainsFunc(strings.TrimSpace(item.Word), unicode.IsSpace) {
+		return domain.AssessCategorySentence
+	}
+	return domain.AssessCategoryWord
+}
+
+// wordScoreOf 从单词模式评测结果中取目标单词得分，找不到时使用总分
+func wordScoreOf(total float64, words []domain.WordEvaluationResult, word string) float64 {
+	for _, w := range words {
+		if normalizeReviewWord(w.Word) == word {
+			return w.Score
+		}
+	}
+	return total
+}
+
+// demoURLForWord 从示范音频映射中查找单词（忽略大小写和标点）
+func demoURLForWord(urls map[string]string, word string) string {
+	for w, url := range urls {
+		if normalizeReviewWord(w) == word {
+			return url
+		}
+	}
+	return ""
+}
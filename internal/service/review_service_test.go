@@ -0,0 +1,129 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"pronunciation-correction-system/internal/domain"
+	"pronunciation-correction-system/internal/model"
+)
+
+func TestScoreToQuality(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  int
+	}{
+		{100, 5}, {90, 5}, {89.9, 4}, {75, 4}, {74, 3}, {60, 3}, {59.9, 2}, {40, 2}, {39, 1}, {20, 1}, {19, 0}, {0, 0},
+	}
+	for _, tt := range tests {
+		if got := scoreToQuality(tt.score); got != tt.want {
+			t.Errorf("scoreToQuality(%v) = %d, want %d", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestScheduleReview(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name         string
+		item         model.ReviewItem
+		score        float64
+		wantQuality  int
+		wantEase     float64
+		wantInterval int
+		wantReps     int
+		wantLapses   int
+		wantStatus   string
+	}{
+		{
+			name:  "first pass",
+			item:  model.ReviewItem{EaseFactor: 2.5},
+			score: 95, wantQuality: 5, wantEase: 2.6, wantInterval: 1, wantReps: 1, wantStatus: model.ReviewStatusReviewing,
+		},
+		{
+			name:  "second pass",
+			item:  model.ReviewItem{EaseFactor: 2.6, IntervalDays: 1, Repetitions: 1},
+			score: 80, wantQuality: 4, wantEase: 2.6, wantInterval: 6, wantReps: 2, wantStatus: model.ReviewStatusReviewing,
+		},
+		{
+			name:  "later pass multiplies interval",
+			item:  model.ReviewItem{EaseFactor: 2.6, IntervalDays: 6, Repetitions: 2},
+			score: 95, wantQuality: 5, wantEase: 2.7, wantInterval: 16, wantReps: 3, wantStatus: model.ReviewStatusReviewing,
+		},
+		{
+			name:  "long interval is mastered",
+			item:  model.ReviewItem{EaseFactor: 2.5, IntervalDays: 16, Repetitions: 3},
+			score: 95, wantQuality: 5, wantEase: 2.6, wantInterval: 42, wantReps: 4, wantStatus: model.ReviewStatusMastered,
+		},
+		{
+			name:  "barely passing lowers ease",
+			item:  model.ReviewItem{EaseFactor: 2.5},
+			score: 60, wantQuality: 3, wantEase: 2.36, wantInterval: 1, wantReps: 1, wantStatus: model.ReviewStatusReviewing,
+		},
+		{
+			name:  "lapse resets and keeps ease floor",
+			item:  model.ReviewItem{EaseFactor: 1.3, IntervalDays: 16, Repetitions: 3, LapseCount: 1},
+			score: 10, wantQuality: 0, wantEase: 1.3, wantInterval: 1, wantReps: 0, wantLapses: 2, wantStatus: model.ReviewStatusLearning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			q := scheduleReview(&item, tt.score, now)
+			if q != tt.wantQuality {
+				t.Errorf("quality = %d, want %d", q, tt.wantQuality)
+			}
+			if math.Abs(item.EaseFactor-tt.wantEase) > 1e-9 {
+				t.Errorf("ease factor = %v, want %v", item.EaseFactor, tt.wantEase)
+			}
+			if item.IntervalDays != tt.wantInterval || item.Repetitions != tt.wantReps || item.LapseCount != tt.wantLapses {
+				t.Errorf("interval/reps/lapses = %d/%d/%d, want %d/%d/%d",
+					item.IntervalDays, item.Repetitions, item.LapseCount, tt.wantInterval, tt.wantReps, tt.wantLapses)
+			}
+			if item.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", item.Status, tt.wantStatus)
+			}
+			if want := now.AddDate(0, 0, tt.wantInterval); !item.DueAt.Equal(want) {
+				t.Errorf("due at = %v, want %v", item.DueAt, want)
+			}
+			if item.ReviewCount != tt.item.ReviewCount+1 || item.LastReviewedAt == nil {
+				t.Errorf("review count = %d, last reviewed = %v", item.ReviewCount, item.LastReviewedAt)
+			}
+		})
+	}
+}
+
+func TestReviewItemCategory(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"apple", domain.AssessCategoryWord},
+		{" apple ", domain.AssessCategoryWord},
+		{"ice cream", domain.AssessCategorySentence},
+		{"good\tmorning", domain.AssessCategorySentence},
+	}
+	for _, tt := range tests {
+		if got := reviewItemCategory(&model.ReviewItem{Word: tt.word}); got != tt.want {
+			t.Errorf("reviewItemCategory(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeReviewWord(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"Apple,", "apple"},
+		{"\"Hello!\"", "hello"},
+		{"don't", "don't"},
+		{"...", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeReviewWord(tt.word); got != tt.want {
+			t.Errorf("normalizeReviewWord(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package service 提供请求分阶段耗时统计
+package service
+
+import (
+	"sync"
+	"time"
+
+	"pronunciation-correction-system/internal/model"
+)
+
+// LatencyBreakdown 一次请求的耗时明细（仅服务以 debug 模式运行时随响应返回）
+type LatencyBreakdown struct {
+	TotalMS int                  `json:"total_ms"` // 总耗时（含未单列的会话解析、内容审核、数据库读取等）
+	Stages  model.StageLatencies `json:"stages"`   // 各阶段耗时（毫秒），阶段名见 model.LatencyStage*
+}
+
+// stageTimer 记录一次请求各处理阶段的耗时，同一阶段多次计时时累加
+// 零值不可用，需通过 newStageTimer 创建；nil 时所有方法为空操作
+type stageTimer struct {
+	mu     sync.Mutex
+	start  time.Time
+	stages map[string]time.Duration
+	marked map[string]bool
+}
+
+// newStageTimer 创建并从当前时间开始计时
+func newStageTimer() *stageTimer {
+	return &stageTimer{
+		start:  time.Now(),
+		stages: make(map[string]time.Duration),
+		marked: make(map[string]bool),
+	}
+}
+
+// restartAt 将总耗时的起点改为 at（实时对话从孩子说完开始计时）
+func (t *stageTimer) restartAt(at time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start = at
+}
+
+// track 开始为 stage 计时，调用返回的函数结束计时
+func (t *stageTimer) track(stage string) func() {
+	if t == nil {
+		return func() {}
+	}
+	begin := time.Now()
+	return func() { t.add(stage, time.Since(begin)) }
+}
+
+// trackExcluding 同 track，但计时期间累加到 nested 阶段的耗时不计入 stage
+// 用于流式生成：LLM 每凑满一句即同步合成语音，合成耗时单独计入 tts
+func (t *stageTimer) trackExcluding(stage, nested string) func() {
+	if t == nil {
+		return func() {}
+	}
+	begin := time.Now()
+	nestedBefore := t.get(nested)
+	return func() { t.add(stage, time.Since(begin)-(t.get(nested)-nestedBefore)) }
+}
+
+// mark 记录从起点到当前的耗时到 stage，仅第一次调用生效（如首段音频推送）
+func (t *stageTimer) mark(stage string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.marked[stage] {
+		return
+	}
+	t.marked[stage] = true
+	t.stages[stage] = time.Since(t.start)
+}
+
+// add 累加 stage 的耗时
+func (t *stageTimer) add(stage string, d time.Duration) {
+	if t == nil || d < 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stages[stage] += d
+}
+
+// get 获取 stage 当前累计的耗时
+func (t *stageTimer) get(stage string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stages[stage]
+}
+
+// breakdown 返回截至当前的耗时明细（毫秒）
+func (t *stageTimer) breakdown() *LatencyBreakdown {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stages := make(model.StageLatencies, len(t.stages))
+	for stage, d := range t.stages {
+		stages[stage] = int(d.Milliseconds())
+	}
+	return &LatencyBreakdown{TotalMS: int(time.Since(t.start).Milliseconds()), Stages: stages}
+}
@@ -0,0 +1,258 @@
+// Package service 提供个人词汇表业务逻辑（会的单词、本周新词、词汇增长）
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"pronunciation-correction-system/internal/db"
+	"pronunciation-correction-system/internal/model"
+	apperr "pronunciation-correction-system/internal/pkg/errors"
+	"pronunciation-correction-system/internal/pkg/uuid"
+	"pronunciation-correction-system/internal/pkg/vocab"
+)
+
+// 个人词汇表规则
+const (
+	vocabularyKnownMinSpoken   = 2  // 对话中说出次数达到该值的单词算作"会的单词"（朗读不计入）
+	vocabularyMaxWordLength    = 50 // 词元最大长度（与表字段一致，超长的视为识别噪声）
+	vocabularyGrowthSampleSize = 10 // 词汇增长中展示的新词示例数
+)
+
+// vocabularySources 词汇来源
+var vocabularySources = map[string]bool{
+	model.VocabularySourceConversation: true,
+	model.VocabularySourceEvaluation:   true,
+}
+
+// ===== 请求结构 =====
+
+// RecordVocabularyRequest 记录文本词汇请求
+type RecordVocabularyRequest struct {
+	UserID string
+	Text   string // 对话 ASR 文本或评测目标文本
+	Source string // conversation / evaluation
+}
+
+// KnownWordsRequest 查询会的单词请求
+type KnownWordsRequest struct {
+	UserID   string
+	Band     string // A1 / A2 / B1 / B2 / unlisted（可选）
+	Page     int
+	PageSize int
+}
+
+// ===== 响应结构 =====
+
+// VocabularyWord 词汇表单词
+type VocabularyWord struct {
+	Word        string `json:"word"`
+	Band        string `json:"band"`
+	Frequency   int    `json:"frequency"`
+	SpokenCount int    `json:"spoken_count"`
+	ReadCount   int    `json:"read_count"`
+	FirstSeenAt string `json:"first_seen_at"`
+	LastSeenAt  string `json:"last_seen_at"`
+}
+
+// VocabularyGrowth 时间范围内的词汇增长（用于学习报告）
+type VocabularyGrowth struct {
+	TotalWords     int64            `json:"total_words"`       // 截至范围结束的词汇总数
+	NewWords       int64            `json:"new_words"`         // 范围内首次出现的单词数
+	NewWordsByBand map[string]int64 `json:"new_words_by_band"` // 新词按等级分布
+	NewWordSamples []string         `json:"new_word_samples"`  // 新词示例（等级由高到低）
+}
+
+// ===== Service 接口 =====
+
+// VocabularyService 个人词汇表业务接口
+type VocabularyService interface {
+	// RecordText 分词、还原词形后累计到用户词汇表
+	RecordText(ctx context.Context, req *RecordVocabularyRequest) error
+
+	// GetKnownWords 分页获取会的单词（出现次数达到阈值，按次数降序）
+	GetKnownWords(ctx context.Context, req *KnownWordsRequest) ([]*VocabularyWord, int64, error)
+
+	// GetNewWords 分页获取本周（周一 00:00 起）首次出现的单词
+	GetNewWords(ctx context.Context, userID string, page, pageSize int) ([]*VocabularyWord, int64, error)
+
+	// GetGrowth 统计时间范围 [start, end) 内的词汇增长
+	GetGrowth(ctx context.Context, userID string, start, end time.Time) (*VocabularyGrowth, error)
+}
+
+// ===== 实现 =====
+
+// vocabularyServiceImpl Vocabulary Service 实现
+type vocabularyServiceImpl struct {
+	repos  *db.Repositories
+	logger *slog.Logger
+}
+
+// NewVocabularyService 创建 VocabularyService
+func NewVocabularyService(repos *db.Repositories, logger *slog.Logger) VocabularyService {
+	return &vocabularyServiceImpl{repos: repos, logger: logger}
+}
+
+func (s *vocabularyServiceImpl) RecordText(ctx context.Context, req *RecordVocabularyRequest) error {
+	if req == nil || req.UserID == "" || !vocabularySources[req.Source] {
+		return errors.New("record vocabulary request is invalid")
+	}
+
+	// ─── 1. 分词并还原词形 ───
+	counts := vocab.CountLemmas(req.Text)
+	if len(counts) == 0 {
+		return nil
+	}
+
+	// ─── 2. 组装词汇记录（按词元排序，批量写入时加锁顺序固定） ───
+	now := time.Now()
+	entries := make([]*model.UserVocabulary, 0, len(counts))
+	for lemma, count := range counts {
+		if len(lemma) > vocabularyMaxWordLength {
+			continue
+		}
+		entry := &model.UserVocabulary{
+			ID:          uuid.New(),
+			UserID:      req.UserID,
+			Word:        lemma,
+			Band:        vocab.BandUnlisted,
+			Frequency:   count,
+			FirstSeenAt: now,
+			LastSeenAt:  now,
+		}
+		if e, ok := vocab.Lookup(lemma); ok {
+			entry.Band = e.Band
+			entry.FrequencyRank = e.Rank
+		}
+		if req.Source == model.VocabularySourceConversation {
+			entry.SpokenCount = count
+		} else {
+			entry.ReadCount = count
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Word < entries[j].Word })
+
+	// ─── 3. 累计写入 ───
+	if err := s.repos.UserVocabulary.Upsert(ctx, entries); err != nil {
+		return fmt.Errorf("upsert user vocabulary failed: %w", err)
+	}
+	return nil
+}
+
+func (s *vocabularyServiceImpl) GetKnownWords(ctx context.Context, req *KnownWordsRequest) ([]*VocabularyWord, int64, error) {
+	if req == nil || req.UserID == "" {
+		return nil, 0, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+	if req.Band != "" && req.Band != vocab.BandUnlisted && !isVocabularyBand(req.Band) {
+		return nil, 0, apperr.ErrInvalidParam.WithMessage("band must be one of A1, A2, B1, B2, unlisted")
+	}
+
+	entries, total, err := s.repos.UserVocabulary.ListByUserID(ctx, req.UserID, vocabularyKnownMinSpoken, req.Band, req.Page, req.PageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list known words failed: %w", err)
+	}
+	return toVocabularyWords(entries), total, nil
+}
+
+func (s *vocabularyServiceImpl) GetNewWords(ctx context.Context, userID string, page, pageSize int) ([]*VocabularyWord, int64, error) {
+	if userID == "" {
+		return nil, 0, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+
+	now := time.Now()
+	entries, total, err := s.repos.UserVocabulary.ListFirstSeenBetween(ctx, userID, startOfWeek(now), now.Add(time.Second), page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list new words failed: %w", err)
+	}
+	return toVocabularyWords(entries), total, nil
+}
+
+func (s *vocabularyServiceImpl) GetGrowth(ctx context.Context, userID string, start, end time.Time) (*VocabularyGrowth, error) {
+	if userID == "" {
+		return nil, apperr.ErrInvalidParam.WithMessage("user id is empty")
+	}
+
+	// ─── 1. 词汇总数与新词等级分布 ───
+	total, err := s.repos.UserVocabulary.CountByUserID(ctx, userID, end)
+	if err != nil {
+		return nil, fmt.Errorf("count user vocabulary failed: %w", err)
+	}
+	bandCounts, err := s.repos.UserVocabulary.CountBandsFirstSeenBetween(ctx, userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("count new words by band failed: %w", err)
+	}
+	growth := &VocabularyGrowth{
+		TotalWords:     total,
+		NewWordsByBand: make(map[string]int64, len(bandCounts)),
+		NewWordSamples: []string{},
+	}
+	for _, bc := range bandCounts {
+		growth.NewWords += bc.Count
+		growth.NewWordsByBand[bc.Band] = bc.Count
+	}
+	if growth.NewWords == 0 {
+		return growth, nil
+	}
+
+	// ─── 2. 新词示例：优先展示等级高（更难）的词表内单词 ───
+	entries, _, err := s.repos.UserVocabulary.ListFirstSeenBetween(ctx, userID, start, end, 1, int(growth.NewWords))
+	if err != nil {
+		return nil, fmt.Errorf("list new words failed: %w", err)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return vocabularySampleRank(entries[i]) > vocabularySampleRank(entries[j])
+	})
+	for _, e := range entries {
+		if len(growth.NewWordSamples) >= vocabularyGrowthSampleSize {
+			break
+		}
+		growth.NewWordSamples = append(growth.NewWordSamples, e.Word)
+	}
+	return growth, nil
+}
+
+// isVocabularyBand 判断是否为词频表收录的等级
+func isVocabularyBand(band string) bool {
+	for _, b := range vocab.Bands {
+		if b == band {
+			return true
+		}
+	}
+	return false
+}
+
+// vocabularySampleRank 新词示例排序依据：词表内单词按排名（越靠后越难），未收录的单词（多为专有名词或识别噪声）排最后
+func vocabularySampleRank(entry *model.UserVocabulary) int {
+	if entry.Band == vocab.BandUnlisted {
+		return 0
+	}
+	return entry.FrequencyRank
+}
+
+// startOfWeek 本周一 00:00（本地时间）
+func startOfWeek(now time.Time) time.Time {
+	offset := (int(now.Weekday()) + 6) % 7 // 周一为 0
+	return time.Date(now.Year(), now.Month(), now.Day()-offset, 0, 0, 0, 0, now.Location())
+}
+
+// toVocabularyWords 转换为响应结构
+func toVocabularyWords(entries []*model.UserVocabulary) []*VocabularyWord {
+	words := make([]*VocabularyWord, 0, len(entries))
+	for _, e := range entries {
+		words = append(words, &VocabularyWord{
+			Word:        e.Word,
+			Band:        e.Band,
+			Frequency:   e.Frequency,
+			SpokenCount: e.SpokenCount,
+			ReadCount:   e.ReadCount,
+			FirstSeenAt: e.FirstSeenAt.Format(time.RFC3339),
+			LastSeenAt:  e.LastSeenAt.Format(time.RFC3339),
+		})
+	}
+	return words
+}
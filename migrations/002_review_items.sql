@@ -0,0 +1,47 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 单词间隔复习队列
+-- 版本: v2.1
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 8：review_items（单词复习队列表）
+-- 用途：基于 SM-2 算法记录用户问题单词的复习进度
+--
+-- 调度规则：
+--   单词得分 < 60 时入队；后续单词得分决定升级（间隔延长）或降级（重置为 1 天）
+--   interval_days >= 21 视为已掌握（mastered）
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS `review_items` (
+    `id`                    VARCHAR(36)     NOT NULL                    COMMENT '复习项ID (UUID)',
+    `user_id`               VARCHAR(36)     NOT NULL                    COMMENT '用户ID (FK → users.id)',
+    `word`                  VARCHAR(100)    NOT NULL                    COMMENT '单词（小写规范化）',
+
+    -- SM-2 调度字段
+    `ease_factor`           FLOAT           NOT NULL DEFAULT 2.5        COMMENT '难易度因子（>=1.3）',
+    `interval_days`         INT             NOT NULL DEFAULT 0          COMMENT '当前复习间隔（天）',
+    `repetitions`           INT             NOT NULL DEFAULT 0          COMMENT '连续答对次数',
+    `due_at`                TIMESTAMP       NOT NULL                    COMMENT '下次复习时间',
+
+    -- 统计字段
+    `last_score`            INT             NOT NULL DEFAULT 0          COMMENT '最近一次单词得分（0-100）',
+    `review_count`          INT             NOT NULL DEFAULT 0          COMMENT '累计复习次数',
+    `lapse_count`           INT             NOT NULL DEFAULT 0          COMMENT '累计遗忘次数',
+    `status`                ENUM('learning','reviewing','mastered') NOT NULL DEFAULT 'learning' COMMENT '复习状态',
+
+    -- 来源与示范
+    `source_evaluation_id`  VARCHAR(36)     DEFAULT NULL                COMMENT '首次入队的评测ID',
+    `demo_audio_url`        VARCHAR(500)    DEFAULT NULL                COMMENT '单词示范音频URL',
+    `last_reviewed_at`      TIMESTAMP       DEFAULT NULL                COMMENT '最近一次复习时间',
+    `created_at`            TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP  COMMENT '创建时间',
+    `updated_at`            TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_review_items_user_word` (`user_id`, `word`),
+    INDEX `idx_review_items_user_due` (`user_id`, `due_at`),
+    INDEX `idx_review_items_status` (`status`),
+    CONSTRAINT `fk_review_items_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='单词复习队列表';
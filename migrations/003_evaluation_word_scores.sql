@@ -0,0 +1,15 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 持久化单词级评测结果
+-- 版本: v2.2
+-- 数据库: MySQL 8.0+
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 5：pronunciation_evaluations 新增 word_scores
+-- 用途：保存单词级得分，支持同一文本多次尝试的逐词对比
+-- 历史记录该字段为 NULL，对比时回退到 problem_words
+-- ============================================================================
+ALTER TABLE `pronunciation_evaluations`
+    ADD COLUMN `word_scores` JSON DEFAULT NULL COMMENT '单词级评测结果 (JSON数组)' AFTER `problem_word_audio_urls`;
@@ -0,0 +1,14 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 用户套餐
+-- 版本: v2.3
+-- 数据库: MySQL 8.0+
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 1：users 新增 plan
+-- 用途：按套餐区分每日评测/对话配额（free / premium / admin）
+-- ============================================================================
+ALTER TABLE `users`
+    ADD COLUMN `plan` VARCHAR(20) NOT NULL DEFAULT 'free' COMMENT '用户套餐: free/premium/admin' AFTER `grade`;
@@ -0,0 +1,28 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 多轮对话上下文
+-- 版本: v2.4
+-- 数据库: MySQL 8.0+
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 3：voice_conversations 新增上下文摘要与最后交互时间
+-- 用途：多轮对话超出上下文窗口时，较早轮次合并为滚动摘要；会话列表按最后交互时间排序
+-- ============================================================================
+ALTER TABLE `voice_conversations`
+    ADD COLUMN `context_summary`  TEXT      DEFAULT NULL         COMMENT '较早轮次的滚动摘要（LLM 上下文使用）' AFTER `feedback`,
+    ADD COLUMN `summarized_until` INT       NOT NULL DEFAULT 0   COMMENT '已并入摘要的最大消息序号' AFTER `context_summary`,
+    ADD COLUMN `last_message_at`  TIMESTAMP NULL DEFAULT NULL    COMMENT '最后一条消息时间' AFTER `summarized_until`,
+    ADD INDEX `idx_voice_conversations_user_last_message` (`user_id`, `last_message_at`);
+
+-- 历史会话以最后一条消息时间回填
+UPDATE `voice_conversations` vc
+    JOIN (
+        SELECT `conversation_id`, MAX(`created_at`) AS `last_at`
+        FROM `conversation_messages`
+        GROUP BY `conversation_id`
+    ) m ON m.`conversation_id` = vc.`id`
+SET vc.`last_message_at` = m.`last_at`
+WHERE vc.`last_message_at` IS NULL;
+
@@ -0,0 +1,101 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 角色扮演场景库
+-- 版本: v2.5
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 9：conversation_scenarios（角色扮演场景表）
+-- 用途：场景化对话（餐厅点餐、商店购物、学校生活等）的角色、目标与成功标准
+--
+-- 完成规则：
+--   每轮对话后由 LLM 判断孩子已达成的 success_criteria，全部达成即完成目标，会话结束
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS `conversation_scenarios` (
+    `id`                VARCHAR(36)     NOT NULL                    COMMENT '场景ID (UUID)',
+    `code`              VARCHAR(50)     NOT NULL                    COMMENT '场景编码（唯一）',
+    `title`             VARCHAR(200)    NOT NULL                    COMMENT '场景标题',
+    `category`          VARCHAR(50)     NOT NULL                    COMMENT '场景分类（restaurant / shopping / school 等）',
+    `description`       TEXT            DEFAULT NULL                COMMENT '场景简介',
+    `difficulty_level`  ENUM('beginner','intermediate','advanced') NOT NULL DEFAULT 'beginner' COMMENT '难度等级',
+
+    -- 角色扮演设定
+    `persona`           VARCHAR(500)    NOT NULL                    COMMENT 'AI 扮演的角色',
+    `goal`              VARCHAR(500)    NOT NULL                    COMMENT '孩子需要完成的交际目标',
+    `opening_line`      VARCHAR(500)    NOT NULL                    COMMENT 'AI 开场白',
+    `target_vocabulary` JSON            DEFAULT NULL                COMMENT '目标词汇 (JSON数组)',
+    `success_criteria`  JSON            DEFAULT NULL                COMMENT '成功标准 (JSON数组)',
+
+    `is_active`         TINYINT(1)      NOT NULL DEFAULT 1          COMMENT '是否上架',
+    `sort_order`        INT             NOT NULL DEFAULT 0          COMMENT '排序（升序）',
+    `created_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP  COMMENT '创建时间',
+    `updated_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_conversation_scenarios_code` (`code`),
+    INDEX `idx_conversation_scenarios_category` (`category`),
+    INDEX `idx_conversation_scenarios_difficulty_level` (`difficulty_level`),
+    INDEX `idx_conversation_scenarios_is_active` (`is_active`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='角色扮演场景表';
+
+-- ============================================================================
+-- 表 3：voice_conversations 新增角色扮演字段
+-- 用途：role_play 会话关联场景，记录已达成的成功标准与完成目标时间
+-- ============================================================================
+ALTER TABLE `voice_conversations`
+    MODIFY COLUMN `conversation_type` ENUM('free_talk','question_answer','role_play') NOT NULL DEFAULT 'free_talk' COMMENT '对话类型',
+    ADD COLUMN `scenario_id`      VARCHAR(36) DEFAULT NULL       COMMENT '角色扮演场景ID (FK → conversation_scenarios.id)' AFTER `conversation_type`,
+    ADD COLUMN `met_criteria`     JSON        DEFAULT NULL       COMMENT '已达成的成功标准 (JSON数组)' AFTER `scenario_id`,
+    ADD COLUMN `goal_achieved_at` TIMESTAMP   NULL DEFAULT NULL  COMMENT '完成场景目标时间' AFTER `met_criteria`,
+    ADD INDEX `idx_voice_conversations_scenario_id` (`scenario_id`),
+    ADD CONSTRAINT `fk_voice_conversations_scenario_id` FOREIGN KEY (`scenario_id`) REFERENCES `conversation_scenarios` (`id`) ON DELETE SET NULL;
+
+-- ============================================================================
+-- 初始场景
+-- ============================================================================
+INSERT IGNORE INTO `conversation_scenarios`
+    (`id`, `code`, `title`, `category`, `description`, `difficulty_level`, `persona`, `goal`, `opening_line`, `target_vocabulary`, `success_criteria`, `sort_order`)
+VALUES
+    (UUID(), 'restaurant_order', 'Ordering Food', 'restaurant',
+     '在餐厅向服务员点一份主食和一杯饮料，并礼貌地道谢。', 'beginner',
+     'a friendly waiter at a small pizza restaurant',
+     'Order one dish and one drink, then say thank you',
+     'Hello! Welcome to Happy Pizza. What would you like to eat today?',
+     JSON_ARRAY('menu', 'pizza', 'juice', 'water', 'please', 'thank you'),
+     JSON_ARRAY('The child orders a food item', 'The child orders a drink', 'The child says please or thank you'),
+     10),
+    (UUID(), 'shopping_fruit', 'Buying Fruit', 'shopping',
+     '在水果店询问价格并买到想要的水果。', 'beginner',
+     'a cheerful shopkeeper at a fruit shop',
+     'Ask the price of a fruit and buy some',
+     'Hi there! We have fresh apples, bananas and oranges today. What are you looking for?',
+     JSON_ARRAY('apple', 'banana', 'orange', 'how much', 'dollar', 'buy'),
+     JSON_ARRAY('The child names a fruit they want', 'The child asks how much something costs', 'The child says how many they want to buy'),
+     20),
+    (UUID(), 'shopping_clothes', 'Shopping for Clothes', 'shopping',
+     '在服装店描述想要的衣服颜色和尺码，并决定是否购买。', 'intermediate',
+     'a helpful shop assistant at a clothes store',
+     'Find a piece of clothing in the right color and size and decide to buy it',
+     'Good afternoon! Are you looking for anything special today?',
+     JSON_ARRAY('T-shirt', 'jacket', 'size', 'color', 'try on', 'too big', 'too small'),
+     JSON_ARRAY('The child says what clothing they want', 'The child describes a color or size', 'The child asks to try it on or asks the price', 'The child decides to buy or not and says why'),
+     30),
+    (UUID(), 'school_first_day', 'First Day at School', 'school',
+     '新学期第一天向新同学介绍自己，并询问对方的爱好。', 'beginner',
+     'a friendly new classmate named Amy who loves drawing',
+     'Introduce yourself and learn one thing about your new classmate',
+     'Hi! I''m Amy. I''m new in this class too. What''s your name?',
+     JSON_ARRAY('name', 'years old', 'favorite', 'like', 'hobby', 'friend'),
+     JSON_ARRAY('The child tells their name', 'The child tells their age or grade', 'The child asks the classmate a question'),
+     40),
+    (UUID(), 'school_library', 'At the Library', 'school',
+     '在学校图书馆请图书管理员帮忙找书并借书。', 'intermediate',
+     'a kind school librarian',
+     'Ask for help finding a book about a topic you like and borrow it',
+     'Welcome to the library! Can I help you find something?',
+     JSON_ARRAY('book', 'borrow', 'return', 'library card', 'animals', 'stories'),
+     JSON_ARRAY('The child says what kind of book they want', 'The child asks where to find it or asks for help', 'The child asks to borrow the book'),
+     50);
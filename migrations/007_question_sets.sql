@@ -0,0 +1,82 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 问答题集
+-- 版本: v2.6
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 10：question_sets（问答题集表）
+-- 用途：question_answer 会话的题目来源，围绕一篇短文或一个角色扮演场景
+--
+-- 作答规则：
+--   每个回答由 LLM 判定是否切题、是否符合语法并打分（0-100）
+--   切题且得分 >= 60 视为通过；未通过时给出提示并重问，作答 2 次仍未通过则示范参考答案后进入下一题
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS `question_sets` (
+    `id`                VARCHAR(36)     NOT NULL                    COMMENT '题集ID (UUID)',
+    `code`              VARCHAR(50)     NOT NULL                    COMMENT '题集编码（唯一）',
+    `title`             VARCHAR(200)    NOT NULL                    COMMENT '题集标题',
+    `difficulty_level`  ENUM('beginner','intermediate','advanced') NOT NULL DEFAULT 'beginner' COMMENT '难度等级',
+    `scenario_id`       VARCHAR(36)     DEFAULT NULL                COMMENT '关联场景ID (FK → conversation_scenarios.id)',
+    `source_text`       TEXT            DEFAULT NULL                COMMENT '关联短文',
+    `introduction`      VARCHAR(500)    NOT NULL                    COMMENT '开场引导语',
+    `questions`         JSON            NOT NULL                    COMMENT '题目列表 (JSON数组: question / reference_answer / hint / keywords)',
+
+    `is_active`         TINYINT(1)      NOT NULL DEFAULT 1          COMMENT '是否上架',
+    `sort_order`        INT             NOT NULL DEFAULT 0          COMMENT '排序（升序）',
+    `created_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP  COMMENT '创建时间',
+    `updated_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_question_sets_code` (`code`),
+    INDEX `idx_question_sets_difficulty_level` (`difficulty_level`),
+    INDEX `idx_question_sets_scenario_id` (`scenario_id`),
+    INDEX `idx_question_sets_is_active` (`is_active`),
+    CONSTRAINT `fk_question_sets_scenario_id` FOREIGN KEY (`scenario_id`) REFERENCES `conversation_scenarios` (`id`) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='问答题集表';
+
+-- ============================================================================
+-- 表 3：voice_conversations 新增问答字段
+-- 用途：question_answer 会话关联题集，记录当前题目进度与每题作答结果
+-- ============================================================================
+ALTER TABLE `voice_conversations`
+    ADD COLUMN `question_set_id`   VARCHAR(36) DEFAULT NULL       COMMENT '问答题集ID (FK → question_sets.id)' AFTER `goal_achieved_at`,
+    ADD COLUMN `question_index`    INT         NOT NULL DEFAULT 0 COMMENT '当前题目序号（从 0 开始）' AFTER `question_set_id`,
+    ADD COLUMN `question_attempts` INT         NOT NULL DEFAULT 0 COMMENT '当前题目已作答次数' AFTER `question_index`,
+    ADD COLUMN `question_results`  JSON        DEFAULT NULL       COMMENT '每题作答结果 (JSON数组)' AFTER `question_attempts`,
+    ADD INDEX `idx_voice_conversations_question_set_id` (`question_set_id`),
+    ADD CONSTRAINT `fk_voice_conversations_question_set_id` FOREIGN KEY (`question_set_id`) REFERENCES `question_sets` (`id`) ON DELETE SET NULL;
+
+-- ============================================================================
+-- 初始题集
+-- ============================================================================
+INSERT IGNORE INTO `question_sets`
+    (`id`, `code`, `title`, `difficulty_level`, `source_text`, `introduction`, `questions`, `sort_order`)
+VALUES
+    (UUID(), 'story_lost_cat', 'Tom the Lost Cat', 'beginner',
+     'Lily has a little cat. His name is Tom. Tom is white and very small. One day, Tom runs out of the house. Lily looks for him in the garden and in the park. At last, she finds Tom under a big tree. Tom is sleeping! Lily is very happy.',
+     'Let''s read the story about Lily and her cat. Then I will ask you some questions.',
+     JSON_ARRAY(
+         JSON_OBJECT('question', 'What is the cat''s name?', 'reference_answer', 'The cat''s name is Tom.', 'hint', 'Look at the second sentence of the story.', 'keywords', JSON_ARRAY('Tom')),
+         JSON_OBJECT('question', 'What color is Tom?', 'reference_answer', 'Tom is white.', 'hint', 'It is the color of snow.', 'keywords', JSON_ARRAY('white')),
+         JSON_OBJECT('question', 'Where does Lily find Tom?', 'reference_answer', 'She finds Tom under a big tree.', 'hint', 'It is something tall and green in the park.', 'keywords', JSON_ARRAY('tree')),
+         JSON_OBJECT('question', 'How does Lily feel at the end?', 'reference_answer', 'Lily is very happy.', 'hint', 'Is she sad or happy?', 'keywords', JSON_ARRAY('happy'))
+     ),
+     10);
+
+-- 围绕角色扮演场景的题集（场景不存在时不插入）
+INSERT IGNORE INTO `question_sets`
+    (`id`, `code`, `title`, `difficulty_level`, `scenario_id`, `introduction`, `questions`, `sort_order`)
+SELECT UUID(), 'restaurant_questions', 'At the Restaurant', 'beginner', `id`,
+       'Let''s talk about eating at a restaurant. Answer my questions in full sentences.',
+       JSON_ARRAY(
+           JSON_OBJECT('question', 'What food do you like to eat at a restaurant?', 'reference_answer', 'I like to eat pizza.', 'hint', 'You can say: I like to eat ...', 'keywords', JSON_ARRAY('like', 'eat')),
+           JSON_OBJECT('question', 'What do you like to drink?', 'reference_answer', 'I like to drink orange juice.', 'hint', 'You can say: I like to drink ...', 'keywords', JSON_ARRAY('drink')),
+           JSON_OBJECT('question', 'What do you say when the waiter gives you your food?', 'reference_answer', 'I say thank you.', 'hint', 'It is a polite word. It starts with "thank".', 'keywords', JSON_ARRAY('thank'))
+       ),
+       20
+FROM `conversation_scenarios`
+WHERE `code` = 'restaurant_order';
@@ -0,0 +1,19 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 对话消息语法批注
+-- 版本: v2.7
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 4：conversation_messages 新增语法批注字段
+-- 用途：每轮对话保存后，由 LLM 异步分析用户消息中的语法 / 用词错误
+--       在对话历史中展示，并汇总进学习报告的不足分析（weaknesses）
+--
+-- 格式：[{"original":"I go school","corrected":"I went to school","error_type":"tense","explanation":"..."}]
+--       空数组表示已分析且没有错误；NULL 表示尚未分析（AI 消息始终为 NULL）
+-- ============================================================================
+ALTER TABLE `conversation_messages`
+    ADD COLUMN `annotations` JSON DEFAULT NULL COMMENT '语法/用词批注 (JSON数组)' AFTER `audio_duration`;
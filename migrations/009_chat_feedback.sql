@@ -0,0 +1,50 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 对话反馈评分
+-- 版本: v2.8
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 4：conversation_messages 新增提示词版本字段
+-- 用途：记录生成 AI 回复时使用的提示词版本，反馈评分按版本对比
+--       用户消息与非 LLM 生成的消息为 NULL
+-- ============================================================================
+ALTER TABLE `conversation_messages`
+    ADD COLUMN `prompt_version` VARCHAR(20) DEFAULT NULL COMMENT '生成回复的提示词版本（仅 AI 消息）' AFTER `annotations`;
+
+-- ============================================================================
+-- 表 11：chat_feedback（对话反馈表）
+-- 用途：用户对单条 AI 回复的评分，管理后台按提示词版本 / 场景 / 难度分析
+--
+-- 规则：
+--   每个用户对同一条 AI 回复只保留一条反馈，重复提交时覆盖
+--   prompt_version 为提交时从 AI 消息复制的快照
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS `chat_feedback` (
+    `id`                VARCHAR(36)     NOT NULL                    COMMENT '反馈ID (UUID)',
+    `user_id`           VARCHAR(36)     NOT NULL                    COMMENT '用户ID (FK → users.id)',
+    `conversation_id`   VARCHAR(36)     NOT NULL                    COMMENT '会话ID (FK → voice_conversations.id)',
+    `message_id`        VARCHAR(36)     NOT NULL                    COMMENT '被评价的 AI 消息ID (FK → conversation_messages.id)',
+    `turn`              INT             NOT NULL                    COMMENT '对话轮次（从 1 开始）',
+    `task_id`           VARCHAR(64)     DEFAULT NULL                COMMENT '异步对话任务ID（可选）',
+    `rating`            TINYINT         NOT NULL                    COMMENT '评分（1-5）',
+    `helpful`           TINYINT(1)      DEFAULT NULL                COMMENT '是否有帮助（NULL 表示未选择）',
+    `comment`           VARCHAR(500)    DEFAULT NULL                COMMENT '文字评价',
+    `prompt_version`    VARCHAR(20)     DEFAULT NULL                COMMENT '提示词版本快照',
+
+    `created_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP  COMMENT '创建时间',
+    `updated_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_chat_feedback_user_message` (`user_id`, `message_id`),
+    INDEX `idx_chat_feedback_conversation_id` (`conversation_id`),
+    INDEX `idx_chat_feedback_message_id` (`message_id`),
+    INDEX `idx_chat_feedback_created_at` (`created_at`),
+    INDEX `idx_chat_feedback_rating` (`rating`),
+    CONSTRAINT `fk_chat_feedback_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_chat_feedback_conversation_id` FOREIGN KEY (`conversation_id`) REFERENCES `voice_conversations` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_chat_feedback_message_id` FOREIGN KEY (`message_id`) REFERENCES `conversation_messages` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='对话反馈表';
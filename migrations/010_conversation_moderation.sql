@@ -0,0 +1,20 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 对话内容安全复核标记
+-- 版本: v2.9
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 3：voice_conversations 新增复核标记字段
+-- 用途：孩子输入或 AI 回复命中内容安全审核（拦截或改写）时标记会话，管理后台按标记时间复核
+--
+-- 规则：
+--   同一会话只记录首次标记的时间与原因，原因格式为 "方向:类别"（如 input:violence,output:pii）
+-- ============================================================================
+ALTER TABLE `voice_conversations`
+    ADD COLUMN `flagged_at`  TIMESTAMP    NULL DEFAULT NULL COMMENT '标记待复核时间（NULL 表示未标记）' AFTER `last_message_at`,
+    ADD COLUMN `flag_reason` VARCHAR(255) DEFAULT NULL      COMMENT '标记原因' AFTER `flagged_at`,
+    ADD INDEX `idx_voice_conversations_flagged_at` (`flagged_at`);
@@ -0,0 +1,29 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 中英双语对话与回复语言策略
+-- 版本: v2.10
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 2：user_profiles 新增回复语言策略字段
+-- 用途：孩子或家长选择对话回复的语言方式，决定系统提示词与是否返回中文释义
+--
+-- 取值：
+--   english_only         只用英文回复，孩子说中文时引导用英文表达
+--   chinese_scaffolding  初学者可用【】附简短中文提示，孩子说中文 / 中英混合时附中文释义（默认）
+--   translate_on_demand  英文回复，请求携带 translate=true 时附中文释义
+-- ============================================================================
+ALTER TABLE `user_profiles`
+    ADD COLUMN `language_policy` VARCHAR(30) NOT NULL DEFAULT 'chinese_scaffolding' COMMENT '回复语言策略' AFTER `total_study_minutes`;
+
+-- ============================================================================
+-- 表 4：conversation_messages 新增语言与中文释义字段
+-- 用途：language 记录用户消息 ASR 文本的语言（zh / en / mixed），AI 消息为 NULL
+--       translation 记录按语言策略生成的 AI 回复中文释义，未生成时为 NULL
+-- ============================================================================
+ALTER TABLE `conversation_messages`
+    ADD COLUMN `language`    VARCHAR(10) DEFAULT NULL COMMENT '用户消息语言 (zh/en/mixed)' AFTER `annotations`,
+    ADD COLUMN `translation` TEXT        DEFAULT NULL COMMENT 'AI 回复中文释义' AFTER `language`;
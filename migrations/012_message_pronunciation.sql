@@ -0,0 +1,33 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 对话发音评测
+-- 版本: v2.11
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 3：voice_conversations 新增会话问题单词字段
+-- 用途：会话总结时汇总在多轮发言中都发音不准的单词，随会话总结返回，
+--       并写入单词复习队列（review_items）
+--
+-- 格式：["three","bird"]；NULL 表示尚未总结或未开启发音评测
+-- ============================================================================
+ALTER TABLE `voice_conversations`
+    ADD COLUMN `problem_words` JSON DEFAULT NULL COMMENT '会话中反复发音不准的单词 (JSON数组)' AFTER `feedback`;
+
+-- ============================================================================
+-- 表 4：conversation_messages 新增发音评测字段
+-- 用途：每轮对话保存后，以 ASR 识别文本为参考文本，异步评测用户语音的发音
+--       pronunciation_score 为整句综合得分（0-100），word_scores 为单词级得分，
+--       problem_words 为得分 < 60 的单词
+--
+-- 格式：word_scores   [{"index":0,"word":"three","score":45.5,"is_problem":true}]
+--       problem_words ["three"]
+--       NULL 表示尚未评测或不评测（AI 消息、非英文消息、未开启发音评测时始终为 NULL）
+-- ============================================================================
+ALTER TABLE `conversation_messages`
+    ADD COLUMN `pronunciation_score` INT  DEFAULT NULL COMMENT '发音综合得分 (0-100)' AFTER `translation`,
+    ADD COLUMN `word_scores`         JSON DEFAULT NULL COMMENT '单词级发音得分 (JSON数组)' AFTER `pronunciation_score`,
+    ADD COLUMN `problem_words`       JSON DEFAULT NULL COMMENT '发音问题单词 (JSON数组)' AFTER `word_scores`;
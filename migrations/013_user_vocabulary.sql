@@ -0,0 +1,41 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 个人词汇表
+-- 版本: v2.12
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 12：user_vocabulary（个人词汇表）
+-- 用途：统计孩子实际说出 / 朗读过的英文单词，用于"会的单词"、"本周新词"与学习报告的词汇增长
+--
+-- 规则：
+--   对话的 ASR 文本与发音评测的目标文本分词、还原词形后按词元累计（went → go，cats → cat）
+--   每个用户的同一词元只保留一条记录，再次出现时累计次数并更新 last_seen_at
+--   band 来自内置词频表（A1 / A2 / B1 / B2），未收录的单词为 unlisted
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS `user_vocabulary` (
+    `id`                VARCHAR(36)     NOT NULL                    COMMENT '词汇ID (UUID)',
+    `user_id`           VARCHAR(36)     NOT NULL                    COMMENT '用户ID (FK → users.id)',
+    `word`              VARCHAR(50)     NOT NULL                    COMMENT '词元（小写）',
+    `band`              VARCHAR(10)     NOT NULL                    COMMENT '词汇等级 (A1/A2/B1/B2/unlisted)',
+    `frequency_rank`    INT             NOT NULL DEFAULT 0          COMMENT '词频表排名（未收录为 0）',
+
+    -- 统计字段
+    `frequency`         INT             NOT NULL DEFAULT 0          COMMENT '累计出现次数',
+    `spoken_count`      INT             NOT NULL DEFAULT 0          COMMENT '对话中说出的次数',
+    `read_count`        INT             NOT NULL DEFAULT 0          COMMENT '发音评测中朗读的次数',
+    `first_seen_at`     TIMESTAMP       NOT NULL                    COMMENT '首次出现时间',
+    `last_seen_at`      TIMESTAMP       NOT NULL                    COMMENT '最后出现时间',
+
+    `created_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP  COMMENT '创建时间',
+    `updated_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_user_vocabulary_user_word` (`user_id`, `word`),
+    INDEX `idx_user_vocabulary_user_first_seen` (`user_id`, `first_seen_at`),
+    INDEX `idx_user_vocabulary_band` (`band`),
+    CONSTRAINT `fk_user_vocabulary_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='个人词汇表';
@@ -0,0 +1,71 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - AI 老师人设
+-- 版本: v2.13
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 13：tutor_personas（AI 老师人设表）
+-- 用途：可选的 AI 老师形象，决定回复的性格（追加到系统提示词）与声音（TTS 音色、语速）
+--
+-- 规则：
+--   学习者在个人设置中选择人设，之后每轮对话都使用该人设的提示词与合成参数
+--   未选择人设（user_profiles.persona_id 为 NULL）时使用默认老师与 system_settings.default_tts_voice
+--   tts_voice 为 NULL 时同样使用 default_tts_voice；角色扮演会话由场景决定角色，人设只影响声音
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS `tutor_personas` (
+    `id`                VARCHAR(36)     NOT NULL                    COMMENT '人设ID (UUID)',
+    `code`              VARCHAR(50)     NOT NULL                    COMMENT '人设编码（唯一）',
+    `name`              VARCHAR(50)     NOT NULL                    COMMENT '人设名称',
+    `avatar_url`        VARCHAR(500)    DEFAULT NULL                COMMENT '头像URL',
+    `description`       TEXT            DEFAULT NULL                COMMENT '人设简介',
+
+    -- 人设设定
+    `personality`       TEXT            NOT NULL                    COMMENT '性格提示词片段（英文）',
+    `tts_voice`         VARCHAR(50)     DEFAULT NULL                COMMENT 'TTS 音色（NULL 使用默认音色）',
+    `speaking_rate`     FLOAT           NOT NULL DEFAULT 1.0        COMMENT '语速 (0.5-2.0)',
+
+    `is_active`         TINYINT(1)      NOT NULL DEFAULT 1          COMMENT '是否上架',
+    `sort_order`        INT             NOT NULL DEFAULT 0          COMMENT '排序（升序）',
+    `created_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP  COMMENT '创建时间',
+    `updated_at`        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_tutor_personas_code` (`code`),
+    INDEX `idx_tutor_personas_is_active` (`is_active`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='AI 老师人设表';
+
+-- ============================================================================
+-- 表 2：user_profiles 新增人设字段
+-- 用途：学习者选择的 AI 老师人设，NULL 表示使用默认老师
+-- ============================================================================
+ALTER TABLE `user_profiles`
+    ADD COLUMN `persona_id` VARCHAR(36) DEFAULT NULL COMMENT 'AI 老师人设ID (FK → tutor_personas.id)' AFTER `language_policy`,
+    ADD INDEX `idx_user_profiles_persona_id` (`persona_id`),
+    ADD CONSTRAINT `fk_user_profiles_persona_id` FOREIGN KEY (`persona_id`) REFERENCES `tutor_personas` (`id`) ON DELETE SET NULL;
+
+-- ============================================================================
+-- 初始人设
+-- ============================================================================
+INSERT IGNORE INTO `tutor_personas`
+    (`id`, `code`, `name`, `description`, `personality`, `tts_voice`, `speaking_rate`, `sort_order`)
+VALUES
+    (UUID(), 'sunny_sam', 'Sam',
+     '阳光活泼的大哥哥，喜欢运动和冒险，总是充满干劲。',
+     'You are Sam, a cheerful and energetic big brother. You love sports, games and adventures. Use lively words like "Awesome!" and "Let''s go!", and often suggest fun activities.',
+     'longanyang', 1.0, 10),
+    (UUID(), 'gentle_lily', 'Lily',
+     '温柔耐心的大姐姐，说话慢一点，适合刚开始学英语的孩子。',
+     'You are Lily, a gentle and patient big sister. You speak calmly and warmly, never rush the child, and praise every small try. You love flowers, animals and bedtime stories.',
+     'longxiaochun', 0.9, 20),
+    (UUID(), 'playful_momo', 'Momo',
+     '调皮可爱的小伙伴，喜欢讲笑话和玩猜谜游戏。',
+     'You are Momo, a playful and funny friend of the child''s age. You like jokes, riddles and silly guessing games, and you sometimes make harmless funny mistakes for the child to spot.',
+     'longhua', 1.1, 30),
+    (UUID(), 'professor_owl', 'Professor Owl',
+     '博学的猫头鹰教授，喜欢分享有趣的科学小知识。',
+     'You are Professor Owl, a wise and kind owl who knows a lot about nature and science. You love sharing one short fun fact at a time and asking the child "Did you know...?" questions.',
+     'longshu', 0.85, 40);
@@ -0,0 +1,28 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 分阶段耗时
+-- 版本: v2.14
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 4：conversation_messages 新增分阶段耗时字段
+-- 用途：定位慢轮次来自 ASR、LLM、TTS 还是 OSS；latency_ms（已有字段）开始写入本轮总耗时
+--       仅 AI 消息写入，用户消息始终为 NULL
+--
+-- 格式：{"asr": 420, "llm": 1300, "tts": 650, "oss": 80}（毫秒）
+--       实时语音对话（WebSocket）从孩子说完开始计时，并额外记录 first_audio（首段 AI 音频推送耗时）
+--       latency_ms 包含未单列的处理（会话解析、内容审核、数据库读取等），不含本轮消息写库
+-- ============================================================================
+ALTER TABLE `conversation_messages`
+    ADD COLUMN `stage_latencies` JSON DEFAULT NULL COMMENT '各处理阶段耗时（毫秒，JSON对象）' AFTER `latency_ms`;
+
+-- ============================================================================
+-- 表 5：pronunciation_evaluations 新增耗时字段
+-- 用途：同上，阶段为 assessment（讯飞评测）、llm、tts（反馈与示范音频累加）、oss
+-- ============================================================================
+ALTER TABLE `pronunciation_evaluations`
+    ADD COLUMN `latency_ms`      INT  DEFAULT NULL COMMENT '处理延迟（毫秒）' AFTER `error_message`,
+    ADD COLUMN `stage_latencies` JSON DEFAULT NULL COMMENT '各处理阶段耗时（毫秒，JSON对象）' AFTER `latency_ms`;
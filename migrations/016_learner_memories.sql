@@ -0,0 +1,54 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 学习者长期记忆
+-- 版本: v2.15
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 14：learner_memories（学习者长期记忆表）
+-- 用途：会话结束后由 LLM 从孩子的发言中提取的个人信息（名字、宠物、爱好、学校等），
+--       新会话开始时选取最相关的几条注入系统提示词
+--
+-- 规则：
+--   每个用户的同一 fact_key 只保留一条，再次提到时更新内容与置信度并累计 mention_count
+--   提取结果需通过内容安全审核，命中任何类别（含个人隐私）的条目不保存；置信度低于 0.5 的条目不保存
+--   家长可查看与删除；last_seen_at 超过 learner_memory_retention_days 天的条目不再使用并被清除
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS `learner_memories` (
+    `id`                      VARCHAR(36)     NOT NULL                    COMMENT '记忆ID (UUID)',
+    `user_id`                 VARCHAR(36)     NOT NULL                    COMMENT '用户ID',
+    `fact_key`                VARCHAR(100)    NOT NULL                    COMMENT '事实键（如 pet_dog_name）',
+    `category`                VARCHAR(20)     NOT NULL                    COMMENT '类别：name/pet/hobby/school/family/favorite/other',
+    `content`                 VARCHAR(255)    NOT NULL                    COMMENT '事实内容（简短英文）',
+    `confidence`              FLOAT           NOT NULL DEFAULT 0          COMMENT '置信度 (0-1)',
+    `mention_count`           INT             NOT NULL DEFAULT 1          COMMENT '被提取到的会话数',
+    `source_conversation_id`  VARCHAR(36)     DEFAULT NULL                COMMENT '最近一次提取到该事实的会话ID',
+    `first_seen_at`           TIMESTAMP       NOT NULL                    COMMENT '首次提取时间',
+    `last_seen_at`            TIMESTAMP       NOT NULL                    COMMENT '最近一次提取时间',
+    `created_at`              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_learner_memories_user_key` (`user_id`, `fact_key`),
+    KEY `idx_learner_memories_last_seen_at` (`last_seen_at`),
+    CONSTRAINT `fk_learner_memories_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='学习者长期记忆表';
+
+-- ============================================================================
+-- 表 3：voice_conversations 新增会话长期记忆字段
+-- 用途：会话第一轮选取的长期记忆（系统提示词片段），同一会话后续轮次沿用，
+--       会话中途新提取的记忆从下一个会话开始生效；家长删除记忆时清空所有会话的该字段
+-- ============================================================================
+ALTER TABLE `voice_conversations`
+    ADD COLUMN `learner_memory` TEXT DEFAULT NULL COMMENT '会话开始时注入的长期记忆' AFTER `summarized_until`;
+
+-- ============================================================================
+-- 系统配置：长期记忆保留天数（0 表示关闭长期记忆：不提取、不注入）
+-- ============================================================================
+INSERT INTO `system_settings` (`id`, `config_key`, `config_value`, `config_type`, `description`, `is_editable`)
+VALUES
+    ('set_011', 'learner_memory_retention_days', '180', 'int', '长期记忆保留天数（0 表示关闭长期记忆）', TRUE)
+ON DUPLICATE KEY UPDATE `updated_at` = CURRENT_TIMESTAMP;
@@ -0,0 +1,36 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 会话暂停、恢复与空闲超时
+-- 版本: v2.16
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 3：voice_conversations 新增会话生命周期字段
+-- 状态流转：
+--   active → paused      用户暂停（累计本段活跃时间）
+--   paused → active      用户恢复（从恢复时刻重新计时）
+--   active → completed   用户结束 / 达到消息上限 / 完成场景或题集 / 无交互超过 conversation_idle_timeout_minutes 分钟
+--   paused → completed   用户结束 / 暂停超过 conversation_paused_timeout_hours 小时未恢复
+-- duration_seconds 只累计 active 状态下相邻两次活跃之间的时间（单次间隔不超过空闲超时），暂停与空闲不计入
+-- ============================================================================
+ALTER TABLE `voice_conversations`
+    ADD COLUMN `last_active_at` TIMESTAMP NULL DEFAULT NULL COMMENT '最近一次活跃时间（开始、每轮对话、恢复时更新）' AFTER `last_message_at`,
+    ADD COLUMN `paused_at` TIMESTAMP NULL DEFAULT NULL COMMENT '暂停时间（非暂停状态为 NULL）' AFTER `last_active_at`,
+    ADD KEY `idx_voice_conversations_last_active_at` (`last_active_at`);
+
+-- 存量会话以最后一条消息时间（没有消息时为创建时间）作为最近一次活跃时间
+UPDATE `voice_conversations`
+SET `last_active_at` = COALESCE(`last_message_at`, `created_at`)
+WHERE `last_active_at` IS NULL;
+
+-- ============================================================================
+-- 系统配置：空闲与暂停超时
+-- ============================================================================
+INSERT INTO `system_settings` (`id`, `config_key`, `config_value`, `config_type`, `description`, `is_editable`)
+VALUES
+    ('set_012', 'conversation_idle_timeout_minutes', '15', 'int', '会话无交互自动结束的分钟数', TRUE),
+    ('set_013', 'conversation_paused_timeout_hours', '24', 'int', '暂停的会话未恢复自动结束的小时数', TRUE)
+ON DUPLICATE KEY UPDATE `updated_at` = CURRENT_TIMESTAMP;
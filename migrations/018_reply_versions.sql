@@ -0,0 +1,30 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 回复重新生成、失败轮次重试与回复版本
+-- 版本: v2.17
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 4：conversation_messages 新增失败阶段与回复版本字段（仅 AI 消息）
+-- failed_stage：同步对话在 LLM / TTS 阶段失败时，本轮照常保存（孩子的语音与识别文本不丢失），
+--               AI 消息标记失败阶段（llm 失败时文本为空，tts 失败时有文本无音频），重试成功后清空
+-- reply_versions：重新生成或重试替换掉的历史回复，按替换先后排列，当前版本号 = 数组长度 + 1
+--   [{"text": "...", "translation": "...", "audio_url": "...", "prompt_version": "v2",
+--     "failed_stage": null, "reason": "regenerate", "created_at": "...", "replaced_at": "..."}]
+-- ============================================================================
+ALTER TABLE `conversation_messages`
+    ADD COLUMN `failed_stage`   VARCHAR(20) DEFAULT NULL COMMENT '处理失败的阶段：llm/tts（NULL 表示成功）' AFTER `stage_latencies`,
+    ADD COLUMN `reply_versions` JSON        DEFAULT NULL COMMENT '被替换的历史回复（JSON数组）' AFTER `failed_stage`;
+
+-- ============================================================================
+-- 表 11：chat_feedback 新增回复版本号快照
+-- 用途：按版本对比首次生成与重新生成后的评分；存量反馈均为首次生成的回复
+-- 唯一键加入 reply_version：同一条回复的每个版本各保留一条反馈，评价新版本不覆盖旧版本的评分
+-- ============================================================================
+ALTER TABLE `chat_feedback`
+    ADD COLUMN `reply_version` INT NOT NULL DEFAULT 1 COMMENT '回复版本号快照（1 为首次生成）' AFTER `prompt_version`,
+    DROP INDEX `uk_chat_feedback_user_message`,
+    ADD UNIQUE KEY `uk_chat_feedback_user_message_version` (`user_id`, `message_id`, `reply_version`);
@@ -0,0 +1,18 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 管理员权限
+-- 版本: v2.18
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 1：users 新增管理员标记
+-- 用途：管理后台接口按 is_admin 授权，与决定每日配额的 plan 无关（设置套餐不会授予管理权限）
+-- 存量 admin 套餐的用户迁移为管理员，其套餐保持不变
+-- ============================================================================
+ALTER TABLE `users`
+    ADD COLUMN `is_admin` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否管理员（可访问管理后台）' AFTER `plan`;
+
+UPDATE `users` SET `is_admin` = 1 WHERE `plan` = 'admin';
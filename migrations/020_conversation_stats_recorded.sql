@@ -0,0 +1,22 @@
+-- ============================================================================
+-- OKTalk AI 发音纠正系统 - 会话学习统计累计标记
+-- 版本: v2.19
+-- 数据库: MySQL 8.0+
+-- 字符集: utf8mb4_unicode_ci
+-- ============================================================================
+
+SET NAMES utf8mb4;
+
+-- ============================================================================
+-- 表 3：voice_conversations 新增学习统计累计标记
+-- 用途：会话结束后累计用户的对话数、学习时长与最后对话时间，与 LLM 会话总结分开记录
+--
+-- 规则：
+--   仅在 stats_recorded_at 为 NULL 时条件更新并累计，重复触发（空闲清理、消息上限、用户结束）不重复计数
+--   LLM 总结失败或解析失败不影响统计
+--   存量已生成摘要的会话已在摘要保存时累计过，迁移时补写标记
+-- ============================================================================
+ALTER TABLE `voice_conversations`
+    ADD COLUMN `stats_recorded_at` TIMESTAMP NULL DEFAULT NULL COMMENT '已累计用户学习统计的时间（NULL 表示尚未累计）' AFTER `flag_reason`;
+
+UPDATE `voice_conversations` SET `stats_recorded_at` = `updated_at` WHERE `summary` IS NOT NULL;